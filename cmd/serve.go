@@ -30,6 +30,7 @@ var (
 	port           int
 	insecure       bool
 	readOnly       bool
+	monitoring     bool
 	authzServerURL string
 	jwksURL        string
 	resourceURL    string
@@ -48,6 +49,7 @@ func init() {
 	serveCmd.Flags().IntVar(&port, "port", 9092, "Port to listen on")
 	serveCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS verification")
 	serveCmd.Flags().BoolVar(&readOnly, "read-only", false, "Only register read-only tools")
+	serveCmd.Flags().BoolVar(&monitoring, "monitoring", false, "Register the monitoring tools, which query the Prometheus of rancher-monitoring")
 
 	serveCmd.Flags().StringVar(&authzServerURL, "authz-server-url", "", "Authorization Server URL - used to generate the OIDC urls")
 	serveCmd.Flags().StringVar(&jwksURL, "jwks-url", "", "JWKS URL - from the OAuth2 server")
@@ -61,9 +63,10 @@ func runServe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	toolsets.AddAllTools(client, mcpServer, readOnly)
+	toolsets.AddAllTools(client, mcpServer, readOnly, monitoring)
 
 	zap.L().Info("read-only mode", zap.Bool("enabled", readOnly))
+	zap.L().Info("monitoring tools", zap.Bool("enabled", monitoring))
 
 	handler := mcp.NewStreamableHTTPHandler(func(request *http.Request) *mcp.Server {
 		return mcpServer
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// DefaultClientCacheSize is the maximum number of token and cluster pairs whose clients are kept.
+	DefaultClientCacheSize = 256
+	// DefaultClientCacheTTL is how long clients are kept after they are created.
+	DefaultClientCacheTTL = 10 * time.Minute
+)
+
+// clientCacheEntry holds the clients created for a single token and cluster pair.
+// Clients are created on first use, so an entry may hold only some of them.
+// Entries are only modified while holding the cache lock, callers get copies.
+type clientCacheEntry struct {
+	key        string
+	expiresAt  time.Time
+	restConfig *rest.Config
+	dynClient  dynamic.Interface
+	clientSet  kubernetes.Interface
+}
+
+// clientCache is an LRU cache of rest configs and clients keyed by a hash of the token and cluster ID.
+// Reusing clients reuses their HTTP transport, avoiding a new TLS handshake for every request.
+// Entries are dropped after the TTL, or earlier if the token is a JWT that expires before then.
+type clientCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+func newClientCache(maxSize int, ttl time.Duration) *clientCache {
+	return &clientCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		now:     time.Now,
+	}
+}
+
+// unauthorizedRoundTripper drops the cached clients of a token and cluster when Rancher answers 401 Unauthorized.
+type unauthorizedRoundTripper struct {
+	rt         http.RoundTripper
+	invalidate func()
+}
+
+func (u *unauthorizedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := u.rt.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		u.invalidate()
+	}
+
+	return resp, err
+}
+
+// clientCacheKey hashes the token so that it is never held in memory as a map key.
+func clientCacheKey(token, clusterID string) string {
+	sum := sha256.Sum256([]byte(token + "\x00" + clusterID))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the entry of the token and cluster, if there is one that didn't expire.
+func (c *clientCache) get(token, clusterID string) (clientCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[clientCacheKey(token, clusterID)]
+	if !ok {
+		return clientCacheEntry{}, false
+	}
+	entry := elem.Value.(*clientCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(elem)
+		return clientCacheEntry{}, false
+	}
+	c.lru.MoveToFront(elem)
+
+	return *entry, true
+}
+
+// add stores the clients of entry for the token and cluster and returns the resulting entry. Clients are created
+// without holding the lock, so another caller may have cached some of them in the meantime. Those are kept and
+// returned instead of the new ones, so that all callers share the same clients and HTTP transports.
+func (c *clientCache) add(token, clusterID string, entry clientCacheEntry) clientCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := clientCacheKey(token, clusterID)
+	now := c.now()
+	if elem, ok := c.entries[key]; ok {
+		cached := elem.Value.(*clientCacheEntry)
+		if now.Before(cached.expiresAt) {
+			if cached.restConfig == nil {
+				cached.restConfig = entry.restConfig
+			}
+			if cached.dynClient == nil {
+				cached.dynClient = entry.dynClient
+			}
+			if cached.clientSet == nil {
+				cached.clientSet = entry.clientSet
+			}
+			c.lru.MoveToFront(elem)
+			return *cached
+		}
+		c.remove(elem)
+	}
+
+	entry.key = key
+	entry.expiresAt = now.Add(c.ttl)
+	if exp, ok := tokenExpiration(token); ok && exp.Before(entry.expiresAt) {
+		entry.expiresAt = exp
+	}
+	if !now.Before(entry.expiresAt) {
+		// the token has already expired, there is no point in caching its clients.
+		return entry
+	}
+
+	c.entries[key] = c.lru.PushFront(&entry)
+	for c.lru.Len() > c.maxSize {
+		c.remove(c.lru.Back())
+	}
+
+	return entry
+}
+
+// invalidate drops the clients of a token and cluster pair. It is called when Rancher rejects the token,
+// as API tokens aren't JWTs and their expiration or revocation can't be known in advance.
+func (c *clientCache) invalidate(token, clusterID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[clientCacheKey(token, clusterID)]; ok {
+		c.remove(elem)
+	}
+}
+
+// len returns the number of cached entries, including expired ones that were not evicted yet.
+func (c *clientCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *clientCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*clientCacheEntry).key)
+}
+
+// tokenExpiration returns the expiration of the token if it is a JWT with an exp claim.
+// The signature is not verified here, the token has already been validated by the middleware
+// and is validated again by Rancher on every request.
+func tokenExpiration(token string) (time.Time, bool) {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}, false
+	}
+
+	return claims.ExpiresAt.Time, true
+}
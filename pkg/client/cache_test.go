@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newFakeJWT(t *testing.T, expiresAt time.Time) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestClientCacheReusesClients(t *testing.T) {
+	tests := map[string]struct {
+		calls           []GetParams
+		expectedCreated int
+		expectedEntries int
+	}{
+		"same token and cluster": {
+			calls: []GetParams{
+				{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: fakeToken},
+				{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: fakeToken},
+				{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: fakeToken},
+			},
+			expectedCreated: 1,
+			expectedEntries: 1,
+		},
+		"different tokens": {
+			calls: []GetParams{
+				{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: fakeToken},
+				{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: "token-yyy"},
+			},
+			expectedCreated: 2,
+			expectedEntries: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			created := 0
+			c := &Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					created++
+					return dynamicfake.NewSimpleDynamicClient(scheme(), &v1.Pod{
+						ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+					}), nil
+				},
+			}
+
+			for _, params := range test.calls {
+				_, err := c.GetResource(context.Background(), params)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedCreated, created)
+			assert.Equal(t, test.expectedEntries, c.clientCache().len())
+		})
+	}
+}
+
+func TestClientCacheExpiration(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		token         string
+		advance       time.Duration
+		expectedFound bool
+	}{
+		"entry is reused before the ttl": {
+			token:         fakeToken,
+			advance:       time.Minute,
+			expectedFound: true,
+		},
+		"entry expires after the ttl": {
+			token:   fakeToken,
+			advance: DefaultClientCacheTTL,
+		},
+		"entry expires with the token": {
+			token:   newFakeJWT(t, now.Add(time.Minute)),
+			advance: 2 * time.Minute,
+		},
+		"expired token is not cached": {
+			token: newFakeJWT(t, now.Add(-time.Minute)),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := newClientCache(DefaultClientCacheSize, DefaultClientCacheTTL)
+			cache.now = func() time.Time { return now }
+
+			cache.add(test.token, "local", clientCacheEntry{restConfig: &rest.Config{}})
+			cache.now = func() time.Time { return now.Add(test.advance) }
+			_, found := cache.get(test.token, "local")
+
+			assert.Equal(t, test.expectedFound, found)
+		})
+	}
+}
+
+func TestClientCacheAddKeepsCachedClients(t *testing.T) {
+	cache := newClientCache(DefaultClientCacheSize, DefaultClientCacheTTL)
+	first := dynamicfake.NewSimpleDynamicClient(scheme())
+	second := dynamicfake.NewSimpleDynamicClient(scheme())
+	clientSet := k8sfake.NewClientset()
+
+	cache.add(fakeToken, "local", clientCacheEntry{dynClient: first})
+	entry := cache.add(fakeToken, "local", clientCacheEntry{dynClient: second, clientSet: clientSet})
+
+	assert.Same(t, first, entry.dynClient)
+	assert.Same(t, clientSet, entry.clientSet)
+	assert.Equal(t, 1, cache.len())
+}
+
+func TestClientCacheEviction(t *testing.T) {
+	cache := newClientCache(2, DefaultClientCacheTTL)
+
+	cache.add(fakeToken, "c-1", clientCacheEntry{})
+	cache.add(fakeToken, "c-2", clientCacheEntry{})
+	// use c-1 so that c-2 becomes the least recently used entry.
+	_, found := cache.get(fakeToken, "c-1")
+	require.True(t, found)
+	cache.add(fakeToken, "c-3", clientCacheEntry{})
+
+	assert.Equal(t, 2, cache.len())
+	assert.Contains(t, cache.entries, clientCacheKey(fakeToken, "c-1"))
+	assert.Contains(t, cache.entries, clientCacheKey(fakeToken, "c-3"))
+	assert.NotContains(t, cache.entries, clientCacheKey(fakeToken, "c-2"))
+
+	cache.invalidate(fakeToken, "c-1")
+	assert.Equal(t, 1, cache.len())
+}
+
+func TestClientCacheUnauthorized(t *testing.T) {
+	tests := map[string]struct {
+		status          int
+		expectedEntries int
+	}{
+		"rejected token drops the cached clients": {
+			status:          http.StatusUnauthorized,
+			expectedEntries: 0,
+		},
+		"other errors keep the cached clients": {
+			status:          http.StatusForbidden,
+			expectedEntries: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","code":` + strconv.Itoa(test.status) + `}`))
+			}))
+			defer server.Close()
+			c := &Client{
+				rancherURL: server.URL,
+				DynClientCreator: func(cfg *rest.Config) (dynamic.Interface, error) {
+					return dynamic.NewForConfig(cfg)
+				},
+			}
+
+			_, err := c.GetResource(context.Background(), GetParams{Cluster: "local", Kind: "pod", Namespace: "default", Name: "test-pod", Token: fakeToken})
+
+			require.Error(t, err)
+			assert.Equal(t, test.expectedEntries, c.clientCache().len())
+		})
+	}
+}
+
+func TestClientCacheConcurrentCreation(t *testing.T) {
+	c2Created := make(chan struct{})
+	c := &Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			// creating the client of c-1 only finishes once the client of c-2 was created,
+			// which deadlocks if clients are created while holding the cache lock.
+			if strings.HasSuffix(inConfig.Host, "/c-1") {
+				select {
+				case <-c2Created:
+				case <-time.After(5 * time.Second):
+					return nil, errors.New("client creation is serialized")
+				}
+			}
+			return dynamicfake.NewSimpleDynamicClient(scheme()), nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	var c1Err error
+	wg.Go(func() {
+		_, c1Err = c.dynClientFor(fakeToken, "c-1")
+	})
+	_, err := c.dynClientFor(fakeToken, "c-2")
+	require.NoError(t, err)
+	close(c2Created)
+	wg.Wait()
+
+	require.NoError(t, c1Err)
+	assert.Equal(t, 2, c.clientCache().len())
+}
+
+func TestClientCacheKey(t *testing.T) {
+	key := clientCacheKey(fakeToken, "local")
+
+	assert.NotContains(t, key, fakeToken)
+	assert.NotContains(t, key, "local")
+	assert.NotEqual(t, key, clientCacheKey(fakeToken, "c-1"))
+	assert.NotEqual(t, key, clientCacheKey("token-yyy", "local"))
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -19,15 +20,21 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
-var clusterIdsCache = sync.Map{}
-var clustersDisplayNameToIDCache = sync.Map{}
-
 // Client is a struct that provides methods for interacting with Kubernetes clusters.
 type Client struct {
 	insecure         bool
 	rancherURL       string
 	DynClientCreator func(*rest.Config) (dynamic.Interface, error)
 	ClientSetCreator func(*rest.Config) (kubernetes.Interface, error)
+
+	// clients caches the rest configs and clients per token and cluster. It is created on first use
+	// so that a Client built as a struct literal, as tests do, gets the default cache.
+	clients     *clientCache
+	clientsOnce sync.Once
+
+	// clusterIDs caches the cluster IDs resolved by GetClusterID. It is created on first use, like clients.
+	clusterIDs     *clusterIDResolver
+	clusterIDsOnce sync.Once
 }
 
 // GetParams holds the parameters required to get a resource from k8s.
@@ -47,6 +54,7 @@ type ListParams struct {
 	Name          string // The Name of the resource (optional).
 	Token         string // The authentication Token for Steve.
 	LabelSelector string // Optional LabelSelector string for the request.
+	FieldSelector string // Optional FieldSelector string for the request.
 	Limit         int64  // Optional maximum number of resources to return. 0 means no limit.
 }
 
@@ -81,12 +89,8 @@ func (c *Client) CreateClientSet(ctx context.Context, token string, cluster stri
 	if err != nil {
 		return nil, err
 	}
-	restConfig, err := c.CreateRestConfig(token, clusterID)
-	if err != nil {
-		return nil, err
-	}
 
-	return c.ClientSetCreator(restConfig)
+	return c.clientSetFor(token, clusterID)
 }
 
 // GetResourceInterface returns a dynamic resource interface for the given Token, URL, Namespace, and GroupVersionResource.
@@ -95,11 +99,7 @@ func (c *Client) GetResourceInterface(ctx context.Context, token string, namespa
 	if err != nil {
 		return nil, err
 	}
-	restConfig, err := c.CreateRestConfig(token, clusterID)
-	if err != nil {
-		return nil, err
-	}
-	dynClient, err := c.DynClientCreator(restConfig)
+	dynClient, err := c.dynClientFor(token, clusterID)
 	if err != nil {
 		return nil, err
 	}
@@ -198,6 +198,9 @@ func (c *Client) GetResources(ctx context.Context, params ListParams) ([]*unstru
 	if params.LabelSelector != "" {
 		opts.LabelSelector = params.LabelSelector
 	}
+	if params.FieldSelector != "" {
+		opts.FieldSelector = params.FieldSelector
+	}
 	if params.Limit > 0 {
 		opts.Limit = params.Limit
 	}
@@ -270,92 +273,118 @@ func (c *Client) GetResourcesAtAnyAPIVersion(ctx context.Context, params ListPar
 	return objs, err
 }
 
-// getClusterId returns the cluster's unique ID given either its cluster ID (metadata.name)
-// or its display name (spec.displayName). It uses local caches to avoid redundant lookups.
+// GetClusterID returns the cluster's unique ID given either its cluster ID (metadata.name)
+// or its display name (spec.displayName).
 //
 // The lookup order is:
 //  1. If the input is "local", return immediately.
-//  2. Check in-memory caches for cluster ID or display name.
+//  2. Check the cache of IDs resolved with the same token. A cached ID is only returned after checking that
+//     the caller can still get the cluster; if the cluster is gone or was renamed since, the entry is dropped
+//     and resolved again.
 //  3. Query the cluster resource API by ID.
-//  4. If not found, fall back to listing all clusters and matching by display name.
+//  4. If not found, fall back to listing all clusters and matching by display name, ignoring case.
+//     An AmbiguousClusterError is returned when more than one cluster has that display name.
 //
-// both cluster ID and display name are cached for future lookups.
+// Concurrent lookups of the same cluster with the same token share a single query.
 func (c *Client) GetClusterID(ctx context.Context, token string, clusterNameOrID string) (string, error) {
 	// handle the special case for the local cluster, it always exists and is known by ID and displayName "local"
 	if clusterNameOrID == "local" {
 		return "local", nil
 	}
 
-	// check if the provided identifier is already known to be a cluster ID
-	if _, ok := clusterIdsCache.Load(clusterNameOrID); ok {
-		return clusterNameOrID, nil // it is a cluster ID
-	}
-
-	// check if the provided identifier matches a display name cached earlier
-	if clusterID, exists := clustersDisplayNameToIDCache.Load(clusterNameOrID); exists {
-		return clusterID.(string), nil
-	}
-
-	// try to fetch the cluster directly by its ID
-	clusterInterface, err := c.GetResourceInterface(ctx, token, "", "local", converter.K8sKindsToGVRs["managementcluster"])
+	clusterInterface, err := c.GetResourceInterface(ctx, token, "", "local", converter.K8sKindsToGVRs[converter.ManagementClusterResourceKind])
 	if err != nil {
 		return "", err
 	}
 
-	cluster, err := clusterInterface.Get(ctx, clusterNameOrID, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
+	resolver := c.clusterIDResolver()
+	if clusterID, ok := resolver.get(token, clusterNameOrID); ok {
+		// the cache only saves listing all clusters, the caller must still be allowed to get the cluster.
+		cluster, err := clusterInterface.Get(ctx, clusterID, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
 			return "", err
 		}
+		if err == nil && (clusterID == clusterNameOrID || clusterHasDisplayName(cluster, clusterNameOrID)) {
+			return clusterID, nil
+		}
+		resolver.invalidate(token, clusterNameOrID)
+	}
 
-		// If not found by ID, try to locate it by display name.
-		clusters, err := clusterInterface.List(ctx, metav1.ListOptions{})
+	clusterID, err, _ := resolver.group.Do(clientCacheKey(token, clusterNameOrID), func() (any, error) {
+		clusterID, err := lookupClusterID(ctx, clusterInterface, clusterNameOrID)
 		if err != nil {
 			return "", err
 		}
-		for _, cluster := range clusters.Items {
-			clusterID := cluster.GetName()
-			clusterIdsCache.Store(clusterID, struct{}{})
-
-			displayName, found, err := unstructured.NestedString(
-				cluster.Object,
-				"spec",
-				"displayName",
-			)
-			if err != nil {
-				return "", err
-			}
+		resolver.set(token, clusterNameOrID, clusterID)
 
-			if found {
-				clustersDisplayNameToIDCache.Store(displayName, clusterID)
+		return clusterID, nil
+	})
+	if err != nil {
+		return "", err
+	}
 
-				// If the given identifier matches this display name, return its ID.
-				if displayName == clusterNameOrID {
-					return clusterID, nil
-				}
-			}
-		}
+	return clusterID.(string), nil
+}
 
-		return "", fmt.Errorf("cluster '%s' not found", clusterNameOrID)
-	}
+// clusterHasDisplayName reports whether the display name of the cluster is the given name, ignoring case.
+func clusterHasDisplayName(cluster *unstructured.Unstructured, name string) bool {
+	displayName, _, _ := unstructured.NestedString(cluster.Object, "spec", "displayName")
+
+	return strings.EqualFold(displayName, name)
+}
 
-	// clusterNameOrIDInput contains the cluster ID. Store it in the cache.
-	clusterID := clusterNameOrID
-	clusterIdsCache.Store(clusterID, struct{}{})
+// lookupClusterID queries the cluster by ID and, if there is none, lists all clusters to find it by display name.
+// Display names are matched case-insensitively.
+func lookupClusterID(ctx context.Context, clusterInterface dynamic.ResourceInterface, clusterNameOrID string) (string, error) {
+	_, err := clusterInterface.Get(ctx, clusterNameOrID, metav1.GetOptions{})
+	if err == nil {
+		return clusterNameOrID, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", err
+	}
 
-	displayName, found, err := unstructured.NestedString(
-		cluster.Object,
-		"spec",
-		"displayName",
-	)
+	// If not found by ID, try to locate it by display name. Rancher allows duplicate display names, so every
+	// match is collected and the lookup fails if there is more than one rather than picking one of them.
+	clusters, err := clusterInterface.List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return "", err
 	}
-	if found {
-		clustersDisplayNameToIDCache.Store(displayName, clusterID)
+	var candidates []ClusterCandidate
+	for _, cluster := range clusters.Items {
+		displayName, found, err := unstructured.NestedString(
+			cluster.Object,
+			"spec",
+			"displayName",
+		)
+		if err != nil {
+			return "", err
+		}
+
+		if found && strings.EqualFold(displayName, clusterNameOrID) {
+			candidates = append(candidates, newClusterCandidate(&cluster, displayName))
+		}
 	}
 
-	return clusterID, nil
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("cluster '%s' not found", clusterNameOrID)
+	case 1:
+		return candidates[0].ID, nil
+	default:
+		return "", &AmbiguousClusterError{Name: clusterNameOrID, Candidates: candidates}
+	}
+}
+
+// clusterIDResolver returns the cache of resolved cluster IDs, creating it on first use.
+func (c *Client) clusterIDResolver() *clusterIDResolver {
+	c.clusterIDsOnce.Do(func() {
+		if c.clusterIDs == nil {
+			c.clusterIDs = newClusterIDResolver(DefaultClusterIDCacheSize, DefaultClusterIDCacheTTL)
+		}
+	})
+
+	return c.clusterIDs
 }
 
 // CreateRestConfig creates a new rest.Config for accessing a Kubernetes cluster through Rancher.
@@ -388,19 +417,91 @@ func (c *Client) CreateRestConfig(token string, clusterID string) (*rest.Config,
 	return restConfig, nil
 }
 
-// getAPIVersionsForGR queries the API server for all supported versions of the specified GroupResource.
-// It returns a slice of version strings or an error if the query fails.
-func (c *Client) getAPIVersionsForGR(ctx context.Context, token, cluster string, groupResource schema.GroupResource) ([]string, error) {
-	clusterID, err := c.GetClusterID(ctx, token, cluster)
+// clientCache returns the cache of rest configs and clients, creating it on first use.
+func (c *Client) clientCache() *clientCache {
+	c.clientsOnce.Do(func() {
+		if c.clients == nil {
+			c.clients = newClientCache(DefaultClientCacheSize, DefaultClientCacheTTL)
+		}
+	})
+
+	return c.clients
+}
+
+// dynClientFor returns the dynamic client for the token and cluster ID, reusing a cached one when possible.
+func (c *Client) dynClientFor(token, clusterID string) (dynamic.Interface, error) {
+	cache := c.clientCache()
+	entry, _ := cache.get(token, clusterID)
+	if entry.dynClient != nil {
+		return entry.dynClient, nil
+	}
+
+	restConfig, err := c.restConfigFor(entry, token, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	dynClient, err := c.DynClientCreator(restConfig)
 	if err != nil {
 		return nil, err
 	}
+	entry = cache.add(token, clusterID, clientCacheEntry{restConfig: restConfig, dynClient: dynClient})
+
+	return entry.dynClient, nil
+}
+
+// clientSetFor returns the clientset for the token and cluster ID, reusing a cached one when possible.
+func (c *Client) clientSetFor(token, clusterID string) (kubernetes.Interface, error) {
+	cache := c.clientCache()
+	entry, _ := cache.get(token, clusterID)
+	if entry.clientSet != nil {
+		return entry.clientSet, nil
+	}
+
+	restConfig, err := c.restConfigFor(entry, token, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	clientSet, err := c.ClientSetCreator(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	entry = cache.add(token, clusterID, clientCacheEntry{restConfig: restConfig, clientSet: clientSet})
+
+	return entry.clientSet, nil
+}
+
+// restConfigFor returns the rest config of a cache entry, or creates one if the entry has none. Created configs
+// drop the cached clients of the token and cluster when Rancher rejects the token.
+func (c *Client) restConfigFor(entry clientCacheEntry, token, clusterID string) (*rest.Config, error) {
+	if entry.restConfig != nil {
+		return entry.restConfig, nil
+	}
+
 	restConfig, err := c.CreateRestConfig(token, clusterID)
 	if err != nil {
 		return nil, err
 	}
+	cache := c.clientCache()
+	restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &unauthorizedRoundTripper{
+			rt: rt,
+			invalidate: func() {
+				cache.invalidate(token, clusterID)
+			},
+		}
+	})
+
+	return restConfig, nil
+}
 
-	client, err := c.ClientSetCreator(restConfig)
+// getAPIVersionsForGR queries the API server for all supported versions of the specified GroupResource.
+// It returns a slice of version strings or an error if the query fails.
+func (c *Client) getAPIVersionsForGR(ctx context.Context, token, cluster string, groupResource schema.GroupResource) ([]string, error) {
+	clusterID, err := c.GetClusterID(ctx, token, cluster)
+	if err != nil {
+		return nil, err
+	}
+	client, err := c.clientSetFor(token, clusterID)
 	if err != nil {
 		return nil, err
 	}
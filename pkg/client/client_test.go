@@ -2,8 +2,8 @@ package client
 
 import (
 	"context"
-	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
@@ -44,82 +45,47 @@ func TestGetClusterId(t *testing.T) {
 	)
 
 	tests := map[string]struct {
-		clusterNameOrIDInput                 string
-		fakeDynClient                        *dynamicfake.FakeDynamicClient
-		clusterIdsCache                      map[string]any
-		clustersDisplayNameToIDCache         map[string]any
-		expectedClusterIdsCache              map[string]any
-		expectedClustersDisplayNameToIDCache map[string]any
-		expectedID                           string
-		expectErr                            string
+		clusterNameOrIDInput string
+		fakeDynClient        *dynamicfake.FakeDynamicClient
+		expectedID           string
+		expectedCached       bool
+		expectErr            string
 	}{
 		"should return clusterID if input is a clusterID": {
-			clusterNameOrIDInput:                 clusterID,
-			fakeDynClient:                        dynamicfake.NewSimpleDynamicClient(scheme(), newFakeCluster(clusterID, clusterDN)),
-			expectedClusterIdsCache:              map[string]any{clusterID: struct{}{}},
-			expectedClustersDisplayNameToIDCache: map[string]any{clusterDN: clusterID},
-			expectedID:                           clusterID,
+			clusterNameOrIDInput: clusterID,
+			fakeDynClient:        dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster(clusterID, clusterDN)),
+			expectedID:           clusterID,
+			expectedCached:       true,
 		},
 
 		"should return clusterID if input is a cluster displayName": {
-			clusterNameOrIDInput:                 clusterDN,
-			fakeDynClient:                        dynamicfake.NewSimpleDynamicClient(scheme(), newFakeCluster(clusterID, clusterDN)),
-			expectedClusterIdsCache:              map[string]any{clusterID: struct{}{}},
-			expectedClustersDisplayNameToIDCache: map[string]any{clusterDN: clusterID},
-			expectedID:                           clusterID,
+			clusterNameOrIDInput: clusterDN,
+			fakeDynClient:        dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster(clusterID, clusterDN)),
+			expectedID:           clusterID,
+			expectedCached:       true,
 		},
 
-		"should return clusterID if clusterID is in the cache": {
-			clusterNameOrIDInput:                 clusterID,
-			clusterIdsCache:                      map[string]any{clusterID: struct{}{}},
-			clustersDisplayNameToIDCache:         map[string]any{clusterDN: clusterID},
-			fakeDynClient:                        dynamicfake.NewSimpleDynamicClient(scheme()),
-			expectedClusterIdsCache:              map[string]any{clusterID: struct{}{}},
-			expectedClustersDisplayNameToIDCache: map[string]any{clusterDN: clusterID},
-			expectedID:                           clusterID,
-		},
-
-		"should return clusterID if displayName is in the cache": {
-			clusterNameOrIDInput:                 clusterDN,
-			clusterIdsCache:                      map[string]any{clusterID: struct{}{}},
-			clustersDisplayNameToIDCache:         map[string]any{clusterDN: clusterID},
-			fakeDynClient:                        dynamicfake.NewSimpleDynamicClient(scheme()),
-			expectedClusterIdsCache:              map[string]any{clusterID: struct{}{}},
-			expectedClustersDisplayNameToIDCache: map[string]any{clusterDN: clusterID},
-			expectedID:                           clusterID,
+		"display name is matched ignoring case": {
+			clusterNameOrIDInput: "My-Display-Name",
+			fakeDynClient:        dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster(clusterID, clusterDN)),
+			expectedID:           clusterID,
+			expectedCached:       true,
 		},
 
 		"local": {
-			clusterNameOrIDInput:                 "local",
-			expectedClusterIdsCache:              map[string]any{},
-			expectedClustersDisplayNameToIDCache: map[string]any{},
-			expectedID:                           "local",
+			clusterNameOrIDInput: "local",
+			expectedID:           "local",
 		},
 
 		"cluster not found": {
-			clusterNameOrIDInput:                 clusterDN,
-			fakeDynClient:                        dynamicfake.NewSimpleDynamicClient(scheme(), newFakeCluster(clusterID, "another cluster")),
-			expectedClusterIdsCache:              map[string]any{clusterID: struct{}{}},
-			expectedClustersDisplayNameToIDCache: map[string]any{"another cluster": clusterID},
-			expectErr:                            "cluster 'my-display-name' not found",
+			clusterNameOrIDInput: clusterDN,
+			fakeDynClient:        dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster(clusterID, "another cluster")),
+			expectErr:            "cluster 'my-display-name' not found",
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			clusterIdsCache = sync.Map{}
-			if test.clusterIdsCache != nil {
-				for key, value := range test.clusterIdsCache {
-					clusterIdsCache.Store(key, value)
-				}
-			}
-			clustersDisplayNameToIDCache = sync.Map{}
-			if test.clustersDisplayNameToIDCache != nil {
-				for key, value := range test.clustersDisplayNameToIDCache {
-					clustersDisplayNameToIDCache.Store(key, value)
-				}
-			}
-
 			c := &Client{
 				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
 					return test.fakeDynClient, nil
@@ -134,19 +100,53 @@ func TestGetClusterId(t *testing.T) {
 				require.NoError(t, err)
 			}
 			assert.Equal(t, test.expectedID, clusterID)
-			assert.Equal(t, test.expectedClusterIdsCache, syncMapToMap(&clusterIdsCache))
-			assert.Equal(t, test.expectedClustersDisplayNameToIDCache, syncMapToMap(&clustersDisplayNameToIDCache))
+			cachedID, cached := c.clusterIDResolver().get(fakeToken, test.clusterNameOrIDInput)
+			assert.Equal(t, test.expectedCached, cached)
+			if test.expectedCached {
+				assert.Equal(t, test.expectedID, cachedID)
+			}
 		})
 	}
 }
 
-func syncMapToMap(syncMap *sync.Map) map[string]any {
-	result := make(map[string]any)
-	syncMap.Range(func(key, value any) bool {
-		result[key.(string)] = value
-		return true
-	})
-	return result
+func TestGetClusterIdAmbiguous(t *testing.T) {
+	newCluster := func(id, displayName, provider string, created time.Time) *unstructured.Unstructured {
+		cluster := newFakeCluster(id, displayName)
+		cluster.SetCreationTimestamp(metav1.NewTime(created))
+		_ = unstructured.SetNestedField(cluster.Object, provider, "status", "provider")
+		return cluster
+	}
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(),
+		newCluster("c-m-1", "prod", "rke2", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+		newCluster("c-m-2", "PROD", "k3s", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)),
+		newCluster("c-m-3", "staging", "rke2", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)),
+	)
+	c := &Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return fakeDynClient, nil
+		},
+	}
+
+	clusterID, err := c.GetClusterID(t.Context(), fakeToken, "prod")
+
+	assert.Empty(t, clusterID)
+	var ambiguousErr *AmbiguousClusterError
+	require.ErrorAs(t, err, &ambiguousErr)
+	assert.Equal(t, "prod", ambiguousErr.Name)
+	assert.ElementsMatch(t, []ClusterCandidate{
+		{ID: "c-m-1", DisplayName: "prod", Provider: "rke2", CreationTimestamp: "2025-01-01T00:00:00Z"},
+		{ID: "c-m-2", DisplayName: "PROD", Provider: "k3s", CreationTimestamp: "2026-02-01T00:00:00Z"},
+	}, ambiguousErr.Candidates)
+	assert.ErrorContains(t, err, "cluster name 'prod' is ambiguous, it matches 2 clusters")
+	assert.ErrorContains(t, err, `"id":"c-m-1"`)
+	_, cached := c.clusterIDResolver().get(fakeToken, "prod")
+	assert.False(t, cached)
+}
+
+func clusterListKinds() map[schema.GroupVersionResource]string {
+	return map[schema.GroupVersionResource]string{
+		{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}: "ClusterList",
+	}
 }
 
 func scheme() *runtime.Scheme {
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DefaultClusterIDCacheTTL is how long a resolved cluster ID is kept before it is looked up again.
+	DefaultClusterIDCacheTTL = 5 * time.Minute
+	// DefaultClusterIDCacheSize is the maximum number of resolved cluster IDs that are kept.
+	DefaultClusterIDCacheSize = 1024
+)
+
+// ClusterCandidate describes one of the clusters matching an ambiguous cluster name.
+type ClusterCandidate struct {
+	ID                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	Provider          string `json:"provider,omitempty"`
+	CreationTimestamp string `json:"creationTimestamp,omitempty"`
+}
+
+// AmbiguousClusterError is returned by GetClusterID when a display name matches more than one cluster.
+// Its message lists the candidates so that the agent can ask the user which cluster they meant.
+type AmbiguousClusterError struct {
+	Name       string
+	Candidates []ClusterCandidate
+}
+
+func (e *AmbiguousClusterError) Error() string {
+	candidates, _ := json.Marshal(e.Candidates)
+	return fmt.Sprintf("cluster name '%s' is ambiguous, it matches %d clusters. Ask the user which one they mean and use its ID: %s", e.Name, len(e.Candidates), candidates)
+}
+
+func newClusterCandidate(cluster *unstructured.Unstructured, displayName string) ClusterCandidate {
+	provider, _, _ := unstructured.NestedString(cluster.Object, "status", "provider")
+	if provider == "" {
+		provider, _, _ = unstructured.NestedString(cluster.Object, "status", "driver")
+	}
+	candidate := ClusterCandidate{
+		ID:          cluster.GetName(),
+		DisplayName: displayName,
+		Provider:    provider,
+	}
+	if ts := cluster.GetCreationTimestamp(); !ts.IsZero() {
+		candidate.CreationTimestamp = ts.UTC().Format(time.RFC3339)
+	}
+
+	return candidate
+}
+
+// clusterIDEntry is a cluster ID resolved for a token and a cluster name or ID.
+type clusterIDEntry struct {
+	clusterID string
+	expiresAt time.Time
+}
+
+// clusterIDResolver caches the cluster IDs resolved from cluster names or IDs. Entries are keyed by the token that
+// resolved them, so a lookup made by one user never answers the lookup of another, and they expire after the TTL so
+// that renamed or deleted clusters are eventually resolved again. Concurrent lookups of the same name by the same
+// token are collapsed into one.
+type clusterIDResolver struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]clusterIDEntry
+	group   singleflight.Group
+	now     func() time.Time
+}
+
+func newClusterIDResolver(maxSize int, ttl time.Duration) *clusterIDResolver {
+	return &clusterIDResolver{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]clusterIDEntry),
+		now:     time.Now,
+	}
+}
+
+// get returns the cluster ID cached for the token and cluster name or ID, if it didn't expire.
+func (r *clusterIDResolver) get(token, clusterNameOrID string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := clientCacheKey(token, clusterNameOrID)
+	entry, ok := r.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !r.now().Before(entry.expiresAt) {
+		delete(r.entries, key)
+		return "", false
+	}
+
+	return entry.clusterID, true
+}
+
+// set caches the cluster ID resolved for the token and cluster name or ID.
+func (r *clusterIDResolver) set(token, clusterNameOrID, clusterID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if len(r.entries) >= r.maxSize {
+		for key, entry := range r.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(r.entries, key)
+			}
+		}
+	}
+	// still full, drop arbitrary entries. They will be resolved again when needed.
+	for key := range r.entries {
+		if len(r.entries) < r.maxSize {
+			break
+		}
+		delete(r.entries, key)
+	}
+
+	r.entries[clientCacheKey(token, clusterNameOrID)] = clusterIDEntry{
+		clusterID: clusterID,
+		expiresAt: now.Add(r.ttl),
+	}
+}
+
+// invalidate drops the cluster ID cached for the token and cluster name or ID.
+func (r *clusterIDResolver) invalidate(token, clusterNameOrID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, clientCacheKey(token, clusterNameOrID))
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var managementClustersGVR = schema.GroupVersionResource{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}
+
+func countListActions(fakeDynClient *dynamicfake.FakeDynamicClient) int {
+	count := 0
+	for _, action := range fakeDynClient.Actions() {
+		if action.GetVerb() == "list" {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestGetClusterIdCache(t *testing.T) {
+	const clusterDN = "prod"
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		secondToken   string
+		between       func(t *testing.T, fakeDynClient *dynamicfake.FakeDynamicClient, resolver *clusterIDResolver)
+		expectedID    string
+		expectedLists int
+		expectErr     string
+	}{
+		"cached display name is not listed again": {
+			expectedID:    "c-1",
+			expectedLists: 1,
+		},
+		"cache is scoped to the token": {
+			secondToken:   "token-yyy",
+			expectedID:    "c-1",
+			expectedLists: 2,
+		},
+		"expired entry is resolved again": {
+			between: func(t *testing.T, _ *dynamicfake.FakeDynamicClient, resolver *clusterIDResolver) {
+				resolver.now = func() time.Time { return now.Add(DefaultClusterIDCacheTTL) }
+			},
+			expectedID:    "c-1",
+			expectedLists: 2,
+		},
+		"deleted cluster is resolved again": {
+			between: func(t *testing.T, fakeDynClient *dynamicfake.FakeDynamicClient, _ *clusterIDResolver) {
+				require.NoError(t, fakeDynClient.Resource(managementClustersGVR).Delete(t.Context(), "c-1", metav1.DeleteOptions{}))
+				_, err := fakeDynClient.Resource(managementClustersGVR).Create(t.Context(), newFakeCluster("c-2", clusterDN), metav1.CreateOptions{})
+				require.NoError(t, err)
+			},
+			expectedID:    "c-2",
+			expectedLists: 2,
+		},
+		"renamed cluster is resolved again": {
+			between: func(t *testing.T, fakeDynClient *dynamicfake.FakeDynamicClient, _ *clusterIDResolver) {
+				_, err := fakeDynClient.Resource(managementClustersGVR).Update(t.Context(), newFakeCluster("c-1", "staging"), metav1.UpdateOptions{})
+				require.NoError(t, err)
+				_, err = fakeDynClient.Resource(managementClustersGVR).Create(t.Context(), newFakeCluster("c-2", clusterDN), metav1.CreateOptions{})
+				require.NoError(t, err)
+			},
+			expectedID:    "c-2",
+			expectedLists: 2,
+		},
+		"renamed cluster is not found by its former name": {
+			between: func(t *testing.T, fakeDynClient *dynamicfake.FakeDynamicClient, _ *clusterIDResolver) {
+				_, err := fakeDynClient.Resource(managementClustersGVR).Update(t.Context(), newFakeCluster("c-1", "staging"), metav1.UpdateOptions{})
+				require.NoError(t, err)
+			},
+			expectErr:     "cluster 'prod' not found",
+			expectedLists: 2,
+		},
+		"access is checked before returning a cached id": {
+			between: func(t *testing.T, fakeDynClient *dynamicfake.FakeDynamicClient, _ *clusterIDResolver) {
+				fakeDynClient.PrependReactor("get", "clusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, apierrors.NewForbidden(managementClustersGVR.GroupResource(), "c-1", errors.New("denied"))
+				})
+			},
+			expectErr:     "forbidden",
+			expectedLists: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster("c-1", clusterDN))
+			c := &Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			resolver := c.clusterIDResolver()
+			resolver.now = func() time.Time { return now }
+
+			_, err := c.GetClusterID(t.Context(), fakeToken, clusterDN)
+			require.NoError(t, err)
+			if test.between != nil {
+				test.between(t, fakeDynClient, resolver)
+			}
+			secondToken := fakeToken
+			if test.secondToken != "" {
+				secondToken = test.secondToken
+			}
+			clusterID, err := c.GetClusterID(t.Context(), secondToken, clusterDN)
+
+			if test.expectErr != "" {
+				assert.ErrorContains(t, err, test.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedID, clusterID)
+			}
+			assert.Equal(t, test.expectedLists, countListActions(fakeDynClient))
+		})
+	}
+}
+
+func TestGetClusterIdConcurrentLookups(t *testing.T) {
+	release := make(chan struct{})
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), newFakeCluster("c-1", "prod"))
+	fakeDynClient.PrependReactor("list", "clusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		<-release
+		return false, nil, nil
+	})
+	c := &Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return fakeDynClient, nil
+		},
+	}
+
+	var wg sync.WaitGroup
+	ids := make([]string, 5)
+	for i := range ids {
+		wg.Go(func() {
+			id, err := c.GetClusterID(t.Context(), fakeToken, "prod")
+			assert.NoError(t, err)
+			ids[i] = id
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"c-1", "c-1", "c-1", "c-1", "c-1"}, ids)
+	// lookups that started after the first one finished are answered by the cache.
+	assert.Equal(t, 1, countListActions(fakeDynClient))
+}
+
+func TestClusterIDResolverSize(t *testing.T) {
+	resolver := newClusterIDResolver(2, DefaultClusterIDCacheTTL)
+
+	resolver.set(fakeToken, "a", "c-a")
+	resolver.set(fakeToken, "b", "c-b")
+	resolver.set(fakeToken, "c", "c-c")
+
+	assert.Len(t, resolver.entries, 2)
+	id, ok := resolver.get(fakeToken, "c")
+	assert.True(t, ok)
+	assert.Equal(t, "c-c", id)
+}
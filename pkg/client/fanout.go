@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// DefaultFanOutWorkers is the maximum number of clusters queried concurrently when no limit is configured.
+	DefaultFanOutWorkers = 10
+	// DefaultFanOutTimeout is the time budget given to each cluster when no timeout is configured.
+	DefaultFanOutTimeout = 30 * time.Second
+)
+
+// Reasons used to classify a per-cluster failure.
+const (
+	ClusterErrorReasonUnreachable = "Unreachable"
+	ClusterErrorReasonForbidden   = "Forbidden"
+	ClusterErrorReasonNotFound    = "NotFound"
+	ClusterErrorReasonTimeout     = "Timeout"
+	ClusterErrorReasonUnknown     = "Error"
+)
+
+// FanOutOptions configures how work is spread across clusters.
+type FanOutOptions struct {
+	Workers int           // Maximum number of clusters processed concurrently. Defaults to DefaultFanOutWorkers.
+	Timeout time.Duration // Time budget for each cluster. Defaults to DefaultFanOutTimeout.
+}
+
+// ClusterError describes why a single cluster could not be queried. It is meant to be returned
+// to the LLM alongside the results of the clusters that succeeded.
+type ClusterError struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// FanOut runs fn once per cluster using a bounded pool of workers. Each invocation gets its own
+// context with the configured timeout, so a slow or unreachable cluster can't hold up the others.
+// It returns the results of the clusters that succeeded and the errors of the ones that failed,
+// both keyed by the cluster passed in. Duplicate clusters are only processed once.
+func FanOut[T any](ctx context.Context, clusters []string, opts FanOutOptions, fn func(ctx context.Context, cluster string) (T, error)) (map[string]T, map[string]error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultFanOutWorkers
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultFanOutTimeout
+	}
+
+	results := make(map[string]T)
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	queue := make(chan string)
+	var wg sync.WaitGroup
+	for range min(workers, len(clusters)) {
+		wg.Go(func() {
+			for cluster := range queue {
+				clusterCtx, cancel := context.WithTimeout(ctx, timeout)
+				result, err := fn(clusterCtx, cluster)
+				if err == nil && clusterCtx.Err() != nil {
+					err = clusterCtx.Err()
+				}
+				cancel()
+
+				mu.Lock()
+				if err != nil {
+					errs[cluster] = err
+				} else {
+					results[cluster] = result
+				}
+				mu.Unlock()
+			}
+		})
+	}
+
+	seen := make(map[string]struct{}, len(clusters))
+	for _, cluster := range clusters {
+		if _, ok := seen[cluster]; ok {
+			continue
+		}
+		seen[cluster] = struct{}{}
+		queue <- cluster
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, errs
+}
+
+// ResourceLister lists resources. It is implemented by Client and by the clients used in the toolsets.
+type ResourceLister interface {
+	GetResources(ctx context.Context, params ListParams) ([]*unstructured.Unstructured, error)
+}
+
+// ClustersOrAll returns the given clusters, or the IDs of all clusters visible to the token when none are given.
+// It is used by the tools that fan out over clusters so that they agree on what "all clusters" means.
+func ClustersOrAll(ctx context.Context, lister ResourceLister, token string, clusters []string) ([]string, error) {
+	if len(clusters) > 0 {
+		return clusters, nil
+	}
+
+	clusterList, err := lister.GetResources(ctx, ListParams{
+		Cluster: "local",
+		Kind:    converter.ManagementClusterResourceKind,
+		Token:   token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get clusters: %w", err)
+	}
+
+	ids := make([]string, 0, len(clusterList))
+	for _, cluster := range clusterList {
+		ids = append(ids, cluster.GetName())
+	}
+
+	return ids, nil
+}
+
+// NewClusterError classifies err into a ClusterError so that callers can tell unreachable,
+// forbidden and missing clusters apart without parsing error strings.
+func NewClusterError(err error) ClusterError {
+	reason := ClusterErrorReasonUnknown
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err):
+		reason = ClusterErrorReasonTimeout
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		reason = ClusterErrorReasonForbidden
+	case apierrors.IsNotFound(err):
+		reason = ClusterErrorReasonNotFound
+	// Rancher answers with 503 when the cluster agent is disconnected.
+	case apierrors.IsServiceUnavailable(err), errors.As(err, &netErr):
+		reason = ClusterErrorReasonUnreachable
+	}
+
+	return ClusterError{
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
+
+// NewClusterErrors converts the error map returned by FanOut into ClusterErrors.
+// It returns nil when there are no errors so the field can be omitted from responses.
+func NewClusterErrors(errs map[string]error) map[string]ClusterError {
+	if len(errs) == 0 {
+		return nil
+	}
+	clusterErrs := make(map[string]ClusterError, len(errs))
+	for cluster, err := range errs {
+		clusterErrs[cluster] = NewClusterError(err)
+	}
+
+	return clusterErrs
+}
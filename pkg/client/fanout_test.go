@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFanOut(t *testing.T) {
+	tests := map[string]struct {
+		clusters        []string
+		fn              func(ctx context.Context, cluster string) (string, error)
+		expectedResults map[string]string
+		expectedErrors  map[string]string
+	}{
+		"all clusters succeed": {
+			clusters: []string{"local", "c-1", "c-2"},
+			fn: func(_ context.Context, cluster string) (string, error) {
+				return "ok-" + cluster, nil
+			},
+			expectedResults: map[string]string{"local": "ok-local", "c-1": "ok-c-1", "c-2": "ok-c-2"},
+			expectedErrors:  map[string]string{},
+		},
+		"failing cluster does not affect the others": {
+			clusters: []string{"local", "c-1"},
+			fn: func(_ context.Context, cluster string) (string, error) {
+				if cluster == "c-1" {
+					return "", fmt.Errorf("boom")
+				}
+				return "ok-" + cluster, nil
+			},
+			expectedResults: map[string]string{"local": "ok-local"},
+			expectedErrors:  map[string]string{"c-1": "boom"},
+		},
+		"slow cluster times out": {
+			clusters: []string{"local", "c-slow"},
+			fn: func(ctx context.Context, cluster string) (string, error) {
+				if cluster == "c-slow" {
+					<-ctx.Done()
+					return "", ctx.Err()
+				}
+				return "ok-" + cluster, nil
+			},
+			expectedResults: map[string]string{"local": "ok-local"},
+			expectedErrors:  map[string]string{"c-slow": context.DeadlineExceeded.Error()},
+		},
+		"duplicate clusters are processed once": {
+			clusters: []string{"local", "local"},
+			fn: func(_ context.Context, cluster string) (string, error) {
+				return "ok-" + cluster, nil
+			},
+			expectedResults: map[string]string{"local": "ok-local"},
+			expectedErrors:  map[string]string{},
+		},
+		"no clusters": {
+			fn: func(_ context.Context, cluster string) (string, error) {
+				return "ok-" + cluster, nil
+			},
+			expectedResults: map[string]string{},
+			expectedErrors:  map[string]string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			results, errs := FanOut(t.Context(), test.clusters, FanOutOptions{Timeout: 50 * time.Millisecond}, test.fn)
+
+			assert.Equal(t, test.expectedResults, results)
+			actualErrs := make(map[string]string, len(errs))
+			for cluster, err := range errs {
+				actualErrs[cluster] = err.Error()
+			}
+			assert.Equal(t, test.expectedErrors, actualErrs)
+		})
+	}
+}
+
+func TestFanOutBoundedWorkers(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	clusters := make([]string, 20)
+	for i := range clusters {
+		clusters[i] = fmt.Sprintf("c-%d", i)
+	}
+
+	results, errs := FanOut(t.Context(), clusters, FanOutOptions{Workers: 3}, func(_ context.Context, cluster string) (string, error) {
+		current := running.Add(1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return cluster, nil
+	})
+
+	require.Empty(t, errs)
+	assert.Len(t, results, len(clusters))
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+}
+
+func TestClustersOrAll(t *testing.T) {
+	tests := map[string]struct {
+		clusters      []string
+		objs          []runtime.Object
+		listErr       error
+		expected      []string
+		expectedError string
+	}{
+		"given clusters are returned as is": {
+			clusters: []string{"c-1"},
+			objs:     []runtime.Object{newFakeCluster("c-2", "other")},
+			expected: []string{"c-1"},
+		},
+		"all clusters when none are given": {
+			objs:     []runtime.Object{newFakeCluster("local", "local"), newFakeCluster("c-1", "prod")},
+			expected: []string{"c-1", "local"},
+		},
+		"failure to list clusters": {
+			listErr:       apierrors.NewForbidden(schema.GroupResource{Group: "management.cattle.io", Resource: "clusters"}, "", errors.New("denied")),
+			expectedError: "failed to get clusters",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme(), clusterListKinds(), test.objs...)
+			if test.listErr != nil {
+				fakeDynClient.PrependReactor("list", "clusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, test.listErr
+				})
+			}
+			c := &Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+
+			clusters, err := ClustersOrAll(t.Context(), c, fakeToken, test.clusters)
+
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.ElementsMatch(t, test.expected, clusters)
+			}
+		})
+	}
+}
+
+func TestNewClusterError(t *testing.T) {
+	gr := schema.GroupResource{Resource: "pods"}
+
+	tests := map[string]struct {
+		err            error
+		expectedReason string
+	}{
+		"forbidden": {
+			err:            apierrors.NewForbidden(gr, "", errors.New("denied")),
+			expectedReason: ClusterErrorReasonForbidden,
+		},
+		"unauthorized": {
+			err:            apierrors.NewUnauthorized("bad token"),
+			expectedReason: ClusterErrorReasonForbidden,
+		},
+		"not found": {
+			err:            fmt.Errorf("wrapped: %w", apierrors.NewNotFound(gr, "c-1")),
+			expectedReason: ClusterErrorReasonNotFound,
+		},
+		"disconnected cluster": {
+			err:            apierrors.NewServiceUnavailable("cluster agent disconnected"),
+			expectedReason: ClusterErrorReasonUnreachable,
+		},
+		"deadline exceeded": {
+			err:            fmt.Errorf("list pods: %w", context.DeadlineExceeded),
+			expectedReason: ClusterErrorReasonTimeout,
+		},
+		"unknown": {
+			err:            errors.New("boom"),
+			expectedReason: ClusterErrorReasonUnknown,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clusterErr := NewClusterError(test.err)
+
+			assert.Equal(t, test.expectedReason, clusterErr.Reason)
+			assert.Equal(t, test.err.Error(), clusterErr.Message)
+		})
+	}
+}
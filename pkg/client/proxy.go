@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/client-go/rest"
+)
+
+// MaxErrorBodySize is how much of the body of a failed proxied request is reported in errors.
+const MaxErrorBodySize = 512
+
+// steveError is the body of the errors returned by the Steve API.
+type steveError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// RestConfigCreator resolves clusters and creates their rest config, like Client does.
+type RestConfigCreator interface {
+	GetClusterID(ctx context.Context, token string, clusterNameOrID string) (string, error)
+	CreateRestConfig(token string, clusterID string) (*rest.Config, error)
+}
+
+// ProxyRequest is an HTTP request sent to a cluster through the Rancher proxy.
+type ProxyRequest struct {
+	// Cluster is the name or ID of the cluster.
+	Cluster string
+	Method  string
+	// Path is relative to the URL of the cluster in the Rancher proxy, like /v1/catalog.cattle.io.clusterrepos or
+	// /api/v1/namespaces/default/services/http:web:80/proxy/healthz.
+	Path  string
+	Query url.Values
+	// Body is sent as JSON when it is not nil.
+	Body any
+}
+
+// DoProxyRequest sends a request to a cluster through the Rancher proxy, with the token of the caller and the TLS
+// settings of the rest config of the cluster. It returns the body and status code of the response, and an error when
+// the request could not be sent or its response read. Checking the status code is left to the caller.
+func DoProxyRequest(ctx context.Context, c RestConfigCreator, token string, proxyReq ProxyRequest) ([]byte, int, error) {
+	clusterID, err := c.GetClusterID(ctx, token, proxyReq.Cluster)
+	if err != nil {
+		return nil, 0, err
+	}
+	restConfig, err := c.CreateRestConfig(token, clusterID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create the rest config: %w", err)
+	}
+	httpClient, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create the HTTP client: %w", err)
+	}
+
+	var requestBody io.Reader
+	if proxyReq.Body != nil {
+		b, err := json.Marshal(proxyReq.Body)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal the request: %w", err)
+		}
+		requestBody = bytes.NewReader(b)
+	}
+
+	requestURL := strings.TrimSuffix(restConfig.Host, "/") + proxyReq.Path
+	if len(proxyReq.Query) > 0 {
+		requestURL += "?" + proxyReq.Query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, proxyReq.Method, requestURL, requestBody)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	// clientcmd leaves out the token of plain HTTP hosts, it is always set so that the request is authenticated.
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if proxyReq.Body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to send the request to cluster '%s': %w", proxyReq.Cluster, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	return respBody, resp.StatusCode, nil
+}
+
+// SteveRequest sends a request to the Steve API of a cluster through the Rancher proxy, with the token of the caller.
+// path is relative to the Steve API, like /catalog.cattle.io.clusterrepos/rancher-charts. It returns the body of the
+// response, or an error with the message of the Steve error when its status code is not successful.
+func SteveRequest(ctx context.Context, c RestConfigCreator, token, cluster, method, path string, query url.Values, body any) ([]byte, error) {
+	respBody, statusCode, err := DoProxyRequest(ctx, c, token, ProxyRequest{
+		Cluster: cluster,
+		Method:  method,
+		Path:    "/v1" + path,
+		Query:   query,
+		Body:    body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if statusCode >= http.StatusMultipleChoices {
+		var steveErr steveError
+		if json.Unmarshal(respBody, &steveErr) == nil && steveErr.Message != "" {
+			return nil, fmt.Errorf("%d %s: %s", statusCode, steveErr.Code, steveErr.Message)
+		}
+		return nil, fmt.Errorf("%d %s", statusCode, TruncateBody(respBody))
+	}
+
+	return respBody, nil
+}
+
+// TruncateBody returns the body of a response to report in an error, truncated to MaxErrorBodySize.
+func TruncateBody(body []byte) string {
+	if len(body) > MaxErrorBodySize {
+		return string(body[:MaxErrorBodySize]) + "..."
+	}
+
+	return string(body)
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoProxyRequest(t *testing.T) {
+	var gotMethod, gotPath, gotQuery, gotBody, gotAuthorization, gotContentType string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotQuery, gotBody = r.Method, r.URL.Path, r.URL.RawQuery, string(body)
+		gotAuthorization, gotContentType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"message": "conflict"}`)
+	}))
+	t.Cleanup(svr.Close)
+	c, err := NewClient(false, svr.URL)
+	require.NoError(t, err)
+
+	body, statusCode, err := DoProxyRequest(t.Context(), c, fakeToken, ProxyRequest{
+		Cluster: "local",
+		Method:  http.MethodPost,
+		Path:    "/v1/catalog.cattle.io.clusterrepos/rancher-charts",
+		Query:   url.Values{"action": {"install"}},
+		Body:    map[string]any{"namespace": "backup"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode, "unsuccessful status codes are left to the caller")
+	assert.JSONEq(t, `{"message": "conflict"}`, string(body))
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "/k8s/clusters/local/v1/catalog.cattle.io.clusterrepos/rancher-charts", gotPath)
+	assert.Equal(t, "action=install", gotQuery)
+	assert.JSONEq(t, `{"namespace": "backup"}`, gotBody)
+	assert.Equal(t, "Bearer "+fakeToken, gotAuthorization)
+	assert.Equal(t, "application/json", gotContentType)
+}
+
+func TestTruncateBody(t *testing.T) {
+	assert.Equal(t, "short", TruncateBody([]byte("short")))
+	assert.Equal(t, strings.Repeat("x", MaxErrorBodySize)+"...", TruncateBody([]byte(strings.Repeat("x", MaxErrorBodySize+1))))
+}
@@ -0,0 +1,66 @@
+// Package clusterrepo reads the Helm indexes of the ClusterRepos of the Rancher catalog, the Helm repositories Rancher
+// installs Apps from, as downloaded by Rancher.
+package clusterrepo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+)
+
+// SourceRepoAnnotation is the chart annotation where Rancher records the ClusterRepo an App was installed from.
+const SourceRepoAnnotation = "catalog.cattle.io/ui-source-repo"
+
+// Index is the Helm index of a ClusterRepo, as returned by its index link.
+type Index struct {
+	Entries map[string][]ChartVersion `json:"entries"`
+}
+
+// ChartVersion is a version of a chart in a Helm index.
+type ChartVersion struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	AppVersion  string            `json:"appVersion,omitempty"`
+	Description string            `json:"description,omitempty"`
+	KubeVersion string            `json:"kubeVersion,omitempty"`
+	Deprecated  bool              `json:"deprecated,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GetIndex returns the Helm index of a ClusterRepo of a cluster, from the Steve API of the cluster.
+func GetIndex(ctx context.Context, c client.RestConfigCreator, token, cluster, repo string) (*Index, error) {
+	body, err := client.SteveRequest(ctx, c, token, cluster, http.MethodGet, "/catalog.cattle.io.clusterrepos/"+url.PathEscape(repo), url.Values{"link": {"index"}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the index of ClusterRepo '%s' in cluster '%s': %w", repo, cluster, err)
+	}
+
+	var index Index
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode the index of ClusterRepo '%s': %w", repo, err)
+	}
+
+	return &index, nil
+}
+
+// Version returns the version of a chart of the index, or its most recent version when version is empty.
+func (i *Index) Version(repo, name, version string) (*ChartVersion, error) {
+	versions, ok := i.Entries[name]
+	if !ok || len(versions) == 0 {
+		return nil, fmt.Errorf("chart '%s' not found in ClusterRepo '%s'", name, repo)
+	}
+	if version == "" {
+		// Helm indexes list the versions of a chart from the most recent one
+		return &versions[0], nil
+	}
+	for i := range versions {
+		if versions[i].Version == version {
+			return &versions[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("version '%s' of chart '%s' not found in ClusterRepo '%s', the most recent version is %s", version, name, repo, versions[0].Version)
+}
@@ -0,0 +1,93 @@
+package clusterrepo
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeToken = "fakeToken"
+
+func TestGetIndex(t *testing.T) {
+	tests := map[string]struct {
+		status        int
+		body          string
+		expectedIndex *Index
+		expectedError string
+	}{
+		"index": {
+			status:        http.StatusOK,
+			body:          `{"apiVersion": "v1", "entries": {"rancher-backup": [{"name": "rancher-backup", "version": "106.0.1", "annotations": {"catalog.cattle.io/hidden": "true"}}]}}`,
+			expectedIndex: &Index{Entries: map[string][]ChartVersion{"rancher-backup": {{Name: "rancher-backup", Version: "106.0.1", Annotations: map[string]string{"catalog.cattle.io/hidden": "true"}}}}},
+		},
+		"steve error": {
+			status:        http.StatusNotFound,
+			body:          `{"type": "error", "status": 404, "code": "NotFound", "message": "clusterrepos.catalog.cattle.io \"rancher-charts\" not found"}`,
+			expectedError: `failed to get the index of ClusterRepo 'rancher-charts' in cluster 'local': 404 NotFound: clusterrepos.catalog.cattle.io "rancher-charts" not found`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/k8s/clusters/local/v1/catalog.cattle.io.clusterrepos/rancher-charts", r.URL.Path)
+				assert.Equal(t, "index", r.URL.Query().Get("link"))
+				assert.Equal(t, "Bearer "+fakeToken, r.Header.Get("Authorization"))
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			t.Cleanup(svr.Close)
+			c, err := client.NewClient(false, svr.URL)
+			require.NoError(t, err)
+
+			index, err := GetIndex(t.Context(), c, fakeToken, "local", "rancher-charts")
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIndex, index)
+		})
+	}
+}
+
+func TestIndexVersion(t *testing.T) {
+	index := Index{Entries: map[string][]ChartVersion{
+		"rancher-backup": {{Name: "rancher-backup", Version: "106.0.1"}, {Name: "rancher-backup", Version: "106.0.0"}},
+	}}
+
+	tests := map[string]struct {
+		chart           string
+		version         string
+		expectedVersion string
+		expectedError   string
+	}{
+		"most recent version": {chart: "rancher-backup", expectedVersion: "106.0.1"},
+		"given version":       {chart: "rancher-backup", version: "106.0.0", expectedVersion: "106.0.0"},
+		"unknown version": {
+			chart:         "rancher-backup",
+			version:       "1.0.0",
+			expectedError: "version '1.0.0' of chart 'rancher-backup' not found in ClusterRepo 'rancher-charts', the most recent version is 106.0.1",
+		},
+		"unknown chart": {chart: "nginx", expectedError: "chart 'nginx' not found in ClusterRepo 'rancher-charts'"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			version, err := index.Version("rancher-charts", tt.chart, tt.version)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, version.Version)
+		})
+	}
+}
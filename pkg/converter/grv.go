@@ -51,10 +51,11 @@ var K8sKindsToGVRs = map[string]schema.GroupVersionResource{
 	"limitrange":            {Group: "", Version: "v1", Resource: "limitranges"},
 
 	// --- Apps Resources (Group: "apps") ---
-	"deployment":  {Group: "apps", Version: "v1", Resource: "deployments"},
-	"statefulset": {Group: "apps", Version: "v1", Resource: "statefulsets"},
-	"daemonset":   {Group: "apps", Version: "v1", Resource: "daemonsets"},
-	"replicaset":  {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"deployment":         {Group: "apps", Version: "v1", Resource: "deployments"},
+	"statefulset":        {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"daemonset":          {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"replicaset":         {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"controllerrevision": {Group: "apps", Version: "v1", Resource: "controllerrevisions"},
 
 	// --- Batch Resources (Group: "batch") ---
 	"job":     {Group: "batch", Version: "v1", Resource: "jobs"},
@@ -115,6 +116,7 @@ var K8sKindsToGVRs = map[string]schema.GroupVersionResource{
 	ManagementClusterResourceKind: {Group: ManagementGroup, Version: "v3", Resource: "clusters"},
 	"project":                     {Group: ManagementGroup, Version: "v3", Resource: "projects"},
 	"user":                        {Group: ManagementGroup, Version: "v3", Resource: "users"},
+	"group":                       {Group: ManagementGroup, Version: "v3", Resource: "groups"},
 	"roletemplate":                {Group: ManagementGroup, Version: "v3", Resource: "roletemplates"},
 	"globalrole":                  {Group: ManagementGroup, Version: "v3", Resource: "globalroles"},
 	"globalrolebinding":           {Group: ManagementGroup, Version: "v3", Resource: "globalrolebindings"},
@@ -136,6 +138,11 @@ var K8sKindsToGVRs = map[string]schema.GroupVersionResource{
 	"clustergroup":     {Group: "fleet.cattle.io", Version: "v1alpha1", Resource: "clustergroups"},
 	"fleetcluster":     {Group: "fleet.cattle.io", Version: "v1alpha1", Resource: "clusters"}, // Renamed to avoid collision with management.cattle.io/v3/clusters
 
+	// --- RANCHER CATALOG Resources (Group: "catalog.cattle.io") ---
+	"clusterrepo": {Group: "catalog.cattle.io", Version: "v1", Resource: "clusterrepos"},
+	"app":         {Group: "catalog.cattle.io", Version: "v1", Resource: "apps"},
+	"operation":   {Group: "catalog.cattle.io", Version: "v1", Resource: "operations"},
+
 	// --- RANCHER CATTLE Resources (Group: "cattle.io") ---
 	"setting": {Group: ManagementGroup, Version: "v3", Resource: "settings"},
 
@@ -0,0 +1,156 @@
+// Package diagnosis classifies the common reasons for a pod to fail from its status, events, logs and node, so that
+// tools can return findings with their evidence and remediations instead of raw data only.
+package diagnosis
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Classes of the findings.
+const (
+	ClassImagePullAuth              = "ImagePullBackOff/Unauthorized"
+	ClassImagePullNotFound          = "ImagePullBackOff/NotFound"
+	ClassImagePullRateLimited       = "ImagePullBackOff/RateLimited"
+	ClassImagePull                  = "ImagePullBackOff"
+	ClassOOMKilled                  = "OOMKilled"
+	ClassCrashLoop                  = "CrashLoopBackOff"
+	ClassLivenessProbe              = "FailedProbe/Liveness"
+	ClassReadinessProbe             = "FailedProbe/Readiness"
+	ClassStartupProbe               = "FailedProbe/Startup"
+	ClassUnschedulableTaints        = "Unschedulable/Taints"
+	ClassUnschedulableResources     = "Unschedulable/Resources"
+	ClassUnschedulableAffinity      = "Unschedulable/Affinity"
+	ClassUnschedulableVolumeZone    = "Unschedulable/VolumeZone"
+	ClassUnschedulableUnboundPVC    = "Unschedulable/UnboundPVC"
+	ClassUnschedulable              = "Unschedulable"
+	ClassMissingConfigMap           = "MissingConfigMap"
+	ClassMissingSecret              = "MissingSecret"
+	ClassCreateContainerConfigError = "CreateContainerConfigError"
+	ClassNodeNotReady               = "NodeNotReady"
+	ClassEvicted                    = "Evicted"
+)
+
+// Input is the state of a pod the rules are evaluated against.
+type Input struct {
+	Pod corev1.Pod
+	// Events are the events involving the pod.
+	Events []corev1.Event
+	// Logs are the recent logs of the containers of the pod, by container name.
+	Logs map[string]string
+	// Node is the node the pod is scheduled on, nil if it isn't scheduled or the node couldn't be read.
+	Node *corev1.Node
+}
+
+// Finding is a failure of a pod identified by a rule.
+type Finding struct {
+	Class        string   `json:"class"`
+	Container    string   `json:"container,omitempty"`
+	Summary      string   `json:"summary"`
+	Evidence     []string `json:"evidence"`
+	Remediations []string `json:"remediations"`
+}
+
+// Rule returns the findings of a class of failures.
+type Rule func(in Input) []Finding
+
+// Rules are the rules evaluated by Diagnose, in the order their findings are returned.
+var Rules = []Rule{
+	unschedulable,
+	imagePull,
+	missingReferences,
+	createContainerConfigError,
+	oomKilled,
+	crashLoop,
+	failedProbes,
+	nodeNotReady,
+	evicted,
+}
+
+// Diagnose evaluates all the rules and returns their findings.
+func Diagnose(in Input) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		findings = append(findings, rule(in)...)
+	}
+
+	return findings
+}
+
+// containerStatuses returns the statuses of the init and regular containers of the pod.
+func containerStatuses(pod corev1.Pod) []corev1.ContainerStatus {
+	return append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+}
+
+// container returns the spec of the init or regular container with the given name.
+func container(pod corev1.Pod, name string) *corev1.Container {
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			if containers[i].Name == name {
+				return &containers[i]
+			}
+		}
+	}
+
+	return nil
+}
+
+// waitingReason returns the reason and message of a waiting container.
+func waitingReason(status corev1.ContainerStatus) (string, string) {
+	if status.State.Waiting == nil {
+		return "", ""
+	}
+
+	return status.State.Waiting.Reason, status.State.Waiting.Message
+}
+
+// eventContainer returns the container an event is about, from the field path of its involved object
+// (e.g. spec.containers{app}).
+func eventContainer(event corev1.Event) string {
+	fieldPath := event.InvolvedObject.FieldPath
+	start, end := strings.Index(fieldPath, "{"), strings.LastIndex(fieldPath, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return fieldPath[start+1 : end]
+}
+
+// eventEvidence describes an event as evidence.
+func eventEvidence(event corev1.Event) string {
+	count := max(event.Count, 1)
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+	if count > 1 {
+		return fmt.Sprintf("event %s (x%d): %s", event.Reason, count, event.Message)
+	}
+
+	return fmt.Sprintf("event %s: %s", event.Reason, event.Message)
+}
+
+// containsAny reports whether s contains any of the substrings, ignoring case.
+func containsAny(s string, substrings ...string) bool {
+	s = strings.ToLower(s)
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastLines returns the last n non-empty lines of the logs.
+func lastLines(logs string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(logs, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines[max(len(lines)-n, 0):]
+}
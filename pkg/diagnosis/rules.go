@@ -0,0 +1,701 @@
+package diagnosis
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	missingObjectRegexp = regexp.MustCompile(`(?i)\b(configmap|secret) "([^"]+)" not found`)
+	missingKeyRegexp    = regexp.MustCompile(`couldn't find key (\S+) in (ConfigMap|Secret) ([^/\s]+)/([^\s]+)`)
+)
+
+// imagePull classifies the containers that can't pull their image by the error returned by the registry.
+func imagePull(in Input) []Finding {
+	var findings []Finding
+	for _, status := range containerStatuses(in.Pod) {
+		reason, message := waitingReason(status)
+		if reason != "ImagePullBackOff" && reason != "ErrImagePull" && reason != "InvalidImageName" {
+			continue
+		}
+
+		image := status.Image
+		if c := container(in.Pod, status.Name); c != nil {
+			image = c.Image
+		}
+		evidence := []string{fmt.Sprintf("container %s is waiting: %s %s", status.Name, reason, message)}
+		text := message
+		for _, event := range in.Events {
+			if event.Reason == "Failed" && strings.Contains(event.Message, "Failed to pull image") && eventContainer(event) == status.Name {
+				evidence = append(evidence, eventEvidence(event))
+				text += "\n" + event.Message
+			}
+		}
+
+		finding := Finding{Container: status.Name, Evidence: evidence}
+		registry := imageRegistry(image)
+		switch {
+		case reason == "InvalidImageName":
+			finding.Class = ClassImagePull
+			finding.Summary = fmt.Sprintf("the image reference %q of container %s is invalid", image, status.Name)
+			finding.Remediations = []string{"Fix the image reference, it must be [registry/]repository[:tag][@digest] in lowercase"}
+		case containsAny(text, "toomanyrequests", "rate limit", "429 too many requests"):
+			finding.Class = ClassImagePullRateLimited
+			finding.Summary = fmt.Sprintf("the registry %s refuses to serve image %s because the pull rate limit is reached", registry, image)
+			finding.Remediations = []string{
+				fmt.Sprintf("Authenticate the pulls from %s with an imagePullSecret to get a higher rate limit", registry),
+				"Mirror the image to a private registry or use a pull-through cache",
+				"Use imagePullPolicy IfNotPresent so nodes that already have the image don't pull it again",
+			}
+		case containsAny(text, "manifest unknown", "not found", "name unknown", "404"):
+			finding.Class = ClassImagePullNotFound
+			finding.Summary = fmt.Sprintf("the image %s doesn't exist in registry %s", image, registry)
+			finding.Remediations = []string{
+				fmt.Sprintf("Check the repository and tag of image %s for typos", image),
+				"Check that the tag was pushed, e.g. by the CI pipeline building the image, and that it is built for the architecture of the node",
+			}
+		case containsAny(text, "unauthorized", "authentication required", "access denied", "no basic auth credentials", "403 forbidden", "401"):
+			finding.Class = ClassImagePullAuth
+			finding.Summary = fmt.Sprintf("the registry %s refuses to serve image %s without valid credentials", registry, image)
+			finding.Remediations = pullSecretRemediations(in.Pod, registry)
+		default:
+			finding.Class = ClassImagePull
+			finding.Summary = fmt.Sprintf("container %s can't pull image %s", status.Name, image)
+			finding.Remediations = []string{
+				fmt.Sprintf("Check that the registry %s is reachable from the node, e.g. through the proxy and firewall", registry),
+				fmt.Sprintf("Check that the image %s exists and that the pod has credentials for it if the repository is private", image),
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// pullSecretRemediations returns the remediations for a registry refusing the credentials of the pod.
+func pullSecretRemediations(pod corev1.Pod, registry string) []string {
+	serviceAccount := pod.Spec.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	if len(pod.Spec.ImagePullSecrets) == 0 {
+		return []string{
+			fmt.Sprintf("Create a kubernetes.io/dockerconfigjson secret with credentials for %s and reference it in the imagePullSecrets of the pod or of its service account %s", registry, serviceAccount),
+			"Check the image name, registries also answer with an authorization error for repositories that don't exist",
+		}
+	}
+
+	var names []string
+	for _, secret := range pod.Spec.ImagePullSecrets {
+		names = append(names, secret.Name)
+	}
+
+	return []string{
+		fmt.Sprintf("Check that the imagePullSecrets %s exist in namespace %s and hold valid credentials for %s", strings.Join(names, ", "), pod.Namespace, registry),
+		"Check the image name, registries also answer with an authorization error for repositories that don't exist",
+	}
+}
+
+// imageRegistry returns the registry of an image reference, docker.io when it has none.
+func imageRegistry(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		return first
+	}
+
+	return "docker.io"
+}
+
+// oomKilled finds the containers killed because they exceeded their memory limit, or because the node ran out of
+// memory for containers without limit.
+func oomKilled(in Input) []Finding {
+	var findings []Finding
+	for _, status := range containerStatuses(in.Pod) {
+		terminated := status.LastTerminationState.Terminated
+		if status.State.Terminated != nil {
+			terminated = status.State.Terminated
+		}
+		if terminated == nil || terminated.Reason != "OOMKilled" {
+			continue
+		}
+
+		evidence := []string{fmt.Sprintf("container %s was OOMKilled (exit code %d) at %s, it restarted %d times",
+			status.Name, terminated.ExitCode, terminated.FinishedAt.UTC().Format("2006-01-02T15:04:05Z"), status.RestartCount)}
+		var remediations []string
+		limit := resource.Quantity{}
+		if c := container(in.Pod, status.Name); c != nil {
+			limit = c.Resources.Limits[corev1.ResourceMemory]
+		}
+		if limit.IsZero() {
+			evidence = append(evidence, fmt.Sprintf("container %s has no memory limit", status.Name))
+			remediations = []string{
+				"The container was killed because the node ran out of memory: set a memory request matching the actual usage of the container so it is scheduled on a node with enough memory",
+				"Set a memory limit so that a leak in this container doesn't affect the other pods of the node",
+			}
+		} else {
+			evidence = append(evidence, fmt.Sprintf("memory limit of container %s: %s", status.Name, limit.String()))
+			remediations = []string{
+				fmt.Sprintf("Increase the memory limit of container %s above %s if its usage is legitimate", status.Name, limit.String()),
+				"Check the application for memory leaks, or size its heap and caches to fit in the limit (e.g. -XX:MaxRAMPercentage for the JVM, GOMEMLIMIT for Go)",
+			}
+		}
+
+		findings = append(findings, Finding{
+			Class:        ClassOOMKilled,
+			Container:    status.Name,
+			Summary:      fmt.Sprintf("container %s is killed because it runs out of memory", status.Name),
+			Evidence:     evidence,
+			Remediations: remediations,
+		})
+	}
+
+	return findings
+}
+
+// crashLoop explains the containers in CrashLoopBackOff for other reasons than running out of memory, from the exit
+// code and logs of their last run.
+func crashLoop(in Input) []Finding {
+	var findings []Finding
+	for _, status := range containerStatuses(in.Pod) {
+		reason, message := waitingReason(status)
+		terminated := status.LastTerminationState.Terminated
+		if reason != "CrashLoopBackOff" || (terminated != nil && terminated.Reason == "OOMKilled") {
+			continue
+		}
+
+		evidence := []string{fmt.Sprintf("container %s is waiting: %s %s", status.Name, reason, message)}
+		remediations := []string{fmt.Sprintf("Read the logs of the previous run of container %s with getPodLogs and previous set to true", status.Name)}
+		if terminated != nil {
+			evidence = append(evidence, fmt.Sprintf("the last run of container %s terminated with exit code %d (%s) after %d restarts", status.Name, terminated.ExitCode, terminated.Reason, status.RestartCount))
+			if terminated.Message != "" {
+				evidence = append(evidence, "termination message: "+terminated.Message)
+			}
+			remediations = append(remediations, exitCodeRemediations(terminated.ExitCode)...)
+		}
+		for _, line := range lastLines(in.Logs[status.Name], 5) {
+			evidence = append(evidence, "log: "+line)
+		}
+
+		findings = append(findings, Finding{
+			Class:        ClassCrashLoop,
+			Container:    status.Name,
+			Summary:      fmt.Sprintf("container %s keeps exiting and is restarted with an increasing back-off", status.Name),
+			Evidence:     evidence,
+			Remediations: remediations,
+		})
+	}
+
+	return findings
+}
+
+// exitCodeRemediations returns the remediations for the usual meanings of an exit code.
+func exitCodeRemediations(exitCode int32) []string {
+	switch exitCode {
+	case 0:
+		return []string{"The process exits successfully but the restartPolicy restarts it: run it as a Job if it isn't meant to run forever, or make it keep running in the foreground"}
+	case 126, 127:
+		return []string{"The command of the container can't be found or executed: check the command and args of the container and the entrypoint of the image"}
+	case 137:
+		return []string{"The process was killed with SIGKILL: check whether a failing liveness probe restarts it or whether it is killed by the node"}
+	case 139:
+		return []string{"The process crashed with a segmentation fault: check the image is built for the architecture of the node"}
+	case 143:
+		return []string{"The process was stopped with SIGTERM: check whether a failing liveness probe restarts it"}
+	default:
+		return []string{"The application exits with an error: its logs usually tell which configuration, dependency or permission is missing"}
+	}
+}
+
+// failedProbes finds the containers whose liveness, readiness or startup probe fails.
+func failedProbes(in Input) []Finding {
+	type probeKey struct {
+		container string
+		class     string
+	}
+	var keys []probeKey
+	events := map[probeKey][]corev1.Event{}
+	for _, event := range in.Events {
+		if event.Reason != "Unhealthy" {
+			continue
+		}
+		key := probeKey{container: eventContainer(event)}
+		switch {
+		case containsAny(event.Message, "liveness probe"):
+			key.class = ClassLivenessProbe
+		case containsAny(event.Message, "readiness probe"):
+			key.class = ClassReadinessProbe
+		case containsAny(event.Message, "startup probe"):
+			key.class = ClassStartupProbe
+		default:
+			continue
+		}
+		if _, ok := events[key]; !ok {
+			keys = append(keys, key)
+		}
+		events[key] = append(events[key], event)
+	}
+
+	var findings []Finding
+	for _, key := range keys {
+		var evidence []string
+		for _, event := range events[key] {
+			evidence = append(evidence, eventEvidence(event))
+		}
+
+		var probe *corev1.Probe
+		if c := container(in.Pod, key.container); c != nil {
+			switch key.class {
+			case ClassLivenessProbe:
+				probe = c.LivenessProbe
+			case ClassReadinessProbe:
+				probe = c.ReadinessProbe
+			case ClassStartupProbe:
+				probe = c.StartupProbe
+			}
+		}
+		if probe != nil {
+			evidence = append(evidence, "probe: "+describeProbe(probe))
+		}
+
+		finding := Finding{Class: key.class, Container: key.container, Evidence: evidence}
+		checkProbe := fmt.Sprintf("Check that the probe matches the application of container %s: port, path, scheme and command", key.container)
+		switch key.class {
+		case ClassLivenessProbe:
+			finding.Summary = fmt.Sprintf("the liveness probe of container %s fails, so the kubelet restarts it", key.container)
+			finding.Remediations = []string{
+				checkProbe,
+				"Add a startupProbe, or increase initialDelaySeconds, if the application takes longer to start than the probe allows",
+				"Increase timeoutSeconds and failureThreshold if the application is slow to answer under load, and don't make the probe depend on external services",
+			}
+		case ClassReadinessProbe:
+			finding.Summary = fmt.Sprintf("the readiness probe of container %s fails, so the pod is removed from the endpoints of its services", key.container)
+			finding.Remediations = []string{
+				checkProbe,
+				"Check the dependencies the application waits for before reporting ready, e.g. databases or other services",
+				"Increase timeoutSeconds if the application is slow to answer under load",
+			}
+		case ClassStartupProbe:
+			finding.Summary = fmt.Sprintf("the startup probe of container %s fails, so the kubelet restarts it before it finishes starting", key.container)
+			finding.Remediations = []string{
+				checkProbe,
+				"Increase failureThreshold or periodSeconds so that failureThreshold x periodSeconds covers the worst case start time of the application",
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// describeProbe returns a short description of the probe handler and its timings.
+func describeProbe(probe *corev1.Probe) string {
+	var handler string
+	switch {
+	case probe.HTTPGet != nil:
+		scheme := strings.ToLower(string(probe.HTTPGet.Scheme))
+		if scheme == "" {
+			scheme = "http"
+		}
+		handler = fmt.Sprintf("httpGet %s://:%s%s", scheme, probe.HTTPGet.Port.String(), probe.HTTPGet.Path)
+	case probe.TCPSocket != nil:
+		handler = fmt.Sprintf("tcpSocket :%s", probe.TCPSocket.Port.String())
+	case probe.GRPC != nil:
+		handler = fmt.Sprintf("grpc :%d", probe.GRPC.Port)
+	case probe.Exec != nil:
+		handler = fmt.Sprintf("exec %s", strings.Join(probe.Exec.Command, " "))
+	}
+
+	return fmt.Sprintf("%s initialDelaySeconds=%d timeoutSeconds=%d periodSeconds=%d failureThreshold=%d",
+		handler, probe.InitialDelaySeconds, probe.TimeoutSeconds, probe.PeriodSeconds, probe.FailureThreshold)
+}
+
+// schedulingPredicate is a reason reported by the scheduler for nodes not fitting a pod.
+type schedulingPredicate struct {
+	class        string
+	keywords     []string
+	summary      string
+	remediations func(pod corev1.Pod) []string
+}
+
+// schedulingPredicates are matched in order, so the volume zone conflict is matched before node affinity.
+var schedulingPredicates = []schedulingPredicate{
+	{
+		class:    ClassUnschedulableVolumeZone,
+		keywords: []string{"volume node affinity conflict", "volume zone"},
+		summary:  "a PersistentVolume of the pod is restricted to a zone where no node can run the pod",
+		remediations: func(pod corev1.Pod) []string {
+			return []string{
+				"Add schedulable nodes in the zone of the PersistentVolume, or check that the nodes of that zone are Ready and not cordoned",
+				"Use a StorageClass with volumeBindingMode WaitForFirstConsumer, so that new volumes are provisioned in the zone the pod is scheduled in",
+			}
+		},
+	},
+	{
+		class:    ClassUnschedulableUnboundPVC,
+		keywords: []string{"unbound", "persistentvolumeclaim"},
+		summary:  "a PersistentVolumeClaim of the pod isn't bound to a volume",
+		remediations: func(pod corev1.Pod) []string {
+			return []string{
+				fmt.Sprintf("Check that the PersistentVolumeClaims %s exist and are Bound, and that their StorageClass exists and can provision volumes", strings.Join(podClaims(pod), ", ")),
+			}
+		},
+	},
+	{
+		class:    ClassUnschedulableTaints,
+		keywords: []string{"taint"},
+		summary:  "nodes have taints the pod doesn't tolerate",
+		remediations: func(pod corev1.Pod) []string {
+			return []string{
+				"Add a toleration matching the taint to the pod if it is meant to run on these nodes",
+				"Otherwise check that enough untainted nodes are Ready and schedulable, e.g. not cordoned or under pressure",
+			}
+		},
+	},
+	{
+		class:    ClassUnschedulableResources,
+		keywords: []string{"insufficient", "too many pods"},
+		summary:  "nodes don't have enough allocatable resources left for the requests of the pod",
+		remediations: func(pod corev1.Pod) []string {
+			return []string{
+				fmt.Sprintf("The pod requests %s: lower the requests if they are over-provisioned", podRequests(pod)),
+				"Free capacity by scaling down other workloads, or add nodes to the cluster, e.g. by scaling up its node pool",
+			}
+		},
+	},
+	{
+		class:    ClassUnschedulableAffinity,
+		keywords: []string{"affinity", "selector", "topology spread"},
+		summary:  "no node matches the node selector, affinity or topology spread constraints of the pod",
+		remediations: func(pod corev1.Pod) []string {
+			remediations := []string{"Check that the node affinity of the pod matches the labels of at least one schedulable node"}
+			if len(pod.Spec.NodeSelector) > 0 {
+				remediations[0] = fmt.Sprintf("Check that the nodeSelector %v and node affinity of the pod match the labels of at least one schedulable node", pod.Spec.NodeSelector)
+			}
+
+			return append(remediations, "Relax the pod anti-affinity or topology spread constraints, or add nodes in the required topology domains")
+		},
+	},
+}
+
+// unschedulable explains why the scheduler can't find a node for the pod, with one finding per failed predicate.
+// The FailedScheduling events of a pod that has since been scheduled are ignored.
+func unschedulable(in Input) []Finding {
+	var message string
+	pending := in.Pod.Spec.NodeName == ""
+	for _, condition := range in.Pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse {
+			pending = true
+			if condition.Reason == corev1.PodReasonUnschedulable {
+				message = condition.Message
+			}
+		}
+	}
+	if !pending {
+		return nil
+	}
+	var schedulingEvents []string
+	for _, event := range in.Events {
+		if event.Reason == "FailedScheduling" {
+			schedulingEvents = append(schedulingEvents, eventEvidence(event))
+			if message == "" {
+				message = event.Message
+			}
+		}
+	}
+	if message == "" {
+		return nil
+	}
+
+	// e.g. 0/3 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, 2 Insufficient cpu. preemption: ...
+	reasons := message
+	if _, after, found := strings.Cut(reasons, "are available: "); found {
+		reasons = after
+	}
+	reasons, _, _ = strings.Cut(reasons, ". preemption:")
+	reasons = strings.TrimSuffix(reasons, ".")
+
+	var findings []Finding
+	for _, reason := range strings.Split(reasons, ", ") {
+		index := slices.IndexFunc(schedulingPredicates, func(predicate schedulingPredicate) bool {
+			return containsAny(reason, predicate.keywords...)
+		})
+		if index < 0 {
+			continue
+		}
+		predicate := schedulingPredicates[index]
+		if i := slices.IndexFunc(findings, func(finding Finding) bool { return finding.Class == predicate.class }); i >= 0 {
+			findings[i].Evidence = append(findings[i].Evidence, reason)
+			continue
+		}
+		findings = append(findings, Finding{
+			Class:        predicate.class,
+			Summary:      "the pod can't be scheduled: " + predicate.summary,
+			Evidence:     []string{reason},
+			Remediations: predicate.remediations(in.Pod),
+		})
+	}
+	if len(findings) == 0 {
+		return []Finding{{
+			Class:        ClassUnschedulable,
+			Summary:      "the pod can't be scheduled",
+			Evidence:     append([]string{message}, schedulingEvents...),
+			Remediations: []string{"Check the scheduler message: it lists how many nodes failed each scheduling predicate"},
+		}}
+	}
+	for i := range findings {
+		findings[i].Evidence = append(findings[i].Evidence, schedulingEvents...)
+	}
+
+	return findings
+}
+
+// podRequests returns the resources requested by the containers of the pod.
+func podRequests(pod corev1.Pod) string {
+	requests := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, quantity := range c.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	if len(requests) == 0 {
+		return "no resources"
+	}
+
+	var names []string
+	for name := range requests {
+		names = append(names, string(name))
+	}
+	slices.Sort(names)
+	var parts []string
+	for _, name := range names {
+		quantity := requests[corev1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s %s", name, quantity.String()))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// podClaims returns the names of the PersistentVolumeClaims mounted by the pod.
+func podClaims(pod corev1.Pod) []string {
+	var claims []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+
+	return claims
+}
+
+// missingReferences finds the ConfigMaps and Secrets, or keys of them, referenced by the pod that don't exist, from
+// the waiting containers and the volume mount events. The events of containers that have since started are ignored.
+func missingReferences(in Input) []Finding {
+	type source struct {
+		container string
+		text      string
+	}
+	var sources []source
+	for _, status := range containerStatuses(in.Pod) {
+		if _, message := waitingReason(status); message != "" {
+			sources = append(sources, source{container: status.Name, text: fmt.Sprintf("container %s is waiting: %s", status.Name, message)})
+		}
+	}
+	for _, event := range in.Events {
+		if (event.Reason == "FailedMount" || event.Reason == "Failed") && !eventRecovered(in.Pod, event) {
+			sources = append(sources, source{container: eventContainer(event), text: eventEvidence(event)})
+		}
+	}
+
+	var findings []Finding
+	// index holds the position of the finding of each kind, name and key.
+	index := map[string]int{}
+	add := func(src source, class, kind, name, key string) {
+		if i, ok := index[kind+"/"+name+"/"+key]; ok {
+			if !slices.Contains(findings[i].Evidence, src.text) {
+				findings[i].Evidence = append(findings[i].Evidence, src.text)
+			}
+			return
+		}
+		index[kind+"/"+name+"/"+key] = len(findings)
+
+		finding := Finding{Class: class, Container: src.container, Evidence: []string{src.text}}
+		for _, reference := range references(in.Pod, kind, name) {
+			finding.Evidence = append(finding.Evidence, fmt.Sprintf("%s %s is referenced by %s", kind, name, reference))
+		}
+		if key == "" {
+			finding.Summary = fmt.Sprintf("%s %q referenced by the pod doesn't exist in namespace %s", kind, name, in.Pod.Namespace)
+			finding.Remediations = []string{
+				fmt.Sprintf("Create the %s %s in namespace %s, or fix the name of the reference", kind, name, in.Pod.Namespace),
+				"Mark the reference as optional if the pod can run without it",
+			}
+		} else {
+			finding.Summary = fmt.Sprintf("%s %q referenced by the pod has no key %s", kind, name, key)
+			finding.Remediations = []string{
+				fmt.Sprintf("Add the key %s to the %s %s, or fix the key of the reference", key, kind, name),
+				"Mark the reference as optional if the pod can run without it",
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	for _, src := range sources {
+		for _, match := range missingKeyRegexp.FindAllStringSubmatch(src.text, -1) {
+			kind, class := referenceKind(match[2])
+			add(src, class, kind, match[4], match[1])
+		}
+		for _, match := range missingObjectRegexp.FindAllStringSubmatch(src.text, -1) {
+			kind, class := referenceKind(match[1])
+			add(src, class, kind, match[2], "")
+		}
+	}
+
+	return findings
+}
+
+// eventRecovered reports whether the containers an event is about are running, meaning the problem reported by the
+// event is gone. An event about the pod, like FailedMount, is recovered once all the containers of the pod run.
+func eventRecovered(pod corev1.Pod, event corev1.Event) bool {
+	if name := eventContainer(event); name != "" {
+		statuses := containerStatuses(pod)
+		index := slices.IndexFunc(statuses, func(status corev1.ContainerStatus) bool { return status.Name == name })
+		return index >= 0 && statuses[index].State.Running != nil
+	}
+
+	return len(pod.Status.ContainerStatuses) > 0 && !slices.ContainsFunc(pod.Status.ContainerStatuses, func(status corev1.ContainerStatus) bool {
+		return status.State.Running == nil
+	})
+}
+
+// referenceKind returns the kind and finding class of a ConfigMap or Secret named in a message.
+func referenceKind(kind string) (string, string) {
+	if strings.EqualFold(kind, "secret") {
+		return "Secret", ClassMissingSecret
+	}
+
+	return "ConfigMap", ClassMissingConfigMap
+}
+
+// references returns where the pod references the ConfigMap or Secret.
+func references(pod corev1.Pod, kind, name string) []string {
+	var refs []string
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			for _, env := range c.Env {
+				if env.ValueFrom == nil {
+					continue
+				}
+				if (kind == "ConfigMap" && env.ValueFrom.ConfigMapKeyRef != nil && env.ValueFrom.ConfigMapKeyRef.Name == name) ||
+					(kind == "Secret" && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == name) {
+					refs = append(refs, fmt.Sprintf("env %s of container %s", env.Name, c.Name))
+				}
+			}
+			for _, envFrom := range c.EnvFrom {
+				if (kind == "ConfigMap" && envFrom.ConfigMapRef != nil && envFrom.ConfigMapRef.Name == name) ||
+					(kind == "Secret" && envFrom.SecretRef != nil && envFrom.SecretRef.Name == name) {
+					refs = append(refs, fmt.Sprintf("envFrom of container %s", c.Name))
+				}
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if (kind == "ConfigMap" && volume.ConfigMap != nil && volume.ConfigMap.Name == name) ||
+			(kind == "Secret" && volume.Secret != nil && volume.Secret.SecretName == name) {
+			refs = append(refs, "volume "+volume.Name)
+		}
+	}
+
+	return refs
+}
+
+// createContainerConfigError explains the containers that can't be created because of their configuration, other
+// than the missing references found by missingReferences.
+func createContainerConfigError(in Input) []Finding {
+	var findings []Finding
+	for _, status := range containerStatuses(in.Pod) {
+		reason, message := waitingReason(status)
+		if reason != "CreateContainerConfigError" || missingObjectRegexp.MatchString(message) || missingKeyRegexp.MatchString(message) {
+			continue
+		}
+
+		remediations := []string{fmt.Sprintf("Check the env, envFrom, volumeMounts and securityContext of container %s against the message", status.Name)}
+		if containsAny(message, "runasnonroot") {
+			remediations = []string{
+				fmt.Sprintf("The image of container %s runs as root while runAsNonRoot is set: set runAsUser to a non-root UID in the securityContext, or use an image running as a non-root user", status.Name),
+			}
+		}
+		findings = append(findings, Finding{
+			Class:        ClassCreateContainerConfigError,
+			Container:    status.Name,
+			Summary:      fmt.Sprintf("the kubelet can't create container %s from its configuration", status.Name),
+			Evidence:     []string{fmt.Sprintf("container %s is waiting: %s %s", status.Name, reason, message)},
+			Remediations: remediations,
+		})
+	}
+
+	return findings
+}
+
+// nodeNotReady reports the node of the pod when it isn't Ready or is under pressure.
+func nodeNotReady(in Input) []Finding {
+	if in.Node == nil {
+		return nil
+	}
+
+	var evidence []string
+	ready := false
+	for _, condition := range in.Node.Status.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady:
+			ready = condition.Status == corev1.ConditionTrue
+			if !ready {
+				evidence = append(evidence, fmt.Sprintf("node %s is not Ready (%s): %s %s", in.Node.Name, condition.Status, condition.Reason, condition.Message))
+			}
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if condition.Status == corev1.ConditionTrue {
+				evidence = append(evidence, fmt.Sprintf("node %s has %s: %s", in.Node.Name, condition.Type, condition.Message))
+			}
+		}
+	}
+	if len(evidence) == 0 {
+		return nil
+	}
+
+	summary := fmt.Sprintf("node %s of the pod is under resource pressure, so its pods may be evicted", in.Node.Name)
+	if !ready {
+		summary = fmt.Sprintf("node %s of the pod is not Ready", in.Node.Name)
+	}
+
+	return []Finding{{
+		Class:    ClassNodeNotReady,
+		Summary:  summary,
+		Evidence: evidence,
+		Remediations: []string{
+			fmt.Sprintf("Check the kubelet and container runtime of node %s, and its disk, memory and network", in.Node.Name),
+			"Pods managed by a controller are recreated on other nodes once the pods of an unreachable node are evicted, after 5 minutes by default",
+		},
+	}}
+}
+
+// evicted reports pods evicted by the kubelet because their node ran short of resources.
+func evicted(in Input) []Finding {
+	if in.Pod.Status.Phase != corev1.PodFailed || in.Pod.Status.Reason != "Evicted" {
+		return nil
+	}
+
+	return []Finding{{
+		Class:    ClassEvicted,
+		Summary:  "the pod was evicted by the kubelet because its node ran short of resources",
+		Evidence: []string{"pod status: Evicted " + in.Pod.Status.Message},
+		Remediations: []string{
+			"Set memory and ephemeral-storage requests close to the actual usage of the pod: the pods using the most above their requests are evicted first",
+			"Delete the evicted pod once investigated, its controller already created a replacement",
+		},
+	}}
+}
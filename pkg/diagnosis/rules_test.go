@@ -0,0 +1,313 @@
+package diagnosis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// newPod returns a running pod with a single container named app, the fixtures change its spec and status.
+func newPod(modify ...func(pod *corev1.Pod)) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "app", Image: "registry.example.com/team/web:1.0"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+		},
+	}
+	for _, m := range modify {
+		m(&pod)
+	}
+
+	return pod
+}
+
+func waiting(reason, message string) func(pod *corev1.Pod) {
+	return func(pod *corev1.Pod) {
+		pod.Status.Phase = corev1.PodPending
+		pod.Status.ContainerStatuses[0].Ready = false
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}}
+	}
+}
+
+func lastTerminated(reason string, exitCode int32) func(pod *corev1.Pod) {
+	return func(pod *corev1.Pod) {
+		pod.Status.ContainerStatuses[0].RestartCount = 4
+		pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   exitCode,
+			FinishedAt: metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)),
+		}}
+	}
+}
+
+func unschedulableCondition(message string) func(pod *corev1.Pod) {
+	return func(pod *corev1.Pod) {
+		pod.Spec.NodeName = ""
+		pod.Status = corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{{
+				Type:    corev1.PodScheduled,
+				Status:  corev1.ConditionFalse,
+				Reason:  corev1.PodReasonUnschedulable,
+				Message: message,
+			}},
+		}
+	}
+}
+
+func newEvent(reason, container, message string, count int32) corev1.Event {
+	event := corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", Namespace: "default"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+	}
+	if container != "" {
+		event.InvolvedObject.FieldPath = "spec.containers{" + container + "}"
+	}
+
+	return event
+}
+
+func TestDiagnose(t *testing.T) {
+	tests := map[string]struct {
+		input                Input
+		expectedClasses      []string
+		expectedEvidence     []string
+		expectedRemediations []string
+	}{
+		"healthy pod": {
+			input: Input{Pod: newPod()},
+		},
+		"image pull unauthorized": {
+			input: Input{
+				Pod: newPod(waiting("ImagePullBackOff", `Back-off pulling image "registry.example.com/team/web:1.0"`)),
+				Events: []corev1.Event{newEvent("Failed", "app",
+					`Failed to pull image "registry.example.com/team/web:1.0": failed to authorize: failed to fetch oauth token: unexpected status: 401 Unauthorized`, 3)},
+			},
+			expectedClasses:      []string{ClassImagePullAuth},
+			expectedEvidence:     []string{"event Failed (x3): Failed to pull image", "container app is waiting: ImagePullBackOff"},
+			expectedRemediations: []string{"reference it in the imagePullSecrets of the pod or of its service account default"},
+		},
+		"image pull not found": {
+			input: Input{
+				Pod: newPod(waiting("ErrImagePull", `rpc error: code = NotFound desc = failed to pull and unpack image "registry.example.com/team/web:1.0": registry.example.com/team/web:1.0: not found`)),
+			},
+			expectedClasses:      []string{ClassImagePullNotFound},
+			expectedRemediations: []string{"Check the repository and tag of image registry.example.com/team/web:1.0"},
+		},
+		"image pull rate limited": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) { pod.Spec.Containers[0].Image = "nginx:1.27" },
+					waiting("ErrImagePull", "toomanyrequests: You have reached your pull rate limit.")),
+			},
+			expectedClasses:      []string{ClassImagePullRateLimited},
+			expectedRemediations: []string{"Authenticate the pulls from docker.io"},
+		},
+		"oom killed": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")}
+				}, waiting("CrashLoopBackOff", "back-off 5m0s restarting failed container"), lastTerminated("OOMKilled", 137)),
+			},
+			expectedClasses:      []string{ClassOOMKilled},
+			expectedEvidence:     []string{"container app was OOMKilled (exit code 137) at 2025-01-01T10:00:00Z, it restarted 4 times", "memory limit of container app: 128Mi"},
+			expectedRemediations: []string{"Increase the memory limit of container app above 128Mi"},
+		},
+		"crash loop with command not found": {
+			input: Input{
+				Pod:  newPod(waiting("CrashLoopBackOff", "back-off 40s restarting failed container"), lastTerminated("Error", 127)),
+				Logs: map[string]string{"app": "starting\n\nexec: \"serve\": executable file not found in $PATH\n"},
+			},
+			expectedClasses:      []string{ClassCrashLoop},
+			expectedEvidence:     []string{"terminated with exit code 127 (Error) after 4 restarts", `log: exec: "serve": executable file not found in $PATH`},
+			expectedRemediations: []string{"The command of the container can't be found or executed"},
+		},
+		"failing liveness and readiness probes": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Spec.Containers[0].LivenessProbe = &corev1.Probe{
+						ProbeHandler:     corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)}},
+						TimeoutSeconds:   1,
+						PeriodSeconds:    10,
+						FailureThreshold: 3,
+					}
+				}),
+				Events: []corev1.Event{
+					newEvent("Unhealthy", "app", "Liveness probe failed: Get \"http://10.42.0.5:8080/healthz\": context deadline exceeded", 12),
+					newEvent("Killing", "app", "Container app failed liveness probe, will be restarted", 4),
+					newEvent("Unhealthy", "app", "Readiness probe failed: HTTP probe failed with statuscode: 503", 20),
+				},
+			},
+			expectedClasses:      []string{ClassLivenessProbe, ClassReadinessProbe},
+			expectedEvidence:     []string{"probe: httpGet http://:8080/healthz initialDelaySeconds=0 timeoutSeconds=1 periodSeconds=10 failureThreshold=3"},
+			expectedRemediations: []string{"Add a startupProbe", "Check the dependencies the application waits for"},
+		},
+		"unschedulable because of taints and resources": {
+			input: Input{
+				Pod: newPod(unschedulableCondition("0/3 nodes are available: 1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }, 2 Insufficient cpu. preemption: 0/3 nodes are available: 1 Preemption is not helpful for scheduling, 2 No preemption victims found for incoming pod."),
+					func(pod *corev1.Pod) {
+						pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("1Gi")}
+					}),
+			},
+			expectedClasses:      []string{ClassUnschedulableTaints, ClassUnschedulableResources},
+			expectedEvidence:     []string{"1 node(s) had untolerated taint {node-role.kubernetes.io/control-plane: }", "2 Insufficient cpu"},
+			expectedRemediations: []string{"The pod requests cpu 4, memory 1Gi"},
+		},
+		"unschedulable because of node affinity": {
+			input: Input{
+				Pod: newPod(unschedulableCondition("0/2 nodes are available: 2 node(s) didn't match Pod's node affinity/selector."),
+					func(pod *corev1.Pod) { pod.Spec.NodeSelector = map[string]string{"disktype": "ssd"} }),
+			},
+			expectedClasses:      []string{ClassUnschedulableAffinity},
+			expectedRemediations: []string{"Check that the nodeSelector map[disktype:ssd]"},
+		},
+		"unschedulable because of the zone of a volume": {
+			input: Input{
+				Pod:    newPod(unschedulableCondition("0/2 nodes are available: 2 node(s) had volume node affinity conflict.")),
+				Events: []corev1.Event{newEvent("FailedScheduling", "", "0/2 nodes are available: 2 node(s) had volume node affinity conflict.", 5)},
+			},
+			expectedClasses:      []string{ClassUnschedulableVolumeZone},
+			expectedEvidence:     []string{"2 node(s) had volume node affinity conflict", "event FailedScheduling (x5)"},
+			expectedRemediations: []string{"volumeBindingMode WaitForFirstConsumer"},
+		},
+		"unschedulable for an unknown reason": {
+			input: Input{
+				Events: []corev1.Event{newEvent("FailedScheduling", "", "0/1 nodes are available: 1 node(s) were unschedulable by a plugin.", 1)},
+				Pod:    newPod(func(pod *corev1.Pod) { pod.Spec.NodeName = ""; pod.Status = corev1.PodStatus{Phase: corev1.PodPending} }),
+			},
+			expectedClasses: []string{ClassUnschedulable},
+		},
+		"scheduled after failing to be scheduled": {
+			input: Input{
+				Pod:    newPod(),
+				Events: []corev1.Event{newEvent("FailedScheduling", "", "0/3 nodes are available: 3 Insufficient cpu.", 4)},
+			},
+		},
+		"missing configmap": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "LOG_LEVEL", ValueFrom: &corev1.EnvVarSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}, Key: "logLevel"},
+					}}}
+				}, waiting("CreateContainerConfigError", `configmap "web-config" not found`)),
+				Events: []corev1.Event{newEvent("Failed", "app", `Error: configmap "web-config" not found`, 7)},
+			},
+			expectedClasses:      []string{ClassMissingConfigMap},
+			expectedEvidence:     []string{"ConfigMap web-config is referenced by env LOG_LEVEL of container app", `event Failed (x7): Error: configmap "web-config" not found`},
+			expectedRemediations: []string{"Create the ConfigMap web-config in namespace default"},
+		},
+		"missing secret of a volume": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Spec.Volumes = []corev1.Volume{{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "web-tls"}}}}
+					pod.Status.Phase = corev1.PodPending
+					pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+				}),
+				Events: []corev1.Event{newEvent("FailedMount", "", `MountVolume.SetUp failed for volume "certs" : secret "web-tls" not found`, 9)},
+			},
+			expectedClasses:  []string{ClassMissingSecret},
+			expectedEvidence: []string{"Secret web-tls is referenced by volume certs"},
+		},
+		"running after the missing configmap and secret were created": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Spec.Volumes = []corev1.Volume{{Name: "certs", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "web-tls"}}}}
+				}),
+				Events: []corev1.Event{
+					newEvent("FailedMount", "", `MountVolume.SetUp failed for volume "certs" : secret "web-tls" not found`, 9),
+					newEvent("Failed", "app", `Error: configmap "web-config" not found`, 7),
+				},
+			},
+		},
+		"missing key of a configmap": {
+			input: Input{
+				Pod: newPod(waiting("CreateContainerConfigError", "couldn't find key DB_URL in ConfigMap default/web-config")),
+			},
+			expectedClasses:      []string{ClassMissingConfigMap},
+			expectedRemediations: []string{"Add the key DB_URL to the ConfigMap web-config"},
+		},
+		"create container config error": {
+			input: Input{
+				Pod: newPod(waiting("CreateContainerConfigError", `container has runAsNonRoot and image will run as root (pod: "web-1_default(1234)", container: app)`)),
+			},
+			expectedClasses:      []string{ClassCreateContainerConfigError},
+			expectedRemediations: []string{"set runAsUser to a non-root UID"},
+		},
+		"node not ready": {
+			input: Input{
+				Pod: newPod(),
+				Node: &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+					Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+						{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Reason: "NodeStatusUnknown", Message: "Kubelet stopped posting node status."},
+						{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Message: "kubelet has disk pressure"},
+					}},
+				},
+			},
+			expectedClasses:  []string{ClassNodeNotReady},
+			expectedEvidence: []string{"node node-1 is not Ready (Unknown): NodeStatusUnknown Kubelet stopped posting node status.", "node node-1 has DiskPressure"},
+		},
+		"evicted": {
+			input: Input{
+				Pod: newPod(func(pod *corev1.Pod) {
+					pod.Status = corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: memory."}
+				}),
+			},
+			expectedClasses:  []string{ClassEvicted},
+			expectedEvidence: []string{"pod status: Evicted The node was low on resource: memory."},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			findings := Diagnose(tt.input)
+
+			var classes, evidence, remediations []string
+			for _, finding := range findings {
+				classes = append(classes, finding.Class)
+				evidence = append(evidence, finding.Evidence...)
+				remediations = append(remediations, finding.Remediations...)
+				assert.NotEmpty(t, finding.Summary)
+				assert.NotEmpty(t, finding.Evidence)
+				assert.NotEmpty(t, finding.Remediations)
+			}
+			assert.Equal(t, tt.expectedClasses, classes)
+			for _, expected := range tt.expectedEvidence {
+				assert.True(t, containsSubstring(evidence, expected), "missing evidence %q in %q", expected, evidence)
+			}
+			for _, expected := range tt.expectedRemediations {
+				assert.True(t, containsSubstring(remediations, expected), "missing remediation %q in %q", expected, remediations)
+			}
+		})
+	}
+}
+
+func TestImageRegistry(t *testing.T) {
+	assert.Equal(t, "docker.io", imageRegistry("nginx"))
+	assert.Equal(t, "docker.io", imageRegistry("rancher/rancher:v2.12.0"))
+	assert.Equal(t, "registry.example.com", imageRegistry("registry.example.com/team/web:1.0"))
+	assert.Equal(t, "localhost:5000", imageRegistry("localhost:5000/web"))
+}
+
+func containsSubstring(values []string, substring string) bool {
+	for _, value := range values {
+		if strings.Contains(value, substring) {
+			return true
+		}
+	}
+
+	return false
+}
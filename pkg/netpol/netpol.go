@@ -0,0 +1,288 @@
+// Package netpol evaluates whether traffic between pods is allowed by NetworkPolicies, following the standard
+// Kubernetes semantics, from the policy objects only.
+package netpol
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Peer is one end of the traffic: a pod, or a block of addresses outside of the cluster.
+type Peer struct {
+	Namespace       string
+	NamespaceLabels map[string]string
+	Labels          map[string]string
+	// Ports are the ports of the containers of the pod, used to resolve named ports.
+	Ports []corev1.ContainerPort
+	// IP is the address of the pod, matched by ipBlocks.
+	IP string
+	// IPBlock is the CIDR, or the single IP, of a peer outside of the cluster. Such a peer is only matched by the
+	// ipBlocks containing the whole block, and isn't selected by any policy.
+	IPBlock string
+}
+
+// Result is the evaluation of the policies for one side of the traffic.
+type Result struct {
+	Allowed bool `json:"allowed"`
+	// Policies are the policies selecting the pod for the direction of the traffic. The pod is isolated when there
+	// is any, and the traffic must then be allowed by one of their rules.
+	Policies []string `json:"policies"`
+	// MatchedRules are the rules allowing the traffic.
+	MatchedRules []string `json:"matchedRules"`
+}
+
+// Ingress evaluates whether the traffic from src to port and protocol of dst is allowed by the ingress rules of the
+// policies of the namespace of dst.
+func Ingress(policies []networkingv1.NetworkPolicy, src, dst Peer, port int32, protocol corev1.Protocol) (Result, error) {
+	return evaluate(policies, networkingv1.PolicyTypeIngress, dst, src, dst.Ports, port, protocol)
+}
+
+// Egress evaluates whether the traffic from src to port and protocol of dst is allowed by the egress rules of the
+// policies of the namespace of src.
+func Egress(policies []networkingv1.NetworkPolicy, src, dst Peer, port int32, protocol corev1.Protocol) (Result, error) {
+	return evaluate(policies, networkingv1.PolicyTypeEgress, src, dst, dst.Ports, port, protocol)
+}
+
+// evaluate evaluates the rules of a direction of the policies selecting a pod against the peer at the other end of
+// the traffic. Named ports are resolved against the ports of the destination.
+func evaluate(policies []networkingv1.NetworkPolicy, policyType networkingv1.PolicyType, pod, peer Peer, dstPorts []corev1.ContainerPort, port int32, protocol corev1.Protocol) (Result, error) {
+	result := Result{Policies: []string{}, MatchedRules: []string{}}
+	if pod.IPBlock != "" {
+		result.Allowed = true
+		return result, nil
+	}
+
+	direction := "ingress"
+	if policyType == networkingv1.PolicyTypeEgress {
+		direction = "egress"
+	}
+	for _, policy := range policies {
+		if policy.Namespace != pod.Namespace || !hasPolicyType(policy, policyType) {
+			continue
+		}
+		selected, err := selectorMatches(&policy.Spec.PodSelector, pod.Labels)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid podSelector of NetworkPolicy %s/%s: %w", policy.Namespace, policy.Name, err)
+		}
+		if !selected {
+			continue
+		}
+		result.Policies = append(result.Policies, policy.Namespace+"/"+policy.Name)
+
+		for i, rule := range policyRules(policy, policyType) {
+			peerMatches, err := peersMatch(policy.Namespace, rule.peers, peer)
+			if err != nil {
+				return Result{}, fmt.Errorf("invalid %s rule %d of NetworkPolicy %s/%s: %w", direction, i, policy.Namespace, policy.Name, err)
+			}
+			if peerMatches && portsMatch(rule.ports, dstPorts, port, protocol) {
+				result.MatchedRules = append(result.MatchedRules, fmt.Sprintf("%s/%s %s[%d]", policy.Namespace, policy.Name, direction, i))
+			}
+		}
+	}
+	result.Allowed = len(result.Policies) == 0 || len(result.MatchedRules) > 0
+
+	return result, nil
+}
+
+// rule is an ingress or egress rule of a policy.
+type rule struct {
+	peers []networkingv1.NetworkPolicyPeer
+	ports []networkingv1.NetworkPolicyPort
+}
+
+// policyRules returns the rules of a direction of a policy.
+func policyRules(policy networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) []rule {
+	var rules []rule
+	if policyType == networkingv1.PolicyTypeIngress {
+		for _, ingress := range policy.Spec.Ingress {
+			rules = append(rules, rule{peers: ingress.From, ports: ingress.Ports})
+		}
+	} else {
+		for _, egress := range policy.Spec.Egress {
+			rules = append(rules, rule{peers: egress.To, ports: egress.Ports})
+		}
+	}
+
+	return rules
+}
+
+// hasPolicyType reports whether a policy applies to a direction of the traffic. Policies without policyTypes
+// apply to ingress, and to egress when they have egress rules.
+func hasPolicyType(policy networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return policyType == networkingv1.PolicyTypeIngress || len(policy.Spec.Egress) > 0
+	}
+	for _, t := range policy.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// peersMatch reports whether the other end of the traffic matches any of the peers of a rule. A rule without peers
+// matches all the traffic.
+func peersMatch(policyNamespace string, peers []networkingv1.NetworkPolicyPeer, other Peer) (bool, error) {
+	if len(peers) == 0 {
+		return true, nil
+	}
+	for _, peer := range peers {
+		match, err := peerMatches(policyNamespace, peer, other)
+		if err != nil || match {
+			return match, err
+		}
+	}
+
+	return false, nil
+}
+
+// peerMatches reports whether a peer of a rule matches the other end of the traffic. A podSelector alone selects
+// pods of the namespace of the policy, a namespaceSelector alone all the pods of the selected namespaces, and both
+// the selected pods of the selected namespaces. An ipBlock matches the addresses it contains, except its exceptions.
+func peerMatches(policyNamespace string, peer networkingv1.NetworkPolicyPeer, other Peer) (bool, error) {
+	if peer.IPBlock != nil {
+		return ipBlockMatches(peer.IPBlock, other)
+	}
+	if other.IPBlock != "" || (peer.PodSelector == nil && peer.NamespaceSelector == nil) {
+		return false, nil
+	}
+	if peer.NamespaceSelector == nil {
+		if other.Namespace != policyNamespace {
+			return false, nil
+		}
+	} else if match, err := selectorMatches(peer.NamespaceSelector, namespaceLabels(other)); err != nil || !match {
+		return false, err
+	}
+	if peer.PodSelector == nil {
+		return true, nil
+	}
+
+	return selectorMatches(peer.PodSelector, other.Labels)
+}
+
+// ipBlockMatches reports whether an ipBlock contains the address of a pod, or the whole block of a peer outside of
+// the cluster, without overlapping one of its exceptions.
+func ipBlockMatches(ipBlock *networkingv1.IPBlock, other Peer) (bool, error) {
+	address := other.IPBlock
+	if address == "" {
+		address = other.IP
+	}
+	if address == "" {
+		return false, nil
+	}
+	prefix, err := ParsePrefix(address)
+	if err != nil {
+		return false, err
+	}
+	cidr, err := netip.ParsePrefix(ipBlock.CIDR)
+	if err != nil {
+		return false, fmt.Errorf("invalid ipBlock cidr %q: %w", ipBlock.CIDR, err)
+	}
+	if cidr.Addr().Is4() != prefix.Addr().Is4() || cidr.Bits() > prefix.Bits() || !cidr.Contains(prefix.Addr()) {
+		return false, nil
+	}
+	for _, except := range ipBlock.Except {
+		exceptPrefix, err := netip.ParsePrefix(except)
+		if err != nil {
+			return false, fmt.Errorf("invalid ipBlock except %q: %w", except, err)
+		}
+		if exceptPrefix.Overlaps(prefix) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// ParsePrefix parses a CIDR, or a single IP as the block of that address only.
+func ParsePrefix(address string) (netip.Prefix, error) {
+	if strings.Contains(address, "/") {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR %q: %w", address, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP %q: %w", address, err)
+	}
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// namespaceLabels returns the labels of the namespace of a pod, with the kubernetes.io/metadata.name label set by
+// the API server.
+func namespaceLabels(pod Peer) map[string]string {
+	nsLabels := make(map[string]string, len(pod.NamespaceLabels)+1)
+	for key, value := range pod.NamespaceLabels {
+		nsLabels[key] = value
+	}
+	nsLabels[corev1.LabelMetadataName] = pod.Namespace
+
+	return nsLabels
+}
+
+// portsMatch reports whether a port and protocol matches any of the ports of a rule. A rule without ports matches
+// all the ports, and named ports are resolved against the ports of the containers of the destination.
+func portsMatch(rulePorts []networkingv1.NetworkPolicyPort, containerPorts []corev1.ContainerPort, port int32, protocol corev1.Protocol) bool {
+	if len(rulePorts) == 0 {
+		return true
+	}
+	for _, rulePort := range rulePorts {
+		ruleProtocol := corev1.ProtocolTCP
+		if rulePort.Protocol != nil {
+			ruleProtocol = *rulePort.Protocol
+		}
+		if ruleProtocol != protocol {
+			continue
+		}
+		if rulePort.Port == nil {
+			return true
+		}
+		if rulePort.Port.Type == intstr.String {
+			for _, containerPort := range containerPorts {
+				if containerPort.Name == rulePort.Port.StrVal && containerPort.ContainerPort == port && containerProtocol(containerPort) == protocol {
+					return true
+				}
+			}
+			continue
+		}
+		endPort := rulePort.Port.IntVal
+		if rulePort.EndPort != nil {
+			endPort = *rulePort.EndPort
+		}
+		if port >= rulePort.Port.IntVal && port <= endPort {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containerProtocol returns the protocol of a container port, TCP when unset.
+func containerProtocol(port corev1.ContainerPort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return port.Protocol
+}
+
+// selectorMatches reports whether a label selector matches labels. An empty selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, err
+	}
+
+	return s.Matches(labels.Set(set)), nil
+}
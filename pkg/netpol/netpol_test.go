@@ -0,0 +1,235 @@
+package netpol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func newPolicy(name string, podSelector map[string]string, ingress ...networkingv1.NetworkPolicyIngressRule) networkingv1.NetworkPolicy {
+	return networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: podSelector},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+func tcpPort(port intstr.IntOrString) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{Protocol: ptr.To(corev1.ProtocolTCP), Port: &port}
+}
+
+func TestIngress(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	dst := Peer{
+		Namespace: "shop",
+		Labels:    web,
+		Ports:     []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+	}
+	frontend := Peer{Namespace: "shop", Labels: map[string]string{"app": "frontend"}}
+	monitoring := Peer{Namespace: "monitoring", NamespaceLabels: map[string]string{"team": "ops"}, Labels: map[string]string{"app": "prometheus"}}
+	fromFrontend := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}}
+
+	tests := map[string]struct {
+		policies       []networkingv1.NetworkPolicy
+		src            Peer
+		port           int32
+		protocol       corev1.Protocol
+		expectedResult Result
+	}{
+		"pods not selected by any policy are not isolated": {
+			policies:       []networkingv1.NetworkPolicy{newPolicy("db", map[string]string{"app": "db"})},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+		},
+		"a policy without rules denies all the ingress traffic": {
+			policies:       []networkingv1.NetworkPolicy{newPolicy("deny-all", nil)},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Policies: []string{"shop/deny-all"}, MatchedRules: []string{}},
+		},
+		"podSelector peers only select pods of the namespace of the policy": {
+			policies:       []networkingv1.NetworkPolicy{newPolicy("web", web, networkingv1.NetworkPolicyIngressRule{From: []networkingv1.NetworkPolicyPeer{fromFrontend}})},
+			src:            Peer{Namespace: "other", Labels: map[string]string{"app": "frontend"}},
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Policies: []string{"shop/web"}, MatchedRules: []string{}},
+		},
+		"allowed by a rule on a named port": {
+			policies: []networkingv1.NetworkPolicy{newPolicy("web", web, networkingv1.NetworkPolicyIngressRule{
+				From:  []networkingv1.NetworkPolicyPeer{fromFrontend},
+				Ports: []networkingv1.NetworkPolicyPort{tcpPort(intstr.FromString("http"))},
+			})},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Allowed: true, Policies: []string{"shop/web"}, MatchedRules: []string{"shop/web ingress[0]"}},
+		},
+		"denied on another protocol": {
+			policies: []networkingv1.NetworkPolicy{newPolicy("web", web, networkingv1.NetworkPolicyIngressRule{
+				Ports: []networkingv1.NetworkPolicyPort{tcpPort(intstr.FromInt32(8080))},
+			})},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolUDP,
+			expectedResult: Result{Policies: []string{"shop/web"}, MatchedRules: []string{}},
+		},
+		"allowed by a port range": {
+			policies: []networkingv1.NetworkPolicy{newPolicy("web", web, networkingv1.NetworkPolicyIngressRule{
+				Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromInt32(8000)), EndPort: ptr.To(int32(9000))}},
+			})},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Allowed: true, Policies: []string{"shop/web"}, MatchedRules: []string{"shop/web ingress[0]"}},
+		},
+		"namespaceSelector and podSelector in the same peer must both match": {
+			policies: []networkingv1.NetworkPolicy{newPolicy("web", web,
+				networkingv1.NetworkPolicyIngressRule{From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ops"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "grafana"}},
+				}}},
+				networkingv1.NetworkPolicyIngressRule{From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "monitoring"}},
+				}}},
+			)},
+			src:            monitoring,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Allowed: true, Policies: []string{"shop/web"}, MatchedRules: []string{"shop/web ingress[1]"}},
+		},
+		"egress only policies don't isolate ingress": {
+			policies: []networkingv1.NetworkPolicy{{
+				ObjectMeta: metav1.ObjectMeta{Name: "egress", Namespace: "shop"},
+				Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+			}},
+			src:            frontend,
+			port:           8080,
+			protocol:       corev1.ProtocolTCP,
+			expectedResult: Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Ingress(tt.policies, tt.src, dst, tt.port, tt.protocol)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestEgress(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	src := Peer{Namespace: "shop", Labels: web}
+	db := Peer{
+		Namespace: "data",
+		Labels:    map[string]string{"app": "db"},
+		Ports:     []corev1.ContainerPort{{Name: "postgres", ContainerPort: 5432}},
+		IP:        "10.42.1.7",
+	}
+	egressPolicy := func(rules ...networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicy {
+		return networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-egress", Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: web},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+				Egress:      rules,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		policies       []networkingv1.NetworkPolicy
+		src            Peer
+		expectedResult Result
+	}{
+		"ingress only policies don't isolate egress": {
+			policies:       []networkingv1.NetworkPolicy{newPolicy("web", web)},
+			src:            src,
+			expectedResult: Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+		},
+		"allowed to a named port of the destination": {
+			policies: []networkingv1.NetworkPolicy{egressPolicy(networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: "data"}}}},
+				Ports: []networkingv1.NetworkPolicyPort{tcpPort(intstr.FromString("postgres"))},
+			})},
+			src:            src,
+			expectedResult: Result{Allowed: true, Policies: []string{"shop/web-egress"}, MatchedRules: []string{"shop/web-egress egress[0]"}},
+		},
+		"allowed to the address of the destination": {
+			policies: []networkingv1.NetworkPolicy{egressPolicy(networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.42.0.0/16"}}},
+			})},
+			src:            src,
+			expectedResult: Result{Allowed: true, Policies: []string{"shop/web-egress"}, MatchedRules: []string{"shop/web-egress egress[0]"}},
+		},
+		"denied to an excepted address": {
+			policies: []networkingv1.NetworkPolicy{egressPolicy(networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.42.0.0/16", Except: []string{"10.42.1.0/24"}}}},
+			})},
+			src:            src,
+			expectedResult: Result{Policies: []string{"shop/web-egress"}, MatchedRules: []string{}},
+		},
+		"sources outside of the cluster are not selected by policies": {
+			policies:       []networkingv1.NetworkPolicy{egressPolicy()},
+			src:            Peer{IPBlock: "192.168.0.0/24"},
+			expectedResult: Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Egress(tt.policies, tt.src, db, 5432, corev1.ProtocolTCP)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
+
+func TestIngressFromIPBlock(t *testing.T) {
+	dst := Peer{Namespace: "shop", Labels: map[string]string{"app": "web"}}
+	policies := []networkingv1.NetworkPolicy{newPolicy("web", nil, networkingv1.NetworkPolicyIngressRule{
+		From: []networkingv1.NetworkPolicyPeer{
+			{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.0.0/16", Except: []string{"192.168.100.0/24"}}},
+			{NamespaceSelector: &metav1.LabelSelector{}},
+		},
+	})}
+
+	tests := map[string]struct {
+		source  string
+		allowed bool
+	}{
+		"single IP in the block":                 {source: "192.168.1.10", allowed: true},
+		"CIDR in the block":                      {source: "192.168.1.0/24", allowed: true},
+		"CIDR larger than the block":             {source: "192.0.0.0/8"},
+		"CIDR overlapping an exception":          {source: "192.168.96.0/20"},
+		"IP outside of the block":                {source: "10.0.0.1"},
+		"IPv6 addresses don't match IPv4 blocks": {source: "fd00::1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Ingress(policies, Peer{IPBlock: tt.source}, dst, 443, corev1.ProtocolTCP)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.allowed, result.Allowed)
+		})
+	}
+
+	_, err := Ingress(policies, Peer{IPBlock: "not-an-ip"}, dst, 443, corev1.ProtocolTCP)
+	assert.ErrorContains(t, err, `invalid IP "not-an-ip"`)
+}
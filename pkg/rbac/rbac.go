@@ -0,0 +1,258 @@
+// Package rbac evaluates Kubernetes RBAC rules and Rancher role templates to find out which subjects are allowed to
+// perform an action on a resource.
+package rbac
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Attributes describes an action on a resource, as in a ResourceAttributes of a SubjectAccessReview.
+type Attributes struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	Name        string
+}
+
+// String returns the action in a human readable form, e.g. "patch deployments.apps/web".
+func (a Attributes) String() string {
+	resource := a.Resource
+	if a.Group != "" {
+		resource += "." + a.Group
+	}
+	if a.Name != "" {
+		resource += "/" + a.Name
+	}
+	if a.Subresource != "" {
+		resource += " (" + a.Subresource + ")"
+	}
+
+	return a.Verb + " " + resource
+}
+
+// RoleTemplate holds the fields of a Rancher RoleTemplate needed to resolve its rules. The management v3 types are not
+// imported since they pull in a lot of indirect dependencies.
+type RoleTemplate struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Context           string              `json:"context,omitempty"`
+	Rules             []rbacv1.PolicyRule `json:"rules,omitempty"`
+	External          bool                `json:"external"`
+	ExternalRules     []rbacv1.PolicyRule `json:"externalRules,omitempty"`
+	RoleTemplateNames []string            `json:"roleTemplateNames,omitempty"`
+}
+
+// RoleTemplateBinding holds the fields of a Rancher ClusterRoleTemplateBinding or ProjectRoleTemplateBinding.
+type RoleTemplateBinding struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	UserName           string `json:"userName,omitempty"`
+	UserPrincipalName  string `json:"userPrincipalName,omitempty"`
+	GroupName          string `json:"groupName,omitempty"`
+	GroupPrincipalName string `json:"groupPrincipalName,omitempty"`
+	ServiceAccount     string `json:"serviceAccount,omitempty"`
+	ClusterName        string `json:"clusterName,omitempty"`
+	ProjectName        string `json:"projectName,omitempty"`
+	RoleTemplateName   string `json:"roleTemplateName"`
+}
+
+// Objects are the RBAC objects of a cluster and the Rancher role templates and bindings applying to it.
+type Objects struct {
+	ClusterRoles        []rbacv1.ClusterRole
+	ClusterRoleBindings []rbacv1.ClusterRoleBinding
+	// Roles and RoleBindings only grant permissions in their own namespace.
+	Roles        []rbacv1.Role
+	RoleBindings []rbacv1.RoleBinding
+
+	RoleTemplates               []RoleTemplate
+	ClusterRoleTemplateBindings []RoleTemplateBinding
+	// ProjectRoleTemplateBindings must only contain the bindings of the project of the namespace being evaluated.
+	ProjectRoleTemplateBindings []RoleTemplateBinding
+}
+
+// Grant is a subject allowed to perform an action, with the binding and role granting it.
+type Grant struct {
+	Subject rbacv1.Subject `json:"subject"`
+	Binding string         `json:"binding"`
+	Role    string         `json:"role"`
+	// Scope is where the grant applies: the whole cluster, a namespace or a Rancher project.
+	Scope string `json:"scope"`
+}
+
+// RuleAllows returns whether a policy rule allows an action, following the matching of the Kubernetes RBAC authorizer.
+func RuleAllows(rule rbacv1.PolicyRule, attrs Attributes) bool {
+	if !matches(rule.Verbs, attrs.Verb) || !AppliesTo(rule, attrs) {
+		return false
+	}
+
+	return len(rule.ResourceNames) == 0 || slices.Contains(rule.ResourceNames, attrs.Name)
+}
+
+// AppliesTo returns whether a policy rule covers the resource of an action, regardless of the verb and name.
+func AppliesTo(rule rbacv1.PolicyRule, attrs Attributes) bool {
+	if !matches(rule.APIGroups, attrs.Group) {
+		return false
+	}
+
+	resource := attrs.Resource
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+	for _, ruleResource := range rule.Resources {
+		switch {
+		case ruleResource == rbacv1.ResourceAll || ruleResource == resource:
+			return true
+		case attrs.Subresource != "" && ruleResource == "*/"+attrs.Subresource:
+			return true
+		}
+	}
+
+	return false
+}
+
+// matches returns whether the values of a rule contain the value or the "*" wildcard.
+func matches(values []string, value string) bool {
+	return slices.Contains(values, value) || slices.Contains(values, "*")
+}
+
+// RulesAllow returns whether any of the rules allows an action.
+func RulesAllow(rules []rbacv1.PolicyRule, attrs Attributes) bool {
+	return slices.ContainsFunc(rules, func(rule rbacv1.PolicyRule) bool {
+		return RuleAllows(rule, attrs)
+	})
+}
+
+// WhoCan returns the subjects allowed to perform an action in a namespace, or cluster-wide when the namespace is empty.
+// RoleBindings of other namespaces are ignored. Rancher role templates are resolved with the templates they inherit,
+// external role templates use their external rules or the ClusterRole of the same name.
+func WhoCan(objs Objects, attrs Attributes, namespace string) []Grant {
+	clusterRoles := map[string]rbacv1.ClusterRole{}
+	for _, clusterRole := range objs.ClusterRoles {
+		clusterRoles[clusterRole.Name] = clusterRole
+	}
+	roles := map[string]rbacv1.Role{}
+	for _, role := range objs.Roles {
+		roles[role.Namespace+"/"+role.Name] = role
+	}
+	roleTemplates := map[string]RoleTemplate{}
+	for _, roleTemplate := range objs.RoleTemplates {
+		roleTemplates[roleTemplate.Name] = roleTemplate
+	}
+
+	grants := []Grant{}
+	for _, binding := range objs.ClusterRoleBindings {
+		clusterRole, ok := clusterRoles[binding.RoleRef.Name]
+		if !ok || !RulesAllow(clusterRole.Rules, attrs) {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			grants = append(grants, newGrant(subject, "ClusterRoleBinding "+binding.Name, "ClusterRole "+clusterRole.Name, "cluster"))
+		}
+	}
+
+	for _, binding := range objs.RoleBindings {
+		if namespace == "" || binding.Namespace != namespace {
+			continue
+		}
+		var rules []rbacv1.PolicyRule
+		role := binding.RoleRef.Kind + " " + binding.RoleRef.Name
+		switch binding.RoleRef.Kind {
+		case "ClusterRole":
+			rules = clusterRoles[binding.RoleRef.Name].Rules
+		case "Role":
+			rules = roles[binding.Namespace+"/"+binding.RoleRef.Name].Rules
+			role = fmt.Sprintf("Role %s/%s", binding.Namespace, binding.RoleRef.Name)
+		}
+		if !RulesAllow(rules, attrs) {
+			continue
+		}
+		for _, subject := range binding.Subjects {
+			grants = append(grants, newGrant(subject, fmt.Sprintf("RoleBinding %s/%s", binding.Namespace, binding.Name), role, "namespace "+namespace))
+		}
+	}
+
+	for _, binding := range objs.ClusterRoleTemplateBindings {
+		if !RulesAllow(roleTemplateRules(binding.RoleTemplateName, roleTemplates, clusterRoles, map[string]bool{}), attrs) {
+			continue
+		}
+		for _, subject := range bindingSubjects(binding) {
+			grants = append(grants, newGrant(subject, fmt.Sprintf("ClusterRoleTemplateBinding %s/%s", binding.Namespace, binding.Name), "RoleTemplate "+binding.RoleTemplateName, "cluster"))
+		}
+	}
+
+	if namespace != "" {
+		for _, binding := range objs.ProjectRoleTemplateBindings {
+			if !RulesAllow(roleTemplateRules(binding.RoleTemplateName, roleTemplates, clusterRoles, map[string]bool{}), attrs) {
+				continue
+			}
+			for _, subject := range bindingSubjects(binding) {
+				grants = append(grants, newGrant(subject, fmt.Sprintf("ProjectRoleTemplateBinding %s/%s", binding.Namespace, binding.Name), "RoleTemplate "+binding.RoleTemplateName, "project "+binding.ProjectName))
+			}
+		}
+	}
+
+	slices.SortStableFunc(grants, func(a, b Grant) int {
+		return cmp.Or(
+			strings.Compare(a.Subject.Kind, b.Subject.Kind),
+			strings.Compare(a.Subject.Namespace, b.Subject.Namespace),
+			strings.Compare(a.Subject.Name, b.Subject.Name),
+		)
+	})
+
+	return grants
+}
+
+// newGrant returns a grant to a subject, without the API group of the subject which doesn't help identifying it.
+func newGrant(subject rbacv1.Subject, binding, role, scope string) Grant {
+	subject.APIGroup = ""
+
+	return Grant{Subject: subject, Binding: binding, Role: role, Scope: scope}
+}
+
+// roleTemplateRules returns the rules of a role template and of the role templates it inherits. Inheritance cycles
+// are broken by ignoring the templates already seen.
+func roleTemplateRules(name string, roleTemplates map[string]RoleTemplate, clusterRoles map[string]rbacv1.ClusterRole, seen map[string]bool) []rbacv1.PolicyRule {
+	roleTemplate, ok := roleTemplates[name]
+	if !ok || seen[name] {
+		return nil
+	}
+	seen[name] = true
+
+	rules := slices.Clone(roleTemplate.Rules)
+	if roleTemplate.External {
+		if len(roleTemplate.ExternalRules) > 0 {
+			rules = append(rules, roleTemplate.ExternalRules...)
+		} else {
+			rules = append(rules, clusterRoles[name].Rules...)
+		}
+	}
+	for _, inherited := range roleTemplate.RoleTemplateNames {
+		rules = append(rules, roleTemplateRules(inherited, roleTemplates, clusterRoles, seen)...)
+	}
+
+	return rules
+}
+
+// bindingSubjects returns the subjects of a Rancher role template binding. Users and groups are identified by their
+// name when set, by their principal otherwise.
+func bindingSubjects(binding RoleTemplateBinding) []rbacv1.Subject {
+	var subjects []rbacv1.Subject
+	if user := cmp.Or(binding.UserName, binding.UserPrincipalName); user != "" {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.UserKind, Name: user})
+	}
+	if group := cmp.Or(binding.GroupName, binding.GroupPrincipalName); group != "" {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.GroupKind, Name: group})
+	}
+	if namespace, name, ok := strings.Cut(binding.ServiceAccount, ":"); ok {
+		subjects = append(subjects, rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: name})
+	}
+
+	return subjects
+}
@@ -0,0 +1,145 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRuleAllows(t *testing.T) {
+	patchDeployments := Attributes{Verb: "patch", Group: "apps", Resource: "deployments", Name: "web"}
+
+	tests := map[string]struct {
+		rule    rbacv1.PolicyRule
+		attrs   Attributes
+		allowed bool
+	}{
+		"exact match": {
+			rule:    rbacv1.PolicyRule{Verbs: []string{"get", "patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+			attrs:   patchDeployments,
+			allowed: true,
+		},
+		"wildcards": {
+			rule:    rbacv1.PolicyRule{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}},
+			attrs:   patchDeployments,
+			allowed: true,
+		},
+		"other verb": {
+			rule:  rbacv1.PolicyRule{Verbs: []string{"get"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+			attrs: patchDeployments,
+		},
+		"other group": {
+			rule:  rbacv1.PolicyRule{Verbs: []string{"patch"}, APIGroups: []string{""}, Resources: []string{"deployments"}},
+			attrs: patchDeployments,
+		},
+		"resource name listed": {
+			rule:    rbacv1.PolicyRule{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			attrs:   patchDeployments,
+			allowed: true,
+		},
+		"resource names don't match requests without a name": {
+			rule:  rbacv1.PolicyRule{Verbs: []string{"list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+			attrs: Attributes{Verb: "list", Group: "apps", Resource: "deployments"},
+		},
+		"the resource doesn't grant its subresources": {
+			rule:  rbacv1.PolicyRule{Verbs: []string{"create"}, Resources: []string{"pods"}, APIGroups: []string{""}},
+			attrs: Attributes{Verb: "create", Resource: "pods", Subresource: "eviction"},
+		},
+		"subresource": {
+			rule:    rbacv1.PolicyRule{Verbs: []string{"create"}, Resources: []string{"pods/eviction"}, APIGroups: []string{""}},
+			attrs:   Attributes{Verb: "create", Resource: "pods", Subresource: "eviction"},
+			allowed: true,
+		},
+		"subresource of any resource": {
+			rule:    rbacv1.PolicyRule{Verbs: []string{"update"}, Resources: []string{"*/scale"}, APIGroups: []string{"apps"}},
+			attrs:   Attributes{Verb: "update", Group: "apps", Resource: "deployments", Subresource: "scale"},
+			allowed: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.allowed, RuleAllows(tt.rule, tt.attrs))
+		})
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	editDeployments := []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}}
+	objs := Objects{
+		ClusterRoles: []rbacv1.ClusterRole{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"}, Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "view"}, Rules: []rbacv1.PolicyRule{{Verbs: []string{"get", "list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "deployer"}, Rules: editDeployments},
+		},
+		ClusterRoleBindings: []rbacv1.ClusterRoleBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "system:masters"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "viewers"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "view"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "viewer"}},
+			},
+		},
+		Roles: []rbacv1.Role{{ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "shop"}, Rules: editDeployments}},
+		RoleBindings: []rbacv1.RoleBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "ci", Namespace: "shop"},
+				RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "deploy"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: "ci", Name: "pipeline"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "deployers", Namespace: "other"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "deployer"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "other-user"}},
+			},
+		},
+		RoleTemplates: []RoleTemplate{
+			{ObjectMeta: metav1.ObjectMeta{Name: "project-member"}, Context: "project", RoleTemplateNames: []string{"edit", "project-member"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "edit"}, Context: "project", External: true},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-owner"}, Context: "cluster", Rules: []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "read-only"}, Context: "project", External: true, ExternalRules: []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{"*"}, Resources: []string{"*"}}}},
+		},
+		ClusterRoleTemplateBindings: []RoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "crtb-owner", Namespace: "c-abc12"}, UserName: "u-owner", ClusterName: "c-abc12", RoleTemplateName: "cluster-owner"},
+		},
+		ProjectRoleTemplateBindings: []RoleTemplateBinding{
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-dev", Namespace: "p-xyz89"}, GroupPrincipalName: "github_team://42", ProjectName: "c-abc12:p-xyz89", RoleTemplateName: "project-member"},
+			{ObjectMeta: metav1.ObjectMeta{Name: "prtb-ro", Namespace: "p-xyz89"}, UserName: "u-reader", ProjectName: "c-abc12:p-xyz89", RoleTemplateName: "read-only"},
+		},
+	}
+	// The edit role template is external, its rules come from the ClusterRole of the same name.
+	objs.ClusterRoles = append(objs.ClusterRoles, rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edit"}, Rules: editDeployments})
+
+	t.Run("namespace", func(t *testing.T) {
+		grants := WhoCan(objs, Attributes{Verb: "patch", Group: "apps", Resource: "deployments", Name: "web"}, "shop")
+
+		assert.Equal(t, []Grant{
+			{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "github_team://42"}, Binding: "ProjectRoleTemplateBinding p-xyz89/prtb-dev", Role: "RoleTemplate project-member", Scope: "project c-abc12:p-xyz89"},
+			{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:masters"}, Binding: "ClusterRoleBinding admins", Role: "ClusterRole cluster-admin", Scope: "cluster"},
+			{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "ci", Name: "pipeline"}, Binding: "RoleBinding shop/ci", Role: "Role shop/deploy", Scope: "namespace shop"},
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-owner"}, Binding: "ClusterRoleTemplateBinding c-abc12/crtb-owner", Role: "RoleTemplate cluster-owner", Scope: "cluster"},
+		}, grants)
+	})
+
+	t.Run("cluster-wide", func(t *testing.T) {
+		grants := WhoCan(objs, Attributes{Verb: "list", Group: "apps", Resource: "deployments"}, "")
+
+		assert.Equal(t, []Grant{
+			{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:masters"}, Binding: "ClusterRoleBinding admins", Role: "ClusterRole cluster-admin", Scope: "cluster"},
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-owner"}, Binding: "ClusterRoleTemplateBinding c-abc12/crtb-owner", Role: "RoleTemplate cluster-owner", Scope: "cluster"},
+			{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "viewer"}, Binding: "ClusterRoleBinding viewers", Role: "ClusterRole view", Scope: "cluster"},
+		}, grants)
+	})
+
+	t.Run("external rules", func(t *testing.T) {
+		grants := WhoCan(objs, Attributes{Verb: "get", Resource: "secrets", Name: "db"}, "shop")
+
+		assert.Contains(t, grants, Grant{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-reader"}, Binding: "ProjectRoleTemplateBinding p-xyz89/prtb-ro", Role: "RoleTemplate read-only", Scope: "project c-abc12:p-xyz89"})
+	})
+}
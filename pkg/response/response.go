@@ -39,42 +39,10 @@ func CreateMcpResponse(objs []*unstructured.Unstructured, cluster string, notes
 		unstructured.RemoveNestedField(obj.Object, "metadata", "managedFields")
 		unstructured.RemoveNestedField(obj.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
 
-		gvk := obj.GetObjectKind().GroupVersionKind()
-		lowerKind := strings.ToLower(gvk.Kind)
-		if lowerKind == "" {
+		if obj.GetKind() == "" {
 			continue
 		}
-
-		// use prefixes to differentiate duplicate kinds from different API groups
-		// (e.g. cluster.x-k8s.io.cluster vs provisioning.cattle.io.cluster)
-		lookupKind := lowerKind
-		steveType := lowerKind
-		switch gvk.Group {
-		case converter.CAPIGroup:
-			lookupKind = converter.CAPIKindPrefix + lookupKind
-		case converter.ProvisioningGroup:
-			lookupKind = converter.ProvisioningKindPrefix + lookupKind
-		case converter.ManagementGroup:
-			lookupKind = converter.ManagementKindPrefix + lookupKind
-		case converter.MachineConfigGroup:
-			// machine configs are dynamically generated from node drivers
-			// using their name, so we can't maintain a mapping for all of them.
-			// fortunately, its highly unlikely there will be a conflict across groups
-			// so we just use the group directly.
-			steveType = gvk.Group + "." + lowerKind
-		}
-
-		if gvr, ok := converter.K8sKindsToGVRs[lookupKind]; ok && gvr.Group != "" {
-			steveType = gvr.Group + "." + lowerKind
-		}
-
-		uiContext = append(uiContext, UIContext{
-			Namespace: obj.GetNamespace(),
-			Kind:      obj.GetKind(),
-			Cluster:   cluster,
-			Name:      obj.GetName(),
-			Type:      steveType,
-		})
+		uiContext = append(uiContext, NewUIContext(obj, cluster))
 	}
 
 	var data any = "no resources found"
@@ -92,6 +60,43 @@ func CreateMcpResponse(objs []*unstructured.Unstructured, cluster string, notes
 	return CreateMcpResponseAny(data, uiContext...)
 }
 
+// NewUIContext builds the UIContext entry the UI uses to link to obj in the given cluster.
+func NewUIContext(obj *unstructured.Unstructured, cluster string) UIContext {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	lowerKind := strings.ToLower(gvk.Kind)
+
+	// use prefixes to differentiate duplicate kinds from different API groups
+	// (e.g. cluster.x-k8s.io.cluster vs provisioning.cattle.io.cluster)
+	lookupKind := lowerKind
+	steveType := lowerKind
+	switch gvk.Group {
+	case converter.CAPIGroup:
+		lookupKind = converter.CAPIKindPrefix + lookupKind
+	case converter.ProvisioningGroup:
+		lookupKind = converter.ProvisioningKindPrefix + lookupKind
+	case converter.ManagementGroup:
+		lookupKind = converter.ManagementKindPrefix + lookupKind
+	case converter.MachineConfigGroup:
+		// machine configs are dynamically generated from node drivers
+		// using their name, so we can't maintain a mapping for all of them.
+		// fortunately, its highly unlikely there will be a conflict across groups
+		// so we just use the group directly.
+		steveType = gvk.Group + "." + lowerKind
+	}
+
+	if gvr, ok := converter.K8sKindsToGVRs[lookupKind]; ok && gvr.Group != "" {
+		steveType = gvr.Group + "." + lowerKind
+	}
+
+	return UIContext{
+		Namespace: obj.GetNamespace(),
+		Kind:      obj.GetKind(),
+		Cluster:   cluster,
+		Name:      obj.GetName(),
+		Type:      steveType,
+	}
+}
+
 // CreateMcpResponseAny constructs an MCPResponse with any data that can be marshaled into JSON.
 // This gives a full control over the shape of the returned data and the optional UI context.
 func CreateMcpResponseAny(data any, uiContext ...UIContext) (string, error) {
@@ -180,6 +185,21 @@ func NewUpdateResourceInput(obj *unstructured.Unstructured, patch []byte, cluste
 	return plan_resource
 }
 
+// NewDeleteResourceInput constructs a PlanResource for a DELETE operation.
+// It extracts the resource metadata from the given unstructured object, the
+// operation has no payload.
+func NewDeleteResourceInput(obj *unstructured.Unstructured, cluster string) PlanResource {
+	return PlanResource{
+		Type: OperationDelete,
+		Resource: Resource{
+			Name:      obj.GetName(),
+			Kind:      obj.GetKind(),
+			Cluster:   cluster,
+			Namespace: obj.GetNamespace(),
+		},
+	}
+}
+
 // CreatePlanResponse serializes a slice of PlanResource into a JSON string.
 // It returns the JSON representation and any marshalling error encountered.
 func CreatePlanResponse(resources []PlanResource) (string, error) {
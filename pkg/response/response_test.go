@@ -270,6 +270,20 @@ func TestNewUpdateResourceInput(t *testing.T) {
 	}
 }
 
+func TestNewDeleteResourceInput(t *testing.T) {
+	got := NewDeleteResourceInput(newUnstructured("prtb-abcde", "p-xyz89", "ProjectRoleTemplateBinding"), "local")
+
+	assert.Equal(t, PlanResource{
+		Type: OperationDelete,
+		Resource: Resource{
+			Name:      "prtb-abcde",
+			Kind:      "ProjectRoleTemplateBinding",
+			Cluster:   "local",
+			Namespace: "p-xyz89",
+		},
+	}, got)
+}
+
 func TestCreatePlanResponse(t *testing.T) {
 	tests := map[string]struct {
 		resources   []PlanResource
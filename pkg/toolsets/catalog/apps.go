@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// app is the summary of an App, the Helm release of a chart installed through Rancher.
+type app struct {
+	Name         string         `json:"name"`
+	Namespace    string         `json:"namespace"`
+	Chart        string         `json:"chart"`
+	Version      string         `json:"version"`
+	AppVersion   string         `json:"appVersion,omitempty"`
+	Repo         string         `json:"repo,omitempty"`
+	Revision     int64          `json:"revision"`
+	Status       string         `json:"status"`
+	LastDeployed string         `json:"lastDeployed,omitempty"`
+	Values       map[string]any `json:"values,omitempty"`
+}
+
+// appValuesDiff is the response of getAppValuesDiff.
+type appValuesDiff struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Chart     string `json:"chart"`
+	Version   string `json:"version"`
+	valuesDiff
+}
+
+// listAppsParams specifies the parameters needed to list the Apps of a cluster.
+type listAppsParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"the namespace of the Apps. Defaults to all namespaces"`
+}
+
+// appParams identifies an App.
+type appParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace" jsonschema:"the namespace of the App"`
+	Name      string `json:"name" jsonschema:"the name of the App, which is the name of its Helm release"`
+}
+
+// listApps returns the Apps of a cluster with their chart, version, status and values.
+func (t *Tools) listApps(ctx context.Context, toolReq *mcp.CallToolRequest, params listAppsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listApps called")
+
+	objs, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:   params.Cluster,
+		Kind:      "app",
+		Namespace: params.Namespace,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to list apps", zap.String("tool", "listApps"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	apps := make([]app, 0, len(objs))
+	uiContext := make([]response.UIContext, 0, len(objs))
+	for _, obj := range objs {
+		apps = append(apps, newApp(obj))
+		uiContext = append(uiContext, response.NewUIContext(obj, params.Cluster))
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(apps, uiContext...)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "listApps"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// getAppValuesDiff returns the difference between the values of an App and the default values of its chart.
+func (t *Tools) getAppValuesDiff(ctx context.Context, toolReq *mcp.CallToolRequest, params appParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("getAppValuesDiff called")
+
+	obj, err := t.getApp(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to get app", zap.String("tool", "getAppValuesDiff"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	// the App keeps the chart it was installed from, so its default values don't need to be downloaded
+	defaults, _, _ := unstructured.NestedMap(obj.Object, "spec", "chart", "values")
+	values, _, _ := unstructured.NestedMap(obj.Object, "spec", "values")
+	summary := newApp(obj)
+
+	mcpResponse, err := response.CreateMcpResponseAny(appValuesDiff{
+		Name:       summary.Name,
+		Namespace:  summary.Namespace,
+		Chart:      summary.Chart,
+		Version:    summary.Version,
+		valuesDiff: diffValues(defaults, values),
+	}, response.NewUIContext(obj, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "getAppValuesDiff"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// getApp returns the App identified by params.
+func (t *Tools) getApp(ctx context.Context, params appParams) (*unstructured.Unstructured, error) {
+	return t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      "app",
+		Namespace: params.Namespace,
+		Name:      params.Name,
+		Token:     middleware.Token(ctx),
+	})
+}
+
+// newApp returns the summary of an App.
+func newApp(obj *unstructured.Unstructured) app {
+	a := app{Name: obj.GetName(), Namespace: obj.GetNamespace()}
+	a.Chart, _, _ = unstructured.NestedString(obj.Object, "spec", "chart", "metadata", "name")
+	a.Version, _, _ = unstructured.NestedString(obj.Object, "spec", "chart", "metadata", "version")
+	a.AppVersion, _, _ = unstructured.NestedString(obj.Object, "spec", "chart", "metadata", "appVersion")
+	a.Repo, _, _ = unstructured.NestedString(obj.Object, "spec", "chart", "metadata", "annotations", clusterrepo.SourceRepoAnnotation)
+	a.Revision, _, _ = unstructured.NestedInt64(obj.Object, "spec", "version")
+	a.Status, _, _ = unstructured.NestedString(obj.Object, "spec", "info", "status")
+	a.LastDeployed, _, _ = unstructured.NestedString(obj.Object, "spec", "info", "lastDeployed")
+	a.Values, _, _ = unstructured.NestedMap(obj.Object, "spec", "values")
+
+	return a
+}
@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListApps(t *testing.T) {
+	tools, _ := newStandInTools(t, nil, newFakeApp("105.0.0", 3, map[string]any{"persistence": map[string]any{"enabled": true}}))
+
+	result, _, err := tools.listApps(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, listAppsParams{Cluster: "local"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"llm": [{
+			"name": "rancher-backup",
+			"namespace": "cattle-resources-system",
+			"chart": "rancher-backup",
+			"version": "105.0.0",
+			"appVersion": "v105.0.0",
+			"repo": "rancher-charts",
+			"revision": 3,
+			"status": "deployed",
+			"lastDeployed": "2024-05-02T10:00:00Z",
+			"values": {"persistence": {"enabled": true}}
+		}],
+		"uiContext": [{"namespace": "cattle-resources-system", "kind": "App", "cluster": "local", "name": "rancher-backup", "type": "catalog.cattle.io.app"}]
+	}`, result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestGetAppValuesDiff(t *testing.T) {
+	tests := map[string]struct {
+		params        appParams
+		expected      string
+		expectedError string
+	}{
+		"values diff": {
+			params: appParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup"},
+			expected: `{
+				"llm": {
+					"name": "rancher-backup",
+					"namespace": "cattle-resources-system",
+					"chart": "rancher-backup",
+					"version": "105.0.0",
+					"changed": [{"path": "persistence.enabled", "default": false, "value": true}],
+					"added": [{"path": "persistence.storageClass", "value": "longhorn"}],
+					"unchanged": ["s3.enabled"]
+				},
+				"uiContext": [{"namespace": "cattle-resources-system", "kind": "App", "cluster": "local", "name": "rancher-backup", "type": "catalog.cattle.io.app"}]
+			}`,
+		},
+		"app not found": {
+			params:        appParams{Cluster: "local", Namespace: "default", Name: "rancher-backup"},
+			expectedError: `apps.catalog.cattle.io "rancher-backup" not found`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, _ := newStandInTools(t, nil, newFakeApp("105.0.0", 3, map[string]any{
+				"persistence": map[string]any{"enabled": true, "storageClass": "longhorn"},
+				"s3":          map[string]any{"enabled": false},
+			}))
+
+			result, _, err := tools.getAppValuesDiff(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
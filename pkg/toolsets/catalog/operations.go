@@ -0,0 +1,436 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// operationTimeout is how long the Helm operations wait for the resources of a release, like the Rancher UI does.
+	operationTimeout = "600s"
+	// sourceRepoTypeAnnotation is recorded along clusterrepo.SourceRepoAnnotation so that the Rancher UI finds the ClusterRepo.
+	sourceRepoTypeAnnotation = "catalog.cattle.io/ui-source-repo-type"
+	// autoInstallAnnotation names the chart Rancher installs along a chart, before it, like rancher-monitoring-crd=match
+	// where match stands for the version of the chart itself.
+	autoInstallAnnotation = "catalog.cattle.io/auto-install"
+	appKind               = "App"
+)
+
+// chartAction is the body of the install and upgrade actions of a ClusterRepo.
+type chartAction struct {
+	Timeout   string         `json:"timeout"`
+	Wait      bool           `json:"wait"`
+	Namespace string         `json:"namespace"`
+	Charts    []chartRelease `json:"charts"`
+}
+
+// chartRelease is a chart to install or upgrade, and the name and values of its release.
+type chartRelease struct {
+	ChartName   string            `json:"chartName"`
+	Version     string            `json:"version"`
+	ReleaseName string            `json:"releaseName"`
+	Annotations map[string]string `json:"annotations"`
+	Values      map[string]any    `json:"values,omitempty"`
+}
+
+// rollbackAction is the body of the rollback action of an App.
+type rollbackAction struct {
+	Revision int64  `json:"revision"`
+	Timeout  string `json:"timeout"`
+	Wait     bool   `json:"wait"`
+}
+
+// operation identifies the Operation, the pod running Helm, created by a catalog action.
+type operation struct {
+	Name      string `json:"operationName"`
+	Namespace string `json:"operationNamespace"`
+}
+
+// operationResult is the response of the tools running a catalog action.
+type operationResult struct {
+	Action    string    `json:"action"`
+	App       string    `json:"app"`
+	Namespace string    `json:"namespace"`
+	Chart     string    `json:"chart,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Revision  int64     `json:"revision,omitempty"`
+	Operation operation `json:"operation"`
+	Note      string    `json:"note"`
+}
+
+// actionRequest is a catalog action to send to the Steve API, and what it changes.
+type actionRequest struct {
+	path   string
+	action string
+	body   any
+	result operationResult
+	notes  []string
+}
+
+// installAppParams specifies the parameters needed to install a chart.
+type installAppParams struct {
+	Cluster   string         `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Repo      string         `json:"repo" jsonschema:"the name of the ClusterRepo of the chart"`
+	Chart     string         `json:"chart" jsonschema:"the name of the chart"`
+	Version   string         `json:"version,omitempty" jsonschema:"the version of the chart. Defaults to its most recent version"`
+	Namespace string         `json:"namespace" jsonschema:"the namespace of the App"`
+	Name      string         `json:"name,omitempty" jsonschema:"the name of the App, which is the name of its Helm release. Defaults to the name of the chart"`
+	Values    map[string]any `json:"values,omitempty" jsonschema:"the values overriding the default values of the chart"`
+}
+
+// upgradeAppParams specifies the parameters needed to upgrade an App.
+type upgradeAppParams struct {
+	Cluster     string         `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace   string         `json:"namespace" jsonschema:"the namespace of the App"`
+	Name        string         `json:"name" jsonschema:"the name of the App"`
+	Version     string         `json:"version,omitempty" jsonschema:"the version of the chart to upgrade to. Defaults to its most recent version"`
+	Repo        string         `json:"repo,omitempty" jsonschema:"the name of the ClusterRepo of the chart. Defaults to the ClusterRepo the App was installed from"`
+	Values      map[string]any `json:"values,omitempty" jsonschema:"the values to change, merged into the current values of the App"`
+	ResetValues bool           `json:"resetValues,omitempty" jsonschema:"whether the current values of the App are dropped, so that only the given values override the default values of the chart"`
+}
+
+// rollbackAppParams specifies the parameters needed to roll an App back.
+type rollbackAppParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace" jsonschema:"the namespace of the App"`
+	Name      string `json:"name" jsonschema:"the name of the App"`
+	Revision  int64  `json:"revision,omitempty" jsonschema:"the Helm revision to roll back to. Defaults to the revision before the current one"`
+}
+
+// installApp installs a chart of a ClusterRepo with the install action of Rancher.
+func (t *Tools) installApp(ctx context.Context, toolReq *mcp.CallToolRequest, params installAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("installApp called")
+
+	req, err := t.installRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create install request", zap.String("tool", "installApp"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.runAction(ctx, params.Cluster, req, "installApp")
+}
+
+// installAppPlan returns the install action installApp would send.
+func (t *Tools) installAppPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params installAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("installAppPlan called")
+
+	req, err := t.installRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create install request", zap.String("tool", "installAppPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planAction(params.Cluster, response.OperationCreate, req, "installAppPlan")
+}
+
+// upgradeApp upgrades an App to a version of its chart, or changes its values, with the upgrade action of Rancher.
+func (t *Tools) upgradeApp(ctx context.Context, toolReq *mcp.CallToolRequest, params upgradeAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("upgradeApp called")
+
+	req, err := t.upgradeRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create upgrade request", zap.String("tool", "upgradeApp"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.runAction(ctx, params.Cluster, req, "upgradeApp")
+}
+
+// upgradeAppPlan returns the upgrade action upgradeApp would send.
+func (t *Tools) upgradeAppPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params upgradeAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("upgradeAppPlan called")
+
+	req, err := t.upgradeRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create upgrade request", zap.String("tool", "upgradeAppPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planAction(params.Cluster, response.OperationUpdate, req, "upgradeAppPlan")
+}
+
+// rollbackApp rolls an App back to a previous Helm revision with the rollback action of Rancher.
+func (t *Tools) rollbackApp(ctx context.Context, toolReq *mcp.CallToolRequest, params rollbackAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("rollbackApp called")
+
+	req, err := t.rollbackRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create rollback request", zap.String("tool", "rollbackApp"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.runAction(ctx, params.Cluster, req, "rollbackApp")
+}
+
+// rollbackAppPlan returns the rollback action rollbackApp would send.
+func (t *Tools) rollbackAppPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params rollbackAppParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("rollbackAppPlan called")
+
+	req, err := t.rollbackRequest(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create rollback request", zap.String("tool", "rollbackAppPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planAction(params.Cluster, response.OperationUpdate, req, "rollbackAppPlan")
+}
+
+// installRequest returns the install action of a chart, after checking that the chart version exists and that no App
+// has the same name.
+func (t *Tools) installRequest(ctx context.Context, params installAppParams) (*actionRequest, error) {
+	if params.Repo == "" || params.Chart == "" || params.Namespace == "" {
+		return nil, fmt.Errorf("the repo, chart and namespace are required")
+	}
+	name := params.Name
+	if name == "" {
+		name = params.Chart
+	}
+
+	_, err := t.getApp(ctx, appParams{Cluster: params.Cluster, Namespace: params.Namespace, Name: name})
+	if err == nil {
+		return nil, fmt.Errorf("App '%s' already exists in namespace '%s', use upgradeApp to change it", name, params.Namespace)
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	index, err := clusterrepo.GetIndex(ctx, t.client, middleware.Token(ctx), params.Cluster, params.Repo)
+	if err != nil {
+		return nil, err
+	}
+	version, err := index.Version(params.Repo, params.Chart, params.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	charts, err := chartReleases(index, params.Repo, params.Chart, version, name, params.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &actionRequest{
+		path:   "/catalog.cattle.io.clusterrepos/" + url.PathEscape(params.Repo),
+		action: "install",
+		body: chartAction{
+			Timeout:   operationTimeout,
+			Wait:      true,
+			Namespace: params.Namespace,
+			Charts:    charts,
+		},
+		result: operationResult{Action: "install", App: name, Namespace: params.Namespace, Chart: params.Chart, Version: version.Version},
+	}
+	if len(charts) > 1 {
+		req.notes = append(req.notes, autoInstallNote(charts[0], params.Chart))
+	}
+	if version.Deprecated {
+		req.notes = append(req.notes, fmt.Sprintf("Chart '%s' is deprecated.", params.Chart))
+	}
+
+	return req, nil
+}
+
+// upgradeRequest returns the upgrade action of an App. The values of the App are kept unless ResetValues is set,
+// since Helm replaces all the values of a release on upgrade.
+func (t *Tools) upgradeRequest(ctx context.Context, params upgradeAppParams) (*actionRequest, error) {
+	obj, err := t.getApp(ctx, appParams{Cluster: params.Cluster, Namespace: params.Namespace, Name: params.Name})
+	if err != nil {
+		return nil, err
+	}
+	current := newApp(obj)
+
+	repo := params.Repo
+	if repo == "" {
+		repo = current.Repo
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("the ClusterRepo App '%s' was installed from is unknown, the repo is required", params.Name)
+	}
+	index, err := clusterrepo.GetIndex(ctx, t.client, middleware.Token(ctx), params.Cluster, repo)
+	if err != nil {
+		return nil, err
+	}
+	version, err := index.Version(repo, current.Chart, params.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	values := params.Values
+	if !params.ResetValues {
+		values = mergeValues(current.Values, params.Values)
+	}
+
+	charts, err := chartReleases(index, repo, current.Chart, version, params.Name, values)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &actionRequest{
+		path:   "/catalog.cattle.io.clusterrepos/" + url.PathEscape(repo),
+		action: "upgrade",
+		body: chartAction{
+			Timeout:   operationTimeout,
+			Wait:      true,
+			Namespace: params.Namespace,
+			Charts:    charts,
+		},
+		result: operationResult{Action: "upgrade", App: params.Name, Namespace: params.Namespace, Chart: current.Chart, Version: version.Version},
+	}
+	if version.Version == current.Version {
+		req.notes = append(req.notes, fmt.Sprintf("App '%s' stays at version %s of chart '%s'.", params.Name, current.Version, current.Chart))
+	} else {
+		req.notes = append(req.notes, fmt.Sprintf("App '%s' is upgraded from version %s to version %s of chart '%s'.", params.Name, current.Version, version.Version, current.Chart))
+	}
+	if len(charts) > 1 {
+		req.notes = append(req.notes, autoInstallNote(charts[0], current.Chart))
+	}
+	if version.Deprecated {
+		req.notes = append(req.notes, fmt.Sprintf("Chart '%s' is deprecated.", current.Chart))
+	}
+
+	return req, nil
+}
+
+// rollbackRequest returns the rollback action of an App.
+func (t *Tools) rollbackRequest(ctx context.Context, params rollbackAppParams) (*actionRequest, error) {
+	obj, err := t.getApp(ctx, appParams{Cluster: params.Cluster, Namespace: params.Namespace, Name: params.Name})
+	if err != nil {
+		return nil, err
+	}
+	current := newApp(obj)
+
+	revision := params.Revision
+	if revision == 0 {
+		revision = current.Revision - 1
+	}
+	if revision < 1 || revision >= current.Revision {
+		return nil, fmt.Errorf("App '%s' is at revision %d, it can only be rolled back to a revision between 1 and %d", params.Name, current.Revision, current.Revision-1)
+	}
+
+	return &actionRequest{
+		path:   "/catalog.cattle.io.apps/" + url.PathEscape(params.Namespace) + "/" + url.PathEscape(params.Name),
+		action: "rollback",
+		body: rollbackAction{
+			Revision: revision,
+			Timeout:  operationTimeout,
+			Wait:     true,
+		},
+		result: operationResult{Action: "rollback", App: params.Name, Namespace: params.Namespace, Chart: current.Chart, Revision: revision},
+		notes:  []string{fmt.Sprintf("App '%s' is rolled back from revision %d to revision %d.", params.Name, current.Revision, revision)},
+	}, nil
+}
+
+// runAction sends a catalog action and returns the Operation it created.
+func (t *Tools) runAction(ctx context.Context, cluster string, req *actionRequest, tool string) (*mcp.CallToolResult, any, error) {
+	body, err := client.SteveRequest(ctx, t.client, middleware.Token(ctx), cluster, http.MethodPost, req.path, url.Values{"action": {req.action}}, req.body)
+	if err != nil {
+		zap.L().Error("failed to run catalog action", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to %s App '%s' in cluster '%s': %w", req.action, req.result.App, cluster, err)
+	}
+
+	result := req.result
+	if err := json.Unmarshal(body, &result.Operation); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode the operation of the %s action: %w", req.action, err)
+	}
+	result.Note = "Helm runs in the pod of the Operation, the status of the App is updated when it completes."
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, response.UIContext{
+		Namespace: result.Operation.Namespace,
+		Kind:      "Operation",
+		Cluster:   cluster,
+		Name:      result.Operation.Name,
+		Type:      "catalog.cattle.io.operation",
+	})
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// planAction returns the plan of a catalog action, with the action body as payload and its notes.
+func planAction(cluster string, operationType response.OperationType, req *actionRequest, tool string) (*mcp.CallToolResult, any, error) {
+	planResource := response.PlanResource{
+		Type:    operationType,
+		Payload: req.body,
+		Resource: response.Resource{
+			Name:      req.result.App,
+			Kind:      appKind,
+			Cluster:   cluster,
+			Namespace: req.result.Namespace,
+		},
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{planResource})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	content := []mcp.Content{&mcp.TextContent{Text: mcpResponse}}
+	if len(req.notes) > 0 {
+		content = append(content, &mcp.TextContent{Text: strings.Join(req.notes, "\n")})
+	}
+
+	return &mcp.CallToolResult{Content: content}, nil, nil
+}
+
+// chartReleases returns the charts of an install or upgrade action of a version of a chart. The chart named by its
+// auto-install annotation, like its CRD chart, comes first so that Rancher installs it before the chart, as the
+// Rancher UI does. It gets the global values of the chart.
+func chartReleases(index *clusterrepo.Index, repo, chart string, version *clusterrepo.ChartVersion, releaseName string, values map[string]any) ([]chartRelease, error) {
+	release := newChartRelease(repo, chart, version.Version, releaseName, values)
+	autoInstall := version.Annotations[autoInstallAnnotation]
+	if autoInstall == "" {
+		return []chartRelease{release}, nil
+	}
+
+	name, autoInstallVersion, _ := strings.Cut(autoInstall, "=")
+	if autoInstallVersion == "match" {
+		autoInstallVersion = version.Version
+	}
+	dependency, err := index.Version(repo, name, autoInstallVersion)
+	if err != nil {
+		return nil, fmt.Errorf("chart '%s' version %s requires chart %s: %w", chart, version.Version, autoInstall, err)
+	}
+	var dependencyValues map[string]any
+	if global, ok := values["global"]; ok {
+		dependencyValues = map[string]any{"global": global}
+	}
+
+	return []chartRelease{newChartRelease(repo, name, dependency.Version, name, dependencyValues), release}, nil
+}
+
+// autoInstallNote explains that the auto-installed chart is installed or upgraded along the chart.
+func autoInstallNote(autoInstalled chartRelease, chart string) string {
+	return fmt.Sprintf("Chart '%s' version %s is installed or upgraded first, as required by chart '%s'.", autoInstalled.ChartName, autoInstalled.Version, chart)
+}
+
+// newChartRelease returns the chart of an install or upgrade action, annotated with its ClusterRepo like the Rancher UI
+// does so that later upgrades know where the chart comes from.
+func newChartRelease(repo, chartName, version, releaseName string, values map[string]any) chartRelease {
+	return chartRelease{
+		ChartName:   chartName,
+		Version:     version,
+		ReleaseName: releaseName,
+		Annotations: map[string]string{
+			sourceRepoTypeAnnotation:         "cluster",
+			clusterrepo.SourceRepoAnnotation: repo,
+		},
+		Values: values,
+	}
+}
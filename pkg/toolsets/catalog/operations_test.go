@@ -0,0 +1,363 @@
+package catalog
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeOperation = `{"operationName": "helm-operation-x7k2p", "operationNamespace": "cattle-system"}`
+
+func TestInstallApp(t *testing.T) {
+	tests := map[string]struct {
+		params        installAppParams
+		expectedBody  string
+		expectedError string
+	}{
+		"install most recent version": {
+			params: installAppParams{Cluster: "local", Repo: "rancher-charts", Chart: "rancher-backup", Namespace: "backup", Values: map[string]any{"s3": map[string]any{"enabled": true}}},
+			expectedBody: `{
+				"timeout": "600s",
+				"wait": true,
+				"namespace": "backup",
+				"charts": [{
+					"chartName": "rancher-backup-crd",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup-crd",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"}
+				}, {
+					"chartName": "rancher-backup",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"},
+					"values": {"s3": {"enabled": true}}
+				}]
+			}`,
+		},
+		"install with the global values passed to the auto-installed chart": {
+			params: installAppParams{Cluster: "local", Repo: "rancher-charts", Chart: "rancher-backup", Namespace: "backup", Values: map[string]any{"global": map[string]any{"cattle": map[string]any{"systemDefaultRegistry": "registry.example.com"}}}},
+			expectedBody: `{
+				"timeout": "600s",
+				"wait": true,
+				"namespace": "backup",
+				"charts": [{
+					"chartName": "rancher-backup-crd",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup-crd",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"},
+					"values": {"global": {"cattle": {"systemDefaultRegistry": "registry.example.com"}}}
+				}, {
+					"chartName": "rancher-backup",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"},
+					"values": {"global": {"cattle": {"systemDefaultRegistry": "registry.example.com"}}}
+				}]
+			}`,
+		},
+		"app already exists": {
+			params:        installAppParams{Cluster: "local", Repo: "rancher-charts", Chart: "rancher-backup", Namespace: "cattle-resources-system"},
+			expectedError: "App 'rancher-backup' already exists in namespace 'cattle-resources-system', use upgradeApp to change it",
+		},
+		"unknown version": {
+			params:        installAppParams{Cluster: "local", Repo: "rancher-charts", Chart: "rancher-backup", Version: "1.0.0", Namespace: "backup"},
+			expectedError: "version '1.0.0' of chart 'rancher-backup' not found in ClusterRepo 'rancher-charts'",
+		},
+		"missing chart": {
+			params:        installAppParams{Cluster: "local", Repo: "rancher-charts", Namespace: "backup"},
+			expectedError: "the repo, chart and namespace are required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newStandInTools(t, map[string]steveResponse{
+				"GET /catalog.cattle.io.clusterrepos/rancher-charts":  {status: http.StatusOK, body: fakeIndex},
+				"POST /catalog.cattle.io.clusterrepos/rancher-charts": {status: http.StatusCreated, body: fakeOperation},
+			}, newFakeApp("105.0.0", 3, nil))
+
+			result, _, err := tools.installApp(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 2)
+			assert.Equal(t, url.Values{"action": {"install"}}, (*requests)[1].query)
+			assert.JSONEq(t, tt.expectedBody, (*requests)[1].body)
+			assert.JSONEq(t, `{
+				"llm": {
+					"action": "install",
+					"app": "rancher-backup",
+					"namespace": "backup",
+					"chart": "rancher-backup",
+					"version": "106.0.1",
+					"operation": {"operationName": "helm-operation-x7k2p", "operationNamespace": "cattle-system"},
+					"note": "Helm runs in the pod of the Operation, the status of the App is updated when it completes."
+				},
+				"uiContext": [{"namespace": "cattle-system", "kind": "Operation", "cluster": "local", "name": "helm-operation-x7k2p", "type": "catalog.cattle.io.operation"}]
+			}`, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestInstallAppPlan(t *testing.T) {
+	tools, requests := newStandInTools(t, map[string]steveResponse{
+		"GET /catalog.cattle.io.clusterrepos/rancher-charts": {status: http.StatusOK, body: fakeIndex},
+	})
+
+	result, _, err := tools.installAppPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, installAppParams{
+		Cluster:   "local",
+		Repo:      "rancher-charts",
+		Chart:     "rancher-logging",
+		Namespace: "cattle-logging-system",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, *requests, 1, "the plan must not install the chart")
+	require.Len(t, result.Content, 2)
+	assert.JSONEq(t, `[{
+		"type": "create",
+		"payload": {
+			"timeout": "600s",
+			"wait": true,
+			"namespace": "cattle-logging-system",
+			"charts": [{
+				"chartName": "rancher-logging",
+				"version": "106.0.0",
+				"releaseName": "rancher-logging",
+				"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"}
+			}]
+		},
+		"resource": {"name": "rancher-logging", "kind": "App", "cluster": "local", "namespace": "cattle-logging-system"}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "Chart 'rancher-logging' is deprecated.", result.Content[1].(*mcp.TextContent).Text)
+}
+
+func TestUpgradeApp(t *testing.T) {
+	currentValues := map[string]any{"persistence": map[string]any{"enabled": true, "size": "10Gi"}}
+
+	tests := map[string]struct {
+		params         upgradeAppParams
+		expectedValues string
+		expectedError  string
+	}{
+		"upgrade keeping the values": {
+			params:         upgradeAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Values: map[string]any{"persistence": map[string]any{"size": "20Gi"}}},
+			expectedValues: `{"persistence": {"enabled": true, "size": "20Gi"}}`,
+		},
+		"upgrade resetting the values": {
+			params:         upgradeAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Values: map[string]any{"s3": map[string]any{"enabled": true}}, ResetValues: true},
+			expectedValues: `{"s3": {"enabled": true}}`,
+		},
+		"app not found": {
+			params:        upgradeAppParams{Cluster: "local", Namespace: "default", Name: "rancher-backup"},
+			expectedError: `apps.catalog.cattle.io "rancher-backup" not found`,
+		},
+		"unknown repo": {
+			params:        upgradeAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Repo: "partner-charts"},
+			expectedError: "failed to get the index of ClusterRepo 'partner-charts' in cluster 'local'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newStandInTools(t, map[string]steveResponse{
+				"GET /catalog.cattle.io.clusterrepos/rancher-charts":  {status: http.StatusOK, body: fakeIndex},
+				"POST /catalog.cattle.io.clusterrepos/rancher-charts": {status: http.StatusCreated, body: fakeOperation},
+			}, newFakeApp("105.0.0", 3, currentValues))
+
+			result, _, err := tools.upgradeApp(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 2)
+			assert.Equal(t, url.Values{"action": {"upgrade"}}, (*requests)[1].query)
+			assert.JSONEq(t, `{
+				"timeout": "600s",
+				"wait": true,
+				"namespace": "cattle-resources-system",
+				"charts": [{
+					"chartName": "rancher-backup-crd",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup-crd",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"}
+				}, {
+					"chartName": "rancher-backup",
+					"version": "106.0.1",
+					"releaseName": "rancher-backup",
+					"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"},
+					"values": `+tt.expectedValues+`
+				}]
+			}`, (*requests)[1].body)
+			assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"action":"upgrade"`)
+		})
+	}
+}
+
+func TestUpgradeAppPlan(t *testing.T) {
+	tools, requests := newStandInTools(t, map[string]steveResponse{
+		"GET /catalog.cattle.io.clusterrepos/rancher-charts": {status: http.StatusOK, body: fakeIndex},
+	}, newFakeApp("105.0.0", 3, nil))
+
+	result, _, err := tools.upgradeAppPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, upgradeAppParams{
+		Cluster:   "local",
+		Namespace: "cattle-resources-system",
+		Name:      "rancher-backup",
+		Version:   "106.0.0",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, *requests, 1, "the plan must not upgrade the App")
+	require.Len(t, result.Content, 2)
+	assert.JSONEq(t, `[{
+		"type": "update",
+		"payload": {
+			"timeout": "600s",
+			"wait": true,
+			"namespace": "cattle-resources-system",
+			"charts": [{
+				"chartName": "rancher-backup",
+				"version": "106.0.0",
+				"releaseName": "rancher-backup",
+				"annotations": {"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"}
+			}]
+		},
+		"resource": {"name": "rancher-backup", "kind": "App", "cluster": "local", "namespace": "cattle-resources-system"}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "App 'rancher-backup' is upgraded from version 105.0.0 to version 106.0.0 of chart 'rancher-backup'.", result.Content[1].(*mcp.TextContent).Text)
+}
+
+func TestRollbackApp(t *testing.T) {
+	tests := map[string]struct {
+		params           rollbackAppParams
+		rejection        *steveResponse
+		expectedRevision string
+		expectedError    string
+	}{
+		"previous revision": {
+			params:           rollbackAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup"},
+			expectedRevision: "2",
+		},
+		"given revision": {
+			params:           rollbackAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Revision: 1},
+			expectedRevision: "1",
+		},
+		"current revision": {
+			params:        rollbackAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Revision: 3},
+			expectedError: "App 'rancher-backup' is at revision 3, it can only be rolled back to a revision between 1 and 2",
+		},
+		"rejected by Rancher": {
+			params:        rollbackAppParams{Cluster: "local", Namespace: "cattle-resources-system", Name: "rancher-backup", Revision: 2},
+			rejection:     &steveResponse{status: http.StatusForbidden, body: `{"type": "error", "status": 403, "code": "Forbidden", "message": "can not rollback"}`},
+			expectedError: "failed to rollback App 'rancher-backup' in cluster 'local': 403 Forbidden: can not rollback",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rollbackResponse := steveResponse{status: http.StatusCreated, body: fakeOperation}
+			if tt.rejection != nil {
+				rollbackResponse = *tt.rejection
+			}
+			tools, requests := newStandInTools(t, map[string]steveResponse{
+				"POST /catalog.cattle.io.apps/cattle-resources-system/rancher-backup": rollbackResponse,
+			}, newFakeApp("106.0.1", 3, nil))
+
+			result, _, err := tools.rollbackApp(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 1)
+			assert.Equal(t, url.Values{"action": {"rollback"}}, (*requests)[0].query)
+			assert.JSONEq(t, `{"revision": `+tt.expectedRevision+`, "timeout": "600s", "wait": true}`, (*requests)[0].body)
+			assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `"revision":`+tt.expectedRevision)
+		})
+	}
+}
+
+func TestRollbackAppPlan(t *testing.T) {
+	tools, requests := newStandInTools(t, nil, newFakeApp("106.0.1", 3, nil))
+
+	result, _, err := tools.rollbackAppPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, rollbackAppParams{
+		Cluster:   "local",
+		Namespace: "cattle-resources-system",
+		Name:      "rancher-backup",
+	})
+
+	require.NoError(t, err)
+	assert.Empty(t, *requests, "the plan must not roll the App back")
+	require.Len(t, result.Content, 2)
+	assert.JSONEq(t, `[{
+		"type": "update",
+		"payload": {"revision": 2, "timeout": "600s", "wait": true},
+		"resource": {"name": "rancher-backup", "kind": "App", "cluster": "local", "namespace": "cattle-resources-system"}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "App 'rancher-backup' is rolled back from revision 3 to revision 2.", result.Content[1].(*mcp.TextContent).Text)
+}
+
+func TestChartReleases(t *testing.T) {
+	index := &clusterrepo.Index{Entries: map[string][]clusterrepo.ChartVersion{
+		"rancher-monitoring-crd": {{Name: "rancher-monitoring-crd", Version: "106.1.0"}, {Name: "rancher-monitoring-crd", Version: "106.0.0"}},
+	}}
+	annotations := map[string]string{"catalog.cattle.io/ui-source-repo-type": "cluster", "catalog.cattle.io/ui-source-repo": "rancher-charts"}
+
+	tests := map[string]struct {
+		autoInstall    string
+		expectedCharts []chartRelease
+		expectedError  string
+	}{
+		"no auto-install annotation": {
+			expectedCharts: []chartRelease{{ChartName: "rancher-monitoring", Version: "106.1.0", ReleaseName: "rancher-monitoring", Annotations: annotations}},
+		},
+		"auto-installed chart matching the version of the chart": {
+			autoInstall: "rancher-monitoring-crd=match",
+			expectedCharts: []chartRelease{
+				{ChartName: "rancher-monitoring-crd", Version: "106.1.0", ReleaseName: "rancher-monitoring-crd", Annotations: annotations},
+				{ChartName: "rancher-monitoring", Version: "106.1.0", ReleaseName: "rancher-monitoring", Annotations: annotations},
+			},
+		},
+		"auto-installed chart with its own version": {
+			autoInstall: "rancher-monitoring-crd=106.0.0",
+			expectedCharts: []chartRelease{
+				{ChartName: "rancher-monitoring-crd", Version: "106.0.0", ReleaseName: "rancher-monitoring-crd", Annotations: annotations},
+				{ChartName: "rancher-monitoring", Version: "106.1.0", ReleaseName: "rancher-monitoring", Annotations: annotations},
+			},
+		},
+		"auto-installed chart missing from the index": {
+			autoInstall:   "rancher-monitoring-crd=105.0.0",
+			expectedError: "chart 'rancher-monitoring' version 106.1.0 requires chart rancher-monitoring-crd=105.0.0: version '105.0.0' of chart 'rancher-monitoring-crd' not found in ClusterRepo 'rancher-charts'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			version := &clusterrepo.ChartVersion{Name: "rancher-monitoring", Version: "106.1.0"}
+			if tt.autoInstall != "" {
+				version.Annotations = map[string]string{autoInstallAnnotation: tt.autoInstall}
+			}
+
+			charts, err := chartReleases(index, "rancher-charts", "rancher-monitoring", version, "rancher-monitoring", nil)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCharts, charts)
+		})
+	}
+}
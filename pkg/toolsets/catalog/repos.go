@@ -0,0 +1,206 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxChartVersions is the number of most recent versions listed for every chart.
+	maxChartVersions = 10
+	// hiddenAnnotation hides a chart from the Rancher UI, like the CRD charts installed along other charts.
+	hiddenAnnotation = "catalog.cattle.io/hidden"
+)
+
+// clusterRepo is the summary of a ClusterRepo.
+type clusterRepo struct {
+	Name         string `json:"name"`
+	URL          string `json:"url,omitempty"`
+	GitRepo      string `json:"gitRepo,omitempty"`
+	GitBranch    string `json:"gitBranch,omitempty"`
+	Enabled      bool   `json:"enabled"`
+	DownloadTime string `json:"downloadTime,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// chart is the summary of a chart of a ClusterRepo and its most recent versions.
+type chart struct {
+	Repo          string   `json:"repo"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description,omitempty"`
+	LatestVersion string   `json:"latestVersion"`
+	AppVersion    string   `json:"appVersion,omitempty"`
+	KubeVersion   string   `json:"kubeVersion,omitempty"`
+	Deprecated    bool     `json:"deprecated,omitempty"`
+	Versions      []string `json:"versions"`
+}
+
+// chartsResult is the response of listCharts.
+type chartsResult struct {
+	Charts []chart  `json:"charts"`
+	Notes  []string `json:"notes,omitempty"`
+}
+
+// listClusterReposParams specifies the parameters needed to list the ClusterRepos of a cluster.
+type listClusterReposParams struct {
+	Cluster string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+}
+
+// listChartsParams specifies the parameters needed to list the charts of the ClusterRepos of a cluster.
+type listChartsParams struct {
+	Cluster       string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Repo          string `json:"repo,omitempty" jsonschema:"the name of the ClusterRepo. Defaults to all the ClusterRepos of the cluster"`
+	Name          string `json:"name,omitempty" jsonschema:"only the charts whose name contains this value are listed"`
+	IncludeHidden bool   `json:"includeHidden,omitempty" jsonschema:"whether the charts hidden from the Rancher UI, like CRD charts, are listed"`
+}
+
+// listClusterRepos returns the ClusterRepos of a cluster.
+func (t *Tools) listClusterRepos(ctx context.Context, toolReq *mcp.CallToolRequest, params listClusterReposParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listClusterRepos called")
+
+	objs, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: params.Cluster,
+		Kind:    "clusterrepo",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to list cluster repos", zap.String("tool", "listClusterRepos"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	repos := make([]clusterRepo, 0, len(objs))
+	uiContext := make([]response.UIContext, 0, len(objs))
+	for _, obj := range objs {
+		repos = append(repos, newClusterRepo(obj))
+		uiContext = append(uiContext, response.NewUIContext(obj, params.Cluster))
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(repos, uiContext...)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "listClusterRepos"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// listCharts returns the charts of one or all the ClusterRepos of a cluster, with their most recent versions.
+func (t *Tools) listCharts(ctx context.Context, toolReq *mcp.CallToolRequest, params listChartsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listCharts called")
+
+	repos := []string{params.Repo}
+	if params.Repo == "" {
+		objs, err := t.client.GetResources(ctx, client.ListParams{
+			Cluster: params.Cluster,
+			Kind:    "clusterrepo",
+			Token:   middleware.Token(ctx),
+		})
+		if err != nil {
+			zap.L().Error("failed to list cluster repos", zap.String("tool", "listCharts"), zap.Error(err))
+			return nil, nil, err
+		}
+		repos = repos[:0]
+		for _, obj := range objs {
+			repos = append(repos, obj.GetName())
+		}
+	}
+
+	result := chartsResult{Charts: []chart{}}
+	for _, repo := range repos {
+		index, err := clusterrepo.GetIndex(ctx, t.client, middleware.Token(ctx), params.Cluster, repo)
+		if err != nil {
+			// a single repo is requested explicitly, when listing all of them the others are still useful
+			if params.Repo != "" {
+				zap.L().Error("failed to get chart index", zap.String("tool", "listCharts"), zap.Error(err))
+				return nil, nil, err
+			}
+			result.Notes = append(result.Notes, err.Error())
+			continue
+		}
+		result.Charts = append(result.Charts, indexCharts(index, repo, params.Name, params.IncludeHidden)...)
+	}
+	slices.SortFunc(result.Charts, func(a, b chart) int {
+		return strings.Compare(a.Repo+"/"+a.Name, b.Repo+"/"+b.Name)
+	})
+	if slices.ContainsFunc(result.Charts, func(c chart) bool { return len(c.Versions) == maxChartVersions }) {
+		result.Notes = append(result.Notes, fmt.Sprintf("only the %d most recent versions of every chart are listed", maxChartVersions))
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "listCharts"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// indexCharts returns the charts of the index whose name contains nameFilter.
+func indexCharts(index *clusterrepo.Index, repo, nameFilter string, includeHidden bool) []chart {
+	var charts []chart
+	for name, versions := range index.Entries {
+		if len(versions) == 0 || !strings.Contains(strings.ToLower(name), strings.ToLower(nameFilter)) {
+			continue
+		}
+		latest := versions[0]
+		if !includeHidden && latest.Annotations[hiddenAnnotation] == "true" {
+			continue
+		}
+
+		c := chart{
+			Repo:          repo,
+			Name:          name,
+			Description:   latest.Description,
+			LatestVersion: latest.Version,
+			AppVersion:    latest.AppVersion,
+			KubeVersion:   latest.KubeVersion,
+			Deprecated:    latest.Deprecated,
+		}
+		for _, v := range versions[:min(len(versions), maxChartVersions)] {
+			c.Versions = append(c.Versions, v.Version)
+		}
+		charts = append(charts, c)
+	}
+
+	return charts
+}
+
+// newClusterRepo returns the summary of a ClusterRepo. Error is the message of its first failed condition.
+func newClusterRepo(obj *unstructured.Unstructured) clusterRepo {
+	repo := clusterRepo{Name: obj.GetName()}
+	repo.URL, _, _ = unstructured.NestedString(obj.Object, "spec", "url")
+	repo.GitRepo, _, _ = unstructured.NestedString(obj.Object, "spec", "gitRepo")
+	repo.GitBranch, _, _ = unstructured.NestedString(obj.Object, "spec", "gitBranch")
+	repo.DownloadTime, _, _ = unstructured.NestedString(obj.Object, "status", "downloadTime")
+	// ClusterRepos are enabled unless spec.enabled is false
+	enabled, found, _ := unstructured.NestedBool(obj.Object, "spec", "enabled")
+	repo.Enabled = enabled || !found
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok || condition["status"] != "False" {
+			continue
+		}
+		if message, _ := condition["message"].(string); message != "" {
+			repo.Error = message
+			break
+		}
+	}
+
+	return repo
+}
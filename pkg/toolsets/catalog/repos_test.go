@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const fakeIndex = `{
+	"apiVersion": "v1",
+	"entries": {
+		"rancher-backup": [
+			{"name": "rancher-backup", "version": "106.0.1", "appVersion": "v6.0.1", "description": "Provides ability to back up and restore the Rancher application.", "kubeVersion": ">= 1.28.0-0", "annotations": {"catalog.cattle.io/auto-install": "rancher-backup-crd=match"}},
+			{"name": "rancher-backup", "version": "106.0.0", "appVersion": "v6.0.0"},
+			{"name": "rancher-backup", "version": "105.0.0", "appVersion": "v5.0.0"}
+		],
+		"rancher-backup-crd": [
+			{"name": "rancher-backup-crd", "version": "106.0.1", "annotations": {"catalog.cattle.io/hidden": "true"}}
+		],
+		"rancher-logging": [
+			{"name": "rancher-logging", "version": "106.0.0", "deprecated": true}
+		]
+	}
+}`
+
+func newFakeClusterRepo(name string, spec map[string]any, conditions ...any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "catalog.cattle.io/v1",
+		"kind":       "ClusterRepo",
+		"metadata":   map[string]any{"name": name},
+		"spec":       spec,
+		"status": map[string]any{
+			"downloadTime": "2024-05-02T09:00:00Z",
+			"conditions":   conditions,
+		},
+	}}
+}
+
+func TestListClusterRepos(t *testing.T) {
+	tools, _ := newStandInTools(t, nil,
+		newFakeClusterRepo("partner-charts", map[string]any{"gitRepo": "https://git.rancher.io/partner-charts", "gitBranch": "main", "enabled": false}),
+		newFakeClusterRepo("rancher-charts", map[string]any{"gitRepo": "https://git.rancher.io/charts", "gitBranch": "release-v2.9"},
+			map[string]any{"type": "Downloaded", "status": "False", "message": "failed to clone the repository"}),
+	)
+
+	result, _, err := tools.listClusterRepos(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, listClusterReposParams{Cluster: "local"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"llm": [
+			{"name": "partner-charts", "gitRepo": "https://git.rancher.io/partner-charts", "gitBranch": "main", "enabled": false, "downloadTime": "2024-05-02T09:00:00Z"},
+			{"name": "rancher-charts", "gitRepo": "https://git.rancher.io/charts", "gitBranch": "release-v2.9", "enabled": true, "downloadTime": "2024-05-02T09:00:00Z", "error": "failed to clone the repository"}
+		],
+		"uiContext": [
+			{"namespace": "", "kind": "ClusterRepo", "cluster": "local", "name": "partner-charts", "type": "catalog.cattle.io.clusterrepo"},
+			{"namespace": "", "kind": "ClusterRepo", "cluster": "local", "name": "rancher-charts", "type": "catalog.cattle.io.clusterrepo"}
+		]
+	}`, result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestListCharts(t *testing.T) {
+	tests := map[string]struct {
+		params        listChartsParams
+		expected      string
+		expectedError string
+	}{
+		"charts of a repo": {
+			params: listChartsParams{Cluster: "local", Repo: "rancher-charts"},
+			expected: `{"llm": {"charts": [
+				{"repo": "rancher-charts", "name": "rancher-backup", "description": "Provides ability to back up and restore the Rancher application.", "latestVersion": "106.0.1", "appVersion": "v6.0.1", "kubeVersion": ">= 1.28.0-0", "versions": ["106.0.1", "106.0.0", "105.0.0"]},
+				{"repo": "rancher-charts", "name": "rancher-logging", "latestVersion": "106.0.0", "deprecated": true, "versions": ["106.0.0"]}
+			]}}`,
+		},
+		"charts filtered by name, including hidden charts": {
+			params: listChartsParams{Cluster: "local", Repo: "rancher-charts", Name: "Backup", IncludeHidden: true},
+			expected: `{"llm": {"charts": [
+				{"repo": "rancher-charts", "name": "rancher-backup", "description": "Provides ability to back up and restore the Rancher application.", "latestVersion": "106.0.1", "appVersion": "v6.0.1", "kubeVersion": ">= 1.28.0-0", "versions": ["106.0.1", "106.0.0", "105.0.0"]},
+				{"repo": "rancher-charts", "name": "rancher-backup-crd", "latestVersion": "106.0.1", "versions": ["106.0.1"]}
+			]}}`,
+		},
+		"charts of all repos": {
+			params: listChartsParams{Cluster: "local", Name: "logging"},
+			expected: `{"llm": {
+				"charts": [{"repo": "rancher-charts", "name": "rancher-logging", "latestVersion": "106.0.0", "deprecated": true, "versions": ["106.0.0"]}],
+				"notes": ["failed to get the index of ClusterRepo 'partner-charts' in cluster 'local': 404 NotFound: not found"]
+			}}`,
+		},
+		"unknown repo": {
+			params:        listChartsParams{Cluster: "local", Repo: "unknown"},
+			expectedError: "failed to get the index of ClusterRepo 'unknown' in cluster 'local': 404 NotFound: not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newStandInTools(t, map[string]steveResponse{
+				"GET /catalog.cattle.io.clusterrepos/rancher-charts": {status: http.StatusOK, body: fakeIndex},
+			}, newFakeClusterRepo("partner-charts", map[string]any{}), newFakeClusterRepo("rancher-charts", map[string]any{}))
+
+			result, _, err := tools.listCharts(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, req := range *requests {
+				assert.Equal(t, url.Values{"link": {"index"}}, req.query)
+			}
+			assert.JSONEq(t, tt.expected, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
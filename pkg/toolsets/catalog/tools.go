@@ -0,0 +1,135 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	toolsSet    = "catalog"
+	toolsSetAnn = "toolset"
+)
+
+type toolsClient interface {
+	GetResource(ctx context.Context, params client.GetParams) (*unstructured.Unstructured, error)
+	GetResources(ctx context.Context, params client.ListParams) ([]*unstructured.Unstructured, error)
+	GetClusterID(ctx context.Context, token string, clusterNameOrID string) (string, error)
+	CreateRestConfig(token string, clusterID string) (*rest.Config, error)
+}
+
+// Tools contains the tools that manage the Helm charts installed through the Rancher catalog (catalog.cattle.io).
+type Tools struct {
+	client   toolsClient
+	ReadOnly bool
+}
+
+// NewTools creates and returns a new Tools instance.
+func NewTools(client toolsClient, readOnly bool) *Tools {
+	return &Tools{
+		client:   client,
+		ReadOnly: readOnly,
+	}
+}
+
+// AddTools registers all catalog tools with the provided MCP server.
+// Each tool is configured with metadata identifying it as part of the catalog toolset.
+func (t *Tools) AddTools(mcpServer *mcp.Server) {
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listClusterRepos",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the ClusterRepos of a cluster, the Helm repositories Rancher installs charts from, with their URL or Git repository, whether they are enabled, when their index was downloaded and their error.`},
+		t.listClusterRepos,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listCharts",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the charts of a ClusterRepo, or of all the ClusterRepos of a cluster, with their description, most recent version, app version and their 10 most recent versions. Charts can be filtered by name.
+Charts hidden from the Rancher UI, like the CRD charts installed along other charts, are only included when includeHidden is true.`},
+		t.listCharts,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listApps",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the Apps of a cluster, the Helm releases installed through Rancher, with their chart, chart version, app version, ClusterRepo, Helm revision, status and the values set by the user. They can be filtered by namespace.`},
+		t.listApps,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "getAppValuesDiff",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the difference between the values of an App and the default values of its chart: the values changed from their default, the values without default and the paths of the values set to their default.`},
+		t.getAppValuesDiff,
+	)
+
+	if !t.ReadOnly {
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "installApp",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Installs a chart of a ClusterRepo as an App with the install operation of Rancher, at its most recent version unless a version is given, with values overriding the defaults of the chart.
+The chart it requires, like its CRD chart, is installed first as the Rancher UI does. Helm runs asynchronously in the pod of a catalog Operation, which is returned. Use listApps to check the status of the App.`},
+			t.installApp,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "installAppPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to install a chart of a ClusterRepo as an App. It returns the install operation that would be sent without sending it. Only used for displaying the change when using human validation.`},
+			t.installAppPlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "upgradeApp",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Upgrades an App to a version of its chart, its most recent version by default, with the upgrade operation of Rancher. It can also change values without changing the version.
+The chart it requires, like its CRD chart, is upgraded first. The given values are merged into the current values of the App, unless resetValues is true. Helm runs asynchronously in the pod of a catalog Operation, which is returned.`},
+			t.upgradeApp,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "upgradeAppPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to upgrade an App. It returns the upgrade operation that would be sent without sending it, followed by the version change. Only used for displaying the change when using human validation.`},
+			t.upgradeAppPlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "rollbackApp",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Rolls an App back to a previous Helm revision with the rollback operation of Rancher, the revision before the current one by default. Helm runs asynchronously in the pod of a catalog Operation, which is returned.`},
+			t.rollbackApp,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "rollbackAppPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to roll an App back to a previous Helm revision. It returns the rollback operation that would be sent without sending it. Only used for displaying the change when using human validation.`},
+			t.rollbackAppPlan,
+		)
+	}
+}
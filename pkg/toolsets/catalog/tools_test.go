@@ -0,0 +1,145 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+const fakeToken = "fakeToken"
+
+// steveRequest is a request received by the stand-in Steve API.
+type steveRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   string
+}
+
+// steveResponse is the response of the stand-in Steve API to the requests of a method and path.
+type steveResponse struct {
+	status int
+	body   string
+}
+
+// newStandInTools returns tools reaching a stand-in Steve API of the local cluster, which answers the requests with
+// the responses keyed by "METHOD path" and records the requests it received, and a fake dynamic client holding objs.
+func newStandInTools(t *testing.T, responses map[string]steveResponse, objs ...runtime.Object) (*Tools, *[]steveRequest) {
+	t.Helper()
+
+	var requests []steveRequest
+	stevePrefix := "/k8s/clusters/local/v1"
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path, _ := strings.CutPrefix(r.URL.Path, stevePrefix)
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, steveRequest{method: r.Method, path: path, query: r.URL.Query(), body: string(body)})
+		resp, ok := responses[r.Method+" "+path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"type": "error", "status": 404, "code": "NotFound", "message": "not found"}`)
+			return
+		}
+		w.WriteHeader(resp.status)
+		fmt.Fprint(w, resp.body)
+	}))
+	t.Cleanup(svr.Close)
+
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		converter.K8sKindsToGVRs["clusterrepo"]: "ClusterRepoList",
+		converter.K8sKindsToGVRs["app"]:         "AppList",
+	}, objs...)
+	c, err := client.NewClient(false, svr.URL)
+	require.NoError(t, err)
+	c.DynClientCreator = func(*rest.Config) (dynamic.Interface, error) {
+		return fakeDynClient, nil
+	}
+
+	return NewTools(c, false), &requests
+}
+
+// newFakeApp returns an App of chart rancher-backup, installed from the rancher-charts ClusterRepo.
+func newFakeApp(version string, revision int64, values map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "catalog.cattle.io/v1",
+		"kind":       "App",
+		"metadata": map[string]any{
+			"name":      "rancher-backup",
+			"namespace": "cattle-resources-system",
+		},
+		"spec": map[string]any{
+			"chart": map[string]any{
+				"metadata": map[string]any{
+					"name":        "rancher-backup",
+					"version":     version,
+					"appVersion":  "v" + version,
+					"annotations": map[string]any{clusterrepo.SourceRepoAnnotation: "rancher-charts"},
+				},
+				"values": map[string]any{
+					"image":       map[string]any{"repository": "rancher/backup-restore-operator", "tag": "v" + version},
+					"persistence": map[string]any{"enabled": false, "size": "2Gi"},
+					"s3":          map[string]any{"enabled": false, "bucketName": ""},
+				},
+			},
+			"values":  values,
+			"version": revision,
+			"info": map[string]any{
+				"status":       "deployed",
+				"lastDeployed": "2024-05-02T10:00:00Z",
+			},
+		},
+	}}
+}
+
+func TestAddTools(t *testing.T) {
+	tests := map[string]struct {
+		readOnly      bool
+		expectedTools int
+	}{
+		"all tools":       {readOnly: false, expectedTools: 10},
+		"read only tools": {readOnly: true, expectedTools: 4},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _ := client.NewClient(true, "")
+			mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "v1.0.0"}, nil)
+			NewTools(c, tt.readOnly).AddTools(mcpServer)
+
+			serverTransport, clientTransport := mcp.NewInMemoryTransports()
+			_, err := mcpServer.Connect(t.Context(), serverTransport, nil)
+			require.NoError(t, err)
+			cs, err := mcp.NewClient(&mcp.Implementation{Name: "mcp-client", Version: "v1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+			require.NoError(t, err)
+			defer cs.Close()
+
+			toolsResult, err := cs.ListTools(t.Context(), &mcp.ListToolsParams{})
+
+			require.NoError(t, err)
+			assert.Len(t, toolsResult.Tools, tt.expectedTools, "incorrect number of tools registered")
+			for _, tool := range toolsResult.Tools {
+				assert.Equal(t, toolsSet, tool.Meta[toolsSetAnn])
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// valueChange is a value of an App at a path of its values, like image.tag, and the default value of its chart.
+type valueChange struct {
+	Path    string `json:"path"`
+	Default any    `json:"default,omitempty"`
+	Value   any    `json:"value"`
+}
+
+// valuesDiff is the difference between the values of an App and the default values of its chart.
+type valuesDiff struct {
+	// Changed are the values that differ from the default values.
+	Changed []valueChange `json:"changed"`
+	// Added are the values that have no default value.
+	Added []valueChange `json:"added"`
+	// Unchanged are the paths of the values that are set to their default value.
+	Unchanged []string `json:"unchanged,omitempty"`
+}
+
+// diffValues returns the difference between the values of an App and the default values of its chart. Nested maps are
+// compared value by value, other values like lists are compared as a whole.
+func diffValues(defaults, values map[string]any) valuesDiff {
+	defaultLeaves := flattenValues(defaults)
+	leaves := flattenValues(values)
+
+	diff := valuesDiff{Changed: []valueChange{}, Added: []valueChange{}}
+	for _, path := range slices.Sorted(maps.Keys(leaves)) {
+		value := leaves[path]
+		defaultValue, ok := defaultLeaves[path]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, valueChange{Path: path, Value: value})
+		case reflect.DeepEqual(value, defaultValue):
+			diff.Unchanged = append(diff.Unchanged, path)
+		default:
+			diff.Changed = append(diff.Changed, valueChange{Path: path, Default: defaultValue, Value: value})
+		}
+	}
+
+	return diff
+}
+
+// flattenValues returns the values that are not maps, or are empty maps, keyed by their dotted path.
+func flattenValues(values map[string]any) map[string]any {
+	leaves := map[string]any{}
+	var flatten func(prefix string, values map[string]any)
+	flatten = func(prefix string, values map[string]any) {
+		for key, value := range values {
+			path := prefix + key
+			if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+				flatten(path+".", nested)
+				continue
+			}
+			leaves[path] = value
+		}
+	}
+	flatten("", values)
+
+	return leaves
+}
+
+// mergeValues returns the values of base overridden by the values of overrides. Nested maps are merged, other values
+// are replaced. base is not modified.
+func mergeValues(base, overrides map[string]any) map[string]any {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	for key, value := range overrides {
+		baseMap, baseIsMap := merged[key].(map[string]any)
+		overrideMap, overrideIsMap := value.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			merged[key] = mergeValues(baseMap, overrideMap)
+			continue
+		}
+		merged[key] = value
+	}
+
+	return merged
+}
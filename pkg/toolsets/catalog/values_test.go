@@ -0,0 +1,74 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffValues(t *testing.T) {
+	defaults := map[string]any{
+		"image":       map[string]any{"repository": "rancher/backup-restore-operator", "tag": "v1"},
+		"persistence": map[string]any{"enabled": false, "size": "2Gi"},
+		"tolerations": []any{},
+	}
+
+	tests := map[string]struct {
+		values   map[string]any
+		expected valuesDiff
+	}{
+		"no values": {
+			expected: valuesDiff{Changed: []valueChange{}, Added: []valueChange{}},
+		},
+		"changed, added and unchanged values": {
+			values: map[string]any{
+				"image":       map[string]any{"tag": "v2"},
+				"persistence": map[string]any{"enabled": true, "size": "2Gi", "storageClass": "longhorn"},
+				"tolerations": []any{map[string]any{"key": "dedicated", "operator": "Exists"}},
+			},
+			expected: valuesDiff{
+				Changed: []valueChange{
+					{Path: "image.tag", Default: "v1", Value: "v2"},
+					{Path: "persistence.enabled", Default: false, Value: true},
+					{Path: "tolerations", Default: []any{}, Value: []any{map[string]any{"key": "dedicated", "operator": "Exists"}}},
+				},
+				Added:     []valueChange{{Path: "persistence.storageClass", Value: "longhorn"}},
+				Unchanged: []string{"persistence.size"},
+			},
+		},
+		"map replacing a value": {
+			values: map[string]any{"tolerations": map[string]any{"key": "dedicated"}},
+			expected: valuesDiff{
+				Changed: []valueChange{},
+				Added:   []valueChange{{Path: "tolerations.key", Value: "dedicated"}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, diffValues(defaults, tt.values))
+		})
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]any{
+		"image":       map[string]any{"repository": "rancher/backup-restore-operator", "tag": "v1"},
+		"persistence": map[string]any{"enabled": true},
+	}
+
+	merged := mergeValues(base, map[string]any{
+		"image":       map[string]any{"tag": "v2"},
+		"persistence": false,
+		"s3":          map[string]any{"enabled": true},
+	})
+
+	assert.Equal(t, map[string]any{
+		"image":       map[string]any{"repository": "rancher/backup-restore-operator", "tag": "v2"},
+		"persistence": false,
+		"s3":          map[string]any{"enabled": true},
+	}, merged)
+	assert.Equal(t, "v1", base["image"].(map[string]any)["tag"], "base must not be modified")
+	assert.Equal(t, map[string]any{"a": 1}, mergeValues(nil, map[string]any{"a": 1}))
+}
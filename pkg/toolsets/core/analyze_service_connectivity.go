@@ -0,0 +1,691 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/netpol"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Statuses of the checks of analyzeServiceConnectivity.
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+const (
+	ingressClassAnnotation        = "kubernetes.io/ingress.class"
+	defaultIngressClassAnnotation = "ingressclass.kubernetes.io/is-default-class"
+)
+
+var zapAnalyzeServiceConnectivity = zap.String("tool", "analyzeServiceConnectivity")
+
+// analyzeServiceConnectivityParams specifies the parameters needed to analyze the connectivity of a Service.
+type analyzeServiceConnectivityParams struct {
+	Cluster         string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace       string `json:"namespace" jsonschema:"the namespace of the Service"`
+	Name            string `json:"name" jsonschema:"the name of the Service"`
+	SourceNamespace string `json:"sourceNamespace,omitempty" jsonschema:"the namespace the traffic comes from. When set, the NetworkPolicies selecting the pods of the Service are evaluated for this source"`
+	SourcePod       string `json:"sourcePod,omitempty" jsonschema:"the name of the pod in sourceNamespace the traffic comes from. Empty to evaluate a pod without labels"`
+}
+
+// connectivityCheck is a check of the checklist returned by analyzeServiceConnectivity.
+type connectivityCheck struct {
+	Name     string   `json:"name"`
+	Status   string   `json:"status"`
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// serviceConnectivityResult is the response of the analyzeServiceConnectivity tool.
+type serviceConnectivityResult struct {
+	Service string              `json:"service"`
+	Passed  bool                `json:"passed"`
+	Checks  []connectivityCheck `json:"checks"`
+}
+
+// analyzeServiceConnectivity checks the path of the traffic to a Service: the pods its selector matches and their
+// readiness, its target ports, its EndpointSlices, the Ingresses routing to it and the NetworkPolicies that would
+// block traffic from a source.
+func (t *Tools) analyzeServiceConnectivity(ctx context.Context, toolReq *mcp.CallToolRequest, params analyzeServiceConnectivityParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("analyzeServiceConnectivity called")
+
+	serviceResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      "service",
+		Namespace: params.Namespace,
+		Name:      params.Name,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get Service", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, err
+	}
+	var service corev1.Service
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(serviceResource.Object, &service); err != nil {
+		zap.L().Error("failed to convert unstructured object to Service", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to convert unstructured object to Service: %w", err)
+	}
+
+	var checks []connectivityCheck
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		checks = append(checks, connectivityCheck{
+			Name:     "externalName",
+			Status:   checkPass,
+			Evidence: []string{fmt.Sprintf("the Service is a DNS alias of %s and has no pods nor endpoints", service.Spec.ExternalName)},
+		})
+	} else {
+		checks, err = t.backendChecks(ctx, params, service)
+		if err != nil {
+			zap.L().Error("failed to check the backends of the Service", zapAnalyzeServiceConnectivity, zap.Error(err))
+			return nil, nil, err
+		}
+	}
+
+	ingresses, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:   params.Cluster,
+		Kind:      "ingress",
+		Namespace: params.Namespace,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get Ingresses", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, err
+	}
+	ingressClasses, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: params.Cluster,
+		Kind:    "ingressclass",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get IngressClasses", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, err
+	}
+	ingressCheck, err := checkIngresses(service, ingresses, ingressClasses)
+	if err != nil {
+		zap.L().Error("failed to check Ingresses", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, err
+	}
+	checks = append(checks, ingressCheck)
+
+	result := serviceConnectivityResult{Service: service.Namespace + "/" + service.Name, Passed: true, Checks: checks}
+	for _, check := range checks {
+		if check.Status == checkFail {
+			result.Passed = false
+		}
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, response.NewUIContext(serviceResource, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapAnalyzeServiceConnectivity, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// backendChecks checks the pods selected by a Service, its target ports, its EndpointSlices and the NetworkPolicies
+// selecting its pods.
+func (t *Tools) backendChecks(ctx context.Context, params analyzeServiceConnectivityParams, service corev1.Service) ([]connectivityCheck, error) {
+	var pods []corev1.Pod
+	if len(service.Spec.Selector) > 0 {
+		podResources, err := t.client.GetResources(ctx, client.ListParams{
+			Cluster:       params.Cluster,
+			Kind:          "pod",
+			Namespace:     service.Namespace,
+			LabelSelector: labels.SelectorFromSet(service.Spec.Selector).String(),
+			Token:         middleware.Token(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if pods, err = fromUnstructuredList[corev1.Pod](podResources); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+		}
+	}
+
+	sliceResources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       params.Cluster,
+		Kind:          "endpointslice",
+		Namespace:     service.Namespace,
+		LabelSelector: discoveryv1.LabelServiceName + "=" + service.Name,
+		Token:         middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	endpointSlices, err := fromUnstructuredList[discoveryv1.EndpointSlice](sliceResources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured object to EndpointSlice: %w", err)
+	}
+
+	// the egress policies of the source namespace restrict the traffic as much as the ingress policies of the Service
+	policies, err := t.networkPolicies(ctx, params.Cluster, service.Namespace, params.SourceNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var source *netpol.Peer
+	if params.SourceNamespace != "" {
+		if source, err = t.sourcePeer(ctx, params); err != nil {
+			return nil, err
+		}
+	}
+	policyCheck, err := checkNetworkPolicies(service, pods, policies, source, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return []connectivityCheck{
+		checkSelector(service, pods),
+		checkPodReadiness(service, pods),
+		checkTargetPorts(service, pods),
+		checkEndpointSlices(service, pods, endpointSlices),
+		policyCheck,
+	}, nil
+}
+
+// sourcePeer returns the source of the traffic evaluated against the NetworkPolicies.
+func (t *Tools) sourcePeer(ctx context.Context, params analyzeServiceConnectivityParams) (*netpol.Peer, error) {
+	namespaceLabels, err := t.namespaceLabels(ctx, params.Cluster, params.SourceNamespace)
+	if err != nil {
+		return nil, err
+	}
+	source := &netpol.Peer{Namespace: params.SourceNamespace, NamespaceLabels: namespaceLabels}
+	if params.SourcePod == "" {
+		return source, nil
+	}
+
+	podResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      "pod",
+		Namespace: params.SourceNamespace,
+		Name:      params.SourcePod,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	source.Labels = podResource.GetLabels()
+
+	return source, nil
+}
+
+// checkSelector checks that the selector of a Service matches pods.
+func checkSelector(service corev1.Service, pods []corev1.Pod) connectivityCheck {
+	check := connectivityCheck{Name: "selector"}
+	selector := labels.SelectorFromSet(service.Spec.Selector).String()
+	switch {
+	case len(service.Spec.Selector) == 0:
+		check.Status = checkWarn
+		check.Evidence = []string{"the Service has no selector, its EndpointSlices must be managed outside of Kubernetes"}
+	case len(pods) == 0:
+		check.Status = checkFail
+		check.Evidence = []string{fmt.Sprintf("the selector %s matches no pod in namespace %s", selector, service.Namespace)}
+	default:
+		check.Status = checkPass
+		check.Evidence = []string{fmt.Sprintf("the selector %s matches %d pods: %s", selector, len(pods), strings.Join(podNames(pods), ", "))}
+	}
+
+	return check
+}
+
+// checkPodReadiness checks that the pods selected by a Service are ready to receive traffic.
+func checkPodReadiness(service corev1.Service, pods []corev1.Pod) connectivityCheck {
+	check := connectivityCheck{Name: "podReadiness"}
+	if len(pods) == 0 {
+		check.Status = checkSkip
+		check.Evidence = []string{"no pod is selected by the Service"}
+		return check
+	}
+
+	ready := 0
+	for _, pod := range pods {
+		condition := podCondition(pod, corev1.PodReady)
+		switch {
+		case pod.DeletionTimestamp != nil:
+			check.Evidence = append(check.Evidence, fmt.Sprintf("pod %s is terminating", pod.Name))
+		case condition != nil && condition.Status == corev1.ConditionTrue:
+			ready++
+		case condition != nil && condition.Message != "":
+			check.Evidence = append(check.Evidence, fmt.Sprintf("pod %s is not ready (phase %s): %s, %s", pod.Name, pod.Status.Phase, condition.Reason, condition.Message))
+		case condition != nil && condition.Reason != "":
+			check.Evidence = append(check.Evidence, fmt.Sprintf("pod %s is not ready (phase %s): %s", pod.Name, pod.Status.Phase, condition.Reason))
+		default:
+			check.Evidence = append(check.Evidence, fmt.Sprintf("pod %s is not ready (phase %s)", pod.Name, pod.Status.Phase))
+		}
+	}
+	check.Evidence = append([]string{fmt.Sprintf("%d of %d pods are ready", ready, len(pods))}, check.Evidence...)
+	switch {
+	case ready == 0 && !service.Spec.PublishNotReadyAddresses:
+		check.Status = checkFail
+	case ready < len(pods):
+		check.Status = checkWarn
+	default:
+		check.Status = checkPass
+	}
+
+	return check
+}
+
+// checkTargetPorts checks that the target ports of a Service are ports of the containers of its pods.
+func checkTargetPorts(service corev1.Service, pods []corev1.Pod) connectivityCheck {
+	check := connectivityCheck{Name: "targetPorts", Status: checkPass}
+	if len(pods) == 0 {
+		check.Status = checkSkip
+		check.Evidence = []string{"no pod is selected by the Service"}
+		return check
+	}
+
+	for _, port := range service.Spec.Ports {
+		target := targetPort(port)
+		var declared, missing []string
+		for _, pod := range pods {
+			if containerPort, container, ok := findContainerPort(pod, target, servicePortProtocol(port)); ok {
+				declared = append(declared, fmt.Sprintf("%s/%s:%d", pod.Name, container, containerPort.ContainerPort))
+			} else {
+				missing = append(missing, pod.Name)
+			}
+		}
+
+		switch {
+		case len(missing) == 0:
+			check.Evidence = append(check.Evidence, fmt.Sprintf("port %s targets %s, declared by %s", servicePortName(port), target.String(), strings.Join(declared, ", ")))
+		case target.Type == intstr.String:
+			status := checkWarn
+			if len(declared) == 0 {
+				status = checkFail
+			}
+			check.Status = worstStatus(check.Status, status)
+			check.Evidence = append(check.Evidence, fmt.Sprintf("port %s targets the named port %s/%s, which is not a port of pods %s", servicePortName(port), target.StrVal, servicePortProtocol(port), strings.Join(missing, ", ")))
+		default:
+			check.Status = worstStatus(check.Status, checkWarn)
+			check.Evidence = append(check.Evidence, fmt.Sprintf("port %s targets %d/%s, which is not declared by the containers of pods %s. The traffic still reaches them if a process listens on it", servicePortName(port), target.IntVal, servicePortProtocol(port), strings.Join(missing, ", ")))
+		}
+	}
+
+	return check
+}
+
+// checkEndpointSlices checks that the EndpointSlices of a Service have a ready endpoint for each ready pod.
+func checkEndpointSlices(service corev1.Service, pods []corev1.Pod, endpointSlices []discoveryv1.EndpointSlice) connectivityCheck {
+	check := connectivityCheck{Name: "endpointSlices"}
+	if len(endpointSlices) == 0 {
+		check.Status = checkFail
+		check.Evidence = []string{"no EndpointSlice exists for the Service"}
+		return check
+	}
+
+	ready := 0
+	readyTargets := map[string]bool{}
+	for _, slice := range endpointSlices {
+		sliceReady, notReady := 0, 0
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				notReady++
+				continue
+			}
+			sliceReady++
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+				readyTargets[endpoint.TargetRef.Name] = true
+			}
+		}
+		ready += sliceReady
+		var ports []string
+		for _, port := range slice.Ports {
+			if port.Port != nil {
+				ports = append(ports, fmt.Sprintf("%d", *port.Port))
+			}
+		}
+		check.Evidence = append(check.Evidence, fmt.Sprintf("EndpointSlice %s has %d ready and %d not ready endpoints on ports [%s]", slice.Name, sliceReady, notReady, strings.Join(ports, ", ")))
+	}
+
+	var missing []string
+	for _, pod := range pods {
+		if podReady(pod) && !readyTargets[pod.Name] {
+			missing = append(missing, pod.Name)
+		}
+	}
+	switch {
+	case ready == 0:
+		check.Status = checkFail
+	case len(missing) > 0:
+		check.Status = checkWarn
+		check.Evidence = append(check.Evidence, fmt.Sprintf("the ready pods %s have no ready endpoint yet", strings.Join(missing, ", ")))
+	default:
+		check.Status = checkPass
+	}
+
+	return check
+}
+
+// checkIngresses checks the Ingresses routing to a Service: the ports of their backends and their IngressClass.
+func checkIngresses(service corev1.Service, ingressResources, ingressClassResources []*unstructured.Unstructured) (connectivityCheck, error) {
+	ingresses, err := fromUnstructuredList[networkingv1.Ingress](ingressResources)
+	if err != nil {
+		return connectivityCheck{}, fmt.Errorf("failed to convert unstructured object to Ingress: %w", err)
+	}
+	ingressClasses, err := fromUnstructuredList[networkingv1.IngressClass](ingressClassResources)
+	if err != nil {
+		return connectivityCheck{}, fmt.Errorf("failed to convert unstructured object to IngressClass: %w", err)
+	}
+
+	check := connectivityCheck{Name: "ingresses", Status: checkPass}
+	for _, ingress := range ingresses {
+		routes := ingressRoutes(ingress, service.Name)
+		if len(routes) == 0 {
+			continue
+		}
+		for _, route := range routes {
+			if servicePortExists(service, route.port) {
+				check.Evidence = append(check.Evidence, fmt.Sprintf("Ingress %s routes %s to port %s", ingress.Name, route.path, serviceBackendPortString(route.port)))
+			} else {
+				check.Status = checkFail
+				check.Evidence = append(check.Evidence, fmt.Sprintf("Ingress %s routes %s to port %s, which is not a port of the Service", ingress.Name, route.path, serviceBackendPortString(route.port)))
+			}
+		}
+
+		status, evidence := checkIngressClass(ingress, ingressClasses)
+		check.Status = worstStatus(check.Status, status)
+		check.Evidence = append(check.Evidence, evidence)
+	}
+	if len(check.Evidence) == 0 {
+		check.Status = checkSkip
+		check.Evidence = []string{"no Ingress routes to the Service"}
+	}
+
+	return check, nil
+}
+
+// ingressRoute is a host and path of an Ingress routed to a port of a Service.
+type ingressRoute struct {
+	path string
+	port networkingv1.ServiceBackendPort
+}
+
+// ingressRoutes returns the routes of an Ingress whose backend is a Service.
+func ingressRoutes(ingress networkingv1.Ingress, serviceName string) []ingressRoute {
+	var routes []ingressRoute
+	if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil && backend.Service.Name == serviceName {
+		routes = append(routes, ingressRoute{path: "the default backend", port: backend.Service.Port})
+	}
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service != nil && path.Backend.Service.Name == serviceName {
+				routes = append(routes, ingressRoute{path: host + path.Path, port: path.Backend.Service.Port})
+			}
+		}
+	}
+
+	return routes
+}
+
+// checkIngressClass checks that the IngressClass of an Ingress exists, or that a default one exists when the Ingress
+// has none.
+func checkIngressClass(ingress networkingv1.Ingress, ingressClasses []networkingv1.IngressClass) (string, string) {
+	className := ingress.Annotations[ingressClassAnnotation]
+	if ingress.Spec.IngressClassName != nil {
+		className = *ingress.Spec.IngressClassName
+	}
+	if className == "" {
+		for _, ingressClass := range ingressClasses {
+			if ingressClass.Annotations[defaultIngressClassAnnotation] == "true" {
+				return checkPass, fmt.Sprintf("Ingress %s has no class and uses the default IngressClass %s of controller %s", ingress.Name, ingressClass.Name, ingressClass.Spec.Controller)
+			}
+		}
+		return checkWarn, fmt.Sprintf("Ingress %s has no class and there is no default IngressClass, no controller may serve it", ingress.Name)
+	}
+	for _, ingressClass := range ingressClasses {
+		if ingressClass.Name == className {
+			return checkPass, fmt.Sprintf("Ingress %s uses the IngressClass %s of controller %s", ingress.Name, className, ingressClass.Spec.Controller)
+		}
+	}
+
+	return checkFail, fmt.Sprintf("Ingress %s uses the IngressClass %s, which doesn't exist", ingress.Name, className)
+}
+
+// checkNetworkPolicies checks whether the NetworkPolicies allow the traffic from a source to the target ports of the
+// pods of a Service: the egress policies selecting the source and the ingress policies selecting the pods must both
+// allow it. Without source, it lists the policies selecting the pods.
+func checkNetworkPolicies(service corev1.Service, pods []corev1.Pod, policies []networkingv1.NetworkPolicy, source *netpol.Peer, params analyzeServiceConnectivityParams) (connectivityCheck, error) {
+	check := connectivityCheck{Name: "networkPolicies"}
+	if len(pods) == 0 {
+		check.Status = checkSkip
+		check.Evidence = []string{"no pod is selected by the Service"}
+		return check, nil
+	}
+
+	if source == nil {
+		selecting := map[string]bool{}
+		for _, pod := range pods {
+			result, err := netpol.Ingress(policies, netpol.Peer{}, podPeer(pod), 0, corev1.ProtocolTCP)
+			if err != nil {
+				return connectivityCheck{}, err
+			}
+			for _, policy := range result.Policies {
+				selecting[policy] = true
+			}
+		}
+		if len(selecting) == 0 {
+			check.Status = checkPass
+			check.Evidence = []string{"no NetworkPolicy selects the pods, their ingress traffic is not restricted"}
+			return check, nil
+		}
+		names := make([]string, 0, len(selecting))
+		for name := range selecting {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		check.Status = checkSkip
+		check.Evidence = []string{fmt.Sprintf("the NetworkPolicies %s select the pods, set sourceNamespace to evaluate them", strings.Join(names, ", "))}
+		return check, nil
+	}
+
+	sourceName := "pods without labels of namespace " + params.SourceNamespace
+	if params.SourcePod != "" {
+		sourceName = "pod " + params.SourceNamespace + "/" + params.SourcePod
+	}
+	allowed, denied := 0, 0
+	for _, pod := range pods {
+		for _, port := range service.Spec.Ports {
+			containerPort, ok := resolveTargetPort(pod, port)
+			if !ok {
+				continue
+			}
+			protocol := servicePortProtocol(port)
+			egress, err := netpol.Egress(policies, *source, podPeer(pod), containerPort, protocol)
+			if err != nil {
+				return connectivityCheck{}, err
+			}
+			ingress, err := netpol.Ingress(policies, *source, podPeer(pod), containerPort, protocol)
+			if err != nil {
+				return connectivityCheck{}, err
+			}
+			matchedRules := append(egress.MatchedRules, ingress.MatchedRules...)
+			switch {
+			case egress.Allowed && ingress.Allowed && len(matchedRules) == 0:
+				allowed++
+				check.Evidence = append(check.Evidence, fmt.Sprintf("traffic from %s to pod %s on %d/%s is allowed, no NetworkPolicy selects the source or the pod", sourceName, pod.Name, containerPort, protocol))
+			case egress.Allowed && ingress.Allowed:
+				allowed++
+				check.Evidence = append(check.Evidence, fmt.Sprintf("traffic from %s to pod %s on %d/%s is allowed by %s", sourceName, pod.Name, containerPort, protocol, strings.Join(matchedRules, ", ")))
+			default:
+				denied++
+				var reasons []string
+				if !egress.Allowed {
+					reasons = append(reasons, fmt.Sprintf("the source is selected by the egress policies %s and none of their rules allow it", strings.Join(egress.Policies, ", ")))
+				}
+				if !ingress.Allowed {
+					reasons = append(reasons, fmt.Sprintf("the pod is selected by %s and none of their rules allow it", strings.Join(ingress.Policies, ", ")))
+				}
+				check.Evidence = append(check.Evidence, fmt.Sprintf("traffic from %s to pod %s on %d/%s is denied, %s", sourceName, pod.Name, containerPort, protocol, strings.Join(reasons, "; ")))
+			}
+		}
+	}
+	switch {
+	case denied == 0:
+		check.Status = checkPass
+	case allowed == 0:
+		check.Status = checkFail
+	default:
+		check.Status = checkWarn
+	}
+
+	return check, nil
+}
+
+// podPeer returns a pod as a NetworkPolicy peer.
+func podPeer(pod corev1.Pod) netpol.Peer {
+	peer := netpol.Peer{Namespace: pod.Namespace, Labels: pod.Labels, IP: pod.Status.PodIP}
+	for _, container := range pod.Spec.Containers {
+		peer.Ports = append(peer.Ports, container.Ports...)
+	}
+
+	return peer
+}
+
+// targetPort returns the target port of a Service port, which defaults to its port.
+func targetPort(port corev1.ServicePort) intstr.IntOrString {
+	if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+		return intstr.FromInt32(port.Port)
+	}
+
+	return port.TargetPort
+}
+
+// resolveTargetPort returns the number of the target port of a Service port on a pod. Named target ports only
+// resolve on pods with a container port of that name.
+func resolveTargetPort(pod corev1.Pod, port corev1.ServicePort) (int32, bool) {
+	target := targetPort(port)
+	if target.Type == intstr.Int {
+		return target.IntVal, true
+	}
+	containerPort, _, ok := findContainerPort(pod, target, servicePortProtocol(port))
+
+	return containerPort.ContainerPort, ok
+}
+
+// findContainerPort returns the container port of a pod matching a target port, by name or number, and protocol.
+func findContainerPort(pod corev1.Pod, target intstr.IntOrString, protocol corev1.Protocol) (corev1.ContainerPort, string, bool) {
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if containerProtocol(containerPort) != protocol {
+				continue
+			}
+			if (target.Type == intstr.String && containerPort.Name == target.StrVal) || (target.Type == intstr.Int && containerPort.ContainerPort == target.IntVal) {
+				return containerPort, container.Name, true
+			}
+		}
+	}
+
+	return corev1.ContainerPort{}, "", false
+}
+
+// containerProtocol returns the protocol of a container port, TCP when unset.
+func containerProtocol(port corev1.ContainerPort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return port.Protocol
+}
+
+// servicePortProtocol returns the protocol of a Service port, TCP when unset.
+func servicePortProtocol(port corev1.ServicePort) corev1.Protocol {
+	if port.Protocol == "" {
+		return corev1.ProtocolTCP
+	}
+
+	return port.Protocol
+}
+
+// servicePortName describes a Service port by its name, when it has one, and number.
+func servicePortName(port corev1.ServicePort) string {
+	if port.Name == "" {
+		return fmt.Sprintf("%d/%s", port.Port, servicePortProtocol(port))
+	}
+
+	return fmt.Sprintf("%s (%d/%s)", port.Name, port.Port, servicePortProtocol(port))
+}
+
+// servicePortExists reports whether the port of an Ingress backend is a port of a Service.
+func servicePortExists(service corev1.Service, port networkingv1.ServiceBackendPort) bool {
+	for _, servicePort := range service.Spec.Ports {
+		if (port.Name != "" && servicePort.Name == port.Name) || (port.Name == "" && servicePort.Port == port.Number) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serviceBackendPortString formats the port of an Ingress backend.
+func serviceBackendPortString(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+
+	return fmt.Sprintf("%d", port.Number)
+}
+
+// podCondition returns a condition of a pod, nil if it isn't set.
+func podCondition(pod corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+// podReady reports whether a pod is ready and not terminating.
+func podReady(pod corev1.Pod) bool {
+	condition := podCondition(pod, corev1.PodReady)
+
+	return pod.DeletionTimestamp == nil && condition != nil && condition.Status == corev1.ConditionTrue
+}
+
+// podNames returns the names of pods.
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+
+	return names
+}
+
+// worstStatus returns the most severe of two check statuses.
+func worstStatus(a, b string) string {
+	severity := map[string]int{checkSkip: 0, checkPass: 1, checkWarn: 2, checkFail: 3}
+	if severity[b] > severity[a] {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,249 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+func connectivityScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = discoveryv1.AddToScheme(scheme)
+	_ = networkingv1.AddToScheme(scheme)
+	return scheme
+}
+
+func newBackendPod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: map[string]string{"app": "web"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "app",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status, Reason: "ContainersNotReady"}},
+		},
+	}
+}
+
+func newEndpointSlice(pods ...string) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta:  metav1.ObjectMeta{Name: "web-abc", Namespace: "shop", Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Ports:       []discoveryv1.EndpointPort{{Name: ptr.To("http"), Port: ptr.To(int32(8080))}},
+	}
+	for _, pod := range pods {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{"10.0.0.1"},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(true)},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Name: pod, Namespace: "shop"},
+		})
+	}
+	return slice
+}
+
+func TestAnalyzeServiceConnectivity(t *testing.T) {
+	fakeToken := "fakeToken"
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "web"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http")}},
+		},
+	}
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop"},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("nginx"),
+			Rules: []networkingv1.IngressRule{{Host: "shop.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+				Paths: []networkingv1.HTTPIngressPath{{Path: "/", Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+					Name: "web", Port: networkingv1.ServiceBackendPort{Number: 8080},
+				}}}},
+			}}}},
+		},
+	}
+	denyAll := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: "shop"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+	}
+	fromFrontend := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "from-frontend", Namespace: "shop"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "frontend"}},
+			}}}},
+		},
+	}
+	denyEgress := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: "storefront"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+	}
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	tests := map[string]struct {
+		objs           []runtime.Object
+		params         analyzeServiceConnectivityParams
+		expectedResult serviceConnectivityResult
+	}{
+		"healthy service": {
+			objs:   []runtime.Object{service, newBackendPod("web-1", true), newEndpointSlice("web-1")},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Passed:  true,
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkPass, Evidence: []string{"the selector app=web matches 1 pods: web-1"}},
+					{Name: "podReadiness", Status: checkPass, Evidence: []string{"1 of 1 pods are ready"}},
+					{Name: "targetPorts", Status: checkPass, Evidence: []string{"port http (80/TCP) targets http, declared by web-1/app:8080"}},
+					{Name: "endpointSlices", Status: checkPass, Evidence: []string{"EndpointSlice web-abc has 1 ready and 0 not ready endpoints on ports [8080]"}},
+					{Name: "networkPolicies", Status: checkPass, Evidence: []string{"no NetworkPolicy selects the pods, their ingress traffic is not restricted"}},
+					{Name: "ingresses", Status: checkSkip, Evidence: []string{"no Ingress routes to the Service"}},
+				},
+			},
+		},
+		"no ready pod and a misconfigured Ingress": {
+			objs:   []runtime.Object{service, newBackendPod("web-1", false), newEndpointSlice(), ingress},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkPass, Evidence: []string{"the selector app=web matches 1 pods: web-1"}},
+					{Name: "podReadiness", Status: checkFail, Evidence: []string{"0 of 1 pods are ready", "pod web-1 is not ready (phase Running): ContainersNotReady"}},
+					{Name: "targetPorts", Status: checkPass, Evidence: []string{"port http (80/TCP) targets http, declared by web-1/app:8080"}},
+					{Name: "endpointSlices", Status: checkFail, Evidence: []string{"EndpointSlice web-abc has 0 ready and 0 not ready endpoints on ports [8080]"}},
+					{Name: "networkPolicies", Status: checkPass, Evidence: []string{"no NetworkPolicy selects the pods, their ingress traffic is not restricted"}},
+					{Name: "ingresses", Status: checkFail, Evidence: []string{
+						"Ingress web routes shop.example.com/ to port 8080, which is not a port of the Service",
+						"Ingress web uses the IngressClass nginx, which doesn't exist",
+					}},
+				},
+			},
+		},
+		"selector matching no pod": {
+			objs:   []runtime.Object{service},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkFail, Evidence: []string{"the selector app=web matches no pod in namespace shop"}},
+					{Name: "podReadiness", Status: checkSkip, Evidence: []string{"no pod is selected by the Service"}},
+					{Name: "targetPorts", Status: checkSkip, Evidence: []string{"no pod is selected by the Service"}},
+					{Name: "endpointSlices", Status: checkFail, Evidence: []string{"no EndpointSlice exists for the Service"}},
+					{Name: "networkPolicies", Status: checkSkip, Evidence: []string{"no pod is selected by the Service"}},
+					{Name: "ingresses", Status: checkSkip, Evidence: []string{"no Ingress routes to the Service"}},
+				},
+			},
+		},
+		"traffic allowed from a source namespace": {
+			objs: []runtime.Object{service, newBackendPod("web-1", true), newEndpointSlice("web-1"), fromFrontend,
+				namespace("storefront", map[string]string{"team": "frontend"})},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web", SourceNamespace: "storefront"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Passed:  true,
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkPass, Evidence: []string{"the selector app=web matches 1 pods: web-1"}},
+					{Name: "podReadiness", Status: checkPass, Evidence: []string{"1 of 1 pods are ready"}},
+					{Name: "targetPorts", Status: checkPass, Evidence: []string{"port http (80/TCP) targets http, declared by web-1/app:8080"}},
+					{Name: "endpointSlices", Status: checkPass, Evidence: []string{"EndpointSlice web-abc has 1 ready and 0 not ready endpoints on ports [8080]"}},
+					{Name: "networkPolicies", Status: checkPass, Evidence: []string{"traffic from pods without labels of namespace storefront to pod web-1 on 8080/TCP is allowed by shop/from-frontend ingress[0]"}},
+					{Name: "ingresses", Status: checkSkip, Evidence: []string{"no Ingress routes to the Service"}},
+				},
+			},
+		},
+		"traffic denied by the egress policies of the source namespace": {
+			objs: []runtime.Object{service, newBackendPod("web-1", true), newEndpointSlice("web-1"), fromFrontend, denyEgress,
+				namespace("storefront", map[string]string{"team": "frontend"})},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web", SourceNamespace: "storefront"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkPass, Evidence: []string{"the selector app=web matches 1 pods: web-1"}},
+					{Name: "podReadiness", Status: checkPass, Evidence: []string{"1 of 1 pods are ready"}},
+					{Name: "targetPorts", Status: checkPass, Evidence: []string{"port http (80/TCP) targets http, declared by web-1/app:8080"}},
+					{Name: "endpointSlices", Status: checkPass, Evidence: []string{"EndpointSlice web-abc has 1 ready and 0 not ready endpoints on ports [8080]"}},
+					{Name: "networkPolicies", Status: checkFail, Evidence: []string{"traffic from pods without labels of namespace storefront to pod web-1 on 8080/TCP is denied, the source is selected by the egress policies storefront/deny-egress and none of their rules allow it"}},
+					{Name: "ingresses", Status: checkSkip, Evidence: []string{"no Ingress routes to the Service"}},
+				},
+			},
+		},
+		"traffic denied from a source pod": {
+			objs: []runtime.Object{service, newBackendPod("web-1", true), newEndpointSlice("web-1"), denyAll, fromFrontend,
+				namespace("batch", nil),
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-1", Namespace: "batch", Labels: map[string]string{"app": "job"}}}},
+			params: analyzeServiceConnectivityParams{Cluster: "local", Namespace: "shop", Name: "web", SourceNamespace: "batch", SourcePod: "job-1"},
+			expectedResult: serviceConnectivityResult{
+				Service: "shop/web",
+				Checks: []connectivityCheck{
+					{Name: "selector", Status: checkPass, Evidence: []string{"the selector app=web matches 1 pods: web-1"}},
+					{Name: "podReadiness", Status: checkPass, Evidence: []string{"1 of 1 pods are ready"}},
+					{Name: "targetPorts", Status: checkPass, Evidence: []string{"port http (80/TCP) targets http, declared by web-1/app:8080"}},
+					{Name: "endpointSlices", Status: checkPass, Evidence: []string{"EndpointSlice web-abc has 1 ready and 0 not ready endpoints on ports [8080]"}},
+					{Name: "networkPolicies", Status: checkFail, Evidence: []string{"traffic from pod batch/job-1 to pod web-1 on 8080/TCP is denied, the pod is selected by shop/deny-all, shop/from-frontend and none of their rules allow it"}},
+					{Name: "ingresses", Status: checkSkip, Evidence: []string{"no Ingress routes to the Service"}},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(connectivityScheme(), tt.objs...)
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.analyzeServiceConnectivity(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			require.NoError(t, err)
+			var resp struct {
+				LLM serviceConnectivityResult `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedResult, resp.LLM)
+		})
+	}
+}
+
+func TestCheckTargetPorts(t *testing.T) {
+	pod := *newBackendPod("web-1", true)
+	service := corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+		{Name: "metrics", Port: 9090},
+		{Name: "grpc", Port: 443, TargetPort: intstr.FromString("grpc")},
+	}}}
+
+	check := checkTargetPorts(service, []corev1.Pod{pod})
+
+	assert.Equal(t, connectivityCheck{Name: "targetPorts", Status: checkFail, Evidence: []string{
+		"port metrics (9090/TCP) targets 9090/TCP, which is not declared by the containers of pods web-1. The traffic still reaches them if a process listens on it",
+		"port grpc (443/TCP) targets the named port grpc/TCP, which is not a port of pods web-1",
+	}}, check)
+}
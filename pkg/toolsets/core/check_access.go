@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var zapCheckAccess = zap.String("tool", "checkAccess")
+
+// accessParams describes an action on a resource of a cluster.
+type accessParams struct {
+	Cluster     string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Verb        string `json:"verb" jsonschema:"the verb of the action (e.g. get, list, watch, create, update, patch, delete)"`
+	Group       string `json:"group,omitempty" jsonschema:"the API group of the resource, empty for the core group (e.g. apps for deployments)"`
+	Resource    string `json:"resource" jsonschema:"the plural name of the resource (e.g. pods, deployments)"`
+	Subresource string `json:"subresource,omitempty" jsonschema:"the subresource (e.g. log, scale, eviction)"`
+	Namespace   string `json:"namespace,omitempty" jsonschema:"the namespace of the resource. It must be empty for cluster-wide resources or for actions on all namespaces"`
+	Name        string `json:"name,omitempty" jsonschema:"the name of the resource. Rules restricted to resource names only apply when it is set"`
+}
+
+// attributes returns the action described by the parameters.
+func (p accessParams) attributes() rbac.Attributes {
+	return rbac.Attributes{Verb: p.Verb, Group: p.Group, Resource: p.Resource, Subresource: p.Subresource, Name: p.Name}
+}
+
+// validate returns an error if the action is incomplete.
+func (p accessParams) validate() error {
+	if p.Verb == "" || p.Resource == "" {
+		return fmt.Errorf("verb and resource must be set")
+	}
+
+	return nil
+}
+
+// accessReview is the response of the checkAccess tool.
+type accessReview struct {
+	Allowed         bool   `json:"allowed"`
+	Action          string `json:"action"`
+	Reason          string `json:"reason,omitempty"`
+	EvaluationError string `json:"evaluationError,omitempty"`
+	// Rules are the rules of the caller in RulesNamespace that cover the resource, whatever their verbs.
+	Rules []authorizationv1.ResourceRule `json:"rules"`
+	// RulesNamespace is the namespace the rules were reviewed in, the default namespace for cluster-wide actions.
+	RulesNamespace  string `json:"rulesNamespace"`
+	RulesIncomplete bool   `json:"rulesIncomplete,omitempty"`
+	RulesNote       string `json:"rulesNote,omitempty"`
+	Summary         string `json:"summary"`
+}
+
+// checkAccess checks whether the caller is allowed to perform an action with a SelfSubjectAccessReview, and lists the
+// rules of the caller covering the resource with a SelfSubjectRulesReview.
+func (t *Tools) checkAccess(ctx context.Context, toolReq *mcp.CallToolRequest, params accessParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("checkAccess called")
+
+	if err := params.validate(); err != nil {
+		return nil, nil, err
+	}
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zapCheckAccess, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	attrs := params.attributes()
+	status, err := selfSubjectAccessReview(ctx, clientset, params.Namespace, attrs)
+	if err != nil {
+		zap.L().Error("failed to create SelfSubjectAccessReview", zapCheckAccess, zap.Error(err))
+		return nil, nil, err
+	}
+
+	// rules are reviewed per namespace, the default namespace still returns the rules granted cluster-wide, along with
+	// the ones granted in the default namespace only, which can't be told apart.
+	rulesNamespace := params.Namespace
+	rulesNote := ""
+	if rulesNamespace == "" {
+		rulesNamespace = metav1.NamespaceDefault
+		rulesNote = "The rules are those of namespace default: they include the rules granted cluster-wide, but also the ones granted in namespace default only, which don't apply to other namespaces or to cluster-wide actions."
+	}
+	rulesReview, err := clientset.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: rulesNamespace},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		zap.L().Error("failed to create SelfSubjectRulesReview", zapCheckAccess, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to review the rules of namespace %s: %w", rulesNamespace, err)
+	}
+
+	result := accessReview{
+		Allowed:         status.Allowed,
+		Action:          attrs.String() + actionLocation(params.Cluster, params.Namespace),
+		Reason:          status.Reason,
+		EvaluationError: status.EvaluationError,
+		Rules:           []authorizationv1.ResourceRule{},
+		RulesNamespace:  rulesNamespace,
+		RulesIncomplete: rulesReview.Status.Incomplete,
+		RulesNote:       rulesNote,
+	}
+	for _, rule := range rulesReview.Status.ResourceRules {
+		if rbac.AppliesTo(rbacv1.PolicyRule{APIGroups: rule.APIGroups, Resources: rule.Resources}, attrs) {
+			result.Rules = append(result.Rules, rule)
+		}
+	}
+	if result.Allowed {
+		result.Summary = fmt.Sprintf("You are allowed to %s.", result.Action)
+	} else {
+		result.Summary = fmt.Sprintf("You are not allowed to %s. Use whoCan to find who is allowed, and ask a cluster or project owner to grant you a role allowing it.", result.Action)
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapCheckAccess, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// selfSubjectAccessReview reviews whether the caller is allowed to perform an action in a namespace.
+func selfSubjectAccessReview(ctx context.Context, clientset kubernetes.Interface, namespace string, attrs rbac.Attributes) (authorizationv1.SubjectAccessReviewStatus, error) {
+	review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        attrs.Verb,
+				Group:       attrs.Group,
+				Resource:    attrs.Resource,
+				Subresource: attrs.Subresource,
+				Name:        attrs.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return authorizationv1.SubjectAccessReviewStatus{}, fmt.Errorf("failed to review access to %s: %w", attrs, err)
+	}
+
+	return review.Status, nil
+}
+
+// requirePermission returns a permission error when the caller is not allowed to perform an action, so that write
+// tools fail before doing anything with an error telling what is missing. When the access can't be reviewed the action
+// isn't prevented, the API server still authorizes it.
+func requirePermission(ctx context.Context, clientset kubernetes.Interface, cluster, namespace string, attrs rbac.Attributes) error {
+	if attrs.Resource == "" {
+		return nil
+	}
+	status, err := selfSubjectAccessReview(ctx, clientset, namespace, attrs)
+	if err != nil {
+		zap.L().Debug("skipping the permission check", zap.Error(err))
+		return nil
+	}
+	if status.Allowed {
+		return nil
+	}
+
+	reason := ""
+	if status.Reason != "" {
+		reason = fmt.Sprintf(" (%s)", status.Reason)
+	}
+
+	return fmt.Errorf("permission denied: you are not allowed to %s%s%s, use checkAccess to review your permissions or whoCan to find who is allowed",
+		attrs, actionLocation(cluster, namespace), reason)
+}
+
+// requireClusterPermission is requirePermission for the tools without a clientset.
+func (t *Tools) requireClusterPermission(ctx context.Context, cluster, namespace string, attrs rbac.Attributes) error {
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), cluster)
+	if err != nil {
+		zap.L().Debug("skipping the permission check", zap.Error(err))
+		return nil
+	}
+
+	return requirePermission(ctx, clientset, cluster, namespace, attrs)
+}
+
+// actionLocation returns where an action applies, e.g. " in namespace shop of cluster local".
+func actionLocation(cluster, namespace string) string {
+	if namespace == "" {
+		return " in cluster " + cluster
+	}
+
+	return fmt.Sprintf(" in namespace %s of cluster %s", namespace, cluster)
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// withAccessReviews makes the SelfSubjectAccessReviews of the fake clientset allow or deny every action.
+func withAccessReviews(clientset *fake.Clientset, allowed bool) *fake.Clientset {
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview).DeepCopy()
+		review.Status.Allowed = allowed
+		if !allowed {
+			review.Status.Reason = "no RBAC policy matched"
+		}
+
+		return true, review, nil
+	})
+
+	return clientset
+}
+
+func TestCheckAccess(t *testing.T) {
+	fakeToken := "fakeToken"
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}},
+		{Verbs: []string{"*"}, APIGroups: []string{""}, Resources: []string{"configmaps"}},
+		{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments"}, ResourceNames: []string{"web"}},
+	}
+
+	tests := map[string]struct {
+		params                 accessParams
+		allowed                bool
+		expectedResult         accessReview
+		expectedRulesNamespace string
+		expectedError          string
+	}{
+		"allowed": {
+			params:  accessParams{Cluster: "local", Verb: "patch", Group: "apps", Resource: "deployments", Namespace: "shop", Name: "web"},
+			allowed: true,
+			expectedResult: accessReview{
+				Allowed:        true,
+				Action:         "patch deployments.apps/web in namespace shop of cluster local",
+				Rules:          []authorizationv1.ResourceRule{rules[0], rules[2]},
+				RulesNamespace: "shop",
+				Summary:        "You are allowed to patch deployments.apps/web in namespace shop of cluster local.",
+			},
+			expectedRulesNamespace: "shop",
+		},
+		"denied": {
+			params: accessParams{Cluster: "local", Verb: "delete", Resource: "secrets"},
+			expectedResult: accessReview{
+				Action:         "delete secrets in cluster local",
+				Reason:         "no RBAC policy matched",
+				Rules:          []authorizationv1.ResourceRule{},
+				RulesNamespace: "default",
+				RulesNote:      "The rules are those of namespace default: they include the rules granted cluster-wide, but also the ones granted in namespace default only, which don't apply to other namespaces or to cluster-wide actions.",
+				Summary:        "You are not allowed to delete secrets in cluster local. Use whoCan to find who is allowed, and ask a cluster or project owner to grant you a role allowing it.",
+			},
+			expectedRulesNamespace: "default",
+		},
+		"missing verb": {
+			params:        accessParams{Cluster: "local", Resource: "secrets"},
+			expectedError: "verb and resource must be set",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClientset := withAccessReviews(fake.NewClientset(), tt.allowed)
+			var rulesNamespace string
+			fakeClientset.PrependReactor("create", "selfsubjectrulesreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectRulesReview).DeepCopy()
+				rulesNamespace = review.Spec.Namespace
+				review.Status.ResourceRules = rules
+				return true, review, nil
+			})
+			tools := newClientSetTools(fakeClientset, fakeToken)
+
+			result, _, err := tools.checkAccess(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM accessReview `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedResult, resp.LLM)
+			assert.Equal(t, tt.expectedRulesNamespace, rulesNamespace)
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	attrs := rbac.Attributes{Verb: "patch", Resource: "nodes", Name: "node-1"}
+
+	assert.NoError(t, requirePermission(t.Context(), withAccessReviews(fake.NewClientset(), true), "local", "", attrs))
+	assert.EqualError(t, requirePermission(t.Context(), withAccessReviews(fake.NewClientset(), false), "local", "", attrs),
+		"permission denied: you are not allowed to patch nodes/node-1 in cluster local (no RBAC policy matched), use checkAccess to review your permissions or whoCan to find who is allowed")
+}
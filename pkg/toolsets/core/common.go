@@ -1,5 +1,26 @@
 package core
 
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
 const (
 	LocalCluster = "local"
 )
+
+// newKeyedObject returns an object holding value under key. The object has no kind, so it is returned to the LLM
+// alongside the resources of a tool without adding them a UI context.
+func newKeyedObject(key string, value any) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var content any
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{key: content}}, nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeParams identifies the node a maintenance operation is applied to.
+type nodeParams struct {
+	Cluster string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Name    string `json:"name" jsonschema:"the name of the node"`
+}
+
+// cordonNode marks a node as unschedulable, so that no new pod is scheduled on it.
+func (t *Tools) cordonNode(ctx context.Context, toolReq *mcp.CallToolRequest, params nodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("cordonNode called")
+
+	return t.setUnschedulable(ctx, params, true, "cordonNode")
+}
+
+// cordonNodePlan returns the patch that cordonNode would apply without applying it.
+func (t *Tools) cordonNodePlan(ctx context.Context, toolReq *mcp.CallToolRequest, params nodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("cordonNodePlan called")
+
+	return t.setUnschedulablePlan(ctx, params, true, "cordonNodePlan")
+}
+
+// uncordonNode marks a node as schedulable again.
+func (t *Tools) uncordonNode(ctx context.Context, toolReq *mcp.CallToolRequest, params nodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("uncordonNode called")
+
+	return t.setUnschedulable(ctx, params, false, "uncordonNode")
+}
+
+// uncordonNodePlan returns the patch that uncordonNode would apply without applying it.
+func (t *Tools) uncordonNodePlan(ctx context.Context, toolReq *mcp.CallToolRequest, params nodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("uncordonNodePlan called")
+
+	return t.setUnschedulablePlan(ctx, params, false, "uncordonNodePlan")
+}
+
+// setUnschedulable sets spec.unschedulable of the node and returns the modified node.
+func (t *Tools) setUnschedulable(ctx context.Context, params nodeParams, unschedulable bool, tool string) (*mcp.CallToolResult, any, error) {
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, params.Name, metav1.GetOptions{})
+	if err != nil {
+		zap.L().Error("failed to get node", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+	if err := checkUnschedulable(node, unschedulable); err != nil {
+		return nil, nil, err
+	}
+	if err := requirePermission(ctx, clientset, params.Cluster, "", patchNodeAttributes(params.Name)); err != nil {
+		return nil, nil, err
+	}
+
+	node, err = patchUnschedulable(ctx, clientset, params.Name, unschedulable)
+	if err != nil {
+		zap.L().Error("failed to patch node", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	obj, err := nodeToUnstructured(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	mcpResponse, err := response.CreateMcpResponse([]*unstructured.Unstructured{obj}, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// setUnschedulablePlan returns the plan to set spec.unschedulable of the node.
+func (t *Tools) setUnschedulablePlan(ctx context.Context, params nodeParams, unschedulable bool, tool string) (*mcp.CallToolResult, any, error) {
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, params.Name, metav1.GetOptions{})
+	if err != nil {
+		zap.L().Error("failed to get node", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+	if err := checkUnschedulable(node, unschedulable); err != nil {
+		return nil, nil, err
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{{
+		Type:    response.OperationUpdate,
+		Payload: json.RawMessage(unschedulablePatch(unschedulable)),
+		Resource: response.Resource{
+			Name:    params.Name,
+			Kind:    "Node",
+			Cluster: params.Cluster,
+		},
+	}})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// checkUnschedulable returns an error if the node is already in the requested state.
+func checkUnschedulable(node *corev1.Node, unschedulable bool) error {
+	switch {
+	case unschedulable && node.Spec.Unschedulable:
+		return fmt.Errorf("node '%s' is already cordoned", node.Name)
+	case !unschedulable && !node.Spec.Unschedulable:
+		return fmt.Errorf("node '%s' is not cordoned", node.Name)
+	}
+
+	return nil
+}
+
+// patchNodeAttributes returns the action of patching a node.
+func patchNodeAttributes(name string) rbac.Attributes {
+	return rbac.Attributes{Verb: "patch", Resource: "nodes", Name: name}
+}
+
+// unschedulablePatch returns the JSON Patch setting spec.unschedulable of a node.
+func unschedulablePatch(unschedulable bool) []byte {
+	return fmt.Appendf(nil, `[{"op":"add","path":"/spec/unschedulable","value":%t}]`, unschedulable)
+}
+
+// patchUnschedulable sets spec.unschedulable of the node.
+func patchUnschedulable(ctx context.Context, clientset kubernetes.Interface, name string, unschedulable bool) (*corev1.Node, error) {
+	node, err := clientset.CoreV1().Nodes().Patch(ctx, name, types.JSONPatchType, unschedulablePatch(unschedulable), metav1.PatchOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch node %s: %w", name, err)
+	}
+
+	return node, nil
+}
+
+// nodeToUnstructured converts a node returned by the clientset, which has no type meta, to an unstructured Node.
+func nodeToUnstructured(node *corev1.Node) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert node %s: %w", node.Name, err)
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("Node")
+
+	return obj, nil
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newNode(name string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+	}
+}
+
+func newClientSetTools(fakeClientset kubernetes.Interface, token string) *Tools {
+	c := &client.Client{
+		ClientSetCreator: func(inConfig *rest.Config) (kubernetes.Interface, error) {
+			return fakeClientset, nil
+		},
+	}
+
+	return NewTools(test.WrapClient(c, token), false)
+}
+
+func TestCordonNode(t *testing.T) {
+	fakeToken := "fakeToken"
+	tests := map[string]struct {
+		node                  *corev1.Node
+		call                  func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error)
+		expectedResult        string
+		expectedUnschedulable bool
+		expectedError         string
+	}{
+		"cordon": {
+			node: newNode("node-1", false),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.cordonNode(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedUnschedulable: true,
+		},
+		"cordon a cordoned node": {
+			node: newNode("node-1", true),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.cordonNode(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedUnschedulable: true,
+			expectedError:         "node 'node-1' is already cordoned",
+		},
+		"uncordon": {
+			node: newNode("node-1", true),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.uncordonNode(ctx, &mcp.CallToolRequest{}, params)
+			},
+		},
+		"uncordon a schedulable node": {
+			node: newNode("node-1", false),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.uncordonNode(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedError: "node 'node-1' is not cordoned",
+		},
+		"cordon plan": {
+			node: newNode("node-1", false),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.cordonNodePlan(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedResult: `[{"type": "update", "payload": [{"op": "add", "path": "/spec/unschedulable", "value": true}], "resource": {"name": "node-1", "kind": "Node", "cluster": "local", "namespace": ""}}]`,
+		},
+		"uncordon plan": {
+			node: newNode("node-1", true),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.uncordonNodePlan(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedUnschedulable: true,
+			expectedResult:        `[{"type": "update", "payload": [{"op": "add", "path": "/spec/unschedulable", "value": false}], "resource": {"name": "node-1", "kind": "Node", "cluster": "local", "namespace": ""}}]`,
+		},
+		"node not found": {
+			node: newNode("node-2", false),
+			call: func(ctx context.Context, tools *Tools, params nodeParams) (*mcp.CallToolResult, any, error) {
+				return tools.cordonNode(ctx, &mcp.CallToolRequest{}, params)
+			},
+			expectedError: `nodes "node-1" not found`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClientset := withAccessReviews(fake.NewClientset(tt.node), true)
+			tools := newClientSetTools(fakeClientset, fakeToken)
+
+			result, _, err := tt.call(middleware.WithToken(t.Context(), fakeToken), tools, nodeParams{Cluster: "local", Name: "node-1"})
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expectedResult != "" {
+				assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			}
+			node, err := fakeClientset.CoreV1().Nodes().Get(t.Context(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedUnschedulable, node.Spec.Unschedulable)
+		})
+	}
+}
+
+func TestCordonNodeWithoutPermission(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeClientset := withAccessReviews(fake.NewClientset(newNode("node-1", false)), false)
+	tools := newClientSetTools(fakeClientset, fakeToken)
+
+	_, _, err := tools.cordonNode(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, nodeParams{Cluster: "local", Name: "node-1"})
+
+	assert.ErrorContains(t, err, "permission denied: you are not allowed to patch nodes/node-1 in cluster local")
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rancher/rancher-ai-mcp/internal/middleware"
 	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
 	"github.com/rancher/rancher-ai-mcp/pkg/response"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,12 +29,16 @@ type createKubernetesResourceParams struct {
 func (t *Tools) createKubernetesResource(ctx context.Context, toolReq *mcp.CallToolRequest, params createKubernetesResourceParams) (*mcp.CallToolResult, any, error) {
 	zap.L().Debug("createKubernetesResource called")
 
+	gvr := converter.K8sKindsToGVRs[strings.ToLower(params.Kind)]
 	resourceInterface, err := t.client.GetResourceInterface(
 		ctx, middleware.Token(ctx),
-		params.Namespace, params.Cluster, converter.K8sKindsToGVRs[strings.ToLower(params.Kind)])
+		params.Namespace, params.Cluster, gvr)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := t.requireClusterPermission(ctx, params.Cluster, params.Namespace, rbac.Attributes{Verb: "create", Group: gvr.Group, Resource: gvr.Resource}); err != nil {
+		return nil, nil, err
+	}
 
 	objBytes, err := json.Marshal(params.Resource)
 	if err != nil {
@@ -14,6 +14,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
 )
 
@@ -138,6 +140,9 @@ func TestCreateKubernetesResource(t *testing.T) {
 				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
 					return tt.fakeDynClient, nil
 				},
+				ClientSetCreator: func(inConfig *rest.Config) (kubernetes.Interface, error) {
+					return withAccessReviews(fake.NewClientset(), true), nil
+				},
 			}
 			tools := NewTools(test.WrapClient(c, fakeToken), false)
 			req := &mcp.CallToolRequest{}
@@ -0,0 +1,388 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+const defaultDrainTimeoutSeconds int64 = 300
+
+// evictionRetryInterval is the time waited between two attempts to evict the pods blocked by a PodDisruptionBudget,
+// and between two checks that the evicted pods are gone.
+var evictionRetryInterval = 5 * time.Second
+
+var zapDrainNode = zap.String("tool", "drainNode")
+
+// drainNodeParams specifies the parameters needed to drain a node.
+type drainNodeParams struct {
+	Cluster        string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Name           string `json:"name" jsonschema:"the name of the node"`
+	TimeoutSeconds int64  `json:"timeoutSeconds,omitempty" jsonschema:"maximum number of seconds to wait for the pods to be evicted, defaults to 300"`
+	Force          bool   `json:"force,omitempty" jsonschema:"evict the pods not managed by a controller too, they are lost for good since nothing recreates them"`
+	// DeleteEmptyDirData is named after the kubectl drain flag.
+	DeleteEmptyDirData bool `json:"deleteEmptyDirData,omitempty" jsonschema:"evict the pods using emptyDir volumes too, the data of their emptyDir volumes is deleted"`
+}
+
+// drainPod is a pod of a drained node.
+type drainPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Reason explains why the pod was skipped, blocked or failed to be evicted.
+	Reason               string   `json:"reason,omitempty"`
+	PodDisruptionBudgets []string `json:"podDisruptionBudgets,omitempty"`
+}
+
+// blockingPDB is a PodDisruptionBudget that doesn't allow all the pods it covers to be evicted.
+type blockingPDB struct {
+	Namespace          string   `json:"namespace"`
+	Name               string   `json:"name"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	Pods               []string `json:"pods"`
+}
+
+// drainNodeResult is the response of the drainNode tool.
+type drainNodeResult struct {
+	Node      string     `json:"node"`
+	Completed bool       `json:"completed"`
+	Evicted   []drainPod `json:"evicted"`
+	Blocked   []drainPod `json:"blocked,omitempty"`
+	Pending   []drainPod `json:"pending,omitempty"`
+	Failed    []drainPod `json:"failed,omitempty"`
+	Skipped   []drainPod `json:"skipped,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// drainNodePlan is the payload of the plan of the drainNode tool.
+type drainNodePlan struct {
+	PodsToEvict                  []drainPod    `json:"podsToEvict"`
+	BlockingPodDisruptionBudgets []blockingPDB `json:"blockingPodDisruptionBudgets"`
+	// BlockedPods are the pods that are not evicted unless force or deleteEmptyDirData is set.
+	BlockedPods []drainPod `json:"blockedPods"`
+	SkippedPods []drainPod `json:"skippedPods"`
+}
+
+// nodePods are the pods of a drained node, sorted by what the drain does with them.
+type nodePods struct {
+	// evict are the pods to evict, sorted by namespace and name.
+	evict []corev1.Pod
+	// skipped are the pods that are never evicted because evicting them is pointless.
+	skipped []drainPod
+	// blocked are the pods whose eviction loses data, they are only evicted when the drain is forced to.
+	blocked []drainPod
+}
+
+// drainNode cordons a node and evicts its pods through the Eviction API, so that PodDisruptionBudgets are respected.
+// DaemonSet, mirror and completed pods are skipped. Like kubectl drain, pods not managed by a controller and pods
+// using emptyDir volumes are only evicted when force and deleteEmptyDirData are set, otherwise they are reported as
+// blocked. Evictions refused by a PodDisruptionBudget are retried until the timeout, the pods still blocked by then
+// are reported with the PodDisruptionBudgets covering them.
+func (t *Tools) drainNode(ctx context.Context, toolReq *mcp.CallToolRequest, params drainNodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("drainNode called")
+
+	timeout := time.Duration(params.TimeoutSeconds) * time.Second
+	if params.TimeoutSeconds <= 0 {
+		timeout = time.Duration(defaultDrainTimeoutSeconds) * time.Second
+	}
+
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zapDrainNode, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	node, err := clientset.CoreV1().Nodes().Get(ctx, params.Name, metav1.GetOptions{})
+	if err != nil {
+		zap.L().Error("failed to get node", zapDrainNode, zap.Error(err))
+		return nil, nil, err
+	}
+	if err := requirePermission(ctx, clientset, params.Cluster, "", patchNodeAttributes(params.Name)); err != nil {
+		return nil, nil, err
+	}
+	if !node.Spec.Unschedulable {
+		if node, err = patchUnschedulable(ctx, clientset, params.Name, true); err != nil {
+			zap.L().Error("failed to cordon node", zapDrainNode, zap.Error(err))
+			return nil, nil, err
+		}
+	}
+
+	pods, err := listNodePods(ctx, clientset, params)
+	if err != nil {
+		zap.L().Error("failed to list pods", zapDrainNode, zap.Error(err))
+		return nil, nil, err
+	}
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		zap.L().Error("failed to list pod disruption budgets", zapDrainNode, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	result, err := evictPods(ctx, clientset, pods.evict, pdbs.Items, timeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Node = params.Name
+	result.Skipped = pods.skipped
+	switch {
+	case len(result.Blocked) > 0 || len(result.Pending) > 0:
+		result.Note = fmt.Sprintf("The node is cordoned but the drain didn't complete within %s. Pods blocked by a PodDisruptionBudget can be evicted once their workload has enough available replicas elsewhere, run drainNode again to retry.", timeout)
+	case len(result.Failed) > 0:
+		result.Note = "The node is cordoned but some of its pods couldn't be evicted."
+	case len(pods.blocked) > 0:
+		result.Note = "The node is cordoned but some of its pods were not evicted: pods not managed by a controller are lost for good and the data of their emptyDir volumes is deleted. Run drainNode again with force or deleteEmptyDirData to evict them."
+	}
+	result.Blocked = append(pods.blocked, result.Blocked...)
+	result.Completed = result.Completed && len(pods.blocked) == 0
+
+	obj, err := nodeToUnstructured(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	mcpResponse, err := response.CreateMcpResponseAny(result, response.NewUIContext(obj, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapDrainNode, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// drainNodePlan returns the pods drainNode would evict and the PodDisruptionBudgets that currently block their
+// eviction, along with the pods it would skip or refuse to evict, without cordoning the node or evicting anything.
+func (t *Tools) drainNodePlan(ctx context.Context, toolReq *mcp.CallToolRequest, params drainNodeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("drainNodePlan called")
+
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zap.String("tool", "drainNodePlan"), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	if _, err := clientset.CoreV1().Nodes().Get(ctx, params.Name, metav1.GetOptions{}); err != nil {
+		zap.L().Error("failed to get node", zap.String("tool", "drainNodePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+	pods, err := listNodePods(ctx, clientset, params)
+	if err != nil {
+		zap.L().Error("failed to list pods", zap.String("tool", "drainNodePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		zap.L().Error("failed to list pod disruption budgets", zap.String("tool", "drainNodePlan"), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to list pod disruption budgets: %w", err)
+	}
+
+	plan := drainNodePlan{
+		PodsToEvict:                  []drainPod{},
+		BlockingPodDisruptionBudgets: blockingPDBs(pods.evict, pdbs.Items),
+		BlockedPods:                  append([]drainPod{}, pods.blocked...),
+		SkippedPods:                  append([]drainPod{}, pods.skipped...),
+	}
+	for _, pod := range pods.evict {
+		plan.PodsToEvict = append(plan.PodsToEvict, drainPod{Namespace: pod.Namespace, Name: pod.Name, PodDisruptionBudgets: matchingPDBs(pod, pdbs.Items)})
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{{
+		Type:    response.OperationUpdate,
+		Payload: plan,
+		Resource: response.Resource{
+			Name:    params.Name,
+			Kind:    "Node",
+			Cluster: params.Cluster,
+		},
+	}})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", "drainNodePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// listNodePods returns the pods of the node sorted by what the drain does with them. DaemonSet pods are skipped
+// because they are recreated on the node right away, mirror pods because they are managed by the kubelet and
+// completed pods because they don't run anymore. Pods not managed by a controller and pods using emptyDir volumes
+// are blocked unless the drain is forced to evict them, like kubectl drain does.
+func listNodePods(ctx context.Context, clientset kubernetes.Interface, params drainNodeParams) (nodePods, error) {
+	podList, err := clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + params.Name})
+	if err != nil {
+		return nodePods{}, fmt.Errorf("failed to list the pods of node %s: %w", params.Name, err)
+	}
+
+	var pods nodePods
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != params.Name {
+			continue
+		}
+		owner := metav1.GetControllerOf(&pod)
+		switch {
+		case pod.Annotations[corev1.MirrorPodAnnotationKey] != "":
+			pods.skipped = append(pods.skipped, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "mirror pod"})
+			continue
+		case owner != nil && owner.Kind == "DaemonSet":
+			pods.skipped = append(pods.skipped, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "managed by DaemonSet " + owner.Name})
+			continue
+		case pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed:
+			pods.skipped = append(pods.skipped, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "completed with phase " + string(pod.Status.Phase)})
+			continue
+		}
+
+		var reasons []string
+		if owner == nil && !params.Force {
+			reasons = append(reasons, "not managed by a controller, it is lost for good once evicted: set force to evict it")
+		}
+		if !params.DeleteEmptyDirData && slices.ContainsFunc(pod.Spec.Volumes, func(volume corev1.Volume) bool { return volume.EmptyDir != nil }) {
+			reasons = append(reasons, "uses emptyDir volumes whose data is deleted once evicted: set deleteEmptyDirData to evict it")
+		}
+		if len(reasons) > 0 {
+			pods.blocked = append(pods.blocked, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: strings.Join(reasons, "; ")})
+			continue
+		}
+		pods.evict = append(pods.evict, pod)
+	}
+	slices.SortFunc(pods.evict, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return pods, nil
+}
+
+// evictPods evicts the pods and waits for them to be gone. Evictions refused because of a PodDisruptionBudget are
+// retried until the timeout.
+func evictPods(ctx context.Context, clientset kubernetes.Interface, pods []corev1.Pod, pdbs []policyv1.PodDisruptionBudget, timeout time.Duration) (drainNodeResult, error) {
+	result := drainNodeResult{Evicted: []drainPod{}}
+	deadline := time.Now().Add(timeout)
+	remaining := pods
+	var evicting []corev1.Pod
+	blocked := map[string]drainPod{}
+
+	for {
+		var retry []corev1.Pod
+		for _, pod := range remaining {
+			err := clientset.CoreV1().Pods(pod.Namespace).EvictV1(ctx, &policyv1.Eviction{
+				ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			})
+			switch {
+			case err == nil:
+				evicting = append(evicting, pod)
+			case apierrors.IsNotFound(err):
+				result.Evicted = append(result.Evicted, drainPod{Namespace: pod.Namespace, Name: pod.Name})
+			case apierrors.IsTooManyRequests(err):
+				blocked[pod.Namespace+"/"+pod.Name] = drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: err.Error(), PodDisruptionBudgets: matchingPDBs(pod, pdbs)}
+				retry = append(retry, pod)
+			default:
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				zap.L().Warn("failed to evict pod", zapDrainNode, zap.String("pod", pod.Name), zap.Error(err))
+				result.Failed = append(result.Failed, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: err.Error()})
+			}
+		}
+		remaining = retry
+
+		var terminating []corev1.Pod
+		for _, pod := range evicting {
+			current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+				result.Evicted = append(result.Evicted, drainPod{Namespace: pod.Namespace, Name: pod.Name})
+			} else {
+				terminating = append(terminating, pod)
+			}
+		}
+		evicting = terminating
+
+		if len(remaining) == 0 && len(evicting) == 0 {
+			break
+		}
+		wait := min(evictionRetryInterval, time.Until(deadline))
+		if wait <= 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	for _, pod := range remaining {
+		result.Blocked = append(result.Blocked, blocked[pod.Namespace+"/"+pod.Name])
+	}
+	for _, pod := range evicting {
+		result.Pending = append(result.Pending, drainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "evicted but still terminating"})
+	}
+	result.Completed = len(remaining) == 0 && len(evicting) == 0 && len(result.Failed) == 0
+
+	return result, nil
+}
+
+// matchingPDBs returns the names of the PodDisruptionBudgets covering the pod.
+func matchingPDBs(pod corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []string {
+	var names []string
+	for _, pdb := range pdbs {
+		if pdbCovers(pdb, pod) {
+			names = append(names, pdb.Name)
+		}
+	}
+
+	return names
+}
+
+// pdbCovers returns true if the PodDisruptionBudget selects the pod. A nil selector selects no pod and an empty one
+// selects all the pods of the namespace.
+func pdbCovers(pdb policyv1.PodDisruptionBudget, pod corev1.Pod) bool {
+	if pdb.Namespace != pod.Namespace {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// blockingPDBs returns the PodDisruptionBudgets that currently allow fewer disruptions than the number of pods to
+// evict they cover.
+func blockingPDBs(pods []corev1.Pod, pdbs []policyv1.PodDisruptionBudget) []blockingPDB {
+	blocking := []blockingPDB{}
+	for _, pdb := range pdbs {
+		var covered []string
+		for _, pod := range pods {
+			if pdbCovers(pdb, pod) {
+				covered = append(covered, pod.Name)
+			}
+		}
+		if len(covered) > int(pdb.Status.DisruptionsAllowed) {
+			blocking = append(blocking, blockingPDB{
+				Namespace:          pdb.Namespace,
+				Name:               pdb.Name,
+				DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+				Pods:               covered,
+			})
+		}
+	}
+	slices.SortFunc(blocking, func(a, b blockingPDB) int {
+		return strings.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+
+	return blocking
+}
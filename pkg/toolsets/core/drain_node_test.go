@@ -0,0 +1,279 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+// newNodePod returns a pod of the node managed by a ReplicaSet.
+func newNodePod(name, node string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID("uid-" + name),
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: name + "-rs", Controller: ptr.To(true)}},
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func newPDB(name string, labels map[string]string, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: disruptionsAllowed},
+	}
+}
+
+// evictionReactor deletes the evicted pods, except the ones for which it returns an error.
+func evictionReactor(fakeClientset *fake.Clientset, errors map[string]error) clienttesting.ReactionFunc {
+	return func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(clienttesting.CreateAction).GetObject().(*policyv1.Eviction)
+		if err, ok := errors[eviction.Name]; ok {
+			return true, nil, err
+		}
+
+		return true, nil, fakeClientset.Tracker().Delete(schema.GroupVersionResource{Version: "v1", Resource: "pods"}, eviction.Namespace, eviction.Name)
+	}
+}
+
+func TestDrainNode(t *testing.T) {
+	fakeToken := "fakeToken"
+	retryInterval := evictionRetryInterval
+	evictionRetryInterval = 10 * time.Millisecond
+	t.Cleanup(func() { evictionRetryInterval = retryInterval })
+
+	web := map[string]string{"app": "web"}
+	db := map[string]string{"app": "db"}
+	daemonPod := newNodePod("agent-abc", "node-1", nil)
+	daemonPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: ptr.To(true)}}
+	mirrorPod := newNodePod("etcd-node-1", "node-1", nil)
+	mirrorPod.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"}
+	barePod := newNodePod("debug", "node-1", nil)
+	barePod.OwnerReferences = nil
+	cachePod := newNodePod("cache-0", "node-1", nil)
+	cachePod.Spec.Volumes = []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	completedPod := newNodePod("migrate-abc", "node-1", nil)
+	completedPod.Status.Phase = corev1.PodSucceeded
+	bareReason := "not managed by a controller, it is lost for good once evicted: set force to evict it"
+	emptyDirReason := "uses emptyDir volumes whose data is deleted once evicted: set deleteEmptyDirData to evict it"
+	pdbViolation := apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+
+	tests := map[string]struct {
+		objs           []runtime.Object
+		params         drainNodeParams
+		evictionErrors map[string]error
+		expectedResult drainNodeResult
+		expectedPods   []string
+	}{
+		"evicts the pods and skips DaemonSet and mirror pods": {
+			objs: []runtime.Object{
+				newNode("node-1", false),
+				newNodePod("web-1", "node-1", web),
+				newNodePod("web-2", "node-2", web),
+				daemonPod,
+				mirrorPod,
+			},
+			expectedResult: drainNodeResult{
+				Node:      "node-1",
+				Completed: true,
+				Evicted:   []drainPod{{Namespace: "default", Name: "web-1"}},
+				Skipped: []drainPod{
+					{Namespace: "default", Name: "agent-abc", Reason: "managed by DaemonSet agent"},
+					{Namespace: "default", Name: "etcd-node-1", Reason: "mirror pod"},
+				},
+			},
+			expectedPods: []string{"agent-abc", "etcd-node-1", "web-2"},
+		},
+		"skips completed pods": {
+			objs: []runtime.Object{
+				newNode("node-1", false),
+				completedPod,
+			},
+			expectedResult: drainNodeResult{
+				Node:      "node-1",
+				Completed: true,
+				Evicted:   []drainPod{},
+				Skipped:   []drainPod{{Namespace: "default", Name: "migrate-abc", Reason: "completed with phase Succeeded"}},
+			},
+			expectedPods: []string{"migrate-abc"},
+		},
+		"doesn't evict pods not managed by a controller or using emptyDir volumes by default": {
+			objs: []runtime.Object{
+				newNode("node-1", false),
+				newNodePod("web-1", "node-1", web),
+				barePod,
+				cachePod,
+			},
+			expectedResult: drainNodeResult{
+				Node:    "node-1",
+				Evicted: []drainPod{{Namespace: "default", Name: "web-1"}},
+				Blocked: []drainPod{
+					{Namespace: "default", Name: "cache-0", Reason: emptyDirReason},
+					{Namespace: "default", Name: "debug", Reason: bareReason},
+				},
+				Note: "The node is cordoned but some of its pods were not evicted: pods not managed by a controller are lost for good and the data of their emptyDir volumes is deleted. Run drainNode again with force or deleteEmptyDirData to evict them.",
+			},
+			expectedPods: []string{"debug", "cache-0"},
+		},
+		"evicts pods not managed by a controller or using emptyDir volumes when forced to": {
+			objs: []runtime.Object{
+				newNode("node-1", false),
+				barePod,
+				cachePod,
+			},
+			params: drainNodeParams{Force: true, DeleteEmptyDirData: true},
+			expectedResult: drainNodeResult{
+				Node:      "node-1",
+				Completed: true,
+				Evicted:   []drainPod{{Namespace: "default", Name: "cache-0"}, {Namespace: "default", Name: "debug"}},
+			},
+		},
+		"reports the pods blocked by a PodDisruptionBudget": {
+			objs: []runtime.Object{
+				newNode("node-1", false),
+				newNodePod("db-0", "node-1", db),
+				newNodePod("web-1", "node-1", web),
+				newPDB("db-pdb", db, 0),
+				newPDB("web-pdb", web, 1),
+			},
+			evictionErrors: map[string]error{"db-0": pdbViolation},
+			expectedResult: drainNodeResult{
+				Node:    "node-1",
+				Evicted: []drainPod{{Namespace: "default", Name: "web-1"}},
+				Blocked: []drainPod{{Namespace: "default", Name: "db-0", Reason: pdbViolation.Error(), PodDisruptionBudgets: []string{"db-pdb"}}},
+				Note:    "The node is cordoned but the drain didn't complete within 1s. Pods blocked by a PodDisruptionBudget can be evicted once their workload has enough available replicas elsewhere, run drainNode again to retry.",
+			},
+			expectedPods: []string{"db-0"},
+		},
+		"reports failed evictions": {
+			objs: []runtime.Object{
+				newNode("node-1", true),
+				newNodePod("web-1", "node-1", web),
+			},
+			evictionErrors: map[string]error{"web-1": apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "web-1", nil)},
+			expectedResult: drainNodeResult{
+				Node:    "node-1",
+				Evicted: []drainPod{},
+				Failed:  []drainPod{{Namespace: "default", Name: "web-1", Reason: `pods "web-1" is forbidden: <nil>`}},
+				Note:    "The node is cordoned but some of its pods couldn't be evicted.",
+			},
+			expectedPods: []string{"web-1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClientset := withAccessReviews(fake.NewClientset(tt.objs...), true)
+			fakeClientset.PrependReactor("create", "pods", evictionReactor(fakeClientset, tt.evictionErrors))
+			tools := newClientSetTools(fakeClientset, fakeToken)
+
+			params := tt.params
+			params.Cluster, params.Name, params.TimeoutSeconds = "local", "node-1", 1
+			result, _, err := tools.drainNode(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, params)
+
+			require.NoError(t, err)
+			var resp struct {
+				LLM drainNodeResult `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedResult, resp.LLM)
+
+			node, err := fakeClientset.CoreV1().Nodes().Get(t.Context(), "node-1", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.True(t, node.Spec.Unschedulable, "the node must be cordoned")
+			pods, err := fakeClientset.CoreV1().Pods("default").List(t.Context(), metav1.ListOptions{})
+			require.NoError(t, err)
+			var podNames []string
+			for _, pod := range pods.Items {
+				podNames = append(podNames, pod.Name)
+			}
+			assert.ElementsMatch(t, tt.expectedPods, podNames)
+		})
+	}
+}
+
+func TestDrainNodePlan(t *testing.T) {
+	fakeToken := "fakeToken"
+	web := map[string]string{"app": "web"}
+	db := map[string]string{"app": "db"}
+	barePod := newNodePod("debug", "node-1", nil)
+	barePod.OwnerReferences = nil
+	completedPod := newNodePod("migrate-abc", "node-1", nil)
+	completedPod.Status.Phase = corev1.PodFailed
+	fakeClientset := fake.NewClientset(
+		newNode("node-1", false),
+		barePod,
+		completedPod,
+		newNodePod("db-0", "node-1", db),
+		newNodePod("web-1", "node-1", web),
+		newNodePod("web-2", "node-1", web),
+		newPDB("db-pdb", db, 0),
+		newPDB("web-pdb", web, 2),
+	)
+	tools := newClientSetTools(fakeClientset, fakeToken)
+
+	result, _, err := tools.drainNodePlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, drainNodeParams{Cluster: "local", Name: "node-1"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"type": "update",
+		"payload": {
+			"podsToEvict": [
+				{"namespace": "default", "name": "db-0", "podDisruptionBudgets": ["db-pdb"]},
+				{"namespace": "default", "name": "web-1", "podDisruptionBudgets": ["web-pdb"]},
+				{"namespace": "default", "name": "web-2", "podDisruptionBudgets": ["web-pdb"]}
+			],
+			"blockingPodDisruptionBudgets": [
+				{"namespace": "default", "name": "db-pdb", "disruptionsAllowed": 0, "pods": ["db-0"]}
+			],
+			"blockedPods": [
+				{"namespace": "default", "name": "debug", "reason": "not managed by a controller, it is lost for good once evicted: set force to evict it"}
+			],
+			"skippedPods": [
+				{"namespace": "default", "name": "migrate-abc", "reason": "completed with phase Failed"}
+			]
+		},
+		"resource": {"name": "node-1", "kind": "Node", "cluster": "local", "namespace": ""}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	node, err := fakeClientset.CoreV1().Nodes().Get(t.Context(), "node-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.False(t, node.Spec.Unschedulable, "the plan must not cordon the node")
+	for _, action := range fakeClientset.Actions() {
+		assert.NotEqual(t, "eviction", action.GetSubresource(), "the plan must not evict pods")
+	}
+}
+
+func TestPDBCovers(t *testing.T) {
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Labels: map[string]string{"app": "web"}}}
+
+	assert.True(t, pdbCovers(*newPDB("web", map[string]string{"app": "web"}, 0), pod))
+	assert.True(t, pdbCovers(*newPDB("all", map[string]string{}, 0), pod), "an empty selector selects all the pods of the namespace")
+	assert.False(t, pdbCovers(policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, pod), "a nil selector selects no pod")
+	assert.False(t, pdbCovers(*newPDB("db", map[string]string{"app": "db"}, 0), pod))
+	other := newPDB("web", map[string]string{"app": "web"}, 0)
+	other.Namespace = "other"
+	assert.False(t, pdbCovers(*other, pod))
+}
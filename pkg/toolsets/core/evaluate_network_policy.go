@@ -0,0 +1,218 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/netpol"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var zapEvaluateNetworkPolicy = zap.String("tool", "evaluateNetworkPolicy")
+
+// evaluateNetworkPolicyParams specifies the parameters needed to evaluate the NetworkPolicies for some traffic.
+type evaluateNetworkPolicyParams struct {
+	Cluster              string            `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	SourceNamespace      string            `json:"sourceNamespace,omitempty" jsonschema:"the namespace of the source pod. Either sourceNamespace or sourceIPBlock must be set"`
+	SourceLabels         map[string]string `json:"sourceLabels,omitempty" jsonschema:"the labels of the source pod"`
+	SourceIPBlock        string            `json:"sourceIPBlock,omitempty" jsonschema:"the CIDR or IP of a source outside of the cluster (e.g. 10.0.0.0/24)"`
+	DestinationNamespace string            `json:"destinationNamespace" jsonschema:"the namespace of the destination pod"`
+	DestinationPod       string            `json:"destinationPod" jsonschema:"the name of the destination pod"`
+	Port                 int32             `json:"port" jsonschema:"the destination port"`
+	Protocol             string            `json:"protocol,omitempty" jsonschema:"the protocol: TCP, UDP or SCTP. Defaults to TCP"`
+}
+
+// networkPolicyEvaluation is the response of the evaluateNetworkPolicy tool.
+type networkPolicyEvaluation struct {
+	Allowed     bool   `json:"allowed"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Port        string `json:"port"`
+	// Egress is the evaluation of the policies selecting the source pod.
+	Egress netpol.Result `json:"egress"`
+	// Ingress is the evaluation of the policies selecting the destination pod.
+	Ingress netpol.Result `json:"ingress"`
+	Reason  string        `json:"reason"`
+}
+
+// evaluateNetworkPolicy evaluates whether traffic from a source pod or IP block to a port of a destination pod is
+// allowed by the NetworkPolicies of the cluster. The traffic must be allowed by the egress policies of the source
+// and by the ingress policies of the destination.
+func (t *Tools) evaluateNetworkPolicy(ctx context.Context, toolReq *mcp.CallToolRequest, params evaluateNetworkPolicyParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("evaluateNetworkPolicy called")
+
+	protocol, err := networkProtocol(params.Protocol)
+	if err != nil {
+		return nil, nil, err
+	}
+	if (params.SourceNamespace == "") == (params.SourceIPBlock == "") {
+		return nil, nil, fmt.Errorf("either sourceNamespace or sourceIPBlock must be set")
+	}
+	if params.Port <= 0 || params.Port > 65535 {
+		return nil, nil, fmt.Errorf("invalid port %d", params.Port)
+	}
+
+	podResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      "pod",
+		Namespace: params.DestinationNamespace,
+		Name:      params.DestinationPod,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get Pod", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podResource.Object, &pod); err != nil {
+		zap.L().Error("failed to convert unstructured object to Pod", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+	}
+	dst := podPeer(pod)
+	if dst.NamespaceLabels, err = t.namespaceLabels(ctx, params.Cluster, pod.Namespace); err != nil {
+		zap.L().Error("failed to get Namespace", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+
+	src := netpol.Peer{Namespace: params.SourceNamespace, Labels: params.SourceLabels, IPBlock: params.SourceIPBlock}
+	source := "IP block " + params.SourceIPBlock
+	if params.SourceIPBlock != "" {
+		if _, err := netpol.ParsePrefix(params.SourceIPBlock); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		source = fmt.Sprintf("pods of namespace %s with labels {%s}", params.SourceNamespace, labels.Set(params.SourceLabels).String())
+		if src.NamespaceLabels, err = t.namespaceLabels(ctx, params.Cluster, params.SourceNamespace); err != nil {
+			zap.L().Error("failed to get Namespace", zapEvaluateNetworkPolicy, zap.Error(err))
+			return nil, nil, err
+		}
+	}
+
+	policies, err := t.networkPolicies(ctx, params.Cluster, pod.Namespace, params.SourceNamespace)
+	if err != nil {
+		zap.L().Error("failed to get NetworkPolicies", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+
+	egress, err := netpol.Egress(policies, src, dst, params.Port, protocol)
+	if err != nil {
+		zap.L().Error("failed to evaluate egress policies", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+	ingress, err := netpol.Ingress(policies, src, dst, params.Port, protocol)
+	if err != nil {
+		zap.L().Error("failed to evaluate ingress policies", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+
+	result := networkPolicyEvaluation{
+		Allowed:     egress.Allowed && ingress.Allowed,
+		Source:      source,
+		Destination: fmt.Sprintf("pod %s/%s", pod.Namespace, pod.Name),
+		Port:        fmt.Sprintf("%d/%s", params.Port, protocol),
+		Egress:      egress,
+		Ingress:     ingress,
+		Reason:      evaluationReason(egress, ingress),
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, response.NewUIContext(podResource, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapEvaluateNetworkPolicy, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// networkProtocol validates a protocol, which defaults to TCP.
+func networkProtocol(protocol string) (corev1.Protocol, error) {
+	switch strings.ToUpper(protocol) {
+	case "", string(corev1.ProtocolTCP):
+		return corev1.ProtocolTCP, nil
+	case string(corev1.ProtocolUDP):
+		return corev1.ProtocolUDP, nil
+	case string(corev1.ProtocolSCTP):
+		return corev1.ProtocolSCTP, nil
+	default:
+		return "", fmt.Errorf("invalid protocol '%s', it must be TCP, UDP or SCTP", protocol)
+	}
+}
+
+// namespaceLabels returns the labels of a namespace.
+func (t *Tools) namespaceLabels(ctx context.Context, cluster, namespace string) (map[string]string, error) {
+	namespaceResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster: cluster,
+		Kind:    "namespace",
+		Name:    namespace,
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return namespaceResource.GetLabels(), nil
+}
+
+// networkPolicies returns the NetworkPolicies of namespaces, ignoring the empty and repeated ones.
+func (t *Tools) networkPolicies(ctx context.Context, cluster string, namespaces ...string) ([]networkingv1.NetworkPolicy, error) {
+	var policies []networkingv1.NetworkPolicy
+	seen := map[string]bool{}
+	for _, namespace := range namespaces {
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		policyResources, err := t.client.GetResources(ctx, client.ListParams{
+			Cluster:   cluster,
+			Kind:      "networkpolicy",
+			Namespace: namespace,
+			Token:     middleware.Token(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+		namespacePolicies, err := fromUnstructuredList[networkingv1.NetworkPolicy](policyResources)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to NetworkPolicy: %w", err)
+		}
+		policies = append(policies, namespacePolicies...)
+	}
+
+	return policies, nil
+}
+
+// evaluationReason explains the evaluation of the egress and ingress policies.
+func evaluationReason(egress, ingress netpol.Result) string {
+	var reasons []string
+	if !egress.Allowed {
+		reasons = append(reasons, fmt.Sprintf("the source is selected by the egress policies %s and none of their rules allow the traffic", strings.Join(egress.Policies, ", ")))
+	}
+	if !ingress.Allowed {
+		reasons = append(reasons, fmt.Sprintf("the destination is selected by the ingress policies %s and none of their rules allow the traffic", strings.Join(ingress.Policies, ", ")))
+	}
+	if len(reasons) > 0 {
+		return "Denied: " + strings.Join(reasons, "; ") + "."
+	}
+
+	return fmt.Sprintf("Allowed: %s; %s.", sideReason("source", "egress", egress), sideReason("destination", "ingress", ingress))
+}
+
+// sideReason explains why the traffic is allowed on one side.
+func sideReason(side, direction string, result netpol.Result) string {
+	if len(result.Policies) == 0 {
+		return fmt.Sprintf("no %s policy selects the %s", direction, side)
+	}
+
+	return fmt.Sprintf("the %s traffic of the %s is allowed by %s", direction, side, strings.Join(result.MatchedRules, ", "))
+}
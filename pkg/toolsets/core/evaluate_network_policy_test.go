@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/rancher/rancher-ai-mcp/pkg/netpol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+func TestEvaluateNetworkPolicy(t *testing.T) {
+	fakeToken := "fakeToken"
+	db := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-0", Namespace: "data", Labels: map[string]string{"app": "db"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "postgres",
+			Ports: []corev1.ContainerPort{{Name: "postgres", ContainerPort: 5432}},
+		}}},
+		Status: corev1.PodStatus{PodIP: "10.42.1.7"},
+	}
+	dbIngress := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-ingress", Namespace: "data"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{
+				From: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "apps"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromString("postgres"))}},
+			}},
+		},
+	}
+	webEgress := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-egress", Namespace: "shop"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}},
+	}
+	objs := []runtime.Object{
+		db, dbIngress, webEgress,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "data"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"tier": "apps"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "store", Labels: map[string]string{"tier": "apps"}}},
+	}
+
+	tests := map[string]struct {
+		params         evaluateNetworkPolicyParams
+		expectedResult networkPolicyEvaluation
+		expectedError  string
+	}{
+		"allowed by the ingress policy of the destination": {
+			params: evaluateNetworkPolicyParams{Cluster: "local", SourceNamespace: "store", SourceLabels: map[string]string{"app": "web"}, DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432},
+			expectedResult: networkPolicyEvaluation{
+				Allowed:     true,
+				Source:      "pods of namespace store with labels {app=web}",
+				Destination: "pod data/db-0",
+				Port:        "5432/TCP",
+				Egress:      netpol.Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+				Ingress:     netpol.Result{Allowed: true, Policies: []string{"data/db-ingress"}, MatchedRules: []string{"data/db-ingress ingress[0]"}},
+				Reason:      "Allowed: no egress policy selects the source; the ingress traffic of the destination is allowed by data/db-ingress ingress[0].",
+			},
+		},
+		"denied by the egress policy of the source": {
+			params: evaluateNetworkPolicyParams{Cluster: "local", SourceNamespace: "shop", SourceLabels: map[string]string{"app": "web"}, DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432},
+			expectedResult: networkPolicyEvaluation{
+				Source:      "pods of namespace shop with labels {app=web}",
+				Destination: "pod data/db-0",
+				Port:        "5432/TCP",
+				Egress:      netpol.Result{Policies: []string{"shop/deny-egress"}, MatchedRules: []string{}},
+				Ingress:     netpol.Result{Allowed: true, Policies: []string{"data/db-ingress"}, MatchedRules: []string{"data/db-ingress ingress[0]"}},
+				Reason:      "Denied: the source is selected by the egress policies shop/deny-egress and none of their rules allow the traffic.",
+			},
+		},
+		"denied from an IP block": {
+			params: evaluateNetworkPolicyParams{Cluster: "local", SourceIPBlock: "192.168.0.0/24", DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432, Protocol: "tcp"},
+			expectedResult: networkPolicyEvaluation{
+				Source:      "IP block 192.168.0.0/24",
+				Destination: "pod data/db-0",
+				Port:        "5432/TCP",
+				Egress:      netpol.Result{Allowed: true, Policies: []string{}, MatchedRules: []string{}},
+				Ingress:     netpol.Result{Policies: []string{"data/db-ingress"}, MatchedRules: []string{}},
+				Reason:      "Denied: the destination is selected by the ingress policies data/db-ingress and none of their rules allow the traffic.",
+			},
+		},
+		"source must be set": {
+			params:        evaluateNetworkPolicyParams{Cluster: "local", DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432},
+			expectedError: "either sourceNamespace or sourceIPBlock must be set",
+		},
+		"invalid protocol": {
+			params:        evaluateNetworkPolicyParams{Cluster: "local", SourceIPBlock: "10.0.0.1", DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432, Protocol: "ICMP"},
+			expectedError: "invalid protocol 'ICMP', it must be TCP, UDP or SCTP",
+		},
+		"invalid IP block": {
+			params:        evaluateNetworkPolicyParams{Cluster: "local", SourceIPBlock: "10.0.0.0/33", DestinationNamespace: "data", DestinationPod: "db-0", Port: 5432},
+			expectedError: `invalid CIDR "10.0.0.0/33"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(connectivityScheme(), objs...)
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.evaluateNetworkPolicy(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM networkPolicyEvaluation `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedResult, resp.LLM)
+		})
+	}
+}
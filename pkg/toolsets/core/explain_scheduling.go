@@ -0,0 +1,511 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	resourcehelper "k8s.io/component-helpers/resource"
+	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+)
+
+var zapExplainScheduling = zap.String("tool", "explainScheduling")
+
+// nodeFit is whether a pod fits a node and the reasons why it doesn't.
+type nodeFit struct {
+	Node    string   `json:"node"`
+	Fits    bool     `json:"fits"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// explainSchedulingResult is the response of the explainScheduling tool.
+type explainSchedulingResult struct {
+	Pod          string            `json:"pod"`
+	Requests     map[string]string `json:"requests,omitempty"`
+	FittingNodes []string          `json:"fittingNodes"`
+	Nodes        []nodeFit         `json:"nodes"`
+	Notes        []string          `json:"notes,omitempty"`
+}
+
+// volumeConstraint restricts the nodes a pod can run on because of one of its PersistentVolumeClaims. Either the
+// claim can't be used at all, or it is bound to a PersistentVolume that is only reachable from some nodes.
+type volumeConstraint struct {
+	claim        string
+	reason       string
+	volume       string
+	nodeAffinity *corev1.NodeSelector
+}
+
+// explainScheduling evaluates a pod against every node of its cluster the way the scheduler filters nodes, and
+// returns for each node the reasons why the pod doesn't fit.
+func (t *Tools) explainScheduling(ctx context.Context, toolReq *mcp.CallToolRequest, params specificResourceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("explainScheduling called")
+
+	podResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      "pod",
+		Namespace: params.Namespace,
+		Name:      params.Name,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get Pod", zapExplainScheduling, zap.Error(err))
+		return nil, nil, err
+	}
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podResource.Object, &pod); err != nil {
+		zap.L().Error("failed to convert unstructured object to Pod", zapExplainScheduling, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+	}
+
+	nodeResources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: params.Cluster,
+		Kind:    "node",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get nodes", zapExplainScheduling, zap.Error(err))
+		return nil, nil, err
+	}
+	nodes, err := fromUnstructuredList[corev1.Node](nodeResources)
+	if err != nil {
+		zap.L().Error("failed to convert unstructured object to Node", zapExplainScheduling, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to convert unstructured object to Node: %w", err)
+	}
+
+	podResources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: params.Cluster,
+		Kind:    "pod",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get pods", zapExplainScheduling, zap.Error(err))
+		return nil, nil, err
+	}
+	pods, err := fromUnstructuredList[corev1.Pod](podResources)
+	if err != nil {
+		zap.L().Error("failed to convert unstructured object to Pod", zapExplainScheduling, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+	}
+
+	volumes, err := t.volumeConstraints(ctx, params.Cluster, pod)
+	if err != nil {
+		zap.L().Error("failed to get the volumes of the pod", zapExplainScheduling, zap.Error(err))
+		return nil, nil, err
+	}
+
+	result := explainSchedulingResult{
+		Pod:          pod.Namespace + "/" + pod.Name,
+		Requests:     quantities(podRequests(&pod)),
+		FittingNodes: []string{},
+		Nodes:        explainNodes(pod, nodes, pods, volumes),
+	}
+	for _, fit := range result.Nodes {
+		if fit.Fits {
+			result.FittingNodes = append(result.FittingNodes, fit.Node)
+		}
+	}
+	result.Notes = schedulingNotes(pod, result)
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, response.NewUIContext(podResource, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapExplainScheduling, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// fromUnstructuredList converts unstructured objects to their typed representation.
+func fromUnstructuredList[T any](objs []*unstructured.Unstructured) ([]T, error) {
+	typed := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		var item T
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &item); err != nil {
+			return nil, err
+		}
+		typed = append(typed, item)
+	}
+
+	return typed, nil
+}
+
+// volumeConstraints returns the constraints of the PersistentVolumeClaims of a pod on the nodes it can run on.
+func (t *Tools) volumeConstraints(ctx context.Context, cluster string, pod corev1.Pod) ([]volumeConstraint, error) {
+	var constraints []volumeConstraint
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		claimName := volume.PersistentVolumeClaim.ClaimName
+		claimResource, err := t.client.GetResource(ctx, client.GetParams{
+			Cluster:   cluster,
+			Kind:      "persistentvolumeclaim",
+			Namespace: pod.Namespace,
+			Name:      claimName,
+			Token:     middleware.Token(ctx),
+		})
+		if apierrors.IsNotFound(err) {
+			constraints = append(constraints, volumeConstraint{claim: claimName, reason: fmt.Sprintf("persistentvolumeclaim %s not found", claimName)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var claim corev1.PersistentVolumeClaim
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(claimResource.Object, &claim); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to PersistentVolumeClaim: %w", err)
+		}
+
+		if claim.Spec.VolumeName == "" {
+			if !t.waitsForFirstConsumer(ctx, cluster, claim) {
+				constraints = append(constraints, volumeConstraint{claim: claimName, reason: fmt.Sprintf("persistentvolumeclaim %s is not bound", claimName)})
+			}
+			continue
+		}
+
+		volumeResource, err := t.client.GetResource(ctx, client.GetParams{
+			Cluster: cluster,
+			Kind:    "persistentvolume",
+			Name:    claim.Spec.VolumeName,
+			Token:   middleware.Token(ctx),
+		})
+		if apierrors.IsNotFound(err) {
+			constraints = append(constraints, volumeConstraint{claim: claimName, reason: fmt.Sprintf("persistentvolume %s of claim %s not found", claim.Spec.VolumeName, claimName)})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var pv corev1.PersistentVolume
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(volumeResource.Object, &pv); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to PersistentVolume: %w", err)
+		}
+		if pv.Spec.NodeAffinity != nil && pv.Spec.NodeAffinity.Required != nil {
+			constraints = append(constraints, volumeConstraint{claim: claimName, volume: pv.Name, nodeAffinity: pv.Spec.NodeAffinity.Required})
+		}
+	}
+
+	return constraints, nil
+}
+
+// waitsForFirstConsumer reports whether the volume of an unbound claim is only provisioned once a pod using it is
+// scheduled. The claim is then not a reason for the pod to be unschedulable.
+func (t *Tools) waitsForFirstConsumer(ctx context.Context, cluster string, claim corev1.PersistentVolumeClaim) bool {
+	if claim.Spec.StorageClassName == nil || *claim.Spec.StorageClassName == "" {
+		return false
+	}
+	storageClassResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster: cluster,
+		Kind:    "storageclass",
+		Name:    *claim.Spec.StorageClassName,
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		return false
+	}
+	var storageClass storagev1.StorageClass
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(storageClassResource.Object, &storageClass); err != nil {
+		return false
+	}
+
+	return storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// explainNodes evaluates the pod against each node, the nodes the pod fits first.
+func explainNodes(pod corev1.Pod, nodes []corev1.Node, pods []corev1.Pod, volumes []volumeConstraint) []nodeFit {
+	// the pod itself doesn't count in the usage of its node nor in the topology spread of its workload
+	var others []corev1.Pod
+	podsByNode := map[string][]corev1.Pod{}
+	for _, p := range pods {
+		if p.Namespace == pod.Namespace && p.Name == pod.Name {
+			continue
+		}
+		if p.Spec.NodeName == "" || p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		others = append(others, p)
+		podsByNode[p.Spec.NodeName] = append(podsByNode[p.Spec.NodeName], p)
+	}
+
+	requests := podRequests(&pod)
+	fits := make([]nodeFit, 0, len(nodes))
+	for _, node := range nodes {
+		var reasons []string
+		reasons = append(reasons, taintReasons(pod, node)...)
+		reasons = append(reasons, nodeAffinityReasons(pod, node)...)
+		reasons = append(reasons, resourceReasons(requests, node, podsByNode[node.Name])...)
+		reasons = append(reasons, topologySpreadReasons(pod, node, nodes, others)...)
+		reasons = append(reasons, volumeReasons(volumes, node)...)
+		fits = append(fits, nodeFit{Node: node.Name, Fits: len(reasons) == 0, Reasons: reasons})
+	}
+	slices.SortStableFunc(fits, func(a, b nodeFit) int {
+		if a.Fits != b.Fits {
+			if a.Fits {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Node, b.Node)
+	})
+
+	return fits
+}
+
+// taintReasons returns the reasons why a pod isn't allowed on a cordoned or tainted node.
+func taintReasons(pod corev1.Pod, node corev1.Node) []string {
+	var reasons []string
+	if node.Spec.Unschedulable {
+		unschedulable := corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}
+		if !corev1helpers.TolerationsTolerateTaint(klog.Background(), pod.Spec.Tolerations, &unschedulable, false) {
+			reasons = append(reasons, "node is cordoned")
+		}
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+		if !corev1helpers.TolerationsTolerateTaint(klog.Background(), pod.Spec.Tolerations, &taint, false) {
+			reasons = append(reasons, fmt.Sprintf("untolerated taint %s", taintString(taint)))
+		}
+	}
+
+	return reasons
+}
+
+// taintString formats a taint like kubectl taint does (key=value:Effect).
+func taintString(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
+
+// nodeAffinityReasons returns the reasons why the node selector or the required node affinity of a pod don't match
+// a node.
+func nodeAffinityReasons(pod corev1.Pod, node corev1.Node) []string {
+	var reasons []string
+	if len(pod.Spec.NodeSelector) > 0 && !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		var missing []string
+		for key, value := range pod.Spec.NodeSelector {
+			if node.Labels[key] != value {
+				missing = append(missing, key+"="+value)
+			}
+		}
+		slices.Sort(missing)
+		reasons = append(reasons, fmt.Sprintf("node doesn't match the node selector: missing labels %s", strings.Join(missing, ", ")))
+	}
+	if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil {
+		required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if required != nil {
+			match, err := nodeaffinity.NewLazyErrorNodeSelector(required).Match(&node)
+			if err != nil {
+				reasons = append(reasons, fmt.Sprintf("invalid required node affinity: %s", err))
+			} else if !match {
+				reasons = append(reasons, "node doesn't match the required node affinity")
+			}
+		}
+	}
+
+	return reasons
+}
+
+// podRequests returns the resources a pod requests to be scheduled, the way the scheduler computes them: the
+// largest of its init containers or the sum of its containers, with its sidecars and overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	return resourcehelper.PodRequests(pod, resourcehelper.PodResourcesOptions{})
+}
+
+// resourceReasons returns the resources of a node whose allocatable minus the requests of its pods is lower than the
+// requests of the pod.
+func resourceReasons(requests corev1.ResourceList, node corev1.Node, nodePods []corev1.Pod) []string {
+	var reasons []string
+	if allocatablePods, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && int64(len(nodePods))+1 > allocatablePods.Value() {
+		reasons = append(reasons, fmt.Sprintf("too many pods: %d of %d allocatable already running", len(nodePods), allocatablePods.Value()))
+	}
+
+	requested := corev1.ResourceList{}
+	for i := range nodePods {
+		for name, quantity := range podRequests(&nodePods[i]) {
+			total := requested[name]
+			total.Add(quantity)
+			requested[name] = total
+		}
+	}
+
+	names := make([]corev1.ResourceName, 0, len(requests))
+	for name := range requests {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		request := requests[name]
+		if request.IsZero() {
+			continue
+		}
+		allocatable := node.Status.Allocatable[name]
+		free := allocatable.DeepCopy()
+		free.Sub(requested[name])
+		if request.Cmp(free) > 0 {
+			if free.Sign() < 0 {
+				free = resource.Quantity{}
+			}
+			reasons = append(reasons, fmt.Sprintf("insufficient %s: the pod requests %s, %s of %s allocatable is free", name, request.String(), free.String(), allocatable.String()))
+		}
+	}
+
+	return reasons
+}
+
+// topologySpreadReasons returns the DoNotSchedule topology spread constraints of a pod that placing it on a node
+// would violate. Only the nodes matching the node selector and required node affinity of the pod count as domains.
+func topologySpreadReasons(pod corev1.Pod, node corev1.Node, nodes []corev1.Node, pods []corev1.Pod) []string {
+	var reasons []string
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		if constraint.WhenUnsatisfiable != corev1.DoNotSchedule {
+			continue
+		}
+		domain, ok := node.Labels[constraint.TopologyKey]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("node doesn't have the label %s of a topology spread constraint", constraint.TopologyKey))
+			continue
+		}
+		selector, err := spreadSelector(pod, constraint)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid topology spread constraint on %s: %s", constraint.TopologyKey, err))
+			continue
+		}
+
+		counts := map[string]int{}
+		domainOf := map[string]string{}
+		for _, n := range nodes {
+			value, ok := n.Labels[constraint.TopologyKey]
+			if !ok || len(nodeAffinityReasons(pod, n)) > 0 {
+				continue
+			}
+			if _, ok := counts[value]; !ok {
+				counts[value] = 0
+			}
+			domainOf[n.Name] = value
+		}
+		for _, p := range pods {
+			value, ok := domainOf[p.Spec.NodeName]
+			if ok && p.Namespace == pod.Namespace && selector.Matches(labels.Set(p.Labels)) {
+				counts[value]++
+			}
+		}
+		if _, ok := counts[domain]; !ok {
+			// the node itself doesn't match the node affinity of the pod, which is reported on its own
+			continue
+		}
+
+		minCount := -1
+		for _, count := range counts {
+			if minCount < 0 || count < minCount {
+				minCount = count
+			}
+		}
+		self := 0
+		if selector.Matches(labels.Set(pod.Labels)) {
+			self = 1
+		}
+		if skew := counts[domain] + self - minCount; skew > int(constraint.MaxSkew) {
+			reasons = append(reasons, fmt.Sprintf("topology spread constraint on %s would be violated: skew %d exceeds maxSkew %d (%d matching pods in %s, %d in the least loaded domain)",
+				constraint.TopologyKey, skew, constraint.MaxSkew, counts[domain], domain, minCount))
+		}
+	}
+
+	return reasons
+}
+
+// spreadSelector returns the selector of the pods counted by a topology spread constraint, including the labels of
+// its matchLabelKeys.
+func spreadSelector(pod corev1.Pod, constraint corev1.TopologySpreadConstraint) (labels.Selector, error) {
+	labelSelector := constraint.LabelSelector
+	if len(constraint.MatchLabelKeys) > 0 {
+		labelSelector = labelSelector.DeepCopy()
+		if labelSelector == nil {
+			labelSelector = &metav1.LabelSelector{}
+		}
+		for _, key := range constraint.MatchLabelKeys {
+			if value, ok := pod.Labels[key]; ok {
+				metav1.AddLabelToSelector(labelSelector, key, value)
+			}
+		}
+	}
+	if labelSelector == nil {
+		return labels.Nothing(), nil
+	}
+
+	return metav1.LabelSelectorAsSelector(labelSelector)
+}
+
+// volumeReasons returns the reasons why the PersistentVolumeClaims of a pod can't be used on a node.
+func volumeReasons(volumes []volumeConstraint, node corev1.Node) []string {
+	var reasons []string
+	for _, volume := range volumes {
+		if volume.reason != "" {
+			reasons = append(reasons, volume.reason)
+			continue
+		}
+		match, err := nodeaffinity.NewLazyErrorNodeSelector(volume.nodeAffinity).Match(&node)
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("invalid node affinity of persistentvolume %s: %s", volume.volume, err))
+		} else if !match {
+			reasons = append(reasons, fmt.Sprintf("node doesn't match the node affinity of persistentvolume %s bound to claim %s", volume.volume, volume.claim))
+		}
+	}
+
+	return reasons
+}
+
+// quantities formats a list of resources.
+func quantities(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	formatted := make(map[string]string, len(list))
+	for name, quantity := range list {
+		formatted[string(name)] = quantity.String()
+	}
+
+	return formatted
+}
+
+// schedulingNotes explains the result and the parts of the scheduling the evaluation doesn't cover.
+func schedulingNotes(pod corev1.Pod, result explainSchedulingResult) []string {
+	var notes []string
+	if pod.Spec.NodeName != "" {
+		notes = append(notes, fmt.Sprintf("The pod is already scheduled on node %s, the nodes are evaluated as if it was pending.", pod.Spec.NodeName))
+	}
+	if len(result.Nodes) > 0 && len(result.FittingNodes) == 0 {
+		notes = append(notes, "No node can run the pod. Address the reasons of the nodes it should run on, or add a node that satisfies its constraints.")
+	}
+	if affinity := pod.Spec.Affinity; affinity != nil && (affinity.PodAffinity != nil || affinity.PodAntiAffinity != nil) {
+		notes = append(notes, "Inter-pod affinity and anti-affinity are not evaluated.")
+	}
+	if pod.Spec.SchedulerName != "" && pod.Spec.SchedulerName != corev1.DefaultSchedulerName {
+		notes = append(notes, fmt.Sprintf("The pod is scheduled by %s, which may apply other rules than the default scheduler.", pod.Spec.SchedulerName))
+	}
+
+	return notes
+}
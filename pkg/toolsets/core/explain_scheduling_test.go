@@ -0,0 +1,229 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newSchedulingNode(name string, labels map[string]string, cpu string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}},
+	}
+}
+
+func newSchedulingPod(name, node string, labels map[string]string, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestExplainScheduling(t *testing.T) {
+	fakeToken := "fakeToken"
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+
+	pod := newSchedulingPod("web-0", "", nil, "1")
+	pod.Status.Phase = corev1.PodPending
+	pod.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+	pod.Spec.Volumes = []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-web-0"},
+	}}}
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-web-0", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+	}
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{NodeAffinity: &corev1.VolumeNodeAffinity{Required: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+				{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}},
+			}}},
+		}}},
+	}
+	tainted := newSchedulingNode("node-c", map[string]string{"zone": "a"}, "4")
+	tainted.Spec.Taints = []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	cordoned := newSchedulingNode("node-d", map[string]string{"disk": "ssd", "zone": "a"}, "2")
+	cordoned.Spec.Unschedulable = true
+
+	fakeDynClient := dynamicfake.NewSimpleDynamicClient(scheme,
+		pod, claim, pv,
+		newSchedulingNode("node-a", map[string]string{"disk": "ssd", "zone": "a"}, "4"),
+		newSchedulingNode("node-b", map[string]string{"disk": "ssd", "zone": "b"}, "4"),
+		tainted,
+		cordoned,
+		newSchedulingPod("batch-1", "node-d", nil, "1500m"),
+	)
+	c := &client.Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return fakeDynClient, nil
+		},
+	}
+	tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+	result, _, err := tools.explainScheduling(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, specificResourceParams{Cluster: "local", Namespace: "default", Name: "web-0"})
+
+	require.NoError(t, err)
+	var resp struct {
+		LLM explainSchedulingResult `json:"llm"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+	assert.Equal(t, explainSchedulingResult{
+		Pod:          "default/web-0",
+		Requests:     map[string]string{"cpu": "1"},
+		FittingNodes: []string{"node-a"},
+		Nodes: []nodeFit{
+			{Node: "node-a", Fits: true},
+			{Node: "node-b", Reasons: []string{"node doesn't match the node affinity of persistentvolume pv-1 bound to claim data-web-0"}},
+			{Node: "node-c", Reasons: []string{
+				"untolerated taint dedicated=gpu:NoSchedule",
+				"node doesn't match the node selector: missing labels disk=ssd",
+			}},
+			{Node: "node-d", Reasons: []string{
+				"node is cordoned",
+				"insufficient cpu: the pod requests 1, 500m of 2 allocatable is free",
+			}},
+		},
+	}, resp.LLM)
+}
+
+func TestExplainSchedulingUnboundClaim(t *testing.T) {
+	fakeToken := "fakeToken"
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = storagev1.AddToScheme(scheme)
+	waitForFirstConsumer := storagev1.VolumeBindingWaitForFirstConsumer
+
+	tests := map[string]struct {
+		storageClass   *storagev1.StorageClass
+		expectedReason []string
+	}{
+		"immediate binding": {
+			storageClass:   &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}},
+			expectedReason: []string{"persistentvolumeclaim data is not bound"},
+		},
+		"binding waits for the pod to be scheduled": {
+			storageClass: &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}, VolumeBindingMode: &waitForFirstConsumer},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newSchedulingPod("web-0", "", nil, "100m")
+			pod.Spec.Volumes = []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+			}}}
+			storageClassName := "standard"
+			claim := &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+				Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+			}
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(scheme, pod, claim, tt.storageClass, newSchedulingNode("node-a", nil, "4"))
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.explainScheduling(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, specificResourceParams{Cluster: "local", Namespace: "default", Name: "web-0"})
+
+			require.NoError(t, err)
+			var resp struct {
+				LLM explainSchedulingResult `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			require.Len(t, resp.LLM.Nodes, 1)
+			assert.Equal(t, tt.expectedReason, resp.LLM.Nodes[0].Reasons)
+		})
+	}
+}
+
+func TestTopologySpreadReasons(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	spread := corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       "zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: web},
+	}
+	nodes := []corev1.Node{
+		*newSchedulingNode("node-a", map[string]string{"zone": "a"}, "4"),
+		*newSchedulingNode("node-b", map[string]string{"zone": "b"}, "4"),
+		*newSchedulingNode("node-c", nil, "4"),
+	}
+	running := []corev1.Pod{
+		*newSchedulingPod("web-1", "node-a", web, "100m"),
+		*newSchedulingPod("web-2", "node-a", web, "100m"),
+		*newSchedulingPod("web-3", "node-b", web, "100m"),
+		*newSchedulingPod("db-1", "node-b", map[string]string{"app": "db"}, "100m"),
+	}
+
+	tests := map[string]struct {
+		constraint     corev1.TopologySpreadConstraint
+		node           string
+		expectedReason []string
+	}{
+		"placing the pod in the most loaded domain exceeds the skew": {
+			constraint:     spread,
+			node:           "node-a",
+			expectedReason: []string{"topology spread constraint on zone would be violated: skew 2 exceeds maxSkew 1 (2 matching pods in a, 1 in the least loaded domain)"},
+		},
+		"placing the pod in the least loaded domain": {
+			constraint: spread,
+			node:       "node-b",
+		},
+		"node without the topology key": {
+			constraint:     spread,
+			node:           "node-c",
+			expectedReason: []string{"node doesn't have the label zone of a topology spread constraint"},
+		},
+		"ScheduleAnyway constraints are ignored": {
+			constraint: corev1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: spread.LabelSelector},
+			node:       "node-a",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := *newSchedulingPod("web-4", "", web, "100m")
+			pod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{tt.constraint}
+			var node corev1.Node
+			for _, n := range nodes {
+				if n.Name == tt.node {
+					node = n
+				}
+			}
+
+			assert.Equal(t, tt.expectedReason, topologySpreadReasons(pod, node, nodes, running))
+		})
+	}
+}
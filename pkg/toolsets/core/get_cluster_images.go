@@ -27,78 +27,36 @@ type imageUsage struct {
 	Pods  []podReference `json:"pods"`
 }
 
+// clusterImages holds the images found in each cluster, and the clusters that couldn't be queried.
+type clusterImages struct {
+	Clusters map[string][]imageUsage        `json:"clusters"`
+	Errors   map[string]client.ClusterError `json:"errors,omitempty"`
+}
+
 // getClusterImages retrieves all container images used across specified clusters,
 // along with the pods (name and namespace) using each image.
 // If no clusters are provided, it fetches images from all available clusters.
+// Clusters are queried concurrently; a cluster that can't be queried is reported under "errors"
+// instead of failing the whole call.
 // Returns a JSON map of cluster names to lists of image usage entries.
 func (t *Tools) getClusterImages(ctx context.Context, toolReq *mcp.CallToolRequest, params getClusterImagesParams) (*mcp.CallToolResult, any, error) {
 	zap.L().Debug("getClusterImages called")
 
-	var clusters []string
-	if len(params.Clusters) == 0 {
-		clusterList, err := t.client.GetResources(ctx, client.ListParams{
-			Cluster: "local",
-			Kind:    "managementcluster",
-			Token:   middleware.Token(ctx),
-		})
-
-		if err != nil {
-			zap.L().Error("failed to get clusters", zap.String("tool", "getClusterImages"), zap.Error(err))
-			return nil, nil, fmt.Errorf("failed to get clusters: %w", err)
-		}
-		for _, cluster := range clusterList {
-			clusters = append(clusters, cluster.GetName())
-		}
-	} else {
-		clusters = params.Clusters
+	clusters, err := client.ClustersOrAll(ctx, t.client, middleware.Token(ctx), params.Clusters)
+	if err != nil {
+		zap.L().Error("failed to get clusters", zap.String("tool", "getClusterImages"), zap.Error(err))
+		return nil, nil, err
 	}
 
-	imagesInClusters := map[string][]imageUsage{}
-
-	for _, cluster := range clusters {
-		imageIndex := map[string]int{} // image name → index in slice
-		var usages []imageUsage
-
-		unstructuredPods, err := t.client.GetResources(ctx, client.ListParams{
-			Cluster: cluster,
-			Kind:    "pod",
-			Token:   middleware.Token(ctx),
-		})
-		if err != nil {
-			zap.L().Error("failed to get pods", zap.String("tool", "getClusterImages"), zap.Error(err))
-			return nil, nil, fmt.Errorf("failed to get pods: %w", err)
-		}
-		for _, unstructuredPod := range unstructuredPods {
-			var pod corev1.Pod
-			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredPod.Object, &pod); err != nil {
-				zap.L().Error("failed convert unstructured object to Pod", zap.String("tool", "getClusterImages"), zap.Error(err))
-				return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
-			}
-			ref := podReference{Name: pod.Name, Namespace: pod.Namespace}
-			var containerImages []string
-			for _, c := range pod.Spec.InitContainers {
-				containerImages = append(containerImages, c.Image)
-			}
-			for _, c := range pod.Spec.Containers {
-				containerImages = append(containerImages, c.Image)
-			}
-			for _, image := range containerImages {
-				if idx, ok := imageIndex[image]; ok {
-					usages[idx].Pods = append(usages[idx].Pods, ref)
-				} else {
-					imageIndex[image] = len(usages)
-					usages = append(usages, imageUsage{Image: image, Pods: []podReference{ref}})
-				}
-			}
-		}
-
-		if usages == nil {
-			usages = []imageUsage{}
-		}
-		imagesInClusters[cluster] = usages
+	imagesInClusters, errs := client.FanOut(ctx, clusters, client.FanOutOptions{}, t.getImagesInCluster)
+	for cluster, err := range errs {
+		zap.L().Warn("failed to get images from cluster", zap.String("tool", "getClusterImages"), zap.String("cluster", cluster), zap.Error(err))
 	}
 
-	response, err := json.Marshal(imagesInClusters)
+	response, err := json.Marshal(clusterImages{
+		Clusters: imagesInClusters,
+		Errors:   client.NewClusterErrors(errs),
+	})
 	if err != nil {
 		zap.L().Error("failed to create response", zap.String("tool", "getClusterImages"), zap.Error(err))
 		return nil, nil, fmt.Errorf("failed to marshal JSON: %w", err)
@@ -107,5 +65,43 @@ func (t *Tools) getClusterImages(ctx context.Context, toolReq *mcp.CallToolReque
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: string(response)}},
 	}, nil, nil
+}
+
+// getImagesInCluster returns the images used by all pods in a single cluster.
+func (t *Tools) getImagesInCluster(ctx context.Context, cluster string) ([]imageUsage, error) {
+	imageIndex := map[string]int{} // image name → index in slice
+	usages := []imageUsage{}
+
+	unstructuredPods, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: cluster,
+		Kind:    "pod",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+	for _, unstructuredPod := range unstructuredPods {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredPod.Object, &pod); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+		}
+		ref := podReference{Name: pod.Name, Namespace: pod.Namespace}
+		var containerImages []string
+		for _, c := range pod.Spec.InitContainers {
+			containerImages = append(containerImages, c.Image)
+		}
+		for _, c := range pod.Spec.Containers {
+			containerImages = append(containerImages, c.Image)
+		}
+		for _, image := range containerImages {
+			if idx, ok := imageIndex[image]; ok {
+				usages[idx].Pods = append(usages[idx].Pods, ref)
+			} else {
+				imageIndex[image] = len(usages)
+				usages = append(usages, imageUsage{Image: image, Pods: []podReference{ref}})
+			}
+		}
+	}
 
+	return usages, nil
 }
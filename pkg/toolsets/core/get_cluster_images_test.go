@@ -1,6 +1,7 @@
 package core
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -10,12 +11,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 var fakePodWithImage = &corev1.Pod{
@@ -43,6 +47,19 @@ var fakePodWithImage = &corev1.Pod{
 	},
 }
 
+// newFakeManagementCluster returns a management cluster so that the cluster ID lookup succeeds.
+func newFakeManagementCluster(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "management.cattle.io/v3",
+			"kind":       "Cluster",
+			"metadata": map[string]any{
+				"name": name,
+			},
+		},
+	}
+}
+
 func podScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
@@ -61,6 +78,9 @@ func TestGetClusterImages(t *testing.T) {
 		// used in the creation of the Tools.
 		rancherURL string
 
+		// clusters whose API server answers every request with 503, like a disconnected downstream cluster.
+		unreachableClusters []string
+
 		expectedResult string
 		expectedError  string
 	}{
@@ -71,11 +91,13 @@ func TestGetClusterImages(t *testing.T) {
 				{Group: "", Version: "v1", Resource: "pods"}: "PodList",
 			}, fakePodWithImage),
 			expectedResult: `{
-				"local": [
-					{"image": "busybox:latest", "pods": [{"name": "test-pod", "namespace": "default"}]},
-					{"image": "nginx:1.21",     "pods": [{"name": "test-pod", "namespace": "default"}]},
-					{"image": "redis:alpine",   "pods": [{"name": "test-pod", "namespace": "default"}]}
-				]
+				"clusters": {
+					"local": [
+						{"image": "busybox:latest", "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "nginx:1.21",     "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "redis:alpine",   "pods": [{"name": "test-pod", "namespace": "default"}]}
+					]
+				}
 			}`,
 		},
 		"get images from cluster with no pods": {
@@ -85,7 +107,9 @@ func TestGetClusterImages(t *testing.T) {
 				{Group: "", Version: "v1", Resource: "pods"}: "PodList",
 			}),
 			expectedResult: `{
-				"local": []
+				"clusters": {
+					"local": []
+				}
 			}`,
 		},
 		"get images from cluster when tool is configured with URL": {
@@ -95,19 +119,54 @@ func TestGetClusterImages(t *testing.T) {
 			}, fakePodWithImage),
 			rancherURL: fakeUrl,
 			expectedResult: `{
-				"local": [
-					{"image": "busybox:latest", "pods": [{"name": "test-pod", "namespace": "default"}]},
-					{"image": "nginx:1.21",     "pods": [{"name": "test-pod", "namespace": "default"}]},
-					{"image": "redis:alpine",   "pods": [{"name": "test-pod", "namespace": "default"}]}
-				]
+				"clusters": {
+					"local": [
+						{"image": "busybox:latest", "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "nginx:1.21",     "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "redis:alpine",   "pods": [{"name": "test-pod", "namespace": "default"}]}
+					]
+				}
+			}`,
+		},
+		"unreachable cluster is reported without failing the others": {
+			params:     getClusterImagesParams{Clusters: []string{"local", "c-broken"}},
+			requestURL: fakeUrl,
+			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(podScheme(), map[schema.GroupVersionResource]string{
+				{Group: "", Version: "v1", Resource: "pods"}:                         "PodList",
+				{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}: "ClusterList",
+			}, fakePodWithImage, newFakeManagementCluster("c-broken")),
+			unreachableClusters: []string{"c-broken"},
+			expectedResult: `{
+				"clusters": {
+					"local": [
+						{"image": "busybox:latest", "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "nginx:1.21",     "pods": [{"name": "test-pod", "namespace": "default"}]},
+						{"image": "redis:alpine",   "pods": [{"name": "test-pod", "namespace": "default"}]}
+					]
+				},
+				"errors": {
+					"c-broken": {
+						"reason": "Unreachable",
+						"message": "failed to get pods: cluster agent disconnected"
+					}
+				}
 			}`,
 		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
+			unreachableDynClient := dynamicfake.NewSimpleDynamicClient(podScheme())
+			unreachableDynClient.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewServiceUnavailable("cluster agent disconnected")
+			})
 			c := &client.Client{
 				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					for _, cluster := range tt.unreachableClusters {
+						if strings.HasSuffix(inConfig.Host, "/"+cluster) {
+							return unreachableDynClient, nil
+						}
+					}
 					return tt.fakeDynClient, nil
 				},
 			}
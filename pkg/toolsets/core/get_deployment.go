@@ -63,7 +63,12 @@ func (t *Tools) getDeploymentDetails(ctx context.Context, toolReq *mcp.CallToolR
 		return nil, nil, fmt.Errorf("failed to get pods: %w", err)
 	}
 
-	mcpResponse, err := response.CreateMcpResponse(append([]*unstructured.Unstructured{deploymentResource}, pods...), params.Cluster)
+	resources := append([]*unstructured.Unstructured{deploymentResource}, pods...)
+	if events := t.recentWarningEvents(ctx, params.Cluster, params.Namespace, resources...); events != nil {
+		resources = append(resources, events)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse(resources, params.Cluster)
 	if err != nil {
 		zap.L().Error("failed to create mcp response", zap.String("tool", "getDeploymentDetails"), zap.Error(err))
 		return nil, nil, err
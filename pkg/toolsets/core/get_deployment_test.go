@@ -1,7 +1,9 @@
 package core
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rancher/rancher-ai-mcp/internal/middleware"
@@ -91,6 +93,8 @@ func deploymentScheme() *runtime.Scheme {
 func TestGetDeploymentDetails(t *testing.T) {
 	fakeUrl := "https://localhost:8080"
 	fakeToken := "fakeToken"
+	recentEvent := time.Now().UTC().Truncate(time.Second).Add(-10 * time.Minute)
+	oldEvent := recentEvent.Add(-2 * time.Hour)
 
 	tests := map[string]struct {
 		params        specificResourceParams
@@ -148,6 +152,61 @@ func TestGetDeploymentDetails(t *testing.T) {
 				]
 			}`,
 		},
+		"get deployment with warning events": {
+			params: specificResourceParams{
+				Name:      "nginx-deployment",
+				Namespace: "default",
+				Cluster:   "local",
+			},
+			requestURL: fakeUrl,
+			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(deploymentScheme(), map[schema.GroupVersionResource]string{
+				{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+				{Group: "", Version: "v1", Resource: "pods"}:            "PodList",
+			}, fakeDeployment, fakeDeploymentPod,
+				newEvent("pod.a", "Pod", "nginx-deployment-abc123", corev1.EventTypeWarning, "Unhealthy", "Readiness probe failed", 4, recentEvent, recentEvent),
+				newEvent("pod.b", "Pod", "nginx-deployment-abc123", corev1.EventTypeWarning, "BackOff", "Back-off pulling image", 1, oldEvent, oldEvent),
+				newEvent("other.a", "Pod", "other", corev1.EventTypeWarning, "Unhealthy", "Readiness probe failed", 1, recentEvent, recentEvent),
+			),
+			expectedResult: fmt.Sprintf(`{
+				"llm": [
+					{
+						"apiVersion": "apps/v1",
+						"kind": "Deployment",
+						"metadata": {"name": "nginx-deployment", "namespace": "default"},
+						"spec": {
+							"replicas": 2,
+							"selector": {"matchLabels": {"app": "nginx"}},
+							"strategy": {},
+							"template": {
+								"metadata": {"labels": {"app": "nginx"}},
+								"spec": {
+									"containers": [
+										{"image": "nginx:1.21", "name": "nginx", "ports": [{"containerPort": 80, "protocol": "TCP"}], "resources": {}}
+									]
+								}
+							}
+						},
+						"status": {}
+					},
+					{
+						"apiVersion": "v1",
+						"kind": "Pod",
+						"metadata": {"labels": {"app": "nginx"}, "name": "nginx-deployment-abc123", "namespace": "default"},
+						"spec": {"containers": [{"image": "nginx:1.21", "name": "nginx", "resources": {}}]},
+						"status": {"phase": "Running"}
+					},
+					{
+						"warning-events": [
+							{"type": "Warning", "reason": "Unhealthy", "message": "Readiness probe failed", "object": "Pod/nginx-deployment-abc123", "namespace": "default", "count": 4, "firstSeen": %[1]q, "lastSeen": %[1]q}
+						]
+					}
+				],
+				"uiContext": [
+					{"cluster": "local", "kind": "Deployment", "name": "nginx-deployment", "namespace": "default", "type": "apps.deployment"},
+					{"cluster": "local", "kind": "Pod", "name": "nginx-deployment-abc123", "namespace": "default", "type": "pod"}
+				]
+			}`, recentEvent.Format(time.RFC3339)),
+		},
 		"get deployment - not found": {
 			params: specificResourceParams{
 				Name:      "nonexistent-deployment",
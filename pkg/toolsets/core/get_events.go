@@ -0,0 +1,303 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	defaultEventsLimit = 50
+	// recentEventsWindow is how far back inspectPod and getDeployment look for warning events.
+	recentEventsWindow = time.Hour
+	// warningEventsKey is the key under which warning events are attached to the responses of other tools.
+	warningEventsKey = "warning-events"
+)
+
+var zapGetEvents = zap.String("tool", "getEvents")
+
+// getEventsParams specifies the parameters needed to query events.
+type getEventsParams struct {
+	Cluster      string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace    string `json:"namespace,omitempty" jsonschema:"the namespace of the events. Empty for all namespaces"`
+	Kind         string `json:"kind,omitempty" jsonschema:"the kind of the involved object (e.g. Pod, Deployment, Node)"`
+	Name         string `json:"name,omitempty" jsonschema:"the name of the involved object"`
+	UID          string `json:"uid,omitempty" jsonschema:"the uid of the involved object"`
+	Type         string `json:"type,omitempty" jsonschema:"the type of the events: Warning or Normal. Empty for both"`
+	Reason       string `json:"reason,omitempty" jsonschema:"the reason of the events (e.g. BackOff, FailedScheduling, Unhealthy)"`
+	SinceSeconds int64  `json:"sinceSeconds,omitempty" jsonschema:"only return events last seen in this many seconds"`
+	Limit        int    `json:"limit,omitempty" jsonschema:"maximum number of events returned after deduplication, defaults to 50"`
+}
+
+// eventSummary groups the events of an object that share the same type, reason and message.
+type eventSummary struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Object    string `json:"object"`
+	Namespace string `json:"namespace,omitempty"`
+	Count     int32  `json:"count"`
+	FirstSeen string `json:"firstSeen,omitempty"`
+	LastSeen  string `json:"lastSeen,omitempty"`
+
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// eventsResult is the response of the getEvents tool.
+type eventsResult struct {
+	Events []eventSummary `json:"events"`
+	Note   string         `json:"note,omitempty"`
+}
+
+// eventFilter selects events by their involved object, type, reason and age.
+type eventFilter struct {
+	kind   string
+	name   string
+	uid    string
+	typ    string
+	reason string
+	since  time.Time
+}
+
+// getEvents returns the events of a cluster filtered by involved object, type, reason and age. Repeated events are
+// deduplicated by reason and message and returned with their total count, most recent first.
+func (t *Tools) getEvents(ctx context.Context, toolReq *mcp.CallToolRequest, params getEventsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("getEvents called")
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultEventsLimit
+	}
+	filter := eventFilter{
+		kind:   params.Kind,
+		name:   params.Name,
+		uid:    params.UID,
+		typ:    params.Type,
+		reason: params.Reason,
+	}
+	if params.SinceSeconds > 0 {
+		filter.since = time.Now().Add(-time.Duration(params.SinceSeconds) * time.Second)
+	}
+
+	events, err := t.listEvents(ctx, params.Cluster, params.Namespace, filter)
+	if err != nil {
+		zap.L().Error("failed to get events", zapGetEvents, zap.Error(err))
+		return nil, nil, err
+	}
+
+	result := eventsResult{Events: summarizeEvents(events)}
+	if len(result.Events) > limit {
+		result.Note = fmt.Sprintf("Only the %d most recent out of %d events are returned. Narrow the query or increase the limit to see more.", limit, len(result.Events))
+		result.Events = result.Events[:limit]
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapGetEvents, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// listEvents lists the events of a namespace matching the filter. The name, uid and type are sent as field selectors,
+// and all fields are checked again client-side since the kind and reason are matched ignoring case.
+func (t *Tools) listEvents(ctx context.Context, cluster, namespace string, filter eventFilter) ([]corev1.Event, error) {
+	fieldSet := fields.Set{}
+	if filter.name != "" {
+		fieldSet["involvedObject.name"] = filter.name
+	}
+	if filter.uid != "" {
+		fieldSet["involvedObject.uid"] = filter.uid
+	}
+	switch {
+	case strings.EqualFold(filter.typ, corev1.EventTypeWarning):
+		fieldSet["type"] = corev1.EventTypeWarning
+	case strings.EqualFold(filter.typ, corev1.EventTypeNormal):
+		fieldSet["type"] = corev1.EventTypeNormal
+	case filter.typ != "":
+		return nil, fmt.Errorf("invalid event type '%s', it must be Warning or Normal", filter.typ)
+	}
+
+	resources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       cluster,
+		Kind:          "event",
+		Namespace:     namespace,
+		FieldSelector: fieldSet.AsSelector().String(),
+		Token:         middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []corev1.Event
+	for _, resource := range resources {
+		var event corev1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &event); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to Event: %w", err)
+		}
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+func (f eventFilter) matches(event corev1.Event) bool {
+	switch {
+	case f.kind != "" && !strings.EqualFold(event.InvolvedObject.Kind, f.kind):
+		return false
+	case f.name != "" && event.InvolvedObject.Name != f.name:
+		return false
+	case f.uid != "" && string(event.InvolvedObject.UID) != f.uid:
+		return false
+	case f.typ != "" && !strings.EqualFold(event.Type, f.typ):
+		return false
+	case f.reason != "" && !strings.EqualFold(event.Reason, f.reason):
+		return false
+	case !f.since.IsZero():
+		if lastSeen := eventLastSeen(event); !lastSeen.IsZero() && lastSeen.Before(f.since) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// summarizeEvents deduplicates events of the same object with the same type, reason and message, adding up their
+// counts. Summaries are sorted by the time they were last seen, most recent first.
+func summarizeEvents(events []corev1.Event) []eventSummary {
+	summaries := map[string]*eventSummary{}
+	var keys []string
+	for _, event := range events {
+		object := event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name
+		key := strings.Join([]string{event.InvolvedObject.Namespace, object, event.Type, event.Reason, event.Message}, "\x00")
+		count := event.Count
+		if event.Series != nil && event.Series.Count > count {
+			count = event.Series.Count
+		}
+		count = max(count, 1)
+		firstSeen, lastSeen := eventFirstSeen(event), eventLastSeen(event)
+
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &eventSummary{
+				Type:      event.Type,
+				Reason:    event.Reason,
+				Message:   event.Message,
+				Object:    object,
+				Namespace: event.InvolvedObject.Namespace,
+			}
+			summaries[key] = summary
+			keys = append(keys, key)
+		}
+		summary.Count += count
+		if !firstSeen.IsZero() && (summary.firstSeen.IsZero() || firstSeen.Before(summary.firstSeen)) {
+			summary.firstSeen = firstSeen
+			summary.FirstSeen = firstSeen.UTC().Format(time.RFC3339)
+		}
+		if lastSeen.After(summary.lastSeen) {
+			summary.lastSeen = lastSeen
+			summary.LastSeen = lastSeen.UTC().Format(time.RFC3339)
+		}
+	}
+
+	result := make([]eventSummary, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *summaries[key])
+	}
+	slices.SortStableFunc(result, func(a, b eventSummary) int { return b.lastSeen.Compare(a.lastSeen) })
+
+	return result
+}
+
+func eventFirstSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func eventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// recentWarningEvents returns the warning events of the last hour involving any of the given objects, all of which
+// must be in the namespace. Events are an aid to troubleshooting, so failures are logged and no events are returned.
+func (t *Tools) recentWarningEvents(ctx context.Context, cluster, namespace string, objs ...*unstructured.Unstructured) *unstructured.Unstructured {
+	return warningEventsObject(t.recentWarnings(ctx, cluster, namespace, objs...))
+}
+
+// recentWarnings returns the raw warning events of the last hour involving any of the given objects, all of which
+// must be in the namespace. Failures are logged and no events are returned.
+func (t *Tools) recentWarnings(ctx context.Context, cluster, namespace string, objs ...*unstructured.Unstructured) []corev1.Event {
+	events, err := t.listEvents(ctx, cluster, namespace, eventFilter{
+		typ:   corev1.EventTypeWarning,
+		since: time.Now().Add(-recentEventsWindow),
+	})
+	if err != nil {
+		zap.L().Warn("failed to get warning events", zap.String("cluster", cluster), zap.String("namespace", namespace), zap.Error(err))
+		return nil
+	}
+
+	return slices.DeleteFunc(events, func(event corev1.Event) bool {
+		return !slices.ContainsFunc(objs, func(obj *unstructured.Unstructured) bool {
+			return involves(event, obj)
+		})
+	})
+}
+
+// warningEventsObject returns the summary of the warning events to attach to the response of a tool, nil if there
+// are no events.
+func warningEventsObject(events []corev1.Event) *unstructured.Unstructured {
+	if len(events) == 0 {
+		return nil
+	}
+
+	warningEvents, err := newKeyedObject(warningEventsKey, summarizeEvents(events))
+	if err != nil {
+		zap.L().Warn("failed to convert warning events", zap.Error(err))
+		return nil
+	}
+
+	return warningEvents
+}
+
+// involves reports whether the event is about obj. Objects are matched by uid when both have one, since a deleted
+// and recreated object keeps its name, and by kind and name otherwise.
+func involves(event corev1.Event, obj *unstructured.Unstructured) bool {
+	if event.InvolvedObject.UID != "" && obj.GetUID() != "" {
+		return event.InvolvedObject.UID == obj.GetUID()
+	}
+
+	return strings.EqualFold(event.InvolvedObject.Kind, obj.GetKind()) && event.InvolvedObject.Name == obj.GetName()
+}
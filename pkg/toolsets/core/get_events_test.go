@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newEvent(name, kind, objName, typ, reason, message string, count int32, firstSeen, lastSeen time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      kind,
+			Name:      objName,
+			Namespace: "default",
+		},
+		Type:           typ,
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(firstSeen),
+		LastTimestamp:  metav1.NewTime(lastSeen),
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	fakeToken := "fakeToken"
+	now := time.Now().UTC().Truncate(time.Second)
+	ts := func(ago time.Duration) string { return now.Add(-ago).Format(time.RFC3339) }
+	events := []runtime.Object{
+		newEvent("web-1.a", "Pod", "web-1", corev1.EventTypeWarning, "BackOff", "Back-off restarting failed container", 3, now.Add(-50*time.Minute), now.Add(-40*time.Minute)),
+		// same reason and message as web-1.a, recreated after the previous event expired
+		newEvent("web-1.b", "Pod", "web-1", corev1.EventTypeWarning, "BackOff", "Back-off restarting failed container", 2, now.Add(-10*time.Minute), now.Add(-time.Minute)),
+		newEvent("web-1.c", "Pod", "web-1", corev1.EventTypeNormal, "Pulled", "Container image pulled", 1, now.Add(-2*time.Hour), now.Add(-2*time.Hour)),
+		newEvent("web.a", "Deployment", "web", corev1.EventTypeNormal, "ScalingReplicaSet", "Scaled up replica set web-1 to 1", 1, now.Add(-5*time.Minute), now.Add(-5*time.Minute)),
+	}
+
+	tests := map[string]struct {
+		params         getEventsParams
+		expectedResult string
+		expectedError  string
+	}{
+		"repeated events are deduplicated with counts": {
+			params: getEventsParams{Cluster: "local", Namespace: "default", Kind: "pod", Name: "web-1"},
+			expectedResult: fmt.Sprintf(`{"llm": {"events": [
+				{"type": "Warning", "reason": "BackOff", "message": "Back-off restarting failed container", "object": "Pod/web-1", "namespace": "default", "count": 5, "firstSeen": %q, "lastSeen": %q},
+				{"type": "Normal", "reason": "Pulled", "message": "Container image pulled", "object": "Pod/web-1", "namespace": "default", "count": 1, "firstSeen": %q, "lastSeen": %q}
+			]}}`, ts(50*time.Minute), ts(time.Minute), ts(2*time.Hour), ts(2*time.Hour)),
+		},
+		"filter by type and reason": {
+			params: getEventsParams{Cluster: "local", Namespace: "default", Type: "normal", Reason: "scalingreplicaset"},
+			expectedResult: fmt.Sprintf(`{"llm": {"events": [
+				{"type": "Normal", "reason": "ScalingReplicaSet", "message": "Scaled up replica set web-1 to 1", "object": "Deployment/web", "namespace": "default", "count": 1, "firstSeen": %q, "lastSeen": %q}
+			]}}`, ts(5*time.Minute), ts(5*time.Minute)),
+		},
+		"filter by age with a limit": {
+			params: getEventsParams{Cluster: "local", Namespace: "default", SinceSeconds: 3600, Limit: 1},
+			expectedResult: fmt.Sprintf(`{"llm": {
+				"events": [
+					{"type": "Warning", "reason": "BackOff", "message": "Back-off restarting failed container", "object": "Pod/web-1", "namespace": "default", "count": 5, "firstSeen": %q, "lastSeen": %q}
+				],
+				"note": "Only the 1 most recent out of 2 events are returned. Narrow the query or increase the limit to see more."
+			}}`, ts(50*time.Minute), ts(time.Minute)),
+		},
+		"no matching events": {
+			params:         getEventsParams{Cluster: "local", Namespace: "default", Kind: "Node"},
+			expectedResult: `{"llm": {"events": []}}`,
+		},
+		"invalid type": {
+			params:        getEventsParams{Cluster: "local", Namespace: "default", Type: "Error"},
+			expectedError: "invalid event type 'Error', it must be Warning or Normal",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(deploymentScheme(), events...)
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.getEvents(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			}
+		})
+	}
+}
+
+func TestInvolves(t *testing.T) {
+	pod := &unstructured.Unstructured{}
+	pod.SetKind("Pod")
+	pod.SetName("web-1")
+
+	event := corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1", UID: "old"}}
+	assert.True(t, involves(event, pod), "objects without uid are matched by kind and name")
+
+	pod.SetUID(types.UID("new"))
+	assert.False(t, involves(event, pod), "events of a previous object with the same name must not match")
+
+	event.InvolvedObject.UID = "new"
+	assert.True(t, involves(event, pod))
+
+	event.InvolvedObject = corev1.ObjectReference{Kind: "Deployment", Name: "web-1"}
+	assert.False(t, involves(event, pod))
+}
@@ -0,0 +1,342 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	defaultPodLogsTailLines int64 = 100
+	defaultPodLogsMaxPods         = 10
+	// podLogsGrepScanLines is the number of lines read per container when filtering, so that the filter
+	// is applied to a meaningful window rather than to the last few lines only.
+	podLogsGrepScanLines int64 = 10000
+	// podLogsMaxLines bounds the merged stream so that aggregating many pods can't flood the response.
+	podLogsMaxLines = 2000
+)
+
+var zapGetPodLogs = zap.String("tool", "getPodLogs")
+
+// getPodLogsParams specifies the parameters needed to get the logs of one or more pods.
+type getPodLogsParams struct {
+	Cluster       string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace     string `json:"namespace" jsonschema:"the namespace of the pods"`
+	Name          string `json:"name,omitempty" jsonschema:"the name of the pod. One of name, labelSelector or workloadName must be set"`
+	LabelSelector string `json:"labelSelector,omitempty" jsonschema:"label selector of the pods whose logs are merged (e.g. app=nginx)"`
+	WorkloadKind  string `json:"workloadKind,omitempty" jsonschema:"the kind of the workload whose pods logs are merged: Deployment, StatefulSet, DaemonSet, ReplicaSet or Job"`
+	WorkloadName  string `json:"workloadName,omitempty" jsonschema:"the name of the workload whose pods logs are merged"`
+	Container     string `json:"container,omitempty" jsonschema:"the container to get logs from, init containers included. Empty for all containers"`
+	Previous      bool   `json:"previous,omitempty" jsonschema:"return the logs of the previous instance of the containers, e.g. the crashed one of a pod in CrashLoopBackOff"`
+	SinceSeconds  int64  `json:"sinceSeconds,omitempty" jsonschema:"only return logs newer than this many seconds. Can't be set with sinceTime"`
+	SinceTime     string `json:"sinceTime,omitempty" jsonschema:"only return logs after this RFC3339 time (e.g. 2025-01-01T10:00:00Z). Can't be set with sinceSeconds"`
+	TailLines     int64  `json:"tailLines,omitempty" jsonschema:"number of lines returned per container, defaults to 100. With grep, the number of matching lines"`
+	Grep          string `json:"grep,omitempty" jsonschema:"regular expression, only lines matching it are returned (e.g. (?i)error|panic)"`
+	Timestamps    bool   `json:"timestamps,omitempty" jsonschema:"prefix every line with its timestamp"`
+	MaxPods       int    `json:"maxPods,omitempty" jsonschema:"maximum number of pods whose logs are merged, defaults to 10"`
+}
+
+// podLogsResult is the response of the getPodLogs tool.
+type podLogsResult struct {
+	Pods   []string          `json:"pods"`
+	Logs   []string          `json:"logs"`
+	Errors map[string]string `json:"errors,omitempty"`
+	Note   string            `json:"note,omitempty"`
+}
+
+// logLine is a single line of a container log.
+type logLine struct {
+	timestamp time.Time
+	source    string
+	text      string
+}
+
+// getPodLogs returns the logs of a pod, or the merged logs of all the pods matching a label selector or owned by a
+// workload. Lines of different containers are interleaved in time order and prefixed with the pod and container.
+func (t *Tools) getPodLogs(ctx context.Context, toolReq *mcp.CallToolRequest, params getPodLogsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("getPodLogs called")
+
+	var grep *regexp.Regexp
+	if params.Grep != "" {
+		var err error
+		if grep, err = regexp.Compile(params.Grep); err != nil {
+			return nil, nil, fmt.Errorf("invalid grep expression %q: %w", params.Grep, err)
+		}
+	}
+	// the API server rejects the logs requests setting both
+	if params.SinceTime != "" && params.SinceSeconds > 0 {
+		return nil, nil, fmt.Errorf("only one of sinceTime and sinceSeconds can be set")
+	}
+	var sinceTime *metav1.Time
+	if params.SinceTime != "" {
+		since, err := time.Parse(time.RFC3339, params.SinceTime)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid sinceTime %q, it must be a RFC3339 time: %w", params.SinceTime, err)
+		}
+		sinceTime = ptr.To(metav1.NewTime(since))
+	}
+	tailLines := params.TailLines
+	if tailLines <= 0 {
+		tailLines = defaultPodLogsTailLines
+	}
+	maxPods := params.MaxPods
+	if maxPods <= 0 {
+		maxPods = defaultPodLogsMaxPods
+	}
+
+	clientset, err := t.client.CreateClientSet(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create clientset", zapGetPodLogs, zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	pods, err := podsForLogs(ctx, clientset, params)
+	if err != nil {
+		zap.L().Error("failed to get pods", zapGetPodLogs, zap.Error(err))
+		return nil, nil, err
+	}
+	if len(pods) == 0 {
+		return nil, nil, fmt.Errorf("no pods found in namespace '%s'", params.Namespace)
+	}
+
+	result := podLogsResult{Pods: []string{}, Logs: []string{}}
+	slices.SortFunc(pods, func(a, b corev1.Pod) int { return strings.Compare(a.Name, b.Name) })
+	if len(pods) > maxPods {
+		result.Note = fmt.Sprintf("Only the logs of %d out of %d pods are returned. Increase maxPods to see more.", maxPods, len(pods))
+		pods = pods[:maxPods]
+	}
+
+	type logSource struct {
+		pod       string
+		container string
+	}
+	var sources []logSource
+	for _, pod := range pods {
+		for _, container := range podContainerNames(pod) {
+			if params.Container == "" || params.Container == container {
+				sources = append(sources, logSource{pod: pod.Name, container: container})
+			}
+		}
+		result.Pods = append(result.Pods, pod.Name)
+	}
+	if len(sources) == 0 {
+		return nil, nil, fmt.Errorf("container '%s' not found in the pods", params.Container)
+	}
+
+	var lines []logLine
+	for _, source := range sources {
+		opts := &corev1.PodLogOptions{
+			Container:  source.container,
+			Previous:   params.Previous,
+			SinceTime:  sinceTime,
+			TailLines:  ptr.To(tailLines),
+			Timestamps: true, // always requested, they are needed to merge the streams in order.
+		}
+		if params.SinceSeconds > 0 {
+			opts.SinceSeconds = ptr.To(params.SinceSeconds)
+		}
+		if grep != nil {
+			opts.TailLines = ptr.To(max(tailLines, podLogsGrepScanLines))
+		}
+
+		sourceName := source.pod + "/" + source.container
+		sourceLines, err := readContainerLogs(ctx, clientset, params.Namespace, source.pod, sourceName, opts)
+		if err != nil {
+			zap.L().Warn("unable to retrieve logs for container", zapGetPodLogs, zap.String("source", sourceName), zap.Error(err))
+			if result.Errors == nil {
+				result.Errors = map[string]string{}
+			}
+			result.Errors[sourceName] = err.Error()
+			continue
+		}
+		if grep != nil {
+			sourceLines = slices.DeleteFunc(sourceLines, func(line logLine) bool { return !grep.MatchString(line.text) })
+			if int64(len(sourceLines)) > tailLines {
+				sourceLines = sourceLines[int64(len(sourceLines))-tailLines:]
+			}
+		}
+		lines = append(lines, sourceLines...)
+	}
+
+	// the sort is stable so lines of the same container keep their order even if they share a timestamp.
+	slices.SortStableFunc(lines, func(a, b logLine) int { return a.timestamp.Compare(b.timestamp) })
+	if len(lines) > podLogsMaxLines {
+		lines = lines[len(lines)-podLogsMaxLines:]
+		result.Note = strings.TrimSpace(result.Note + fmt.Sprintf(" Only the last %d lines are returned. Use tailLines, sinceSeconds or grep to narrow the logs.", podLogsMaxLines))
+	}
+	for _, line := range lines {
+		result.Logs = append(result.Logs, formatLogLine(line, params.Timestamps, len(sources) > 1))
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapGetPodLogs, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// podsForLogs returns the pod named in params, or the pods matching the label selector or the workload selector.
+func podsForLogs(ctx context.Context, clientset kubernetes.Interface, params getPodLogsParams) ([]corev1.Pod, error) {
+	switch {
+	case params.Name != "":
+		pod, err := clientset.CoreV1().Pods(params.Namespace).Get(ctx, params.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []corev1.Pod{*pod}, nil
+	case params.WorkloadName != "":
+		selector, err := workloadSelector(ctx, clientset, params.Namespace, params.WorkloadKind, params.WorkloadName)
+		if err != nil {
+			return nil, err
+		}
+		return listPods(ctx, clientset, params.Namespace, selector)
+	case params.LabelSelector != "":
+		return listPods(ctx, clientset, params.Namespace, params.LabelSelector)
+	default:
+		return nil, fmt.Errorf("one of name, labelSelector or workloadName must be set")
+	}
+}
+
+func listPods(ctx context.Context, clientset kubernetes.Interface, namespace, selector string) ([]corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+
+	return pods.Items, nil
+}
+
+// workloadSelector returns the pod selector of a workload as a string.
+func workloadSelector(ctx context.Context, clientset kubernetes.Interface, namespace, kind, name string) (string, error) {
+	var selector *metav1.LabelSelector
+	switch strings.ToLower(kind) {
+	case "deployment":
+		deployment, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = deployment.Spec.Selector
+	case "statefulset":
+		statefulSet, err := clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = statefulSet.Spec.Selector
+	case "daemonset":
+		daemonSet, err := clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = daemonSet.Spec.Selector
+	case "replicaset":
+		replicaSet, err := clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = replicaSet.Spec.Selector
+	case "job":
+		job, err := clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = job.Spec.Selector
+	default:
+		return "", fmt.Errorf("unsupported workload kind '%s', it must be one of Deployment, StatefulSet, DaemonSet, ReplicaSet or Job", kind)
+	}
+	if selector == nil {
+		return "", fmt.Errorf("%s '%s' has no pod selector", kind, name)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector of %s '%s': %w", kind, name, err)
+	}
+
+	return labelSelector.String(), nil
+}
+
+// podContainerNames returns the names of the init, regular and ephemeral containers of a pod, in that order.
+func podContainerNames(pod corev1.Pod) []string {
+	var names []string
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.EphemeralContainers {
+		names = append(names, container.Name)
+	}
+
+	return names
+}
+
+// readContainerLogs reads the logs of a container requested with timestamps and splits them into lines.
+func readContainerLogs(ctx context.Context, clientset kubernetes.Interface, namespace, pod, source string, opts *corev1.PodLogOptions) ([]logLine, error) {
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod, opts).Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			zap.L().Warn("failed to close pod logs stream", zap.Error(err))
+		}
+	}()
+
+	var lines []logLine
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, parseLogLine(scanner.Text(), source))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read logs: %w", err)
+	}
+
+	return lines, nil
+}
+
+// parseLogLine splits the timestamp added by the kubelet from the rest of the line.
+// Lines without a valid timestamp keep their full text and a zero time.
+func parseLogLine(raw, source string) logLine {
+	if timestamp, text, found := strings.Cut(raw, " "); found {
+		if ts, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			return logLine{timestamp: ts, source: source, text: text}
+		}
+	}
+
+	return logLine{source: source, text: raw}
+}
+
+func formatLogLine(line logLine, withTimestamp, withSource bool) string {
+	var sb strings.Builder
+	if withTimestamp && !line.timestamp.IsZero() {
+		sb.WriteString(line.timestamp.UTC().Format(time.RFC3339Nano))
+		sb.WriteString(" ")
+	}
+	if withSource {
+		sb.WriteString("[")
+		sb.WriteString(line.source)
+		sb.WriteString("] ")
+	}
+	sb.WriteString(line.text)
+
+	return sb.String()
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newLogsPod(name string, labels map[string]string, initContainers []string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+	}
+	for _, container := range initContainers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{Name: container})
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+	}
+
+	return pod
+}
+
+// logsReactor returns the logs queued for a container, in order, as the fake clientset doesn't tell pods apart.
+// Logs queued under "<container>/previous" are returned when the previous logs are requested.
+func logsReactor(logs map[string][]string) k8stesting.ReactionFunc {
+	var mu sync.Mutex
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "log" {
+			return false, nil, nil
+		}
+		opts := action.(k8stesting.GenericAction).GetValue().(*corev1.PodLogOptions)
+		key := opts.Container
+		if opts.Previous {
+			key += "/previous"
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		queued := logs[key]
+		if len(queued) == 0 {
+			return true, nil, errors.New("previous terminated container \"" + opts.Container + "\" not found")
+		}
+		logs[key] = queued[1:]
+		return true, &runtime.Unknown{Raw: []byte(queued[0])}, nil
+	}
+}
+
+func TestGetPodLogs(t *testing.T) {
+	fakeToken := "fakeToken"
+	appLabels := map[string]string{"app": "web"}
+	webDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: appLabels},
+		},
+	}
+
+	tests := map[string]struct {
+		params         getPodLogsParams
+		objs           []runtime.Object
+		logs           map[string][]string
+		expectedResult string
+		expectedError  string
+	}{
+		"single pod with init container": {
+			params: getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1"},
+			objs:   []runtime.Object{newLogsPod("web-1", appLabels, []string{"migrate"}, "app")},
+			logs: map[string][]string{
+				"migrate": {"2025-01-01T10:00:00Z running migrations\n2025-01-01T10:00:05Z done\n"},
+				"app":     {"2025-01-01T10:00:06Z listening on :8080\n"},
+			},
+			expectedResult: `{"llm": {
+				"pods": ["web-1"],
+				"logs": [
+					"[web-1/migrate] running migrations",
+					"[web-1/migrate] done",
+					"[web-1/app] listening on :8080"
+				]
+			}}`,
+		},
+		"previous logs of a crashing container": {
+			params: getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1", Container: "app", Previous: true, Timestamps: true},
+			objs:   []runtime.Object{newLogsPod("web-1", appLabels, nil, "app")},
+			logs: map[string][]string{
+				"app":          {"2025-01-01T10:05:00Z restarted\n"},
+				"app/previous": {"2025-01-01T10:04:59.5Z panic: nil pointer dereference\n"},
+			},
+			expectedResult: `{"llm": {
+				"pods": ["web-1"],
+				"logs": ["2025-01-01T10:04:59.5Z panic: nil pointer dereference"]
+			}}`,
+		},
+		"grep keeps the last matching lines": {
+			params: getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1", Grep: "(?i)error", TailLines: 2},
+			objs:   []runtime.Object{newLogsPod("web-1", appLabels, nil, "app")},
+			logs: map[string][]string{
+				"app": {"2025-01-01T10:00:00Z ERROR one\n2025-01-01T10:00:01Z ok\n2025-01-01T10:00:02Z error two\n2025-01-01T10:00:03Z Error three\n"},
+			},
+			expectedResult: `{"llm": {
+				"pods": ["web-1"],
+				"logs": ["error two", "Error three"]
+			}}`,
+		},
+		"workload pods are merged in time order": {
+			params: getPodLogsParams{Cluster: "local", Namespace: "default", WorkloadKind: "Deployment", WorkloadName: "web"},
+			objs: []runtime.Object{
+				webDeployment,
+				newLogsPod("web-1", appLabels, nil, "app"),
+				newLogsPod("web-2", appLabels, nil, "app"),
+				newLogsPod("other", map[string]string{"app": "other"}, nil, "app"),
+			},
+			logs: map[string][]string{
+				"app": {
+					"2025-01-01T10:00:00Z request a\n2025-01-01T10:00:02Z request c\n",
+					"2025-01-01T10:00:01Z request b\n2025-01-01T10:00:03Z request d\n",
+				},
+			},
+			expectedResult: `{"llm": {
+				"pods": ["web-1", "web-2"],
+				"logs": [
+					"[web-1/app] request a",
+					"[web-2/app] request b",
+					"[web-1/app] request c",
+					"[web-2/app] request d"
+				]
+			}}`,
+		},
+		"label selector with max pods and a failing container": {
+			params: getPodLogsParams{Cluster: "local", Namespace: "default", LabelSelector: "app=web", Previous: true, MaxPods: 2},
+			objs: []runtime.Object{
+				newLogsPod("web-1", appLabels, nil, "app"),
+				newLogsPod("web-2", appLabels, nil, "app"),
+				newLogsPod("web-3", appLabels, nil, "app"),
+			},
+			logs: map[string][]string{
+				"app/previous": {"2025-01-01T10:00:00Z crashed\n"},
+			},
+			expectedResult: `{"llm": {
+				"pods": ["web-1", "web-2"],
+				"logs": ["[web-1/app] crashed"],
+				"errors": {"web-2/app": "Get \"https://localhost/api/v1/namespaces/default/pods/web-2/log\": Get \"https://localhost/api/v1/namespaces/default/pods/web-2/log\": previous terminated container \"app\" not found"},
+				"note": "Only the logs of 2 out of 3 pods are returned. Increase maxPods to see more."
+			}}`,
+		},
+		"no pod selection": {
+			params:        getPodLogsParams{Cluster: "local", Namespace: "default"},
+			expectedError: "one of name, labelSelector or workloadName must be set",
+		},
+		"unsupported workload kind": {
+			params:        getPodLogsParams{Cluster: "local", Namespace: "default", WorkloadKind: "Service", WorkloadName: "web"},
+			expectedError: "unsupported workload kind 'Service'",
+		},
+		"invalid grep": {
+			params:        getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1", Grep: "(error"},
+			expectedError: "invalid grep expression",
+		},
+		"sinceTime and sinceSeconds": {
+			params:        getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1", SinceSeconds: 60, SinceTime: "2025-01-01T10:00:00Z"},
+			expectedError: "only one of sinceTime and sinceSeconds can be set",
+		},
+		"unknown container": {
+			params:        getPodLogsParams{Cluster: "local", Namespace: "default", Name: "web-1", Container: "sidecar"},
+			objs:          []runtime.Object{newLogsPod("web-1", appLabels, nil, "app")},
+			expectedError: "container 'sidecar' not found in the pods",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClientset := fake.NewClientset(tt.objs...)
+			fakeClientset.PrependReactor("get", "pods", logsReactor(tt.logs))
+			c := &client.Client{
+				ClientSetCreator: func(inConfig *rest.Config) (kubernetes.Interface, error) {
+					return fakeClientset, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.getPodLogs(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			}
+		})
+	}
+}
+
+func TestParseLogLine(t *testing.T) {
+	line := parseLogLine("2025-01-01T10:00:00.123456789Z hello world", "web-1/app")
+	assert.Equal(t, "hello world", line.text)
+	assert.Equal(t, "2025-01-01T10:00:00.123456789Z", line.timestamp.Format("2006-01-02T15:04:05.999999999Z07:00"))
+
+	line = parseLogLine("no timestamp here", "web-1/app")
+	assert.Equal(t, "no timestamp here", line.text)
+	assert.True(t, line.timestamp.IsZero())
+	assert.False(t, strings.HasPrefix(formatLogLine(line, true, false), " "))
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rancher/rancher-ai-mcp/internal/middleware"
 	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/diagnosis"
 	"github.com/rancher/rancher-ai-mcp/pkg/response"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/ptr"
@@ -20,6 +22,7 @@ import (
 
 const (
 	podLogsTailLines int64 = 50
+	diagnosisKey           = "diagnosis"
 )
 
 // containerLogs holds logs for multiple containers.
@@ -49,60 +52,10 @@ func (t *Tools) inspectPod(ctx context.Context, toolReq *mcp.CallToolRequest, pa
 		return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
 	}
 
-	// find the parent of the pod
-	var replicaSetName string
-	for _, or := range pod.OwnerReferences {
-		if or.Kind == "ReplicaSet" {
-			replicaSetName = or.Name
-			break
-		}
-	}
-
-	var parentResource *unstructured.Unstructured
-
-	// ReplicaSet may not exist
-	if replicaSetName != "" {
-		replicaSetResource, err := t.client.GetResource(ctx, client.GetParams{
-			Cluster:   params.Cluster,
-			Kind:      "replicaset",
-			Namespace: params.Namespace,
-			Name:      replicaSetName,
-			Token:     middleware.Token(ctx),
-		})
-		if err != nil {
-			zap.L().Error("failed to get ReplicaSet", zap.String("tool", "inspectPod"), zap.Error(err))
-			return nil, nil, err
-		}
-
-		var replicaSet appsv1.ReplicaSet
-		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(replicaSetResource.Object, &replicaSet); err != nil {
-			zap.L().Error("failed to convert unstructured object to ReplicaSet", zap.String("tool", "inspectPod"), zap.Error(err))
-			return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
-		}
-
-		var parentName, parentKind string
-		for _, or := range replicaSet.OwnerReferences {
-			if or.Kind == "Deployment" || or.Kind == "StatefulSet" || or.Kind == "DaemonSet" {
-				parentName = or.Name
-				parentKind = or.Kind
-				break
-			}
-		}
-
-		if parentName != "" {
-			var err error
-			parentResource, err = t.client.GetResource(ctx, client.GetParams{
-				Cluster:   params.Cluster,
-				Kind:      parentKind,
-				Namespace: params.Namespace,
-				Name:      parentName,
-				Token:     middleware.Token(ctx),
-			})
-			if err != nil {
-				zap.L().Error("failed to get parent resource", zap.String("tool", "inspectPod"), zap.Error(err))
-				return nil, nil, err
-			}
-		}
+	parentResource, err := t.podParent(ctx, params.Cluster, params.Namespace, pod)
+	if err != nil {
+		zap.L().Error("failed to get parent resource", zap.String("tool", "inspectPod"), zap.Error(err))
+		return nil, nil, err
 	}
 
 	// ignore error as Metrics Server might not be installed in the cluster
@@ -114,7 +67,7 @@ func (t *Tools) inspectPod(ctx context.Context, toolReq *mcp.CallToolRequest, pa
 		Token:     middleware.Token(ctx),
 	})
 
-	logs, err := t.getPodLogs(ctx, params.Cluster, middleware.Token(ctx), pod)
+	logs, err := t.getContainersLogs(ctx, params.Cluster, middleware.Token(ctx), pod)
 	if err != nil {
 		zap.L().Error("failed to get pod logs", zap.String("tool", "inspectPod"), zap.Error(err))
 		return nil, nil, err
@@ -127,6 +80,17 @@ func (t *Tools) inspectPod(ctx context.Context, toolReq *mcp.CallToolRequest, pa
 	if podMetrics != nil {
 		resources = append(resources, podMetrics)
 	}
+	eventObjs := []*unstructured.Unstructured{podResource}
+	if parentResource != nil {
+		eventObjs = append(eventObjs, parentResource)
+	}
+	warnings := t.recentWarnings(ctx, params.Cluster, params.Namespace, eventObjs...)
+	if events := warningEventsObject(warnings); events != nil {
+		resources = append(resources, events)
+	}
+	if diagnosis := t.diagnosePod(ctx, params.Cluster, pod, podResource, warnings, logs); diagnosis != nil {
+		resources = append(resources, diagnosis)
+	}
 
 	mcpResponse, err := response.CreateMcpResponse(resources, params.Cluster)
 	if err != nil {
@@ -139,10 +103,62 @@ func (t *Tools) inspectPod(ctx context.Context, toolReq *mcp.CallToolRequest, pa
 	}, nil, nil
 }
 
-// getPodLogs retrieves the logs for all containers in a pod.
+// podParent returns the workload managing a pod. Pods of a ReplicaSet are managed by its Deployment, when it has one,
+// and StatefulSets, DaemonSets and Jobs own their pods directly. It returns nil for pods without a controller.
+func (t *Tools) podParent(ctx context.Context, cluster, namespace string, pod corev1.Pod) (*unstructured.Unstructured, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return nil, nil
+	}
+
+	parentKind, parentName := owner.Kind, owner.Name
+	switch owner.Kind {
+	case "ReplicaSet":
+		replicaSetResource, err := t.client.GetResource(ctx, client.GetParams{
+			Cluster:   cluster,
+			Kind:      "replicaset",
+			Namespace: namespace,
+			Name:      owner.Name,
+			Token:     middleware.Token(ctx),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var replicaSet appsv1.ReplicaSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(replicaSetResource.Object, &replicaSet); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to ReplicaSet: %w", err)
+		}
+
+		replicaSetOwner := metav1.GetControllerOf(&replicaSet)
+		if replicaSetOwner == nil {
+			return replicaSetResource, nil
+		}
+		switch replicaSetOwner.Kind {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			parentKind, parentName = replicaSetOwner.Kind, replicaSetOwner.Name
+		default:
+			return replicaSetResource, nil
+		}
+	case "StatefulSet", "DaemonSet", "Job":
+	default:
+		return nil, nil
+	}
+
+	return t.client.GetResource(ctx, client.GetParams{
+		Cluster:   cluster,
+		Kind:      parentKind,
+		Namespace: namespace,
+		Name:      parentName,
+		Token:     middleware.Token(ctx),
+	})
+}
+
+// getContainersLogs retrieves the logs for all containers in a pod.
 // It returns the logs as an unstructured object with container names as keys.
-// Only the last 50 lines of logs are retrieved per container to limit payload size.
-func (t *Tools) getPodLogs(ctx context.Context, cluster string, token string, pod corev1.Pod) (*unstructured.Unstructured, error) {
+// Only the last 50 lines of logs are retrieved per container to limit payload size,
+// the getPodLogs tool gives access to the full, previous and init container logs.
+func (t *Tools) getContainersLogs(ctx context.Context, cluster string, token string, pod corev1.Pod) (*unstructured.Unstructured, error) {
 	clientset, err := t.client.CreateClientSet(ctx, token, cluster)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clientset: %w", err)
@@ -187,3 +203,49 @@ func (t *Tools) getPodLogs(ctx context.Context, cluster string, token string, po
 
 	return &unstructured.Unstructured{Object: map[string]any{"pod-logs": logs.Logs}}, nil
 }
+
+// diagnosePod returns the findings of the diagnosis rules for the pod, or nil when the rules find nothing wrong.
+// The node is only read to check its conditions, so failing to read it doesn't prevent the diagnosis.
+func (t *Tools) diagnosePod(ctx context.Context, cluster string, pod corev1.Pod, podResource *unstructured.Unstructured, warnings []corev1.Event, logs *unstructured.Unstructured) *unstructured.Unstructured {
+	input := diagnosis.Input{Pod: pod, Logs: map[string]string{}}
+	for _, event := range warnings {
+		if involves(event, podResource) {
+			input.Events = append(input.Events, event)
+		}
+	}
+	if containerLogs, ok := logs.Object["pod-logs"].(map[string]any); ok {
+		for container, log := range containerLogs {
+			if text, ok := log.(string); ok {
+				input.Logs[container] = text
+			}
+		}
+	}
+	if pod.Spec.NodeName != "" {
+		nodeResource, err := t.client.GetResource(ctx, client.GetParams{
+			Cluster: cluster,
+			Kind:    "node",
+			Name:    pod.Spec.NodeName,
+			Token:   middleware.Token(ctx),
+		})
+		if err != nil {
+			zap.L().Debug("failed to get the node of the pod", zap.String("node", pod.Spec.NodeName), zap.Error(err))
+		} else {
+			var node corev1.Node
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(nodeResource.Object, &node); err == nil {
+				input.Node = &node
+			}
+		}
+	}
+
+	findings := diagnosis.Diagnose(input)
+	if len(findings) == 0 {
+		return nil
+	}
+	obj, err := newKeyedObject(diagnosisKey, findings)
+	if err != nil {
+		zap.L().Warn("failed to convert diagnosis", zap.Error(err))
+		return nil
+	}
+
+	return obj
+}
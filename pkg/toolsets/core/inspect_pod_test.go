@@ -1,6 +1,7 @@
 package core
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -93,6 +95,7 @@ func inspectPodScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme)
 	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
 	return scheme
 }
 
@@ -546,6 +549,105 @@ func TestInspectPod(t *testing.T) {
 				]
 			}`,
 		},
+		"inspect pod - job parent": {
+			params: specificResourceParams{
+				Name:      "migrate-abc",
+				Namespace: "default",
+				Cluster:   "local",
+			},
+			fakeClientset: fake.NewSimpleClientset(),
+			fakeDynClient: dynamicfake.NewSimpleDynamicClient(inspectPodScheme(),
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "migrate-abc",
+						Namespace: "default",
+						OwnerReferences: []metav1.OwnerReference{{
+							APIVersion: "batch/v1",
+							Kind:       "Job",
+							Name:       "migrate",
+							Controller: ptr.To(true),
+						}},
+					},
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "migrate", Image: "migrate:latest"}}},
+				},
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "migrate",
+						Namespace: "default",
+					},
+				},
+			),
+			requestURL: fakeUrl,
+			expectedResult: `{
+				"llm": [
+					{
+						"apiVersion": "v1",
+						"kind": "Pod",
+						"metadata": {
+							"name": "migrate-abc",
+							"namespace": "default",
+							"ownerReferences": [
+								{
+									"apiVersion": "batch/v1",
+									"controller": true,
+									"kind": "Job",
+									"name": "migrate",
+									"uid": ""
+								}
+							]
+						},
+						"spec": {
+							"containers": [
+								{
+									"image": "migrate:latest",
+									"name": "migrate",
+									"resources": {}
+								}
+							]
+						},
+						"status": {}
+					},
+					{
+						"pod-logs": {
+							"migrate": "fake logs"
+						}
+					},
+					{
+						"apiVersion": "batch/v1",
+						"kind": "Job",
+						"metadata": {
+							"name": "migrate",
+							"namespace": "default"
+						},
+						"spec": {
+							"template": {
+								"metadata": {},
+								"spec": {
+									"containers": null
+								}
+							}
+						},
+						"status": {}
+					}
+				],
+				"uiContext": [
+					{
+						"cluster": "local",
+						"kind": "Pod",
+						"name": "migrate-abc",
+						"namespace": "default",
+						"type": "pod"
+					},
+					{
+						"cluster": "local",
+						"kind": "Job",
+						"name": "migrate",
+						"namespace": "default",
+						"type": "batch.job"
+					}
+				]
+			}`,
+		},
 		"inspect pod - no replicaset parent": {
 			params: specificResourceParams{
 				Name:      "standalone-pod",
@@ -647,3 +749,60 @@ func TestInspectPod(t *testing.T) {
 		})
 	}
 }
+
+func TestInspectPodDiagnosis(t *testing.T) {
+	fakeToken := "fakeToken"
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", UID: "web-1-uid"},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			Containers: []corev1.Container{{
+				Name:    "app",
+				Image:   "web:1",
+				EnvFrom: []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "web-config"}}}},
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CreateContainerConfigError", Message: `configmap "web-config" not found`}},
+			}},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}},
+	}
+	c := &client.Client{
+		ClientSetCreator: func(inConfig *rest.Config) (kubernetes.Interface, error) {
+			return fake.NewClientset(pod), nil
+		},
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return dynamicfake.NewSimpleDynamicClient(inspectPodScheme(), pod, node), nil
+		},
+	}
+	tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+	result, _, err := tools.inspectPod(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, specificResourceParams{Cluster: "local", Namespace: "default", Name: "web-1"})
+
+	require.NoError(t, err)
+	var resp struct {
+		LLM []map[string]json.RawMessage `json:"llm"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+	require.Len(t, resp.LLM, 3)
+	assert.JSONEq(t, `[{
+		"class": "MissingConfigMap",
+		"container": "app",
+		"summary": "ConfigMap \"web-config\" referenced by the pod doesn't exist in namespace default",
+		"evidence": [
+			"container app is waiting: configmap \"web-config\" not found",
+			"ConfigMap web-config is referenced by envFrom of container app"
+		],
+		"remediations": [
+			"Create the ConfigMap web-config in namespace default, or fix the name of the reference",
+			"Mark the reference as optional if the pod can run without it"
+		]
+	}]`, string(resp.LLM[2]["diagnosis"]))
+}
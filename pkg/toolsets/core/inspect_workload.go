@@ -0,0 +1,323 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"github.com/rancher/rancher-ai-mcp/pkg/workload"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const defaultWorkloadRuns = 5
+
+var zapInspectWorkload = zap.String("tool", "inspectWorkload")
+
+// workloadKinds are the kinds supported by inspectWorkload.
+var workloadKinds = []string{"deployment", "statefulset", "daemonset", "replicaset", "job", "cronjob"}
+
+// inspectWorkloadParams specifies the parameters needed to inspect a workload.
+type inspectWorkloadParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace" jsonschema:"the namespace of the workload"`
+	Kind      string `json:"kind" jsonschema:"the kind of the workload: Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob"`
+	Name      string `json:"name" jsonschema:"the name of the workload"`
+	Runs      int    `json:"runs,omitempty" jsonschema:"the number of most recent runs returned for Jobs and CronJobs, defaults to 5"`
+}
+
+// workloadRun is the outcome of a run of a CronJob, which is a Job, or of a Job, which is a Pod.
+type workloadRun struct {
+	Name           string `json:"name"`
+	Outcome        string `json:"outcome"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+	StartTime      string `json:"startTime,omitempty"`
+	CompletionTime string `json:"completionTime,omitempty"`
+}
+
+// inspectWorkload returns a workload with its pods and recent warning events. Deployments, StatefulSets and DaemonSets
+// include their revision history and rollout status, and Jobs and CronJobs their most recent runs.
+func (t *Tools) inspectWorkload(ctx context.Context, toolReq *mcp.CallToolRequest, params inspectWorkloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("inspectWorkload called")
+
+	kind := strings.ToLower(params.Kind)
+	if !slices.Contains(workloadKinds, kind) {
+		return nil, nil, fmt.Errorf("unsupported workload kind '%s', it must be one of Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob", params.Kind)
+	}
+	runs := params.Runs
+	if runs <= 0 {
+		runs = defaultWorkloadRuns
+	}
+
+	workloadResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      kind,
+		Namespace: params.Namespace,
+		Name:      params.Name,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get workload", zapInspectWorkload, zap.Error(err))
+		return nil, nil, err
+	}
+
+	var pods, details []*unstructured.Unstructured
+	switch kind {
+	case "cronjob":
+		pods, details, err = t.cronJobDetails(ctx, params.Cluster, workloadResource, runs)
+	case "job":
+		pods, details, err = t.jobDetails(ctx, params.Cluster, workloadResource, runs)
+	case "replicaset":
+		pods, err = t.workloadPods(ctx, params.Cluster, workloadResource)
+	default:
+		pods, details, err = t.rolloutDetails(ctx, params.Cluster, workloadResource)
+	}
+	if err != nil {
+		zap.L().Error("failed to inspect workload", zapInspectWorkload, zap.Error(err))
+		return nil, nil, err
+	}
+
+	resources := append([]*unstructured.Unstructured{workloadResource}, pods...)
+	if events := t.recentWarningEvents(ctx, params.Cluster, params.Namespace, resources...); events != nil {
+		details = append(details, events)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse(append(resources, details...), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapInspectWorkload, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// workloadPods returns the pods matching the selector of a workload.
+func (t *Tools) workloadPods(ctx context.Context, cluster string, obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	selector, err := workload.PodSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       cluster,
+		Kind:          "pod",
+		Namespace:     obj.GetNamespace(),
+		LabelSelector: selector,
+		Token:         middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods: %w", err)
+	}
+
+	return pods, nil
+}
+
+// rolloutDetails returns the pods, revision history and rollout status of a Deployment, StatefulSet or DaemonSet.
+func (t *Tools) rolloutDetails(ctx context.Context, cluster string, obj *unstructured.Unstructured) ([]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	pods, err := t.workloadPods(ctx, cluster, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	revisions, err := workload.ListRevisions(ctx, t.client, cluster, middleware.Token(ctx), obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	revisionHistory, err := newKeyedObject("revision-history", revisions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status, err := workload.RolloutStatus(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	rolloutStatus, err := newKeyedObject("rollout-status", status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pods, []*unstructured.Unstructured{revisionHistory, rolloutStatus}, nil
+}
+
+// jobDetails returns the pods of a Job and its most recent runs, which are its pods.
+func (t *Tools) jobDetails(ctx context.Context, cluster string, obj *unstructured.Unstructured, limit int) ([]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	pods, err := t.workloadPods(ctx, cluster, obj)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typedPods := make([]corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		var typedPod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pod.Object, &typedPod); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+		}
+		typedPods = append(typedPods, typedPod)
+	}
+	slices.SortStableFunc(typedPods, func(a, b corev1.Pod) int { return podStartTime(b).Compare(podStartTime(a)) })
+
+	typedPods = typedPods[:min(limit, len(typedPods))]
+	jobRuns := make([]workloadRun, 0, len(typedPods))
+	for _, pod := range typedPods {
+		jobRuns = append(jobRuns, podRun(pod))
+	}
+	runs, err := newKeyedObject("runs", jobRuns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pods, []*unstructured.Unstructured{runs}, nil
+}
+
+// cronJobDetails returns the most recent runs of a CronJob, which are the Jobs it created, and their pods.
+func (t *Tools) cronJobDetails(ctx context.Context, cluster string, obj *unstructured.Unstructured, limit int) ([]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	jobResources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:   cluster,
+		Kind:      "job",
+		Namespace: obj.GetNamespace(),
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	var jobs []batchv1.Job
+	var runResources []*unstructured.Unstructured
+	for _, jobResource := range jobResources {
+		if !workload.IsControlledBy(jobResource, obj) {
+			continue
+		}
+		var job batchv1.Job
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(jobResource.Object, &job); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert unstructured object to Job: %w", err)
+		}
+		jobs = append(jobs, job)
+		runResources = append(runResources, jobResource)
+	}
+	slices.SortStableFunc(jobs, func(a, b batchv1.Job) int { return jobStartTime(b).Compare(jobStartTime(a)) })
+	jobs = jobs[:min(limit, len(jobs))]
+
+	cronJobRuns := make([]workloadRun, 0, len(jobs))
+	for _, job := range jobs {
+		cronJobRuns = append(cronJobRuns, jobRun(job))
+	}
+	runs, err := newKeyedObject("runs", cronJobRuns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only the pods of the returned runs are relevant
+	runResources = slices.DeleteFunc(runResources, func(jobResource *unstructured.Unstructured) bool {
+		return !slices.ContainsFunc(jobs, func(job batchv1.Job) bool { return job.Name == jobResource.GetName() })
+	})
+	var pods []*unstructured.Unstructured
+	if len(runResources) > 0 {
+		podResources, err := t.client.GetResources(ctx, client.ListParams{
+			Cluster:   cluster,
+			Kind:      "pod",
+			Namespace: obj.GetNamespace(),
+			Token:     middleware.Token(ctx),
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get pods: %w", err)
+		}
+		for _, pod := range podResources {
+			if slices.ContainsFunc(runResources, func(jobResource *unstructured.Unstructured) bool { return workload.IsControlledBy(pod, jobResource) }) {
+				pods = append(pods, pod)
+			}
+		}
+	}
+
+	return pods, []*unstructured.Unstructured{runs}, nil
+}
+
+// jobRun returns the outcome of a Job from its conditions.
+func jobRun(job batchv1.Job) workloadRun {
+	run := workloadRun{Name: job.Name, Outcome: "Running"}
+	if job.Status.StartTime != nil {
+		run.StartTime = job.Status.StartTime.UTC().Format(time.RFC3339)
+	} else if job.Status.Active == 0 {
+		run.Outcome = "Pending"
+	}
+	if job.Status.CompletionTime != nil {
+		run.CompletionTime = job.Status.CompletionTime.UTC().Format(time.RFC3339)
+	}
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			run.Outcome = "Succeeded"
+		case batchv1.JobFailed:
+			run.Outcome = "Failed"
+			run.Reason = condition.Reason
+			run.Message = condition.Message
+		case batchv1.JobSuspended:
+			run.Outcome = "Suspended"
+		}
+	}
+
+	return run
+}
+
+// podRun returns the outcome of a Pod run by a Job, with the reason of the first container that failed.
+func podRun(pod corev1.Pod) workloadRun {
+	run := workloadRun{
+		Name:    pod.Name,
+		Outcome: string(pod.Status.Phase),
+		Reason:  pod.Status.Reason,
+		Message: pod.Status.Message,
+	}
+	if pod.Status.StartTime != nil {
+		run.StartTime = pod.Status.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	var completion time.Time
+	for _, status := range append(slices.Clone(pod.Status.InitContainerStatuses), pod.Status.ContainerStatuses...) {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			continue
+		}
+		if terminated.FinishedAt.After(completion) {
+			completion = terminated.FinishedAt.Time
+		}
+		if terminated.ExitCode != 0 && run.Reason == "" {
+			run.Reason = terminated.Reason
+			run.Message = fmt.Sprintf("container %s exited with code %d", status.Name, terminated.ExitCode)
+		}
+	}
+	if !completion.IsZero() && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed) {
+		run.CompletionTime = completion.UTC().Format(time.RFC3339)
+	}
+
+	return run
+}
+
+func jobStartTime(job batchv1.Job) time.Time {
+	if job.Status.StartTime != nil {
+		return job.Status.StartTime.Time
+	}
+
+	return job.CreationTimestamp.Time
+}
+
+func podStartTime(pod corev1.Pod) time.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+
+	return pod.CreationTimestamp.Time
+}
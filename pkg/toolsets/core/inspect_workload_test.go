@@ -0,0 +1,227 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+func workloadScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	_ = batchv1.AddToScheme(scheme)
+	return scheme
+}
+
+func controlledBy(kind, name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: kind, Name: name, UID: types.UID(name + "-uid"), Controller: ptr.To(true)}}
+}
+
+func TestInspectWorkload(t *testing.T) {
+	fakeToken := "fakeToken"
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(start.Add(time.Hour))
+	labels := map[string]string{"app": "web"}
+	selector := &metav1.LabelSelector{MatchLabels: labels}
+
+	webPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-2-abc", Namespace: "default", Labels: labels, OwnerReferences: controlledBy("ReplicaSet", "web-2")}}
+	otherPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{"app": "other"}}}
+	replicaSet := func(name, revision, image string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Labels:          labels,
+				Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+				OwnerReferences: controlledBy("Deployment", "web"),
+			},
+			Spec: appsv1.ReplicaSetSpec{
+				Replicas: ptr.To(int32(1)),
+				Selector: selector,
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}},
+			},
+		}
+	}
+	cronJobRun := func(name string, startTime metav1.Time, condition batchv1.JobConditionType, reason string) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid"), OwnerReferences: controlledBy("CronJob", "backup")},
+			Status: batchv1.JobStatus{
+				StartTime:  &startTime,
+				Conditions: []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Reason: reason}},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		params          inspectWorkloadParams
+		objs            []runtime.Object
+		expectedPods    []string
+		expectedDetails map[string]string
+		expectedError   string
+	}{
+		"deployment with revision history and rollout status": {
+			params: inspectWorkloadParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"},
+			objs: []runtime.Object{
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid", Generation: 3},
+					Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1)), Selector: selector},
+					Status:     appsv1.DeploymentStatus{ObservedGeneration: 3, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1},
+				},
+				replicaSet("web-1", "1", "web:1"),
+				replicaSet("web-2", "2", "web:2"),
+				webPod,
+				otherPod,
+			},
+			expectedPods: []string{"web-2-abc"},
+			expectedDetails: map[string]string{
+				"revision-history": `[
+					{"revision": 2, "name": "web-2", "images": ["web:2"], "replicas": 1},
+					{"revision": 1, "name": "web-1", "images": ["web:1"], "replicas": 1}
+				]`,
+				"rollout-status": `{"state": "Progressing", "message": "waiting for deployment \"web\" rollout to finish: 1 old replicas are pending termination"}`,
+			},
+		},
+		"job with its runs": {
+			params: inspectWorkloadParams{Cluster: "local", Namespace: "default", Kind: "job", Name: "migrate", Runs: 1},
+			objs: []runtime.Object{
+				&batchv1.Job{
+					ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+					Spec:       batchv1.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "migrate"}}},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "migrate-1", Namespace: "default", Labels: map[string]string{"job-name": "migrate"}},
+					Status: corev1.PodStatus{
+						Phase:     corev1.PodFailed,
+						StartTime: &start,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "migrate",
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", FinishedAt: start}},
+						}},
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "migrate-2", Namespace: "default", Labels: map[string]string{"job-name": "migrate"}},
+					Status: corev1.PodStatus{
+						Phase:     corev1.PodFailed,
+						StartTime: &later,
+						ContainerStatuses: []corev1.ContainerStatus{{
+							Name:  "migrate",
+							State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", FinishedAt: later}},
+						}},
+					},
+				},
+			},
+			expectedPods: []string{"migrate-1", "migrate-2"},
+			expectedDetails: map[string]string{
+				"runs": `[
+					{"name": "migrate-2", "outcome": "Failed", "reason": "OOMKilled", "message": "container migrate exited with code 137", "startTime": "2025-01-01T11:00:00Z", "completionTime": "2025-01-01T11:00:00Z"}
+				]`,
+			},
+		},
+		"cronjob with its most recent runs": {
+			params: inspectWorkloadParams{Cluster: "local", Namespace: "default", Kind: "CronJob", Name: "backup", Runs: 2},
+			objs: []runtime.Object{
+				&batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default", UID: "backup-uid"}},
+				cronJobRun("backup-1", metav1.NewTime(start.Add(-time.Hour)), batchv1.JobComplete, ""),
+				cronJobRun("backup-2", start, batchv1.JobComplete, ""),
+				cronJobRun("backup-3", later, batchv1.JobFailed, "BackoffLimitExceeded"),
+				&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: "default"}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "backup-1-abc", Namespace: "default", OwnerReferences: controlledBy("Job", "backup-1")}},
+				&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "backup-3-abc", Namespace: "default", OwnerReferences: controlledBy("Job", "backup-3")}},
+			},
+			expectedPods: []string{"backup-3-abc"},
+			expectedDetails: map[string]string{
+				"runs": `[
+					{"name": "backup-3", "outcome": "Failed", "reason": "BackoffLimitExceeded", "startTime": "2025-01-01T11:00:00Z"},
+					{"name": "backup-2", "outcome": "Succeeded", "startTime": "2025-01-01T10:00:00Z"}
+				]`,
+			},
+		},
+		"unsupported kind": {
+			params:        inspectWorkloadParams{Cluster: "local", Namespace: "default", Kind: "Service", Name: "web"},
+			expectedError: "unsupported workload kind 'Service'",
+		},
+		"workload not found": {
+			params:        inspectWorkloadParams{Cluster: "local", Namespace: "default", Kind: "StatefulSet", Name: "db"},
+			expectedError: `statefulsets.apps "db" not found`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(workloadScheme(), tt.objs...)
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.inspectWorkload(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM []map[string]json.RawMessage `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+
+			var pods []string
+			details := map[string]string{}
+			for _, obj := range resp.LLM[1:] {
+				if string(obj["kind"]) == `"Pod"` {
+					var metadata metav1.ObjectMeta
+					require.NoError(t, json.Unmarshal(obj["metadata"], &metadata))
+					pods = append(pods, metadata.Name)
+					continue
+				}
+				for key, value := range obj {
+					details[key] = string(value)
+				}
+			}
+			assert.ElementsMatch(t, tt.expectedPods, pods)
+			require.Len(t, details, len(tt.expectedDetails))
+			for key, expected := range tt.expectedDetails {
+				assert.JSONEq(t, expected, details[key], key)
+			}
+		})
+	}
+}
+
+func TestPodRun(t *testing.T) {
+	start := metav1.NewTime(time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC))
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate-1"},
+		Status: corev1.PodStatus{
+			Phase:     corev1.PodRunning,
+			StartTime: &start,
+			InitContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "init",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, FinishedAt: start}},
+			}},
+		},
+	}
+
+	assert.Equal(t, workloadRun{Name: "migrate-1", Outcome: "Running", StartTime: "2025-01-01T10:00:00Z"}, podRun(pod))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rancher/rancher-ai-mcp/internal/middleware"
 	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
 	"github.com/rancher/rancher-ai-mcp/pkg/response"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,10 +39,14 @@ type updateKubernetesResourceParams struct {
 func (t *Tools) updateKubernetesResource(ctx context.Context, toolReq *mcp.CallToolRequest, params updateKubernetesResourceParams) (*mcp.CallToolResult, any, error) {
 	zap.L().Debug("updateKubernetesResource called")
 
-	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), params.Namespace, params.Cluster, converter.K8sKindsToGVRs[strings.ToLower(params.Kind)])
+	gvr := converter.K8sKindsToGVRs[strings.ToLower(params.Kind)]
+	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), params.Namespace, params.Cluster, gvr)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := t.requireClusterPermission(ctx, params.Cluster, params.Namespace, rbac.Attributes{Verb: "patch", Group: gvr.Group, Resource: gvr.Resource, Name: params.Name}); err != nil {
+		return nil, nil, err
+	}
 
 	patchBytes, err := json.Marshal(params.Patch)
 	if err != nil {
@@ -15,6 +15,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
 )
 
@@ -204,6 +206,9 @@ func TestUpdateKubernetesResource(t *testing.T) {
 				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
 					return tt.fakeDynClient, nil
 				},
+				ClientSetCreator: func(inConfig *rest.Config) (kubernetes.Interface, error) {
+					return withAccessReviews(fake.NewClientset(), true), nil
+				},
 			}
 			tools := NewTools(test.WrapClient(c, fakeToken), false)
 			req := test.NewCallToolRequest(tt.requestURL)
@@ -0,0 +1,110 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type listClusterMembersParams struct {
+	Cluster string `json:"cluster" jsonschema:"the name of the cluster"`
+}
+
+type clusterMemberParams struct {
+	Cluster      string `json:"cluster" jsonschema:"the name of the cluster"`
+	Member       string `json:"member" jsonschema:"the Rancher user (ID, username or display name), the Rancher group (ID or display name) or the principal ID of a user or group of the authentication provider (e.g. github_team://1234)"`
+	MemberKind   string `json:"memberKind,omitempty" jsonschema:"user or group. Inferred from principal IDs, defaults to user otherwise"`
+	RoleTemplate string `json:"roleTemplate" jsonschema:"the name of a role template with the cluster context (e.g. cluster-owner, cluster-member)"`
+}
+
+type removeClusterMemberParams struct {
+	Cluster      string `json:"cluster" jsonschema:"the name of the cluster"`
+	Member       string `json:"member" jsonschema:"the Rancher user (ID, username or display name), the Rancher group (ID or display name) or the principal ID of a user or group of the authentication provider (e.g. github_team://1234)"`
+	MemberKind   string `json:"memberKind,omitempty" jsonschema:"user or group. Inferred from principal IDs, defaults to user otherwise"`
+	RoleTemplate string `json:"roleTemplate,omitempty" jsonschema:"the role template to remove. All the roles of the member in the cluster are removed when it is empty"`
+}
+
+// clusterMembership returns the membership of a cluster, its members are bound with ClusterRoleTemplateBindings
+// stored in the namespace of the cluster ID.
+func (t *Tools) clusterMembership(ctx context.Context, cluster string) (membership, error) {
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), cluster)
+	if err != nil {
+		return membership{}, err
+	}
+
+	return membership{
+		Kind:        crtbKind,
+		Namespace:   clusterID,
+		Context:     clusterContext,
+		Fields:      map[string]any{"clusterName": clusterID},
+		ClusterID:   clusterID,
+		Description: "cluster " + clusterID,
+	}, nil
+}
+
+// listClusterMembers returns the members of a cluster with their role.
+func (t *Tools) listClusterMembers(ctx context.Context, toolReq *mcp.CallToolRequest, params listClusterMembersParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listClusterMembers called")
+
+	ms, err := t.clusterMembership(ctx, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "listClusterMembers"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.listMembers(ctx, ms, "listClusterMembers")
+}
+
+// addClusterMember gives a role in a cluster to a user or group.
+func (t *Tools) addClusterMember(ctx context.Context, toolReq *mcp.CallToolRequest, params clusterMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("addClusterMember called")
+
+	ms, err := t.clusterMembership(ctx, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "addClusterMember"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.addMember(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "addClusterMember")
+}
+
+// addClusterMemberPlan returns the ClusterRoleTemplateBinding addClusterMember would create without creating it.
+func (t *Tools) addClusterMemberPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params clusterMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("addClusterMemberPlan called")
+
+	ms, err := t.clusterMembership(ctx, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "addClusterMemberPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.addMemberPlan(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "addClusterMemberPlan")
+}
+
+// removeClusterMember removes a role, or all the roles, of a user or group in a cluster.
+func (t *Tools) removeClusterMember(ctx context.Context, toolReq *mcp.CallToolRequest, params removeClusterMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("removeClusterMember called")
+
+	ms, err := t.clusterMembership(ctx, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "removeClusterMember"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.removeMember(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "removeClusterMember")
+}
+
+// removeClusterMemberPlan returns the ClusterRoleTemplateBindings removeClusterMember would delete without deleting them.
+func (t *Tools) removeClusterMemberPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params removeClusterMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("removeClusterMemberPlan called")
+
+	ms, err := t.clusterMembership(ctx, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "removeClusterMemberPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.removeMemberPlan(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "removeClusterMemberPlan")
+}
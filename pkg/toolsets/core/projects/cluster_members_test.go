@@ -0,0 +1,193 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var crtbGVR = schema.GroupVersionResource{Group: "management.cattle.io", Version: "v3", Resource: "clusterroletemplatebindings"}
+
+func TestListClusterMembers(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+	tools := newMembersTools(fakeDynClient, fakeToken)
+
+	result, _, err := tools.listClusterMembers(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, listClusterMembersParams{Cluster: "prod"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"llm": [
+			{
+				"apiVersion": "management.cattle.io/v3",
+				"kind": "ClusterRoleTemplateBinding",
+				"metadata": {"name": "crtb-alice", "namespace": "c-abc12"},
+				"userPrincipalName": "github_user://100",
+				"roleTemplateName": "cluster-member"
+			}
+		],
+		"uiContext": [
+			{"cluster": "c-abc12", "kind": "ClusterRoleTemplateBinding", "name": "crtb-alice", "namespace": "c-abc12", "type": "clusterroletemplatebinding"}
+		]
+	}`, result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestAddClusterMember(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params         clusterMemberParams
+		expectedResult string
+		expectedError  string
+	}{
+		"add a user by username": {
+			params: clusterMemberParams{Cluster: "prod", Member: "bob2", RoleTemplate: "cluster-member"},
+			expectedResult: `{
+				"llm": [
+					{
+						"apiVersion": "management.cattle.io/v3",
+						"kind": "ClusterRoleTemplateBinding",
+						"metadata": {"name": "", "namespace": "c-abc12"},
+						"userName": "u-bob2",
+						"roleTemplateName": "cluster-member"
+					}
+				],
+				"uiContext": [
+					{"cluster": "c-abc12", "kind": "ClusterRoleTemplateBinding", "name": "", "namespace": "c-abc12", "type": "clusterroletemplatebinding"}
+				]
+			}`,
+		},
+		"the member already has the role through one of its principals": {
+			params:        clusterMemberParams{Cluster: "prod", Member: "alice", RoleTemplate: "cluster-member"},
+			expectedError: "user u-alice already has the role 'cluster-member' in cluster c-abc12 through ClusterRoleTemplateBinding crtb-alice",
+		},
+		"project role template": {
+			params:        clusterMemberParams{Cluster: "prod", Member: "bob2", RoleTemplate: "project-member"},
+			expectedError: "role template 'project-member' has the context 'project', only role templates with the context 'cluster' can be bound",
+		},
+		"unknown role template": {
+			params:        clusterMemberParams{Cluster: "prod", Member: "bob2", RoleTemplate: "cluster-admin"},
+			expectedError: `roletemplates.management.cattle.io "cluster-admin" not found`,
+		},
+		"missing role template": {
+			params:        clusterMemberParams{Cluster: "prod", Member: "bob2"},
+			expectedError: "member and roleTemplate must be set",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+			tools := newMembersTools(fakeDynClient, fakeToken)
+
+			result, _, err := tools.addClusterMember(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			bindings, err := fakeDynClient.Resource(crtbGVR).Namespace("c-abc12").List(t.Context(), metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Len(t, bindings.Items, 2)
+		})
+	}
+}
+
+func TestAddClusterMemberPlan(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+	tools := newMembersTools(fakeDynClient, fakeToken)
+
+	result, _, err := tools.addClusterMemberPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, clusterMemberParams{Cluster: "prod", Member: "github_team://7", RoleTemplate: "cluster-member"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"type": "create",
+		"resource": {"name": "", "kind": "ClusterRoleTemplateBinding", "cluster": "local", "namespace": "c-abc12"},
+		"payload": {
+			"apiVersion": "management.cattle.io/v3",
+			"kind": "ClusterRoleTemplateBinding",
+			"metadata": {"generateName": "crtb-", "namespace": "c-abc12"},
+			"clusterName": "c-abc12",
+			"groupPrincipalName": "github_team://7",
+			"roleTemplateName": "cluster-member"
+		}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	bindings, err := fakeDynClient.Resource(crtbGVR).Namespace("c-abc12").List(t.Context(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, bindings.Items, 1, "the plan must not create the binding")
+}
+
+func TestRemoveClusterMember(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params         removeClusterMemberParams
+		expectedResult string
+		expectedError  string
+	}{
+		"remove all the roles of a user": {
+			params: removeClusterMemberParams{Cluster: "prod", Member: "alice"},
+			expectedResult: `{"llm": {"removed": [{
+				"apiVersion": "management.cattle.io/v3",
+				"kind": "ClusterRoleTemplateBinding",
+				"metadata": {"name": "crtb-alice", "namespace": "c-abc12"},
+				"userPrincipalName": "github_user://100",
+				"roleTemplateName": "cluster-member"
+			}]}}`,
+		},
+		"role the member doesn't have": {
+			params:        removeClusterMemberParams{Cluster: "prod", Member: "alice", RoleTemplate: "cluster-owner"},
+			expectedError: "user u-alice doesn't have the role 'cluster-owner' in cluster c-abc12",
+		},
+		"not a member": {
+			params:        removeClusterMemberParams{Cluster: "prod", Member: "github_team://7"},
+			expectedError: "group github_team://7 is not a member of cluster c-abc12",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+			tools := newMembersTools(fakeDynClient, fakeToken)
+
+			result, _, err := tools.removeClusterMember(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			_, err = fakeDynClient.Resource(crtbGVR).Namespace("c-abc12").Get(t.Context(), "crtb-alice", metav1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err), "the binding must be deleted")
+		})
+	}
+}
+
+func TestRemoveClusterMemberPlan(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+	tools := newMembersTools(fakeDynClient, fakeToken)
+
+	result, _, err := tools.removeClusterMemberPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, removeClusterMemberParams{Cluster: "prod", Member: "u-alice", RoleTemplate: "cluster-member"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"type": "delete",
+		"resource": {"name": "crtb-alice", "kind": "ClusterRoleTemplateBinding", "cluster": "local", "namespace": "c-abc12"},
+		"payload": null
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	_, err = fakeDynClient.Resource(crtbGVR).Namespace("c-abc12").Get(t.Context(), "crtb-alice", metav1.GetOptions{})
+	assert.NoError(t, err, "the plan must not delete the binding")
+}
@@ -0,0 +1,137 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// resourceQuotaAnnotation holds the quota of a namespace of a project, Rancher creates its ResourceQuota from it.
+	resourceQuotaAnnotation = "field.cattle.io/resourceQuota"
+	// containerDefaultResourceLimitAnnotation holds the default limits of the containers of a namespace of a project,
+	// Rancher creates its LimitRange from it.
+	containerDefaultResourceLimitAnnotation = "field.cattle.io/containerDefaultResourceLimit"
+	// podSecurityLabelPrefix is the prefix of the Pod Security Admission labels of a namespace.
+	podSecurityLabelPrefix = "pod-security.kubernetes.io/"
+)
+
+// podSecurityLevels are the levels of the Pod Security Standards.
+var podSecurityLevels = []string{"privileged", "baseline", "restricted"}
+
+type createNamespaceParams struct {
+	Cluster            string            `json:"cluster" jsonschema:"the cluster to create the namespace in"`
+	Project            string            `json:"project" jsonschema:"the name or display name of the project the namespace belongs to"`
+	Name               string            `json:"name" jsonschema:"the name of the namespace to be created"`
+	Labels             map[string]string `json:"labels,omitempty" jsonschema:"(optional) labels of the namespace"`
+	PodSecurityEnforce string            `json:"podSecurityEnforce,omitempty" jsonschema:"(optional) the Pod Security level enforced in the namespace: privileged, baseline or restricted"`
+	PodSecurityAudit   string            `json:"podSecurityAudit,omitempty" jsonschema:"(optional) the Pod Security level audited in the namespace: privileged, baseline or restricted"`
+	PodSecurityWarn    string            `json:"podSecurityWarn,omitempty" jsonschema:"(optional) the Pod Security level users are warned about in the namespace: privileged, baseline or restricted"`
+}
+
+// createNamespace creates a namespace in a project with the namespace default quota and container limits of the project.
+func (t *Tools) createNamespace(ctx context.Context, toolReq *mcp.CallToolRequest, params createNamespaceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("createNamespace called", zap.String("cluster", params.Cluster), zap.String("project", params.Project))
+
+	clusterID, ns, err := t.createNamespaceObj(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create namespace object", zap.String("tool", "createNamespace"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), "", clusterID, converter.K8sKindsToGVRs["namespace"])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := resourceInterface.Create(ctx, ns, metav1.CreateOptions{})
+	if err != nil {
+		zap.L().Error("failed to create namespace", zap.String("tool", "createNamespace"), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse([]*unstructured.Unstructured{obj}, clusterID)
+	if err != nil {
+		zap.L().Error("failed to create MCP response", zap.String("tool", "createNamespace"), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create MCP response: %w", err)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// createNamespaceObj returns the cluster ID and the namespace to create. The namespace is assigned to the project and
+// carries the annotations Rancher creates its ResourceQuota and LimitRange from.
+func (t *Tools) createNamespaceObj(ctx context.Context, params createNamespaceParams) (string, *unstructured.Unstructured, error) {
+	if params.Name == "" || params.Project == "" {
+		return "", nil, errors.New("name and project must be set")
+	}
+
+	labels := make(map[string]string)
+	for key, value := range params.Labels {
+		labels[key] = value
+	}
+	for _, psa := range []struct{ mode, level string }{
+		{"enforce", params.PodSecurityEnforce},
+		{"audit", params.PodSecurityAudit},
+		{"warn", params.PodSecurityWarn},
+	} {
+		if psa.level == "" {
+			continue
+		}
+		if !slices.Contains(podSecurityLevels, psa.level) {
+			return "", nil, fmt.Errorf("invalid Pod Security level '%s', it must be privileged, baseline or restricted", psa.level)
+		}
+		labels[podSecurityLabelPrefix+psa.mode] = psa.level
+	}
+
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		return "", nil, err
+	}
+	projectID, project, err := t.getProjectID(ctx, middleware.Token(ctx), clusterID, params.Project)
+	if err != nil {
+		return "", nil, err
+	}
+	labels[projectIDLabel] = projectID
+
+	annotations := map[string]string{
+		projectIDLabel: clusterID + ":" + projectID,
+	}
+	for annotation, field := range map[string][]string{
+		resourceQuotaAnnotation:                 {"spec", "namespaceDefaultResourceQuota"},
+		containerDefaultResourceLimitAnnotation: {"spec", "containerDefaultResourceLimit"},
+	} {
+		value, found, err := unstructured.NestedMap(project.Object, field...)
+		if err != nil || !found || len(value) == 0 {
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal %s: %w", annotation, err)
+		}
+		annotations[annotation] = string(data)
+	}
+
+	ns := &unstructured.Unstructured{
+		Object: make(map[string]any),
+	}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(params.Name)
+	ns.SetLabels(labels)
+	ns.SetAnnotations(annotations)
+
+	return clusterID, ns, nil
+}
@@ -0,0 +1,31 @@
+package projects
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+)
+
+// createNamespacePlan plans the creation of a namespace in a project.
+// It returns the JSON representation of the namespace to be created without actually creating it.
+func (t *Tools) createNamespacePlan(ctx context.Context, _ *mcp.CallToolRequest, params createNamespaceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("createNamespacePlan called", zap.String("cluster", params.Cluster), zap.String("project", params.Project))
+
+	clusterID, ns, err := t.createNamespaceObj(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create namespace object", zap.String("tool", "createNamespacePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{response.NewCreateResourceInput(ns, clusterID)})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", "createNamespacePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
@@ -0,0 +1,142 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCreateNamespace(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params           createNamespaceParams
+		expectedResult   string
+		expectedErrorMsg string
+	}{
+		"project with default quota and limits": {
+			params: createNamespaceParams{
+				Cluster:            "test-cluster",
+				Project:            "Limited",
+				Name:               "team-x",
+				Labels:             map[string]string{"team": "x"},
+				PodSecurityEnforce: "restricted",
+				PodSecurityWarn:    "restricted",
+			},
+			expectedResult: `{
+				"llm": [{
+					"apiVersion": "v1",
+					"kind": "Namespace",
+					"metadata": {
+						"name": "team-x",
+						"labels": {
+							"team": "x",
+							"field.cattle.io/projectId": "limited",
+							"pod-security.kubernetes.io/enforce": "restricted",
+							"pod-security.kubernetes.io/warn": "restricted"
+						},
+						"annotations": {
+							"field.cattle.io/projectId": "test-cluster:limited",
+							"field.cattle.io/resourceQuota": "{\"limit\":{\"limitsCpu\":\"250m\"}}",
+							"field.cattle.io/containerDefaultResourceLimit": "{\"limitsCpu\":\"100m\"}"
+						}
+					}
+				}],
+				"uiContext": [{"namespace": "", "kind": "Namespace", "cluster": "test-cluster", "name": "team-x", "type": "namespace"}]
+			}`,
+		},
+		"project without quota": {
+			params: createNamespaceParams{Cluster: "test-cluster", Project: "my-project", Name: "team-y"},
+			expectedResult: `{
+				"llm": [{
+					"apiVersion": "v1",
+					"kind": "Namespace",
+					"metadata": {
+						"name": "team-y",
+						"labels": {"field.cattle.io/projectId": "my-project"},
+						"annotations": {"field.cattle.io/projectId": "test-cluster:my-project"}
+					}
+				}],
+				"uiContext": [{"namespace": "", "kind": "Namespace", "cluster": "test-cluster", "name": "team-y", "type": "namespace"}]
+			}`,
+		},
+		"existing namespace": {
+			params:           createNamespaceParams{Cluster: "test-cluster", Project: "my-project", Name: "ns-1"},
+			expectedErrorMsg: "already exists",
+		},
+		"invalid Pod Security level": {
+			params:           createNamespaceParams{Cluster: "test-cluster", Project: "my-project", Name: "team-z", PodSecurityAudit: "strict"},
+			expectedErrorMsg: "invalid Pod Security level 'strict', it must be privileged, baseline or restricted",
+		},
+		"missing project": {
+			params:           createNamespaceParams{Cluster: "test-cluster", Name: "team-z"},
+			expectedErrorMsg: "name and project must be set",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", namespaceObjects(), nil)
+
+			result, _, err := tools.createNamespace(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), tt.params)
+
+			if tt.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestCreateNamespacePlan(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+	tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", namespaceObjects(), nil)
+
+	result, _, err := tools.createNamespacePlan(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), createNamespaceParams{
+		Cluster:          "test-cluster",
+		Project:          "my-project",
+		Name:             "team-y",
+		PodSecurityAudit: "baseline",
+	})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"type": "create",
+		"resource": {"name": "team-y", "kind": "Namespace", "cluster": "test-cluster", "namespace": ""},
+		"payload": {
+			"apiVersion": "v1",
+			"kind": "Namespace",
+			"metadata": {
+				"name": "team-y",
+				"labels": {"field.cattle.io/projectId": "my-project", "pod-security.kubernetes.io/audit": "baseline"},
+				"annotations": {"field.cattle.io/projectId": "test-cluster:my-project"}
+			}
+		}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+
+	// the plan must not create the namespace
+	_, _, err = tools.createNamespace(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), createNamespaceParams{
+		Cluster: "test-cluster",
+		Project: "my-project",
+		Name:    "team-y",
+	})
+	assert.NoError(t, err)
+}
+
+// namespaceObjects returns the quota objects with a project setting a namespace default quota and container limits.
+func namespaceObjects() []runtime.Object {
+	limited := fakeMgmtProject("test-cluster", "limited", "Limited")
+	limited.Object["spec"].(map[string]any)["namespaceDefaultResourceQuota"] = map[string]any{"limit": map[string]any{"limitsCpu": "250m"}}
+	limited.Object["spec"].(map[string]any)["containerDefaultResourceLimit"] = map[string]any{"limitsCpu": "100m"}
+
+	return append(quotaObjects(), limited)
+}
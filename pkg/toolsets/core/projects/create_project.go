@@ -84,19 +84,12 @@ func (t *Tools) createProjectObj(params createProjectParams) (*unstructured.Unst
 	}
 
 	// Create any container resource quotas if specified with their respective units
-	containerResourceQuotas := make(map[string]any)
-	if params.CPULimit != 0 {
-		containerResourceQuotas["limitsCpu"] = fmt.Sprintf("%dm", params.CPULimit)
-	}
-	if params.CPUReservation != 0 {
-		containerResourceQuotas["requestsCpu"] = fmt.Sprintf("%dm", params.CPUReservation)
-	}
-	if params.MemoryLimit != 0 {
-		containerResourceQuotas["limitsMemory"] = fmt.Sprintf("%dMi", params.MemoryLimit)
-	}
-	if params.MemoryReservation != 0 {
-		containerResourceQuotas["requestsMemory"] = fmt.Sprintf("%dMi", params.MemoryReservation)
-	}
+	containerResourceQuotas := resourceLimitParams{
+		CPULimit:          params.CPULimit,
+		CPUReservation:    params.CPUReservation,
+		MemoryLimit:       params.MemoryLimit,
+		MemoryReservation: params.MemoryReservation,
+	}.fields()
 
 	if err := unstructured.SetNestedField(project.Object, containerResourceQuotas, "spec", "containerDefaultResourceLimit"); err != nil {
 		return nil, fmt.Errorf("failed to set project container resource quotas: %w", err)
@@ -112,52 +112,15 @@ func (t *Tools) getResourceUsage(ctx context.Context, toolReq *mcp.CallToolReque
 				return nil, nil, err
 			}
 
-			projectLabel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"field.cattle.io/projectId": projectResource.GetName(),
-				},
-			})
+			totals, namespaces, err := t.projectResourceUsage(ctx, toolReq, clusterID, projectResource.GetName())
 			if err != nil {
-				zap.L().Error("failed to create label selector", zapGetResourceUsage, zap.Error(err))
+				zap.L().Error("failed to get resource usage for project", zapGetResourceUsage, zap.String("project", projectResource.GetName()), zap.Error(err))
 				return nil, nil, err
 			}
 
-			projectNamespaces, err := t.client.GetResources(ctx, client.ListParams{
-				Cluster:       clusterID,
-				Kind:          "namespace",
-				LabelSelector: projectLabel.String(),
-				Token:         middleware.Token(ctx),
-			})
-			if err != nil {
-				zap.L().Error("failed to get namespaces for project", zapGetResourceUsage, zap.Error(err))
-				return nil, nil, err
-			}
-
-			totals := newSample()
-			byNs := make(map[string]sample)
-
-			// aggregate resource usage across all namespaces in the project
-			for _, ns := range projectNamespaces {
-				nsTotals, err := t.getNamespaceResourceUsage(ctx, toolReq, clusterID, ns.GetName())
-				if err != nil {
-					zap.L().Error("failed to get resource usage for namespace", zapGetResourceUsage, zap.String("namespace", ns.GetName()), zap.Error(err))
-					return nil, nil, err
-				}
-
-				totals.podCount += nsTotals.podCount
-				totals.cpuRequests.Add(*nsTotals.cpuRequests)
-				totals.cpuLimits.Add(*nsTotals.cpuLimits)
-				totals.memoryRequests.Add(*nsTotals.memoryRequests)
-				totals.memoryLimits.Add(*nsTotals.memoryLimits)
-				totals.cpuUsage.Add(*nsTotals.cpuUsage)
-				totals.memoryUsage.Add(*nsTotals.memoryUsage)
-
-				byNs[ns.GetName()] = nsTotals
-			}
-
 			var namespaceSummary []map[string]any
-			for ns, nsTotals := range byNs {
-				namespaceSummary = append(namespaceSummary, toNamespaceSummary(ns, nsTotals))
+			for _, ns := range namespaces {
+				namespaceSummary = append(namespaceSummary, toNamespaceSummary(ns.name, ns.usage))
 			}
 
 			projectSummary = append(projectSummary, map[string]any{
@@ -194,6 +157,61 @@ func (t *Tools) getResourceUsage(ctx context.Context, toolReq *mcp.CallToolReque
 	}, nil, nil
 }
 
+// namespaceUsage is the resource usage of a namespace of a project.
+type namespaceUsage struct {
+	name  string
+	usage sample
+}
+
+// add adds the requests, limits, usage and pods of o to s.
+func (s *sample) add(o sample) {
+	s.podCount += o.podCount
+	s.cpuRequests.Add(*o.cpuRequests)
+	s.cpuLimits.Add(*o.cpuLimits)
+	s.memoryRequests.Add(*o.memoryRequests)
+	s.memoryLimits.Add(*o.memoryLimits)
+	s.cpuUsage.Add(*o.cpuUsage)
+	s.memoryUsage.Add(*o.memoryUsage)
+}
+
+// projectResourceUsage returns the resource usage of a project, in total and by namespace.
+func (t *Tools) projectResourceUsage(ctx context.Context, toolReq *mcp.CallToolRequest, clusterID, projectID string) (sample, []namespaceUsage, error) {
+	totals := newSample()
+
+	projectLabel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			projectIDLabel: projectID,
+		},
+	})
+	if err != nil {
+		return totals, nil, fmt.Errorf("failed to create label selector: %w", err)
+	}
+
+	projectNamespaces, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       clusterID,
+		Kind:          "namespace",
+		LabelSelector: projectLabel.String(),
+		Token:         middleware.Token(ctx),
+	})
+	if err != nil {
+		return totals, nil, fmt.Errorf("failed to get namespaces for project %s: %w", projectID, err)
+	}
+
+	// aggregate resource usage across all namespaces in the project
+	var namespaces []namespaceUsage
+	for _, ns := range projectNamespaces {
+		nsTotals, err := t.getNamespaceResourceUsage(ctx, toolReq, clusterID, ns.GetName())
+		if err != nil {
+			return totals, nil, err
+		}
+
+		totals.add(nsTotals)
+		namespaces = append(namespaces, namespaceUsage{name: ns.GetName(), usage: nsTotals})
+	}
+
+	return totals, namespaces, nil
+}
+
 func (t *Tools) getNamespaceResourceUsage(ctx context.Context, toolReq *mcp.CallToolRequest, clusterID, namespace string) (sample, error) {
 	empty := newSample()
 
@@ -0,0 +1,73 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var zapListRoleTemplates = zap.String("tool", "listRoleTemplates")
+
+// roleTemplateFields are the fields of a role template returned by listRoleTemplates, its rules are left out.
+var roleTemplateFields = []string{"displayName", "description", "context", "builtin", "external", "locked", "roleTemplateNames"}
+
+type listRoleTemplatesParams struct {
+	Context string `json:"context,omitempty" jsonschema:"only return the role templates of this context: cluster or project. All are returned when it is empty"`
+}
+
+// listRoleTemplates returns the role templates that can be bound to cluster or project members. Hidden role
+// templates are left out.
+func (t *Tools) listRoleTemplates(ctx context.Context, toolReq *mcp.CallToolRequest, params listRoleTemplatesParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listRoleTemplates called")
+
+	if params.Context != "" && params.Context != clusterContext && params.Context != projectContext {
+		return nil, nil, fmt.Errorf("invalid context '%s', it must be cluster or project", params.Context)
+	}
+
+	roleTemplates, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: LocalCluster,
+		Kind:    "roletemplate",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to list role templates", zapListRoleTemplates, zap.Error(err))
+		return nil, nil, err
+	}
+
+	resources := []*unstructured.Unstructured{}
+	for _, roleTemplate := range roleTemplates {
+		roleTemplateContext, _, _ := unstructured.NestedString(roleTemplate.Object, "context")
+		if hidden, _, _ := unstructured.NestedBool(roleTemplate.Object, "hidden"); hidden || (params.Context != "" && roleTemplateContext != params.Context) {
+			continue
+		}
+		slim := &unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": roleTemplate.GetAPIVersion(),
+				"kind":       roleTemplate.GetKind(),
+				"metadata":   map[string]any{"name": roleTemplate.GetName()},
+			},
+		}
+		for _, field := range roleTemplateFields {
+			if value, ok := roleTemplate.Object[field]; ok {
+				slim.Object[field] = value
+			}
+		}
+		resources = append(resources, slim)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse(resources, LocalCluster)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapListRoleTemplates, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
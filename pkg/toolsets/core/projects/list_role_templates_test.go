@@ -0,0 +1,65 @@
+package projects
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func TestListRoleTemplates(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params        listRoleTemplatesParams
+		expectedNames []string
+		expectedError string
+	}{
+		"all the visible role templates": {
+			expectedNames: []string{"cluster-member", "legacy", "project-member"},
+		},
+		"cluster context": {
+			params:        listRoleTemplatesParams{Context: "cluster"},
+			expectedNames: []string{"cluster-member"},
+		},
+		"invalid context": {
+			params:        listRoleTemplatesParams{Context: "global"},
+			expectedError: "invalid context 'global', it must be cluster or project",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+			tools := newMembersTools(fakeDynClient, fakeToken)
+
+			result, _, err := tools.listRoleTemplates(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM []struct {
+					Metadata struct {
+						Name string `json:"name"`
+					} `json:"metadata"`
+					Context string `json:"context"`
+				} `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			var names []string
+			for _, roleTemplate := range resp.LLM {
+				names = append(names, roleTemplate.Metadata.Name)
+				assert.NotEmpty(t, roleTemplate.Context)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	memberKindUser  = "user"
+	memberKindGroup = "group"
+
+	clusterContext = "cluster"
+	projectContext = "project"
+
+	crtbKind = "ClusterRoleTemplateBinding"
+	prtbKind = "ProjectRoleTemplateBinding"
+)
+
+// bindingNamePrefixes are the prefixes Rancher uses for the generated names of role template bindings.
+var bindingNamePrefixes = map[string]string{crtbKind: "crtb-", prtbKind: "prtb-"}
+
+// memberFields are the fields of a role template binding identifying its subject and role.
+var memberFields = []string{"userName", "userPrincipalName", "groupName", "groupPrincipalName", "roleTemplateName"}
+
+// member is a user or group resolved to the fields identifying it in a role template binding.
+type member struct {
+	UserName           string
+	UserPrincipalName  string
+	GroupName          string
+	GroupPrincipalName string
+	// PrincipalIDs are the principals of the Rancher user, a binding to any of them applies to the user.
+	PrincipalIDs []string
+}
+
+// String returns the member in a human readable form.
+func (m member) String() string {
+	switch {
+	case m.UserName != "":
+		return "user " + m.UserName
+	case m.UserPrincipalName != "":
+		return "user " + m.UserPrincipalName
+	case m.GroupName != "":
+		return "group " + m.GroupName
+	default:
+		return "group " + m.GroupPrincipalName
+	}
+}
+
+// setSubject sets the subject fields of a role template binding.
+func (m member) setSubject(binding *unstructured.Unstructured) {
+	for field, value := range map[string]string{
+		"userName":           m.UserName,
+		"userPrincipalName":  m.UserPrincipalName,
+		"groupName":          m.GroupName,
+		"groupPrincipalName": m.GroupPrincipalName,
+	} {
+		if value != "" {
+			binding.Object[field] = value
+		}
+	}
+}
+
+// bound returns whether a role template binding binds the member.
+func (m member) bound(binding *unstructured.Unstructured) bool {
+	userName, _, _ := unstructured.NestedString(binding.Object, "userName")
+	userPrincipalName, _, _ := unstructured.NestedString(binding.Object, "userPrincipalName")
+	groupName, _, _ := unstructured.NestedString(binding.Object, "groupName")
+	groupPrincipalName, _, _ := unstructured.NestedString(binding.Object, "groupPrincipalName")
+
+	switch {
+	case m.UserName != "" && userName == m.UserName:
+		return true
+	case userPrincipalName != "" && (userPrincipalName == m.UserPrincipalName || slices.Contains(m.PrincipalIDs, userPrincipalName)):
+		return true
+	case m.GroupName != "" && groupName == m.GroupName:
+		return true
+	case m.GroupPrincipalName != "" && groupPrincipalName == m.GroupPrincipalName:
+		return true
+	}
+
+	return false
+}
+
+// principalKind returns whether a principal ID is a user or a group. The providers name the principals of groups
+// <provider>_group, except GitHub which has teams and organizations.
+func principalKind(principalID string) string {
+	provider, _, _ := strings.Cut(principalID, "://")
+	for _, suffix := range []string{"_group", "_team", "_org"} {
+		if strings.HasSuffix(provider, suffix) {
+			return memberKindGroup
+		}
+	}
+
+	return memberKindUser
+}
+
+// resolveMember resolves a Rancher user (ID, username or display name), a Rancher group (ID or display name) or the
+// principal ID of a user or group. The kind is inferred from principal IDs and defaults to user otherwise.
+func (t *Tools) resolveMember(ctx context.Context, token, name, kind string) (member, error) {
+	kind = strings.ToLower(kind)
+	if kind != "" && kind != memberKindUser && kind != memberKindGroup {
+		return member{}, fmt.Errorf("invalid member kind '%s', it must be user or group", kind)
+	}
+
+	if strings.Contains(name, "://") {
+		if kind == "" {
+			kind = principalKind(name)
+		}
+		if kind == memberKindGroup {
+			return member{GroupPrincipalName: name}, nil
+		}
+		user, err := t.findOne(ctx, token, "user", func(user *unstructured.Unstructured) bool {
+			principalIDs, _, _ := unstructured.NestedStringSlice(user.Object, "principalIds")
+			return slices.Contains(principalIDs, name)
+		})
+		if err != nil || user == nil {
+			// users of the authentication providers get a Rancher user on their first login or binding.
+			return member{UserPrincipalName: name}, err
+		}
+
+		return userMember(user), nil
+	}
+
+	if kind == memberKindGroup {
+		group, err := t.findOne(ctx, token, "group", namedLike(name))
+		if err != nil {
+			return member{}, err
+		}
+		if group == nil {
+			return member{}, fmt.Errorf("group '%s' not found in Rancher, use the principal ID of the group of the authentication provider (e.g. github_team://1234)", name)
+		}
+
+		return member{GroupName: group.GetName()}, nil
+	}
+
+	user, err := t.findOne(ctx, token, "user", func(user *unstructured.Unstructured) bool {
+		username, _, _ := unstructured.NestedString(user.Object, "username")
+		return username == name || namedLike(name)(user)
+	})
+	if err != nil {
+		return member{}, err
+	}
+	if user == nil {
+		return member{}, fmt.Errorf("user '%s' not found in Rancher, use the principal ID of a user of the authentication provider (e.g. github_user://1234)", name)
+	}
+
+	return userMember(user), nil
+}
+
+// userMember returns the member of a Rancher user.
+func userMember(user *unstructured.Unstructured) member {
+	principalIDs, _, _ := unstructured.NestedStringSlice(user.Object, "principalIds")
+
+	return member{UserName: user.GetName(), PrincipalIDs: principalIDs}
+}
+
+// namedLike returns a filter matching the objects with the given name or display name.
+func namedLike(name string) func(*unstructured.Unstructured) bool {
+	return func(obj *unstructured.Unstructured) bool {
+		displayName, _, _ := unstructured.NestedString(obj.Object, "displayName")
+		return obj.GetName() == name || strings.EqualFold(displayName, name)
+	}
+}
+
+// findOne returns the only Rancher object of a kind matching the filter, or nil when none match.
+func (t *Tools) findOne(ctx context.Context, token, kind string, matches func(*unstructured.Unstructured) bool) (*unstructured.Unstructured, error) {
+	resources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: LocalCluster,
+		Kind:    kind,
+		Token:   token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*unstructured.Unstructured
+	for _, resource := range resources {
+		if matches(resource) {
+			found = append(found, resource)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		names := make([]string, 0, len(found))
+		for _, resource := range found {
+			names = append(names, resource.GetName())
+		}
+		return nil, fmt.Errorf("several Rancher %ss match, use one of their IDs: %s", kind, strings.Join(names, ", "))
+	}
+}
+
+// roleTemplate returns a role template that can be bound in the given context.
+func (t *Tools) roleTemplate(ctx context.Context, token, name, bindingContext string) (*unstructured.Unstructured, error) {
+	roleTemplate, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster: LocalCluster,
+		Kind:    "roletemplate",
+		Name:    name,
+		Token:   token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if roleTemplateContext, _, _ := unstructured.NestedString(roleTemplate.Object, "context"); roleTemplateContext != bindingContext {
+		return nil, fmt.Errorf("role template '%s' has the context '%s', only role templates with the context '%s' can be bound", name, roleTemplateContext, bindingContext)
+	}
+	if locked, _, _ := unstructured.NestedBool(roleTemplate.Object, "locked"); locked {
+		return nil, fmt.Errorf("role template '%s' is locked, it can't be used for new bindings", name)
+	}
+
+	return roleTemplate, nil
+}
+
+// roleTemplateBindings returns the role template bindings of a kind in a namespace, the cluster ID for
+// ClusterRoleTemplateBindings or the project ID for ProjectRoleTemplateBindings.
+func (t *Tools) roleTemplateBindings(ctx context.Context, token, kind, namespace string) ([]*unstructured.Unstructured, error) {
+	return t.client.GetResources(ctx, client.ListParams{
+		Cluster:   LocalCluster,
+		Kind:      kind,
+		Namespace: namespace,
+		Token:     token,
+	})
+}
+
+// memberBindings returns the bindings of a member, only those of the role template when it is set.
+func memberBindings(bindings []*unstructured.Unstructured, m member, roleTemplate string) []*unstructured.Unstructured {
+	var matching []*unstructured.Unstructured
+	for _, binding := range bindings {
+		roleTemplateName, _, _ := unstructured.NestedString(binding.Object, "roleTemplateName")
+		if m.bound(binding) && (roleTemplate == "" || roleTemplateName == roleTemplate) {
+			matching = append(matching, binding)
+		}
+	}
+
+	return matching
+}
+
+// newRoleTemplateBinding returns a role template binding of the member, its name is generated on creation.
+func newRoleTemplateBinding(kind, namespace string, m member, roleTemplate string, fields map[string]any) *unstructured.Unstructured {
+	binding := &unstructured.Unstructured{Object: map[string]any{"roleTemplateName": roleTemplate}}
+	binding.SetAPIVersion(converter.ManagementGroup + "/v3")
+	binding.SetKind(kind)
+	binding.SetNamespace(namespace)
+	binding.SetGenerateName(bindingNamePrefixes[kind])
+	m.setSubject(binding)
+	for field, value := range fields {
+		binding.Object[field] = value
+	}
+
+	return binding
+}
+
+// slimMembers returns only the subject identity and role of role template bindings instead of the full objects, which
+// bloat the responses for clusters and projects with many members.
+func slimMembers(bindings []*unstructured.Unstructured) []*unstructured.Unstructured {
+	members := make([]*unstructured.Unstructured, 0, len(bindings))
+	for _, binding := range bindings {
+		slim := &unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": binding.GetAPIVersion(),
+				"kind":       binding.GetKind(),
+				"metadata": map[string]any{
+					"name":      binding.GetName(),
+					"namespace": binding.GetNamespace(),
+				},
+			},
+		}
+		// A binding is either a user binding (userName/userPrincipalName) or a group binding
+		// (groupName/groupPrincipalName), never both; copying only non-empty fields
+		// naturally produces the right pair without branching.
+		for _, field := range memberFields {
+			if v, _, _ := unstructured.NestedString(binding.Object, field); v != "" {
+				slim.Object[field] = v
+			}
+		}
+		members = append(members, slim)
+	}
+
+	return members
+}
+
+// membership is a cluster or a project members are added to through role template bindings.
+type membership struct {
+	// Kind is the kind of the role template bindings, which are stored in the Namespace of the local cluster.
+	Kind      string
+	Namespace string
+	// Context is the context of the role templates that can be bound.
+	Context string
+	// Fields are the fields of the bindings referencing the cluster or the project.
+	Fields map[string]any
+	// ClusterID is the cluster of the membership, used for the UI context.
+	ClusterID string
+	// Description is the membership in a human readable form, e.g. "project p-xyz89 of cluster c-abc12".
+	Description string
+}
+
+// memberParams identifies a member and a role template.
+type memberParams struct {
+	Member       string
+	MemberKind   string
+	RoleTemplate string
+}
+
+// newMemberBinding returns the binding adding a member to the membership. It fails if the member already has the role.
+func (t *Tools) newMemberBinding(ctx context.Context, token string, ms membership, params memberParams) (*unstructured.Unstructured, error) {
+	if params.Member == "" || params.RoleTemplate == "" {
+		return nil, fmt.Errorf("member and roleTemplate must be set")
+	}
+	m, err := t.resolveMember(ctx, token, params.Member, params.MemberKind)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := t.roleTemplate(ctx, token, params.RoleTemplate, ms.Context); err != nil {
+		return nil, err
+	}
+
+	bindings, err := t.roleTemplateBindings(ctx, token, ms.Kind, ms.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if existing := memberBindings(bindings, m, params.RoleTemplate); len(existing) > 0 {
+		return nil, fmt.Errorf("%s already has the role '%s' in %s through %s %s", m, params.RoleTemplate, ms.Description, ms.Kind, existing[0].GetName())
+	}
+
+	return newRoleTemplateBinding(ms.Kind, ms.Namespace, m, params.RoleTemplate, ms.Fields), nil
+}
+
+// memberBindingsToRemove returns the bindings of a member of the membership, only those of the role template when it
+// is set. It fails if the member has none.
+func (t *Tools) memberBindingsToRemove(ctx context.Context, token string, ms membership, params memberParams) ([]*unstructured.Unstructured, error) {
+	if params.Member == "" {
+		return nil, fmt.Errorf("member must be set")
+	}
+	m, err := t.resolveMember(ctx, token, params.Member, params.MemberKind)
+	if err != nil {
+		return nil, err
+	}
+
+	bindings, err := t.roleTemplateBindings(ctx, token, ms.Kind, ms.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	toRemove := memberBindings(bindings, m, params.RoleTemplate)
+	if len(toRemove) == 0 {
+		if params.RoleTemplate != "" {
+			return nil, fmt.Errorf("%s doesn't have the role '%s' in %s", m, params.RoleTemplate, ms.Description)
+		}
+		return nil, fmt.Errorf("%s is not a member of %s", m, ms.Description)
+	}
+
+	return toRemove, nil
+}
+
+// addMember creates the binding adding a member to the membership.
+func (t *Tools) addMember(ctx context.Context, ms membership, params memberParams, tool string) (*mcp.CallToolResult, any, error) {
+	token := middleware.Token(ctx)
+	binding, err := t.newMemberBinding(ctx, token, ms, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the binding", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	resourceInterface, err := t.client.GetResourceInterface(ctx, token, ms.Namespace, LocalCluster, converter.K8sKindsToGVRs[strings.ToLower(ms.Kind)])
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := resourceInterface.Create(ctx, binding, metav1.CreateOptions{})
+	if err != nil {
+		zap.L().Error("failed to create the binding", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to create %s: %w", ms.Kind, err)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse(slimMembers([]*unstructured.Unstructured{obj}), ms.ClusterID)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// addMemberPlan returns the binding addMember would create without creating it.
+func (t *Tools) addMemberPlan(ctx context.Context, ms membership, params memberParams, tool string) (*mcp.CallToolResult, any, error) {
+	binding, err := t.newMemberBinding(ctx, middleware.Token(ctx), ms, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the binding", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{response.NewCreateResourceInput(binding, LocalCluster)})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// removeMember deletes the bindings of a member of the membership.
+func (t *Tools) removeMember(ctx context.Context, ms membership, params memberParams, tool string) (*mcp.CallToolResult, any, error) {
+	token := middleware.Token(ctx)
+	toRemove, err := t.memberBindingsToRemove(ctx, token, ms, params)
+	if err != nil {
+		zap.L().Error("failed to find the bindings", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	resourceInterface, err := t.client.GetResourceInterface(ctx, token, ms.Namespace, LocalCluster, converter.K8sKindsToGVRs[strings.ToLower(ms.Kind)])
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, binding := range toRemove {
+		if err := resourceInterface.Delete(ctx, binding.GetName(), metav1.DeleteOptions{}); err != nil {
+			zap.L().Error("failed to delete the binding", zap.String("tool", tool), zap.Error(err))
+			return nil, nil, fmt.Errorf("failed to delete %s %s: %w", ms.Kind, binding.GetName(), err)
+		}
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(map[string]any{"removed": slimMembers(toRemove)})
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// removeMemberPlan returns the bindings removeMember would delete without deleting them.
+func (t *Tools) removeMemberPlan(ctx context.Context, ms membership, params memberParams, tool string) (*mcp.CallToolResult, any, error) {
+	toRemove, err := t.memberBindingsToRemove(ctx, middleware.Token(ctx), ms, params)
+	if err != nil {
+		zap.L().Error("failed to find the bindings", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	plan := make([]response.PlanResource, 0, len(toRemove))
+	for _, binding := range toRemove {
+		plan = append(plan, response.NewDeleteResourceInput(binding, LocalCluster))
+	}
+	mcpResponse, err := response.CreatePlanResponse(plan)
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// listMembers returns the members of the membership.
+func (t *Tools) listMembers(ctx context.Context, ms membership, tool string) (*mcp.CallToolResult, any, error) {
+	bindings, err := t.roleTemplateBindings(ctx, middleware.Token(ctx), ms.Kind, ms.Namespace)
+	if err != nil {
+		zap.L().Error("failed to list the bindings", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	mcpResponse, err := response.CreateMcpResponse(slimMembers(bindings), ms.ClusterID)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
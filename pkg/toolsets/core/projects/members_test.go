@@ -0,0 +1,143 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+var membersListKinds = map[schema.GroupVersionResource]string{
+	{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}:                    "ClusterList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "projects"}:                    "ProjectList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "users"}:                       "UserList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "groups"}:                      "GroupList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "roletemplates"}:               "RoleTemplateList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "clusterroletemplatebindings"}: "ClusterRoleTemplateBindingList",
+	{Group: "management.cattle.io", Version: "v3", Resource: "projectroletemplatebindings"}: "ProjectRoleTemplateBindingList",
+}
+
+func newManagementObject(kind, namespace, name string, fields map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	if obj.Object == nil {
+		obj.Object = map[string]any{}
+	}
+	obj.SetAPIVersion("management.cattle.io/v3")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return obj
+}
+
+// membersObjects returns a cluster with a project, users, a group and role templates of both contexts.
+func membersObjects() []runtime.Object {
+	return []runtime.Object{
+		newManagementObject("Cluster", "", "c-abc12", map[string]any{"spec": map[string]any{"displayName": "prod"}}),
+		newManagementObject("Project", "c-abc12", "p-xyz89", map[string]any{"spec": map[string]any{"displayName": "Shop"}}),
+		newManagementObject("User", "", "u-alice", map[string]any{"username": "alice", "displayName": "Alice", "principalIds": []any{"local://u-alice", "github_user://100"}}),
+		newManagementObject("User", "", "u-bob", map[string]any{"username": "bob", "displayName": "Bob"}),
+		newManagementObject("User", "", "u-bob2", map[string]any{"username": "bob2", "displayName": "Bob"}),
+		newManagementObject("Group", "", "g-ops", map[string]any{"displayName": "Ops"}),
+		newManagementObject("RoleTemplate", "", "cluster-member", map[string]any{"context": "cluster", "displayName": "Cluster Member"}),
+		newManagementObject("RoleTemplate", "", "project-member", map[string]any{"context": "project", "displayName": "Project Member"}),
+		newManagementObject("RoleTemplate", "", "legacy", map[string]any{"context": "project", "locked": true}),
+		newManagementObject("RoleTemplate", "", "hidden-role", map[string]any{"context": "cluster", "hidden": true}),
+		newManagementObject("ClusterRoleTemplateBinding", "c-abc12", "crtb-alice", map[string]any{
+			"userPrincipalName": "github_user://100", "clusterName": "c-abc12", "roleTemplateName": "cluster-member",
+		}),
+		newManagementObject("ProjectRoleTemplateBinding", "p-xyz89", "prtb-ops", map[string]any{
+			"groupPrincipalName": "github_team://7", "projectName": "c-abc12:p-xyz89", "roleTemplateName": "project-member",
+		}),
+	}
+}
+
+// newMembersTools returns the tools with a fake dynamic client holding the objects.
+func newMembersTools(fakeDynClient *dynamicfake.FakeDynamicClient, token string) *Tools {
+	c := &client.Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return fakeDynClient, nil
+		},
+	}
+
+	return &Tools{client: newFakeToolsClient(c, token)}
+}
+
+func TestResolveMember(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		name           string
+		kind           string
+		expectedMember member
+		expectedError  string
+	}{
+		"user ID": {
+			name:           "u-alice",
+			expectedMember: member{UserName: "u-alice", PrincipalIDs: []string{"local://u-alice", "github_user://100"}},
+		},
+		"username": {
+			name:           "alice",
+			expectedMember: member{UserName: "u-alice", PrincipalIDs: []string{"local://u-alice", "github_user://100"}},
+		},
+		"principal of a Rancher user": {
+			name:           "github_user://100",
+			expectedMember: member{UserName: "u-alice", PrincipalIDs: []string{"local://u-alice", "github_user://100"}},
+		},
+		"principal of a user who never logged in": {
+			name:           "github_user://200",
+			expectedMember: member{UserPrincipalName: "github_user://200"},
+		},
+		"principal of a group": {
+			name:           "github_team://7",
+			expectedMember: member{GroupPrincipalName: "github_team://7"},
+		},
+		"principal with an explicit kind": {
+			name:           "openldap_user://cn=admins",
+			kind:           "Group",
+			expectedMember: member{GroupPrincipalName: "openldap_user://cn=admins"},
+		},
+		"Rancher group": {
+			name:           "ops",
+			kind:           "group",
+			expectedMember: member{GroupName: "g-ops"},
+		},
+		"ambiguous display name": {
+			name:          "Bob",
+			expectedError: "several Rancher users match, use one of their IDs: u-bob, u-bob2",
+		},
+		"unknown user": {
+			name:          "carol",
+			expectedError: "user 'carol' not found in Rancher",
+		},
+		"invalid kind": {
+			name:          "alice",
+			kind:          "robot",
+			expectedError: "invalid member kind 'robot', it must be user or group",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+			tools := newMembersTools(fakeDynClient, fakeToken)
+
+			m, err := tools.resolveMember(middleware.WithToken(t.Context(), fakeToken), fakeToken, tt.name, tt.kind)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMember, m)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// projectIDLabel is the label and annotation Rancher uses to assign a namespace to a project.
+// The label holds the project ID, the annotation holds the cluster ID and the project ID.
+const projectIDLabel = "field.cattle.io/projectId"
+
+type moveNamespaceToProjectParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the cluster the namespace belongs to"`
+	Namespace string `json:"namespace" jsonschema:"the name of the namespace to move"`
+	Project   string `json:"project,omitempty" jsonschema:"(optional) the name or display name of the project to move the namespace to. If omitted, the namespace is removed from its project"`
+}
+
+// prepareNamespaceMove returns the patch of the project label and annotation of the namespace, with warnings for the
+// quotas of the project the namespace would exceed.
+func (t *Tools) prepareNamespaceMove(ctx context.Context, toolReq *mcp.CallToolRequest, params moveNamespaceToProjectParams) (plannedUpdate, error) {
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+	ns, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster: clusterID,
+		Kind:    "namespace",
+		Name:    params.Namespace,
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	update := plannedUpdate{
+		cluster: clusterID,
+		gvr:     converter.K8sKindsToGVRs["namespace"],
+		obj:     ns,
+	}
+	currentProjectID := ns.GetLabels()[projectIDLabel]
+
+	if params.Project == "" {
+		if currentProjectID == "" {
+			return plannedUpdate{}, fmt.Errorf("namespace %s is not in a project", ns.GetName())
+		}
+		update.patch, err = projectIDPatch(nil, nil)
+		return update, err
+	}
+
+	projectID, project, err := t.getProjectID(ctx, middleware.Token(ctx), clusterID, params.Project)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+	if currentProjectID == projectID {
+		return plannedUpdate{}, fmt.Errorf("namespace %s is already in project %s", ns.GetName(), projectID)
+	}
+	update.patch, err = projectIDPatch(projectID, clusterID+":"+projectID)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	update.warnings, err = t.namespaceMoveWarnings(ctx, toolReq, clusterID, ns.GetName(), project)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	return update, nil
+}
+
+// projectIDPatch returns the merge patch setting the project label and annotation of a namespace, nil values remove them.
+func projectIDPatch(label, annotation any) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels":      map[string]any{projectIDLabel: label},
+			"annotations": map[string]any{projectIDLabel: annotation},
+		},
+	})
+}
+
+// namespaceMoveWarnings returns a warning for every quota of the project the usage of the namespace would exceed once moved to it:
+// the project quota, shared with the namespaces already in the project, and the default quota of its namespaces.
+func (t *Tools) namespaceMoveWarnings(ctx context.Context, toolReq *mcp.CallToolRequest, clusterID, namespace string, project *unstructured.Unstructured) ([]string, error) {
+	projectLimit, hasProjectQuota, _ := unstructured.NestedMap(project.Object, "spec", "resourceQuota", "limit")
+	namespaceLimit, hasNamespaceQuota, _ := unstructured.NestedMap(project.Object, "spec", "namespaceDefaultResourceQuota", "limit")
+	if !hasProjectQuota && !hasNamespaceQuota {
+		return nil, nil
+	}
+
+	nsUsage, err := t.getNamespaceResourceUsage(ctx, toolReq, clusterID, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	if hasProjectQuota {
+		totals, _, err := t.projectResourceUsage(ctx, toolReq, clusterID, project.GetName())
+		if err != nil {
+			return nil, err
+		}
+		totals.add(nsUsage)
+		warnings = append(warnings, exceededQuota(fmt.Sprintf("project %s with namespace %s", project.GetName(), namespace), projectLimit, totals)...)
+	}
+	if hasNamespaceQuota {
+		warnings = append(warnings, exceededQuota("namespace "+namespace, namespaceLimit, nsUsage)...)
+	}
+
+	return withQuotaNote(warnings), nil
+}
+
+// moveNamespaceToProject moves a namespace to a project, or removes it from its project.
+func (t *Tools) moveNamespaceToProject(ctx context.Context, toolReq *mcp.CallToolRequest, params moveNamespaceToProjectParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("moveNamespaceToProject called", zap.String("cluster", params.Cluster), zap.String("namespace", params.Namespace))
+
+	update, err := t.prepareNamespaceMove(ctx, toolReq, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the namespace move", zap.String("tool", "moveNamespaceToProject"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyUpdate(ctx, update, "moveNamespaceToProject")
+}
+
+// moveNamespaceToProjectPlan returns the patch moveNamespaceToProject would apply to the namespace without applying it.
+func (t *Tools) moveNamespaceToProjectPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params moveNamespaceToProjectParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("moveNamespaceToProjectPlan called", zap.String("cluster", params.Cluster), zap.String("namespace", params.Namespace))
+
+	update, err := t.prepareNamespaceMove(ctx, toolReq, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the namespace move", zap.String("tool", "moveNamespaceToProjectPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return updatePlan(update, "moveNamespaceToProjectPlan")
+}
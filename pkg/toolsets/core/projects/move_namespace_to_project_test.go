@@ -0,0 +1,140 @@
+package projects
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// moveObjects returns the quota objects with a limited project and a namespace outside of any project
+// running a pod requesting 300m CPU and 128Mi, limited to 300m CPU and 256Mi.
+func moveObjects() []runtime.Object {
+	limited := fakeMgmtProject("test-cluster", "limited", "Limited")
+	limited.Object["spec"].(map[string]any)["resourceQuota"] = map[string]any{"limit": map[string]any{"limitsCpu": "400m", "limitsMemory": "1Gi"}}
+	limited.Object["spec"].(map[string]any)["namespaceDefaultResourceQuota"] = map[string]any{"limit": map[string]any{"limitsCpu": "250m"}}
+
+	return append(quotaObjects(),
+		limited,
+		fakeProjectNamespace("ns-2", "limited"),
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "ns-free"},
+		},
+		fakeRunningPod("pod-2", "ns-2",
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")},
+		),
+		fakeRunningPod("pod-free", "ns-free",
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("300m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+		),
+	)
+}
+
+func TestMoveNamespaceToProject(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params              moveNamespaceToProjectParams
+		expectedLabels      map[string]any
+		expectedAnnotations map[string]any
+		expectedNote        string
+		expectedErrorMsg    string
+	}{
+		"move to a project without quota": {
+			params:              moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "ns-free", Project: "My Project"},
+			expectedLabels:      map[string]any{projectIDLabel: "my-project"},
+			expectedAnnotations: map[string]any{projectIDLabel: "test-cluster:my-project"},
+		},
+		"move to a project over its quotas": {
+			params:              moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "ns-free", Project: "limited"},
+			expectedLabels:      map[string]any{projectIDLabel: "limited"},
+			expectedAnnotations: map[string]any{projectIDLabel: "test-cluster:limited"},
+			expectedNote: "project limited with namespace ns-free: 500m of CPU limits used, over the quota of 400m\n" +
+				"namespace ns-free: 300m of CPU limits used, over the quota of 250m\n" +
+				quotaExceededNote,
+		},
+		"move out of its project": {
+			params: moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "ns-1"},
+		},
+		"already in the project": {
+			params:           moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "ns-1", Project: "my-project"},
+			expectedErrorMsg: "namespace ns-1 is already in project my-project",
+		},
+		"not in a project": {
+			params:           moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "ns-free"},
+			expectedErrorMsg: "namespace ns-free is not in a project",
+		},
+		"unknown namespace": {
+			params:           moveNamespaceToProjectParams{Cluster: "test-cluster", Namespace: "missing", Project: "my-project"},
+			expectedErrorMsg: "not found",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", moveObjects(), nil)
+
+			result, _, err := tools.moveNamespaceToProject(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), tt.params)
+
+			if tt.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM json.RawMessage `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			var namespaces []map[string]any
+			if tt.expectedNote != "" {
+				var withNote struct {
+					Resources []map[string]any `json:"resources"`
+					Note      string           `json:"note"`
+				}
+				require.NoError(t, json.Unmarshal(resp.LLM, &withNote))
+				assert.Equal(t, tt.expectedNote, withNote.Note)
+				namespaces = withNote.Resources
+			} else {
+				require.NoError(t, json.Unmarshal(resp.LLM, &namespaces))
+			}
+			require.Len(t, namespaces, 1)
+			metadata := namespaces[0]["metadata"].(map[string]any)
+			labels, _ := metadata["labels"].(map[string]any)
+			annotations, _ := metadata["annotations"].(map[string]any)
+			assert.Equal(t, tt.expectedLabels, labels)
+			assert.Equal(t, tt.expectedAnnotations, annotations)
+		})
+	}
+}
+
+func TestMoveNamespaceToProjectPlan(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+	tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", moveObjects(), nil)
+
+	result, _, err := tools.moveNamespaceToProjectPlan(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), moveNamespaceToProjectParams{
+		Cluster:   "test-cluster",
+		Namespace: "ns-1",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	plan := decodeUpdatePlan(t, result)
+	assert.Equal(t, "update", plan.Type)
+	assert.Equal(t, response.Resource{Name: "ns-1", Kind: "Namespace", Cluster: "test-cluster"}, plan.Resource)
+	assert.JSONEq(t, `{"metadata": {"labels": {"field.cattle.io/projectId": null}, "annotations": {"field.cattle.io/projectId": null}}}`, string(plan.Payload.Patch))
+	assert.Contains(t, plan.Payload.Original["metadata"].(map[string]any)["labels"], projectIDLabel)
+	assert.NotContains(t, plan.Payload.Patched["metadata"].(map[string]any)["labels"], projectIDLabel)
+}
@@ -0,0 +1,118 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"go.uber.org/zap"
+)
+
+type listProjectMembersParams struct {
+	Cluster string `json:"cluster" jsonschema:"the name of the cluster the project belongs to"`
+	Project string `json:"project" jsonschema:"the name or display name of the project"`
+}
+
+type projectMemberParams struct {
+	Cluster      string `json:"cluster" jsonschema:"the name of the cluster the project belongs to"`
+	Project      string `json:"project" jsonschema:"the name or display name of the project"`
+	Member       string `json:"member" jsonschema:"the Rancher user (ID, username or display name), the Rancher group (ID or display name) or the principal ID of a user or group of the authentication provider (e.g. github_team://1234)"`
+	MemberKind   string `json:"memberKind,omitempty" jsonschema:"user or group. Inferred from principal IDs, defaults to user otherwise"`
+	RoleTemplate string `json:"roleTemplate" jsonschema:"the name of a role template with the project context (e.g. project-owner, project-member, read-only)"`
+}
+
+type removeProjectMemberParams struct {
+	Cluster      string `json:"cluster" jsonschema:"the name of the cluster the project belongs to"`
+	Project      string `json:"project" jsonschema:"the name or display name of the project"`
+	Member       string `json:"member" jsonschema:"the Rancher user (ID, username or display name), the Rancher group (ID or display name) or the principal ID of a user or group of the authentication provider (e.g. github_team://1234)"`
+	MemberKind   string `json:"memberKind,omitempty" jsonschema:"user or group. Inferred from principal IDs, defaults to user otherwise"`
+	RoleTemplate string `json:"roleTemplate,omitempty" jsonschema:"the role template to remove. All the roles of the member in the project are removed when it is empty"`
+}
+
+// projectMembership returns the membership of a project, its members are bound with ProjectRoleTemplateBindings
+// stored in the namespace of the project ID.
+func (t *Tools) projectMembership(ctx context.Context, cluster, project string) (membership, error) {
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), cluster)
+	if err != nil {
+		return membership{}, err
+	}
+	projectID, _, err := t.getProjectID(ctx, middleware.Token(ctx), clusterID, project)
+	if err != nil {
+		return membership{}, err
+	}
+
+	return membership{
+		Kind:        prtbKind,
+		Namespace:   projectID,
+		Context:     projectContext,
+		Fields:      map[string]any{"projectName": clusterID + ":" + projectID},
+		ClusterID:   clusterID,
+		Description: fmt.Sprintf("project %s of cluster %s", projectID, clusterID),
+	}, nil
+}
+
+// listProjectMembers returns the members of a project with their role.
+func (t *Tools) listProjectMembers(ctx context.Context, toolReq *mcp.CallToolRequest, params listProjectMembersParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listProjectMembers called")
+
+	ms, err := t.projectMembership(ctx, params.Cluster, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "listProjectMembers"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.listMembers(ctx, ms, "listProjectMembers")
+}
+
+// addProjectMember gives a role in a project to a user or group.
+func (t *Tools) addProjectMember(ctx context.Context, toolReq *mcp.CallToolRequest, params projectMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("addProjectMember called")
+
+	ms, err := t.projectMembership(ctx, params.Cluster, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "addProjectMember"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.addMember(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "addProjectMember")
+}
+
+// addProjectMemberPlan returns the ProjectRoleTemplateBinding addProjectMember would create without creating it.
+func (t *Tools) addProjectMemberPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params projectMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("addProjectMemberPlan called")
+
+	ms, err := t.projectMembership(ctx, params.Cluster, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "addProjectMemberPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.addMemberPlan(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "addProjectMemberPlan")
+}
+
+// removeProjectMember removes a role, or all the roles, of a user or group in a project.
+func (t *Tools) removeProjectMember(ctx context.Context, toolReq *mcp.CallToolRequest, params removeProjectMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("removeProjectMember called")
+
+	ms, err := t.projectMembership(ctx, params.Cluster, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "removeProjectMember"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.removeMember(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "removeProjectMember")
+}
+
+// removeProjectMemberPlan returns the ProjectRoleTemplateBindings removeProjectMember would delete without deleting them.
+func (t *Tools) removeProjectMemberPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params removeProjectMemberParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("removeProjectMemberPlan called")
+
+	ms, err := t.projectMembership(ctx, params.Cluster, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "removeProjectMemberPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.removeMemberPlan(ctx, ms, memberParams{Member: params.Member, MemberKind: params.MemberKind, RoleTemplate: params.RoleTemplate}, "removeProjectMemberPlan")
+}
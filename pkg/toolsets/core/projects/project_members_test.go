@@ -0,0 +1,111 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+var prtbGVR = schema.GroupVersionResource{Group: "management.cattle.io", Version: "v3", Resource: "projectroletemplatebindings"}
+
+func TestListProjectMembers(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+	tools := newMembersTools(fakeDynClient, fakeToken)
+
+	result, _, err := tools.listProjectMembers(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, listProjectMembersParams{Cluster: "prod", Project: "shop"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"llm": [
+			{
+				"apiVersion": "management.cattle.io/v3",
+				"kind": "ProjectRoleTemplateBinding",
+				"metadata": {"name": "prtb-ops", "namespace": "p-xyz89"},
+				"groupPrincipalName": "github_team://7",
+				"roleTemplateName": "project-member"
+			}
+		],
+		"uiContext": [
+			{"cluster": "c-abc12", "kind": "ProjectRoleTemplateBinding", "name": "prtb-ops", "namespace": "p-xyz89", "type": "projectroletemplatebinding"}
+		]
+	}`, result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestAddProjectMember(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params        projectMemberParams
+		expectedError string
+	}{
+		"add a Rancher group": {
+			params: projectMemberParams{Cluster: "prod", Project: "Shop", Member: "Ops", MemberKind: "group", RoleTemplate: "project-member"},
+		},
+		"locked role template": {
+			params:        projectMemberParams{Cluster: "prod", Project: "Shop", Member: "alice", RoleTemplate: "legacy"},
+			expectedError: "role template 'legacy' is locked, it can't be used for new bindings",
+		},
+		"unknown project": {
+			params:        projectMemberParams{Cluster: "prod", Project: "Billing", Member: "alice", RoleTemplate: "project-member"},
+			expectedError: "project 'Billing' not found in cluster 'c-abc12'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+			tools := newMembersTools(fakeDynClient, fakeToken)
+
+			_, _, err := tools.addProjectMember(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			bindings, err := fakeDynClient.Resource(prtbGVR).Namespace("p-xyz89").List(t.Context(), metav1.ListOptions{})
+			require.NoError(t, err)
+			require.Len(t, bindings.Items, 2)
+			created := bindings.Items[0]
+			if created.GetName() != "" {
+				created = bindings.Items[1]
+			}
+			assert.Equal(t, map[string]any{
+				"apiVersion":       "management.cattle.io/v3",
+				"kind":             "ProjectRoleTemplateBinding",
+				"metadata":         map[string]any{"generateName": "prtb-", "namespace": "p-xyz89"},
+				"groupName":        "g-ops",
+				"projectName":      "c-abc12:p-xyz89",
+				"roleTemplateName": "project-member",
+			}, created.Object)
+		})
+	}
+}
+
+func TestRemoveProjectMember(t *testing.T) {
+	fakeToken := "fakeToken"
+	fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), membersListKinds, membersObjects()...)
+	tools := newMembersTools(fakeDynClient, fakeToken)
+
+	planResult, _, err := tools.removeProjectMemberPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, removeProjectMemberParams{Cluster: "prod", Project: "p-xyz89", Member: "github_team://7"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"type": "delete",
+		"resource": {"name": "prtb-ops", "kind": "ProjectRoleTemplateBinding", "cluster": "local", "namespace": "p-xyz89"},
+		"payload": null
+	}]`, planResult.Content[0].(*mcp.TextContent).Text)
+
+	_, _, err = tools.removeProjectMember(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, removeProjectMemberParams{Cluster: "prod", Project: "p-xyz89", Member: "github_team://7"})
+	require.NoError(t, err)
+	_, err = fakeDynClient.Resource(prtbGVR).Namespace("p-xyz89").Get(t.Context(), "prtb-ops", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "the binding must be deleted")
+}
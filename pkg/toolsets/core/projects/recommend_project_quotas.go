@@ -0,0 +1,213 @@
+package projects
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// defaultQuotaHeadroom is the headroom, in percent, added to the usage when no headroom is given.
+	defaultQuotaHeadroom = 20
+	// overProvisionedRatio is how many times the usage the requests must be for a namespace to be over-provisioned.
+	overProvisionedRatio = 2
+	// limitsPressurePercent is the percentage of the limits above which the usage is close to being throttled or OOM killed.
+	limitsPressurePercent = 90
+
+	// cpuQuotaStep is the step, in mCPUs, CPU quotas are rounded up to.
+	cpuQuotaStep = 10
+	mebibyte     = 1024 * 1024
+)
+
+type recommendProjectQuotasParams struct {
+	Cluster  string `json:"cluster" jsonschema:"the name of the cluster the project belongs to"`
+	Project  string `json:"project" jsonschema:"the name or display name of the project"`
+	Headroom *int   `json:"headroom,omitempty" jsonschema:"(optional) the headroom in percent added to the usage to size the quotas. Defaults to 20"`
+}
+
+// resourceComparison is the requests, limits and actual usage of a resource in a namespace.
+type resourceComparison struct {
+	Requests string `json:"requests"`
+	Limits   string `json:"limits"`
+	Usage    string `json:"usage"`
+}
+
+// namespaceRecommendation is the provisioning of a namespace and the quota recommended for it.
+type namespaceRecommendation struct {
+	Namespace        string               `json:"namespace"`
+	PodCount         int                  `json:"podCount"`
+	CPU              resourceComparison   `json:"cpu"`
+	Memory           resourceComparison   `json:"memory"`
+	OverProvisioned  bool                 `json:"overProvisioned,omitempty"`
+	UnderProvisioned bool                 `json:"underProvisioned,omitempty"`
+	Findings         []string             `json:"findings,omitempty"`
+	RecommendedQuota *resourceLimitParams `json:"recommendedQuota,omitempty"`
+}
+
+// quotaRecommendation is the result of recommendProjectQuotas. UpdateProjectQuota holds the parameters to pass to
+// updateProjectQuota to apply the recommended quotas.
+type quotaRecommendation struct {
+	Project            string                    `json:"project"`
+	Headroom           int                       `json:"headroom"`
+	Namespaces         []namespaceRecommendation `json:"namespaces"`
+	UpdateProjectQuota *updateProjectQuotaParams `json:"updateProjectQuota,omitempty"`
+	Notes              []string                  `json:"notes"`
+}
+
+// recommendProjectQuotas compares the requests and limits of the namespaces of a project to their actual usage, and
+// recommends project and namespace quotas sized on the usage with some headroom.
+func (t *Tools) recommendProjectQuotas(ctx context.Context, toolReq *mcp.CallToolRequest, params recommendProjectQuotasParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("recommendProjectQuotas called", zap.String("cluster", params.Cluster), zap.String("project", params.Project))
+
+	headroom := defaultQuotaHeadroom
+	if params.Headroom != nil {
+		headroom = *params.Headroom
+	}
+	if headroom < 0 {
+		return nil, nil, fmt.Errorf("invalid headroom %d, it must be a positive percentage", headroom)
+	}
+
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zap.String("tool", "recommendProjectQuotas"), zap.Error(err))
+		return nil, nil, err
+	}
+	projectID, _, err := t.getProjectID(ctx, middleware.Token(ctx), clusterID, params.Project)
+	if err != nil {
+		zap.L().Error("failed to get project", zap.String("tool", "recommendProjectQuotas"), zap.Error(err))
+		return nil, nil, err
+	}
+	_, namespaces, err := t.projectResourceUsage(ctx, toolReq, clusterID, projectID)
+	if err != nil {
+		zap.L().Error("failed to get resource usage for project", zap.String("tool", "recommendProjectQuotas"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	recommendation := recommendQuotas(namespaces, headroom)
+	recommendation.Project = projectID
+	if recommendation.UpdateProjectQuota != nil {
+		recommendation.UpdateProjectQuota.Cluster = clusterID
+		recommendation.UpdateProjectQuota.Project = projectID
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(recommendation)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "recommendProjectQuotas"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// recommendQuotas evaluates the provisioning of every namespace and recommends a quota for the namespaces with usage data.
+// The namespace default quota fits the largest recommendation, the project quota is the sum of the recommendations.
+func recommendQuotas(namespaces []namespaceUsage, headroom int) quotaRecommendation {
+	recommendation := quotaRecommendation{
+		Headroom:   headroom,
+		Namespaces: []namespaceRecommendation{},
+		Notes: []string{
+			"The usage is a single sample from the metrics server, review the recommendations against the peak usage of the workloads before applying them.",
+		},
+	}
+
+	var projectQuota, namespaceQuota resourceLimitParams
+	for _, ns := range namespaces {
+		nsRecommendation := namespaceRecommendation{
+			Namespace: ns.name,
+			PodCount:  ns.usage.podCount,
+			CPU:       resourceComparison{Requests: ns.usage.cpuRequests.String(), Limits: ns.usage.cpuLimits.String(), Usage: ns.usage.cpuUsage.String()},
+			Memory:    resourceComparison{Requests: ns.usage.memoryRequests.String(), Limits: ns.usage.memoryLimits.String(), Usage: ns.usage.memoryUsage.String()},
+		}
+
+		switch {
+		case ns.usage.podCount == 0:
+			nsRecommendation.Findings = []string{"no running pods, no quota is recommended"}
+		case ns.usage.cpuUsage.IsZero() && ns.usage.memoryUsage.IsZero():
+			nsRecommendation.Findings = []string{"no usage metrics, the metrics server may not be installed, no quota is recommended"}
+		default:
+			for _, r := range []struct {
+				name                    string
+				requests, limits, usage *resource.Quantity
+			}{
+				{"CPU", ns.usage.cpuRequests, ns.usage.cpuLimits, ns.usage.cpuUsage},
+				{"memory", ns.usage.memoryRequests, ns.usage.memoryLimits, ns.usage.memoryUsage},
+			} {
+				findings, over, under := provisioningFindings(r.name, r.requests, r.limits, r.usage)
+				nsRecommendation.Findings = append(nsRecommendation.Findings, findings...)
+				nsRecommendation.OverProvisioned = nsRecommendation.OverProvisioned || over
+				nsRecommendation.UnderProvisioned = nsRecommendation.UnderProvisioned || under
+			}
+
+			quota := resourceLimitParams{
+				CPUReservation:    withHeadroom(ns.usage.cpuUsage.MilliValue(), headroom, cpuQuotaStep),
+				CPULimit:          withHeadroom(max(ns.usage.cpuLimits.MilliValue(), ns.usage.cpuUsage.MilliValue()), headroom, cpuQuotaStep),
+				MemoryReservation: withHeadroom(ns.usage.memoryUsage.Value(), headroom, mebibyte) / mebibyte,
+				MemoryLimit:       withHeadroom(max(ns.usage.memoryLimits.Value(), ns.usage.memoryUsage.Value()), headroom, mebibyte) / mebibyte,
+			}
+			nsRecommendation.RecommendedQuota = &quota
+
+			projectQuota.CPUReservation += quota.CPUReservation
+			projectQuota.CPULimit += quota.CPULimit
+			projectQuota.MemoryReservation += quota.MemoryReservation
+			projectQuota.MemoryLimit += quota.MemoryLimit
+			namespaceQuota.CPUReservation = max(namespaceQuota.CPUReservation, quota.CPUReservation)
+			namespaceQuota.CPULimit = max(namespaceQuota.CPULimit, quota.CPULimit)
+			namespaceQuota.MemoryReservation = max(namespaceQuota.MemoryReservation, quota.MemoryReservation)
+			namespaceQuota.MemoryLimit = max(namespaceQuota.MemoryLimit, quota.MemoryLimit)
+		}
+
+		recommendation.Namespaces = append(recommendation.Namespaces, nsRecommendation)
+	}
+
+	if projectQuota == (resourceLimitParams{}) {
+		recommendation.Notes = append(recommendation.Notes, "No namespace of the project has usage data, no quota is recommended.")
+		return recommendation
+	}
+
+	recommendation.UpdateProjectQuota = &updateProjectQuotaParams{
+		ProjectQuota:          &projectQuota,
+		NamespaceDefaultQuota: &namespaceQuota,
+	}
+	recommendation.Notes = append(recommendation.Notes,
+		"Pass updateProjectQuota to the updateProjectQuota tool to apply the recommended quotas. Requests above the recommended quotas must be lowered first, or new pods will be rejected.")
+
+	return recommendation
+}
+
+// provisioningFindings compares the requests and limits of a resource to its usage. A resource is over-provisioned
+// when its requests are more than overProvisionedRatio times its usage, and under-provisioned when its usage exceeds
+// its requests or comes close to its limits.
+func provisioningFindings(name string, requests, limits, usage *resource.Quantity) ([]string, bool, bool) {
+	var findings []string
+	var over, under bool
+
+	if requests.MilliValue() > usage.MilliValue()*overProvisionedRatio {
+		over = true
+		findings = append(findings, fmt.Sprintf("%s requests of %s are more than %d times the usage of %s", name, requests, overProvisionedRatio, usage))
+	}
+	if usage.Cmp(*requests) > 0 {
+		under = true
+		findings = append(findings, fmt.Sprintf("%s usage of %s exceeds the requests of %s", name, usage, requests))
+	}
+	if !limits.IsZero() && usage.MilliValue()*100 > limits.MilliValue()*limitsPressurePercent {
+		under = true
+		findings = append(findings, fmt.Sprintf("%s usage of %s is over %d%% of the limits of %s", name, usage, limitsPressurePercent, limits))
+	}
+
+	return findings, over, under
+}
+
+// withHeadroom adds the headroom percentage to the value and rounds it up to a multiple of step.
+func withHeadroom(value int64, headroom int, step int64) int {
+	value = (value*int64(100+headroom) + 99) / 100
+	value = (value + step - 1) / step * step
+
+	return int(max(value, step))
+}
@@ -0,0 +1,149 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func TestRecommendProjectQuotas(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+
+	objects := append(quotaObjects(),
+		fakeProjectNamespace("ns-2", "my-project"),
+		fakeRunningPod("pod-2", "ns-2",
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		),
+	)
+	metrics := []runtime.Object{
+		fakePodMetrics("pod-1", "ns-1", corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("20m"),
+			corev1.ResourceMemory: resource.MustParse("250Mi"),
+		}),
+	}
+
+	tests := map[string]struct {
+		params           recommendProjectQuotasParams
+		expectedResult   string
+		expectedErrorMsg string
+	}{
+		"default headroom": {
+			params: recommendProjectQuotasParams{Cluster: "test-cluster", Project: "My Project"},
+			expectedResult: `{"llm": {
+				"project": "my-project",
+				"headroom": 20,
+				"namespaces": [
+					{
+						"namespace": "ns-1",
+						"podCount": 1,
+						"cpu": {"requests": "100m", "limits": "200m", "usage": "20m"},
+						"memory": {"requests": "128Mi", "limits": "256Mi", "usage": "250Mi"},
+						"overProvisioned": true,
+						"underProvisioned": true,
+						"findings": [
+							"CPU requests of 100m are more than 2 times the usage of 20m",
+							"memory usage of 250Mi exceeds the requests of 128Mi",
+							"memory usage of 250Mi is over 90% of the limits of 256Mi"
+						],
+						"recommendedQuota": {"cpuLimit": 240, "cpuReservation": 30, "memoryLimit": 308, "memoryReservation": 300}
+					},
+					{
+						"namespace": "ns-2",
+						"podCount": 1,
+						"cpu": {"requests": "100m", "limits": "100m", "usage": "0"},
+						"memory": {"requests": "0", "limits": "0", "usage": "0"},
+						"findings": ["no usage metrics, the metrics server may not be installed, no quota is recommended"]
+					}
+				],
+				"updateProjectQuota": {
+					"cluster": "test-cluster",
+					"project": "my-project",
+					"projectQuota": {"cpuLimit": 240, "cpuReservation": 30, "memoryLimit": 308, "memoryReservation": 300},
+					"namespaceDefaultQuota": {"cpuLimit": 240, "cpuReservation": 30, "memoryLimit": 308, "memoryReservation": 300}
+				},
+				"notes": [
+					"The usage is a single sample from the metrics server, review the recommendations against the peak usage of the workloads before applying them.",
+					"Pass updateProjectQuota to the updateProjectQuota tool to apply the recommended quotas. Requests above the recommended quotas must be lowered first, or new pods will be rejected."
+				]
+			}}`,
+		},
+		"negative headroom": {
+			params:           recommendProjectQuotasParams{Cluster: "test-cluster", Project: "my-project", Headroom: ptr.To(-10)},
+			expectedErrorMsg: "invalid headroom -10, it must be a positive percentage",
+		},
+		"unknown project": {
+			params:           recommendProjectQuotasParams{Cluster: "test-cluster", Project: "other"},
+			expectedErrorMsg: "project 'other' not found in cluster 'test-cluster'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", objects, metrics)
+
+			result, _, err := tools.recommendProjectQuotas(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), tt.params)
+
+			if tt.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestRecommendQuotas(t *testing.T) {
+	usage := newSample()
+	usage.podCount = 2
+	usage.cpuRequests.Add(resource.MustParse("150m"))
+	usage.cpuUsage.Add(resource.MustParse("100m"))
+	usage.memoryRequests.Add(resource.MustParse("64Mi"))
+	usage.memoryUsage.Add(resource.MustParse("60Mi"))
+
+	tests := map[string]struct {
+		namespaces        []namespaceUsage
+		headroom          int
+		expectedQuota     *resourceLimitParams
+		expectedNamespace resourceLimitParams
+	}{
+		"no headroom": {
+			namespaces:        []namespaceUsage{{name: "a", usage: usage}, {name: "b", usage: usage}},
+			expectedQuota:     &resourceLimitParams{CPULimit: 200, CPUReservation: 200, MemoryLimit: 120, MemoryReservation: 120},
+			expectedNamespace: resourceLimitParams{CPULimit: 100, CPUReservation: 100, MemoryLimit: 60, MemoryReservation: 60},
+		},
+		"headroom rounded up": {
+			namespaces:        []namespaceUsage{{name: "a", usage: usage}},
+			headroom:          15,
+			expectedQuota:     &resourceLimitParams{CPULimit: 120, CPUReservation: 120, MemoryLimit: 69, MemoryReservation: 69},
+			expectedNamespace: resourceLimitParams{CPULimit: 120, CPUReservation: 120, MemoryLimit: 69, MemoryReservation: 69},
+		},
+		"no running pods": {
+			namespaces: []namespaceUsage{{name: "a", usage: newSample()}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recommendation := recommendQuotas(tt.namespaces, tt.headroom)
+
+			if tt.expectedQuota == nil {
+				assert.Nil(t, recommendation.UpdateProjectQuota)
+				return
+			}
+			require.NotNil(t, recommendation.UpdateProjectQuota)
+			assert.Equal(t, tt.expectedQuota, recommendation.UpdateProjectQuota.ProjectQuota)
+			assert.Equal(t, tt.expectedNamespace, *recommendation.UpdateProjectQuota.NamespaceDefaultQuota)
+		})
+	}
+}
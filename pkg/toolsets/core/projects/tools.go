@@ -68,6 +68,44 @@ The resource usage includes CPU and memory requests, limits and actual usage, as
 		t.getResourceUsage,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "recommendProjectQuotas",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Compares the CPU and memory requests and limits of the namespaces of a project to their actual usage, flags over-provisioned and under-provisioned namespaces,
+and recommends project and namespace default quotas sized on the usage with a configurable headroom (20% by default).
+The recommended quotas are returned as the parameters of updateProjectQuota, to be reviewed before being applied.`},
+		t.recommendProjectQuotas,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listClusterMembers",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the members of a cluster: the users and groups bound to a role template of the cluster context through ClusterRoleTemplateBindings.`},
+		t.listClusterMembers,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listProjectMembers",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the members of a project: the users and groups bound to a role template of the project context through ProjectRoleTemplateBindings.`},
+		t.listProjectMembers,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listRoleTemplates",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the role templates that can be given to cluster or project members, with their context (cluster or project) and the role templates they inherit. Locked role templates can't be used for new members.`},
+		t.listRoleTemplates,
+	)
+
 	if !t.ReadOnly {
 		mcp.AddTool(mcpServer, &mcp.Tool{
 			Name: "createProject",
@@ -84,5 +122,120 @@ The resource usage includes CPU and memory requests, limits and actual usage, as
 			},
 			Description: `Plans to create a project resource for a specified cluster. It returns the JSON representation of the project to be created without actually creating it in the cluster. Only used for displaying the resource when using human validation.`},
 			t.createProjectPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "createNamespace",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Creates a namespace in a project of a specified cluster. The project is resolved by name or display name, and the namespace gets the namespace default resource quota and container default limits of the project.
+Pod Security Admission levels (privileged, baseline or restricted) can be enforced, audited or warned about with optional labels.`},
+			t.createNamespace)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "createNamespacePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to create a namespace in a project. It returns the JSON representation of the namespace to be created without actually creating it in the cluster. Only used for displaying the resource when using human validation.`},
+			t.createNamespacePlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "updateProjectQuota",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Updates the quota of a project, the default quota of its namespaces and the default resource limits of its containers. Limits that are not set keep their current value.
+The response includes a note when the current usage reported by getResourceUsage already exceeds the new quotas.`},
+			t.updateProjectQuota)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "updateProjectQuotaPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to update the quotas of a project. It returns the patch to be applied to the project without actually applying it, followed by warnings when the current usage already exceeds the new quotas. Only used for displaying the resource when using human validation.`},
+			t.updateProjectQuotaPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "moveNamespaceToProject",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Moves a namespace to a project, or removes it from its project when no project is given.
+The response includes a note when the usage of the namespace would exceed the project quota or the default namespace quota of the project.`},
+			t.moveNamespaceToProject)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "moveNamespaceToProjectPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to move a namespace to a project, or to remove it from its project. It returns the patch to be applied to the namespace without actually applying it, followed by warnings when the usage of the namespace would exceed the quotas of the project. Only used for displaying the resource when using human validation.`},
+			t.moveNamespaceToProjectPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "addClusterMember",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Gives a role in a cluster to a user or group by creating a ClusterRoleTemplateBinding. The member is a Rancher user (ID, username or display name), a Rancher group or the principal ID of a user or group of the authentication provider.`},
+			t.addClusterMember)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "addClusterMemberPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to give a role in a cluster to a user or group. It returns the ClusterRoleTemplateBinding to be created without actually creating it. Only used for displaying the resource when using human validation.`},
+			t.addClusterMemberPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "removeClusterMember",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Removes a role, or all the roles, of a user or group in a cluster by deleting their ClusterRoleTemplateBindings.`},
+			t.removeClusterMember)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "removeClusterMemberPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to remove a role, or all the roles, of a user or group in a cluster. It returns the ClusterRoleTemplateBindings to be deleted without actually deleting them. Only used for displaying the resources when using human validation.`},
+			t.removeClusterMemberPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "addProjectMember",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Gives a role in a project to a user or group by creating a ProjectRoleTemplateBinding. The member is a Rancher user (ID, username or display name), a Rancher group or the principal ID of a user or group of the authentication provider.`},
+			t.addProjectMember)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "addProjectMemberPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to give a role in a project to a user or group. It returns the ProjectRoleTemplateBinding to be created without actually creating it. Only used for displaying the resource when using human validation.`},
+			t.addProjectMemberPlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "removeProjectMember",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Removes a role, or all the roles, of a user or group in a project by deleting their ProjectRoleTemplateBindings.`},
+			t.removeProjectMember)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "removeProjectMemberPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to remove a role, or all the roles, of a user or group in a project. It returns the ProjectRoleTemplateBindings to be deleted without actually deleting them. Only used for displaying the resources when using human validation.`},
+			t.removeProjectMemberPlan)
 	}
 }
@@ -0,0 +1,273 @@
+package projects
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// quotaExceededNote explains what happens to the workloads of a project or namespace over its quota.
+const quotaExceededNote = "Running pods are not evicted when the usage exceeds a quota, but new pods are rejected until the usage is back under it."
+
+// resourceLimitParams are the CPU and memory limits of a quota or of the default resources of containers.
+type resourceLimitParams struct {
+	CPULimit          int `json:"cpuLimit,omitempty" jsonschema:"the maximum amount of CPU resources (mCPUs) that can be used by containers"`
+	CPUReservation    int `json:"cpuReservation,omitempty" jsonschema:"the amount of CPU resources (mCPUs) reserved for containers"`
+	MemoryLimit       int `json:"memoryLimit,omitempty" jsonschema:"the maximum amount of memory resources (MiB) that can be used by containers"`
+	MemoryReservation int `json:"memoryReservation,omitempty" jsonschema:"the amount of memory resources (MiB) reserved for containers"`
+}
+
+// fields returns the limits set in p with their respective units, keyed by the fields of the Rancher project spec.
+func (p resourceLimitParams) fields() map[string]any {
+	fields := make(map[string]any)
+	if p.CPULimit != 0 {
+		fields["limitsCpu"] = fmt.Sprintf("%dm", p.CPULimit)
+	}
+	if p.CPUReservation != 0 {
+		fields["requestsCpu"] = fmt.Sprintf("%dm", p.CPUReservation)
+	}
+	if p.MemoryLimit != 0 {
+		fields["limitsMemory"] = fmt.Sprintf("%dMi", p.MemoryLimit)
+	}
+	if p.MemoryReservation != 0 {
+		fields["requestsMemory"] = fmt.Sprintf("%dMi", p.MemoryReservation)
+	}
+
+	return fields
+}
+
+// quotaResources maps the fields of a Rancher quota limit to the resources of a usage sample they limit.
+var quotaResources = []struct {
+	field       string
+	description string
+	used        func(sample) *resource.Quantity
+}{
+	{field: "limitsCpu", description: "CPU limits", used: func(s sample) *resource.Quantity { return s.cpuLimits }},
+	{field: "requestsCpu", description: "CPU requests", used: func(s sample) *resource.Quantity { return s.cpuRequests }},
+	{field: "limitsMemory", description: "memory limits", used: func(s sample) *resource.Quantity { return s.memoryLimits }},
+	{field: "requestsMemory", description: "memory requests", used: func(s sample) *resource.Quantity { return s.memoryRequests }},
+}
+
+// exceededQuota returns a warning for every resource of the limit the usage already exceeds.
+// Resources the limit doesn't set, or sets to an invalid quantity, are ignored.
+func exceededQuota(subject string, limit map[string]any, usage sample) []string {
+	var warnings []string
+	for _, r := range quotaResources {
+		value, ok := limit[r.field].(string)
+		if !ok {
+			continue
+		}
+		quota, err := resource.ParseQuantity(value)
+		if err != nil {
+			continue
+		}
+		if used := r.used(usage); used.Cmp(quota) > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: %s of %s used, over the quota of %s", subject, used, r.description, value))
+		}
+	}
+
+	return warnings
+}
+
+// withQuotaNote appends the explanation of exceeded quotas to the warnings, if any.
+func withQuotaNote(warnings []string) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	return append(warnings, quotaExceededNote)
+}
+
+type updateProjectQuotaParams struct {
+	Cluster               string               `json:"cluster" jsonschema:"the cluster that the project belongs to"`
+	Project               string               `json:"project" jsonschema:"the name or display name of the project"`
+	ProjectQuota          *resourceLimitParams `json:"projectQuota,omitempty" jsonschema:"(optional) the total limits shared by all the namespaces of the project. Limits that are not set keep their current value"`
+	NamespaceDefaultQuota *resourceLimitParams `json:"namespaceDefaultQuota,omitempty" jsonschema:"(optional) the default limits of each namespace of the project. Limits that are not set keep their current value"`
+	ContainerDefaultLimit *resourceLimitParams `json:"containerDefaultLimit,omitempty" jsonschema:"(optional) the default requests and limits of containers that don't set them. Limits that are not set keep their current value"`
+}
+
+// projectQuotaPatch returns the merge patch of the quotas of the project spec.
+func projectQuotaPatch(params updateProjectQuotaParams) ([]byte, error) {
+	spec := make(map[string]any)
+	if params.ProjectQuota != nil {
+		spec["resourceQuota"] = map[string]any{"limit": params.ProjectQuota.fields()}
+	}
+	if params.NamespaceDefaultQuota != nil {
+		spec["namespaceDefaultResourceQuota"] = map[string]any{"limit": params.NamespaceDefaultQuota.fields()}
+	}
+	if params.ContainerDefaultLimit != nil {
+		spec["containerDefaultResourceLimit"] = params.ContainerDefaultLimit.fields()
+	}
+	if len(spec) == 0 {
+		return nil, errors.New("at least one of projectQuota, namespaceDefaultQuota or containerDefaultLimit must be set")
+	}
+
+	return json.Marshal(map[string]any{"spec": spec})
+}
+
+// plannedUpdate is a patch to apply to a resource, with the warnings about the usage that already exceeds the
+// quotas it sets.
+type plannedUpdate struct {
+	cluster  string
+	gvr      schema.GroupVersionResource
+	obj      *unstructured.Unstructured
+	patch    []byte
+	warnings []string
+}
+
+// applyUpdate applies the merge patch of the update and returns the patched resource with the warnings as a note.
+func (t *Tools) applyUpdate(ctx context.Context, u plannedUpdate, tool string) (*mcp.CallToolResult, any, error) {
+	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), u.obj.GetNamespace(), u.cluster, u.gvr)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := resourceInterface.Patch(ctx, u.obj.GetName(), types.MergePatchType, u.patch, metav1.PatchOptions{})
+	if err != nil {
+		zap.L().Error("failed to apply patch", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to patch %s %s: %w", u.obj.GetKind(), u.obj.GetName(), err)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse([]*unstructured.Unstructured{obj}, u.cluster, u.warnings...)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// updatePlan returns the plan to apply the patch of the update, with the original resource, the merge patch and what
+// the resource would look like once patched. The warnings are returned as a second content so the plan itself keeps
+// the format the UI expects.
+func updatePlan(u plannedUpdate, tool string) (*mcp.CallToolResult, any, error) {
+	originalBytes, err := json.Marshal(u.obj.Object)
+	if err != nil {
+		zap.L().Error("failed to marshal original resource", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to marshal original: %w", err)
+	}
+	patchedBytes, err := jsonpatch.MergePatch(originalBytes, u.patch)
+	if err != nil {
+		zap.L().Error("failed to apply patch", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to apply patch: %w", err)
+	}
+
+	planResource := response.PlanResource{
+		Type: response.OperationUpdate,
+		Payload: map[string]any{
+			"original": u.obj.Object,
+			"patch":    json.RawMessage(u.patch),
+			"patched":  json.RawMessage(patchedBytes),
+		},
+		Resource: response.Resource{
+			Name:      u.obj.GetName(),
+			Kind:      u.obj.GetKind(),
+			Cluster:   u.cluster,
+			Namespace: u.obj.GetNamespace(),
+		},
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{planResource})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	content := []mcp.Content{&mcp.TextContent{Text: mcpResponse}}
+	if len(u.warnings) > 0 {
+		content = append(content, &mcp.TextContent{Text: strings.Join(u.warnings, "\n")})
+	}
+
+	return &mcp.CallToolResult{
+		Content: content,
+	}, nil, nil
+}
+
+// prepareProjectQuotaUpdate returns the patch of the quotas of the project, with warnings for the usage that
+// already exceeds the new quotas.
+func (t *Tools) prepareProjectQuotaUpdate(ctx context.Context, toolReq *mcp.CallToolRequest, params updateProjectQuotaParams) (plannedUpdate, error) {
+	patch, err := projectQuotaPatch(params)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+	projectID, project, err := t.getProjectID(ctx, middleware.Token(ctx), clusterID, params.Project)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	update := plannedUpdate{
+		cluster: LocalCluster,
+		gvr:     converter.K8sKindsToGVRs["project"],
+		obj:     project,
+		patch:   patch,
+	}
+
+	// The container defaults only apply to new containers, only the project and namespace quotas can be exceeded.
+	if params.ProjectQuota == nil && params.NamespaceDefaultQuota == nil {
+		return update, nil
+	}
+
+	totals, namespaces, err := t.projectResourceUsage(ctx, toolReq, clusterID, projectID)
+	if err != nil {
+		return plannedUpdate{}, err
+	}
+
+	var warnings []string
+	if params.ProjectQuota != nil {
+		warnings = append(warnings, exceededQuota("project "+projectID, params.ProjectQuota.fields(), totals)...)
+	}
+	if params.NamespaceDefaultQuota != nil {
+		for _, ns := range namespaces {
+			warnings = append(warnings, exceededQuota("namespace "+ns.name, params.NamespaceDefaultQuota.fields(), ns.usage)...)
+		}
+	}
+	update.warnings = withQuotaNote(warnings)
+
+	return update, nil
+}
+
+// updateProjectQuota updates the project quota, the default quota of its namespaces and the default limits of its containers.
+func (t *Tools) updateProjectQuota(ctx context.Context, toolReq *mcp.CallToolRequest, params updateProjectQuotaParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("updateProjectQuota called", zap.String("cluster", params.Cluster), zap.String("project", params.Project))
+
+	update, err := t.prepareProjectQuotaUpdate(ctx, toolReq, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the project quota update", zap.String("tool", "updateProjectQuota"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyUpdate(ctx, update, "updateProjectQuota")
+}
+
+// updateProjectQuotaPlan returns the patch updateProjectQuota would apply to the project without applying it.
+func (t *Tools) updateProjectQuotaPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params updateProjectQuotaParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("updateProjectQuotaPlan called", zap.String("cluster", params.Cluster), zap.String("project", params.Project))
+
+	update, err := t.prepareProjectQuotaUpdate(ctx, toolReq, params)
+	if err != nil {
+		zap.L().Error("failed to prepare the project quota update", zap.String("tool", "updateProjectQuotaPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return updatePlan(update, "updateProjectQuotaPlan")
+}
@@ -0,0 +1,168 @@
+package projects
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// quotaObjects returns a project with a namespace running a pod requesting 100m CPU and 128Mi, limited to 200m CPU and 256Mi.
+func quotaObjects() []runtime.Object {
+	return []runtime.Object{
+		fakeMgmtCluster("test-cluster"),
+		fakeMgmtProject("test-cluster", "my-project", "My Project"),
+		fakeProjectNamespace("ns-1", "my-project"),
+		fakeRunningPod("pod-1", "ns-1",
+			corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("200m"),
+				corev1.ResourceMemory: resource.MustParse("256Mi"),
+			},
+		),
+	}
+}
+
+func TestUpdateProjectQuota(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params           updateProjectQuotaParams
+		expectedSpec     map[string]any
+		expectedNote     string
+		expectedErrorMsg string
+	}{
+		"quota under the usage": {
+			params: updateProjectQuotaParams{
+				Cluster:               "test-cluster",
+				Project:               "My Project",
+				ProjectQuota:          &resourceLimitParams{CPULimit: 150, MemoryLimit: 512},
+				NamespaceDefaultQuota: &resourceLimitParams{CPULimit: 150, MemoryReservation: 64},
+			},
+			expectedSpec: map[string]any{
+				"displayName":                   "My Project",
+				"resourceQuota":                 map[string]any{"limit": map[string]any{"limitsCpu": "150m", "limitsMemory": "512Mi"}},
+				"namespaceDefaultResourceQuota": map[string]any{"limit": map[string]any{"limitsCpu": "150m", "requestsMemory": "64Mi"}},
+			},
+			expectedNote: "project my-project: 200m of CPU limits used, over the quota of 150m\n" +
+				"namespace ns-1: 200m of CPU limits used, over the quota of 150m\n" +
+				"namespace ns-1: 128Mi of memory requests used, over the quota of 64Mi\n" +
+				quotaExceededNote,
+		},
+		"container default limits": {
+			params: updateProjectQuotaParams{
+				Cluster:               "test-cluster",
+				Project:               "my-project",
+				ContainerDefaultLimit: &resourceLimitParams{CPULimit: 10, MemoryReservation: 16},
+			},
+			expectedSpec: map[string]any{
+				"displayName":                   "My Project",
+				"containerDefaultResourceLimit": map[string]any{"limitsCpu": "10m", "requestsMemory": "16Mi"},
+			},
+		},
+		"no quota": {
+			params:           updateProjectQuotaParams{Cluster: "test-cluster", Project: "my-project"},
+			expectedErrorMsg: "at least one of projectQuota, namespaceDefaultQuota or containerDefaultLimit must be set",
+		},
+		"unknown project": {
+			params:           updateProjectQuotaParams{Cluster: "test-cluster", Project: "other", ProjectQuota: &resourceLimitParams{CPULimit: 1000}},
+			expectedErrorMsg: "project 'other' not found in cluster 'test-cluster'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", quotaObjects(), nil)
+
+			result, _, err := tools.updateProjectQuota(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), tt.params)
+
+			if tt.expectedErrorMsg != "" {
+				assert.ErrorContains(t, err, tt.expectedErrorMsg)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM json.RawMessage `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			var projects []map[string]any
+			if tt.expectedNote != "" {
+				var withNote struct {
+					Resources []map[string]any `json:"resources"`
+					Note      string           `json:"note"`
+				}
+				require.NoError(t, json.Unmarshal(resp.LLM, &withNote))
+				assert.Equal(t, tt.expectedNote, withNote.Note)
+				projects = withNote.Resources
+			} else {
+				require.NoError(t, json.Unmarshal(resp.LLM, &projects))
+			}
+			require.Len(t, projects, 1)
+			assert.Equal(t, tt.expectedSpec, projects[0]["spec"])
+		})
+	}
+}
+
+// updatePlanResource is a plan resource of updatePlan.
+type updatePlanResource struct {
+	Type     string            `json:"type"`
+	Resource response.Resource `json:"resource"`
+	Payload  struct {
+		Original map[string]any  `json:"original"`
+		Patch    json.RawMessage `json:"patch"`
+		Patched  map[string]any  `json:"patched"`
+	} `json:"payload"`
+}
+
+// decodeUpdatePlan returns the single resource of the plan returned by updatePlan.
+func decodeUpdatePlan(t *testing.T, result *mcp.CallToolResult) updatePlanResource {
+	t.Helper()
+	var plan []updatePlanResource
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &plan))
+	require.Len(t, plan, 1)
+
+	return plan[0]
+}
+
+func TestUpdateProjectQuotaPlan(t *testing.T) {
+	fakeURL := "https://localhost:8080"
+	fakeToken := "fakeToken"
+	tools := newProjectResourceUsageTools(t, fakeToken, fakeURL, "", quotaObjects(), nil)
+
+	result, _, err := tools.updateProjectQuotaPlan(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), updateProjectQuotaParams{
+		Cluster:      "test-cluster",
+		Project:      "my-project",
+		ProjectQuota: &resourceLimitParams{CPUReservation: 50},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, result.Content, 2)
+	plan := decodeUpdatePlan(t, result)
+	assert.Equal(t, "update", plan.Type)
+	assert.Equal(t, response.Resource{Name: "my-project", Kind: "Project", Cluster: "local", Namespace: "test-cluster"}, plan.Resource)
+	assert.JSONEq(t, `{"spec": {"resourceQuota": {"limit": {"requestsCpu": "50m"}}}}`, string(plan.Payload.Patch))
+	assert.Equal(t, "my-project", plan.Payload.Original["metadata"].(map[string]any)["name"])
+	assert.Equal(t, map[string]any{"requestsCpu": "50m"}, plan.Payload.Patched["spec"].(map[string]any)["resourceQuota"].(map[string]any)["limit"])
+	assert.Equal(t, "project my-project: 100m of CPU requests used, over the quota of 50m\n"+quotaExceededNote, result.Content[1].(*mcp.TextContent).Text)
+
+	// the plan must not update the project
+	updated, _, err := tools.updateProjectQuota(middleware.WithToken(t.Context(), fakeToken), test.NewCallToolRequest(fakeURL), updateProjectQuotaParams{
+		Cluster:               "test-cluster",
+		Project:               "my-project",
+		ContainerDefaultLimit: &resourceLimitParams{CPULimit: 10},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, updated.Content[0].(*mcp.TextContent).Text, "resourceQuota")
+}
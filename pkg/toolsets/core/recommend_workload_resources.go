@@ -0,0 +1,203 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"github.com/rancher/rancher-ai-mcp/pkg/workload"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+)
+
+var zapRecommendWorkloadResources = zap.String("tool", "recommendWorkloadResources")
+
+// resourceRecommendationKinds are the kinds supported by recommendWorkloadResources.
+var resourceRecommendationKinds = []string{"deployment", "statefulset", "daemonset"}
+
+// recommendWorkloadResourcesParams specifies the parameters needed to recommend the resources of a workload.
+type recommendWorkloadResourcesParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace" jsonschema:"the namespace of the workload"`
+	Kind      string `json:"kind" jsonschema:"the kind of the workload: Deployment, StatefulSet or DaemonSet"`
+	Name      string `json:"name" jsonschema:"the name of the workload"`
+}
+
+// workloadResourcesRecommendation is the result of recommendWorkloadResources. PatchKubernetesResourcePlan holds the
+// parameters to pass to patchKubernetesResourcePlan to review the recommended resources.
+type workloadResourcesRecommendation struct {
+	Workload                    string                            `json:"workload"`
+	Pods                        int                               `json:"pods"`
+	Containers                  []workload.ResourceRecommendation `json:"containers"`
+	PatchKubernetesResourcePlan *updateKubernetesResourceParams   `json:"patchKubernetesResourcePlan,omitempty"`
+	Notes                       []string                          `json:"notes"`
+}
+
+// recommendWorkloadResources compares the requests and limits of the containers of a workload to the usage of its
+// pods reported by the metrics server, and recommends requests and limits with the JSON patch applying them.
+func (t *Tools) recommendWorkloadResources(ctx context.Context, toolReq *mcp.CallToolRequest, params recommendWorkloadResourcesParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("recommendWorkloadResources called")
+
+	kind := strings.ToLower(params.Kind)
+	if !slices.Contains(resourceRecommendationKinds, kind) {
+		return nil, nil, fmt.Errorf("unsupported workload kind '%s', it must be one of Deployment, StatefulSet or DaemonSet", params.Kind)
+	}
+
+	workloadResource, err := t.client.GetResource(ctx, client.GetParams{
+		Cluster:   params.Cluster,
+		Kind:      kind,
+		Namespace: params.Namespace,
+		Name:      params.Name,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		zap.L().Error("failed to get workload", zapRecommendWorkloadResources, zap.Error(err))
+		return nil, nil, err
+	}
+
+	var template corev1.PodTemplateSpec
+	templateObj, _, err := unstructured.NestedMap(workloadResource.Object, "spec", "template")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the pod template: %w", err)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(templateObj, &template); err != nil {
+		return nil, nil, fmt.Errorf("failed to convert the pod template: %w", err)
+	}
+
+	podResources, err := t.workloadPods(ctx, params.Cluster, workloadResource)
+	if err != nil {
+		zap.L().Error("failed to get workload pods", zapRecommendWorkloadResources, zap.Error(err))
+		return nil, nil, err
+	}
+	var pods []corev1.Pod
+	for _, podResource := range podResources {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(podResource.Object, &pod); err != nil {
+			return nil, nil, fmt.Errorf("failed to convert unstructured object to Pod: %w", err)
+		}
+		pods = append(pods, pod)
+	}
+
+	recommendation := workloadResourcesRecommendation{
+		Workload: fmt.Sprintf("%s %s/%s", workloadResource.GetKind(), params.Namespace, params.Name),
+		Pods:     len(pods),
+		Notes: []string{
+			"The usage is the current sample of the metrics server for every pod, review the recommendations against the peak usage of the workload before applying them.",
+		},
+	}
+
+	metrics, err := t.podMetrics(ctx, params.Cluster, params.Namespace)
+	if err != nil {
+		zap.L().Warn("failed to get pod metrics", zapRecommendWorkloadResources, zap.Error(err))
+		recommendation.Notes = append(recommendation.Notes, "The pod metrics are not available, the metrics server may not be installed.")
+	}
+
+	var patch []jsonPatch
+	for i, container := range template.Spec.Containers {
+		containerRecommendation := workload.RecommendResources(containerUsage(container, pods, metrics))
+		recommendation.Containers = append(recommendation.Containers, containerRecommendation)
+		if containerRecommendation.Recommended.Requests == nil {
+			continue
+		}
+		patch = append(patch, jsonPatch{
+			Op:    "add",
+			Path:  fmt.Sprintf("/spec/template/spec/containers/%d/resources", i),
+			Value: containerRecommendation.Recommended,
+		})
+	}
+
+	if len(patch) > 0 {
+		recommendation.PatchKubernetesResourcePlan = &updateKubernetesResourceParams{
+			Name:      params.Name,
+			Namespace: params.Namespace,
+			Kind:      workloadResource.GetKind(),
+			Cluster:   params.Cluster,
+			Patch:     patch,
+		}
+		recommendation.Notes = append(recommendation.Notes,
+			"Pass patchKubernetesResourcePlan to the patchKubernetesResourcePlan tool to review the recommended resources. Applying them rolls out new pods.")
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(recommendation, response.NewUIContext(workloadResource, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapRecommendWorkloadResources, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// podMetrics returns the metrics of the pods of a namespace by pod name.
+func (t *Tools) podMetrics(ctx context.Context, cluster, namespace string) (map[string]metricsv1beta1.PodMetrics, error) {
+	metricsResources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:   cluster,
+		Kind:      "pod.metrics.k8s.io",
+		Namespace: namespace,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make(map[string]metricsv1beta1.PodMetrics, len(metricsResources))
+	for _, m := range metricsResources {
+		var podMetrics metricsv1beta1.PodMetrics
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m.Object, &podMetrics); err != nil {
+			return nil, fmt.Errorf("failed to convert unstructured object to PodMetrics: %w", err)
+		}
+		metrics[m.GetName()] = podMetrics
+	}
+
+	return metrics, nil
+}
+
+// containerUsage returns the usage samples of a container of the pod template in every pod, and whether it was
+// OOMKilled in any of them.
+func containerUsage(container corev1.Container, pods []corev1.Pod, metrics map[string]metricsv1beta1.PodMetrics) workload.ContainerUsage {
+	usage := workload.ContainerUsage{
+		Name:      container.Name,
+		Resources: container.Resources,
+	}
+
+	for _, pod := range pods {
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container.Name {
+				continue
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+				usage.OOMKilled = true
+			}
+			if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+				usage.OOMKilled = true
+			}
+		}
+
+		podMetrics, ok := metrics[pod.Name]
+		if !ok {
+			continue
+		}
+		for _, containerMetrics := range podMetrics.Containers {
+			if containerMetrics.Name != container.Name {
+				continue
+			}
+			if cpu, ok := containerMetrics.Usage[corev1.ResourceCPU]; ok {
+				usage.CPUSamples = append(usage.CPUSamples, cpu)
+			}
+			if memory, ok := containerMetrics.Usage[corev1.ResourceMemory]; ok {
+				usage.MemorySamples = append(usage.MemorySamples, memory)
+			}
+		}
+	}
+
+	return usage
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"k8s.io/utils/ptr"
+)
+
+func TestRecommendWorkloadResources(t *testing.T) {
+	fakeToken := "fakeToken"
+	labels := map[string]string{"app": "web"}
+	podMetricsGVR := schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(2)),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("512Mi")},
+						Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+					},
+				},
+				{Name: "proxy"},
+			}}},
+		},
+	}
+	pod := func(name string, oomKilled bool) *corev1.Pod {
+		status := corev1.ContainerStatus{Name: "app"}
+		if oomKilled {
+			status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, ContainerStatuses: []corev1.ContainerStatus{status}},
+		}
+	}
+	podMetrics := func(name string, usage map[string][2]string) *metricsv1beta1.PodMetrics {
+		m := &metricsv1beta1.PodMetrics{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "metrics.k8s.io/v1beta1", Kind: "PodMetrics"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		for container, u := range usage {
+			m.Containers = append(m.Containers, metricsv1beta1.ContainerMetrics{Name: container, Usage: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(u[0]),
+				corev1.ResourceMemory: resource.MustParse(u[1]),
+			}})
+		}
+		return m
+	}
+
+	tests := map[string]struct {
+		params        recommendWorkloadResourcesParams
+		objs          []runtime.Object
+		metrics       []*metricsv1beta1.PodMetrics
+		noMetricsAPI  bool
+		expectedPatch string
+		expectedOOM   bool
+		expectedNotes int
+		expectedError string
+	}{
+		"deployment with metrics": {
+			params: recommendWorkloadResourcesParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"},
+			objs:   []runtime.Object{deployment, pod("web-1", false), pod("web-2", false)},
+			metrics: []*metricsv1beta1.PodMetrics{
+				podMetrics("web-1", map[string][2]string{"app": {"100m", "200Mi"}, "proxy": {"5m", "20Mi"}}),
+				podMetrics("web-2", map[string][2]string{"app": {"150m", "300Mi"}}),
+			},
+			expectedPatch: `[
+				{"op": "add", "path": "/spec/template/spec/containers/0/resources", "value": {"limits": {"memory": "450Mi"}, "requests": {"cpu": "180m", "memory": "360Mi"}}},
+				{"op": "add", "path": "/spec/template/spec/containers/1/resources", "value": {"limits": {"memory": "30Mi"}, "requests": {"cpu": "10m", "memory": "24Mi"}}}
+			]`,
+			expectedNotes: 2,
+		},
+		"OOMKilled container": {
+			params:  recommendWorkloadResourcesParams{Cluster: "local", Namespace: "default", Kind: "deployment", Name: "web"},
+			objs:    []runtime.Object{deployment, pod("web-1", true)},
+			metrics: []*metricsv1beta1.PodMetrics{podMetrics("web-1", map[string][2]string{"app": {"100m", "200Mi"}})},
+			expectedPatch: `[
+				{"op": "add", "path": "/spec/template/spec/containers/0/resources", "value": {"limits": {"memory": "768Mi"}, "requests": {"cpu": "120m", "memory": "615Mi"}}}
+			]`,
+			expectedOOM:   true,
+			expectedNotes: 2,
+		},
+		"metrics server not installed": {
+			params:        recommendWorkloadResourcesParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"},
+			objs:          []runtime.Object{deployment, pod("web-1", false)},
+			noMetricsAPI:  true,
+			expectedNotes: 2,
+		},
+		"unsupported kind": {
+			params:        recommendWorkloadResourcesParams{Cluster: "local", Namespace: "default", Kind: "Job", Name: "web"},
+			expectedError: "unsupported workload kind 'Job', it must be one of Deployment, StatefulSet or DaemonSet",
+		},
+		"workload not found": {
+			params:        recommendWorkloadResourcesParams{Cluster: "local", Namespace: "default", Kind: "DaemonSet", Name: "agent"},
+			expectedError: `daemonsets.apps "agent" not found`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			scheme := workloadScheme()
+			_ = metricsv1beta1.AddToScheme(scheme)
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{podMetricsGVR: "PodMetricsList"}, tt.objs...)
+			if tt.noMetricsAPI {
+				fakeDynClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					if action.GetResource() != podMetricsGVR {
+						return false, nil, nil
+					}
+					return true, nil, apierrors.NewNotFound(podMetricsGVR.GroupResource(), "")
+				})
+			}
+			for _, m := range tt.metrics {
+				require.NoError(t, fakeDynClient.Tracker().Create(podMetricsGVR, m, m.Namespace))
+			}
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.recommendWorkloadResources(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM struct {
+					Workload   string `json:"workload"`
+					Containers []struct {
+						Container string `json:"container"`
+						OOMKilled bool   `json:"oomKilled"`
+					} `json:"containers"`
+					PatchKubernetesResourcePlan *struct {
+						Name  string          `json:"name"`
+						Kind  string          `json:"kind"`
+						Patch json.RawMessage `json:"patch"`
+					} `json:"patchKubernetesResourcePlan"`
+					Notes []string `json:"notes"`
+				} `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, "Deployment default/web", resp.LLM.Workload)
+			require.Len(t, resp.LLM.Containers, 2)
+			assert.Equal(t, tt.expectedOOM, resp.LLM.Containers[0].OOMKilled)
+			assert.Len(t, resp.LLM.Notes, tt.expectedNotes)
+			if tt.expectedPatch == "" {
+				assert.Nil(t, resp.LLM.PatchKubernetesResourcePlan)
+				return
+			}
+			require.NotNil(t, resp.LLM.PatchKubernetesResourcePlan)
+			assert.Equal(t, "web", resp.LLM.PatchKubernetesResourcePlan.Name)
+			assert.Equal(t, "Deployment", resp.LLM.PatchKubernetesResourcePlan.Kind)
+			assert.JSONEq(t, tt.expectedPatch, string(resp.LLM.PatchKubernetesResourcePlan.Patch))
+		})
+	}
+}
@@ -0,0 +1,544 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// deprecatedAPIsTable is the table of the deprecated and removed Kubernetes APIs. Keeping it as data lets it follow
+// the Kubernetes releases without code changes.
+//
+//go:embed deprecated_apis.yaml
+var deprecatedAPIsTable []byte
+
+var zapScanDeprecatedAPIs = zap.String("tool", "scanDeprecatedAPIs")
+
+const (
+	// helmReleaseSecretType is the type of the Secrets where Helm stores the revisions of its releases.
+	helmReleaseSecretType = "helm.sh/release.v1"
+	// lastAppliedAnnotation holds the manifest last applied by kubectl apply.
+	lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+	// fleetClusterNameLabel is the label Rancher sets on the Fleet cluster of a cluster, its value is the cluster ID.
+	fleetClusterNameLabel = "management.cattle.io/cluster-name"
+	bundleNameLabel       = "fleet.cattle.io/bundle-name"
+	bundleNamespaceLabel  = "fleet.cattle.io/bundle-namespace"
+)
+
+// The sources of the objects using a deprecated API.
+const (
+	sourceLive  = "live"
+	sourceHelm  = "helm"
+	sourceFleet = "fleet"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b, 0x08}
+	// the top-level fields of a manifest that isn't valid YAML, like the template of a Helm chart.
+	apiVersionLine = regexp.MustCompile(`(?m)^apiVersion:\s*["']?([^\s"']+)`)
+	kindLine       = regexp.MustCompile(`(?m)^kind:\s*["']?([^\s"']+)`)
+)
+
+type scanDeprecatedAPIsParams struct {
+	Cluster       string `json:"cluster" jsonschema:"the cluster to scan"`
+	TargetVersion string `json:"targetVersion" jsonschema:"the Kubernetes version the cluster will be upgraded to, like v1.32"`
+}
+
+// deprecatedAPI is an entry of the deprecation table, an API version of a kind.
+type deprecatedAPI struct {
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	Resource     string `json:"resource"`
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	Replacement  string `json:"replacement,omitempty"`
+	Note         string `json:"note,omitempty"`
+
+	deprecatedIn *version.Version
+	removedIn    *version.Version
+}
+
+// deprecatedAPIUsage is an object using a deprecated API, live in the cluster or in a manifest deployed to it.
+type deprecatedAPIUsage struct {
+	// Source is where the object was found: live, helm or fleet.
+	Source     string `json:"source"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name,omitempty"`
+	APIVersion string `json:"apiVersion"`
+	// Replacement is empty when the kind has no replacement.
+	Replacement string `json:"replacement,omitempty"`
+	RemovedIn   string `json:"removedIn"`
+	// Removed is set when the target version no longer serves the API, otherwise the API is only deprecated.
+	Removed bool `json:"removed"`
+	// Origin is what uses the API: the field managers of a live object, or the Helm release or Fleet bundle of a
+	// manifest.
+	Origin string `json:"origin"`
+	Note   string `json:"note,omitempty"`
+}
+
+// deprecatedAPIScan is the response of the scanDeprecatedAPIs tool.
+type deprecatedAPIScan struct {
+	Cluster       string               `json:"cluster"`
+	TargetVersion string               `json:"targetVersion"`
+	Usages        []deprecatedAPIUsage `json:"usages"`
+	Notes         []string             `json:"notes,omitempty"`
+}
+
+// manifestObject is an object of a manifest deployed by Helm or Fleet.
+type manifestObject struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+	Origin     string
+}
+
+// helmRelease is the part of a Helm release revision holding its rendered manifest.
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Manifest  string `json:"manifest"`
+}
+
+// scanDeprecatedAPIs finds the objects of a cluster using an API that the target Kubernetes version deprecates or
+// removes. Live objects are matched on the API version their field managers and kubectl apply wrote them with,
+// manifests on their apiVersion, from the latest revision of every Helm release and the Fleet bundles deployed to
+// the cluster.
+func (t *Tools) scanDeprecatedAPIs(ctx context.Context, toolReq *mcp.CallToolRequest, params scanDeprecatedAPIsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("scanDeprecatedAPIs called")
+
+	target, err := version.ParseGeneric(params.TargetVersion)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid targetVersion '%s', expected a Kubernetes version like v1.32: %w", params.TargetVersion, err)
+	}
+	apis, err := loadDeprecatedAPIs(deprecatedAPIsTable)
+	if err != nil {
+		zap.L().Error("failed to load the deprecated APIs", zapScanDeprecatedAPIs, zap.Error(err))
+		return nil, nil, err
+	}
+	apis = slices.DeleteFunc(apis, func(api deprecatedAPI) bool {
+		return !reachedBy(api.deprecatedIn, target)
+	})
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zapScanDeprecatedAPIs, zap.Error(err))
+		return nil, nil, err
+	}
+
+	scan := deprecatedAPIScan{
+		Cluster:       params.Cluster,
+		TargetVersion: fmt.Sprintf("v%d.%d", target.Major(), target.Minor()),
+		Usages:        []deprecatedAPIUsage{},
+	}
+	if len(apis) == 0 {
+		scan.Notes = append(scan.Notes, fmt.Sprintf("no API is deprecated in Kubernetes %s", scan.TargetVersion))
+	} else {
+		usages, notes := t.scanLiveObjects(ctx, clusterID, apis, target)
+		scan.Usages = append(scan.Usages, usages...)
+		scan.Notes = append(scan.Notes, notes...)
+
+		objs, notes, err := t.helmReleaseObjects(ctx, clusterID)
+		if err != nil {
+			zap.L().Warn("failed to get Helm releases", zapScanDeprecatedAPIs, zap.Error(err))
+			scan.Notes = append(scan.Notes, fmt.Sprintf("the Helm releases were not scanned: %v", err))
+		}
+		scan.Notes = append(scan.Notes, notes...)
+		scan.Usages = append(scan.Usages, matchManifestObjects(sourceHelm, objs, apis, target)...)
+
+		objs, notes, err = t.fleetBundleObjects(ctx, clusterID)
+		if err != nil {
+			zap.L().Warn("failed to get Fleet bundles", zapScanDeprecatedAPIs, zap.Error(err))
+			scan.Notes = append(scan.Notes, fmt.Sprintf("the Fleet bundles were not scanned: %v", err))
+		}
+		scan.Notes = append(scan.Notes, notes...)
+		scan.Usages = append(scan.Usages, matchManifestObjects(sourceFleet, objs, apis, target)...)
+	}
+	slices.SortStableFunc(scan.Usages, func(a, b deprecatedAPIUsage) int {
+		return cmp.Or(
+			cmp.Compare(a.Source, b.Source),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Kind, b.Kind),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	mcpResponse, err := response.CreateMcpResponseAny(scan)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapScanDeprecatedAPIs, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// scanLiveObjects lists the objects of every deprecated API still served by the cluster, and returns the ones written
+// with it. The API server serves every object at every version of its kind, only the field managers and the
+// last-applied-configuration of kubectl apply record the version a client used.
+func (t *Tools) scanLiveObjects(ctx context.Context, clusterID string, apis []deprecatedAPI, target *version.Version) ([]deprecatedAPIUsage, []string) {
+	var usages []deprecatedAPIUsage
+	var notes []string
+	for _, api := range apis {
+		gv, _ := schema.ParseGroupVersion(api.APIVersion)
+		resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), "", clusterID, gv.WithResource(api.Resource))
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("the live %s objects of %s were not scanned: %v", api.Kind, api.APIVersion, err))
+			continue
+		}
+		list, err := resourceInterface.List(ctx, metav1.ListOptions{})
+		if apierrors.IsNotFound(err) {
+			// the cluster doesn't serve the API anymore, so no client can use it
+			continue
+		}
+		if err != nil {
+			zap.L().Warn("failed to list objects", zapScanDeprecatedAPIs, zap.String("apiVersion", api.APIVersion), zap.String("kind", api.Kind), zap.Error(err))
+			notes = append(notes, fmt.Sprintf("the live %s objects of %s were not scanned: %v", api.Kind, api.APIVersion, err))
+			continue
+		}
+
+		for _, obj := range list.Items {
+			writers := apiVersionWriters(&obj, api.APIVersion)
+			if len(writers) == 0 {
+				continue
+			}
+			usages = append(usages, newDeprecatedAPIUsage(sourceLive, manifestObject{
+				APIVersion: api.APIVersion,
+				Kind:       api.Kind,
+				Namespace:  obj.GetNamespace(),
+				Name:       obj.GetName(),
+				Origin:     "written by " + strings.Join(writers, ", "),
+			}, api, target))
+		}
+	}
+
+	return usages, notes
+}
+
+// helmReleaseObjects returns the objects of the manifest of the deployed revision of every Helm release of the
+// cluster, and notes for the releases that couldn't be decoded. Superseded and failed revisions are not listed.
+func (t *Tools) helmReleaseObjects(ctx context.Context, clusterID string) ([]manifestObject, []string, error) {
+	secrets, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       clusterID,
+		Kind:          "secret",
+		LabelSelector: "owner=helm,status=deployed",
+		Token:         middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// a release has a single deployed revision, unless an interrupted upgrade left an older one marked as deployed
+	latest := map[string]*unstructured.Unstructured{}
+	revision := func(secret *unstructured.Unstructured) int {
+		revision, _ := strconv.Atoi(secret.GetLabels()["version"])
+		return revision
+	}
+	for _, secret := range secrets {
+		if secretType, _, _ := unstructured.NestedString(secret.Object, "type"); secretType != helmReleaseSecretType {
+			continue
+		}
+		key := secret.GetNamespace() + "/" + secret.GetLabels()["name"]
+		if current, ok := latest[key]; !ok || revision(secret) > revision(current) {
+			latest[key] = secret
+		}
+	}
+
+	var objs []manifestObject
+	var notes []string
+	for _, key := range slices.Sorted(maps.Keys(latest)) {
+		release, err := decodeHelmRelease(latest[key])
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("Helm release %s was not scanned: %v", key, err))
+			continue
+		}
+		origin := fmt.Sprintf("Helm release %s/%s revision %d", release.Namespace, release.Name, release.Version)
+		objs = append(objs, parseManifests([]byte(release.Manifest), origin)...)
+	}
+
+	return objs, notes, nil
+}
+
+// fleetBundleObjects returns the objects of the resources of the Fleet bundles deployed to the cluster, and notes for
+// the Fleet clusters and bundles that couldn't be read. Bundles are read from the local cluster, through the
+// BundleDeployments of the Fleet cluster of the cluster.
+func (t *Tools) fleetBundleObjects(ctx context.Context, clusterID string) ([]manifestObject, []string, error) {
+	token := middleware.Token(ctx)
+	fleetClusters, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:       "local",
+		Kind:          "fleetcluster",
+		LabelSelector: fleetClusterNameLabel + "=" + clusterID,
+		Token:         token,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var objs []manifestObject
+	var notes []string
+	for _, fleetCluster := range fleetClusters {
+		namespace, _, _ := unstructured.NestedString(fleetCluster.Object, "status", "namespace")
+		if namespace == "" {
+			continue
+		}
+		bundleDeployments, err := t.client.GetResources(ctx, client.ListParams{
+			Cluster:   "local",
+			Kind:      "bundledeployment",
+			Namespace: namespace,
+			Token:     token,
+		})
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("the Fleet bundles of Fleet cluster %s/%s were not scanned: %v", fleetCluster.GetNamespace(), fleetCluster.GetName(), err))
+			continue
+		}
+
+		for _, bundleDeployment := range bundleDeployments {
+			labels := bundleDeployment.GetLabels()
+			bundleName := labels[bundleNamespaceLabel] + "/" + labels[bundleNameLabel]
+			bundle, err := t.client.GetResource(ctx, client.GetParams{
+				Cluster:   "local",
+				Kind:      "bundle",
+				Namespace: labels[bundleNamespaceLabel],
+				Name:      labels[bundleNameLabel],
+				Token:     token,
+			})
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("Fleet bundle %s was not scanned: %v", bundleName, err))
+				continue
+			}
+
+			resources, _, _ := unstructured.NestedSlice(bundle.Object, "spec", "resources")
+			for _, resource := range resources {
+				resource, _ := resource.(map[string]any)
+				name, _ := resource["name"].(string)
+				content, err := decodeBundleResource(resource)
+				if err != nil {
+					notes = append(notes, fmt.Sprintf("resource %s of Fleet bundle %s was not scanned: %v", name, bundleName, err))
+					continue
+				}
+				objs = append(objs, parseManifests(content, fmt.Sprintf("Fleet bundle %s, resource %s", bundleName, name))...)
+			}
+		}
+	}
+
+	return objs, notes, nil
+}
+
+// matchManifestObjects returns the usages of the deprecated APIs by the objects of manifests.
+func matchManifestObjects(source string, objs []manifestObject, apis []deprecatedAPI, target *version.Version) []deprecatedAPIUsage {
+	var usages []deprecatedAPIUsage
+	for _, obj := range objs {
+		i := slices.IndexFunc(apis, func(api deprecatedAPI) bool {
+			return api.APIVersion == obj.APIVersion && api.Kind == obj.Kind
+		})
+		if i >= 0 {
+			usages = append(usages, newDeprecatedAPIUsage(source, obj, apis[i], target))
+		}
+	}
+
+	return usages
+}
+
+func newDeprecatedAPIUsage(source string, obj manifestObject, api deprecatedAPI, target *version.Version) deprecatedAPIUsage {
+	return deprecatedAPIUsage{
+		Source:      source,
+		Kind:        obj.Kind,
+		Namespace:   obj.Namespace,
+		Name:        obj.Name,
+		APIVersion:  obj.APIVersion,
+		Replacement: api.Replacement,
+		RemovedIn:   "v" + api.RemovedIn,
+		Removed:     reachedBy(api.removedIn, target),
+		Origin:      obj.Origin,
+		Note:        api.Note,
+	}
+}
+
+// apiVersionWriters returns the field managers of an object that wrote it with apiVersion, and kubectl apply when its
+// last-applied-configuration uses it.
+func apiVersionWriters(obj *unstructured.Unstructured, apiVersion string) []string {
+	var writers []string
+	for _, field := range obj.GetManagedFields() {
+		if field.APIVersion == apiVersion && !slices.Contains(writers, field.Manager) {
+			writers = append(writers, field.Manager)
+		}
+	}
+	if lastApplied := obj.GetAnnotations()[lastAppliedAnnotation]; lastApplied != "" {
+		var applied struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(lastApplied), &applied); err == nil && applied.APIVersion == apiVersion {
+			writers = append(writers, "kubectl apply")
+		}
+	}
+
+	return writers
+}
+
+// parseManifests returns the objects of a multi-document YAML manifest. Documents that aren't valid YAML, like the
+// templates of a Helm chart bundled by Fleet, still have their top-level apiVersion and kind read.
+func parseManifests(manifest []byte, origin string) []manifestObject {
+	var objs []manifestObject
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			break
+		}
+
+		var obj struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			obj.APIVersion = firstSubmatch(apiVersionLine, doc)
+			obj.Kind = firstSubmatch(kindLine, doc)
+		}
+		if obj.APIVersion == "" || obj.Kind == "" {
+			continue
+		}
+		objs = append(objs, manifestObject{
+			APIVersion: obj.APIVersion,
+			Kind:       obj.Kind,
+			Namespace:  obj.Metadata.Namespace,
+			Name:       obj.Metadata.Name,
+			Origin:     origin,
+		})
+	}
+
+	return objs
+}
+
+// decodeHelmRelease decodes the release stored by Helm in a Secret: the JSON of the release, gzipped and base64
+// encoded on top of the encoding of the Secret data.
+func decodeHelmRelease(secret *unstructured.Unstructured) (helmRelease, error) {
+	encoded, _, _ := unstructured.NestedString(secret.Object, "data", "release")
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return helmRelease{}, fmt.Errorf("failed to decode the Secret data: %w", err)
+	}
+	data, err = base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return helmRelease{}, fmt.Errorf("failed to decode the release: %w", err)
+	}
+	if bytes.HasPrefix(data, gzipMagic) {
+		if data, err = gunzip(data); err != nil {
+			return helmRelease{}, fmt.Errorf("failed to decompress the release: %w", err)
+		}
+	}
+
+	var release helmRelease
+	if err := json.Unmarshal(data, &release); err != nil {
+		return helmRelease{}, fmt.Errorf("failed to unmarshal the release: %w", err)
+	}
+
+	return release, nil
+}
+
+// decodeBundleResource returns the content of a resource of a Fleet bundle, following its encoding.
+func decodeBundleResource(resource map[string]any) ([]byte, error) {
+	content, _ := resource["content"].(string)
+	encoding, _ := resource["encoding"].(string)
+	switch encoding {
+	case "":
+		return []byte(content), nil
+	case "base64", "base64+gz":
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, err
+		}
+		if encoding == "base64+gz" {
+			return gunzip(data)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding '%s'", encoding)
+	}
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func firstSubmatch(re *regexp.Regexp, data []byte) string {
+	if match := re.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+
+	return ""
+}
+
+// loadDeprecatedAPIs parses and validates the deprecation table.
+func loadDeprecatedAPIs(data []byte) ([]deprecatedAPI, error) {
+	var apis []deprecatedAPI
+	if err := yaml.Unmarshal(data, &apis); err != nil {
+		return nil, fmt.Errorf("failed to parse the deprecated APIs: %w", err)
+	}
+
+	var errs []error
+	for i := range apis {
+		api := &apis[i]
+		if api.APIVersion == "" || api.Kind == "" || api.Resource == "" {
+			errs = append(errs, fmt.Errorf("entry %d: apiVersion, kind and resource must be set", i))
+			continue
+		}
+		if _, err := schema.ParseGroupVersion(api.APIVersion); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i, err))
+		}
+		var err error
+		if api.deprecatedIn, err = version.ParseGeneric(api.DeprecatedIn); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: invalid deprecatedIn: %w", i, err))
+		}
+		if api.removedIn, err = version.ParseGeneric(api.RemovedIn); err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: invalid removedIn: %w", i, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid deprecated APIs: %w", err)
+	}
+
+	return apis, nil
+}
+
+// reachedBy returns whether the minor version of target is v or a later one, Kubernetes deprecates and removes APIs
+// in minor versions.
+func reachedBy(v, target *version.Version) bool {
+	return cmp.Or(cmp.Compare(target.Major(), v.Major()), cmp.Compare(target.Minor(), v.Minor())) >= 0
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+// newHelmReleaseSecret returns the Secret where Helm stores a revision of a release with its status and rendered manifest.
+func newHelmReleaseSecret(t *testing.T, namespace, name string, revision int, status, manifest string) *unstructured.Unstructured {
+	t.Helper()
+
+	release, err := json.Marshal(helmRelease{Name: name, Namespace: namespace, Version: revision, Manifest: manifest})
+	require.NoError(t, err)
+	helmEncoded := base64.StdEncoding.EncodeToString(gzipped(t, string(release)))
+
+	secret := &unstructured.Unstructured{Object: map[string]any{
+		"type": helmReleaseSecretType,
+		"data": map[string]any{"release": base64.StdEncoding.EncodeToString([]byte(helmEncoded))},
+	}}
+	secret.SetAPIVersion("v1")
+	secret.SetKind("Secret")
+	secret.SetNamespace(namespace)
+	secret.SetName(fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision))
+	secret.SetLabels(map[string]string{"owner": "helm", "name": name, "version": fmt.Sprint(revision), "status": status})
+
+	return secret
+}
+
+// newLiveObject returns an object stored at apiVersion, with a field manager per API version it was written with.
+func newLiveObject(apiVersion, kind, namespace, name string, writtenWith map[string]string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{}}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	var managedFields []metav1.ManagedFieldsEntry
+	for manager, apiVersion := range writtenWith {
+		managedFields = append(managedFields, metav1.ManagedFieldsEntry{Manager: manager, APIVersion: apiVersion, Operation: metav1.ManagedFieldsOperationUpdate})
+	}
+	obj.SetManagedFields(managedFields)
+
+	return obj
+}
+
+func TestScanDeprecatedAPIs(t *testing.T) {
+	fakeToken := "fakeToken"
+	apis, err := loadDeprecatedAPIs(deprecatedAPIsTable)
+	require.NoError(t, err)
+	listKinds := map[schema.GroupVersionResource]string{
+		converter.K8sKindsToGVRs["secret"]:           "SecretList",
+		converter.K8sKindsToGVRs["fleetcluster"]:     "ClusterList",
+		converter.K8sKindsToGVRs["bundledeployment"]: "BundleDeploymentList",
+	}
+	for _, api := range apis {
+		gv, _ := schema.ParseGroupVersion(api.APIVersion)
+		listKinds[gv.WithResource(api.Resource)] = api.Kind + "List"
+	}
+
+	fleetCluster := &unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"namespace": "cluster-fleet-local-local-1a3d67d0a899"},
+	}}
+	fleetCluster.SetAPIVersion("fleet.cattle.io/v1alpha1")
+	fleetCluster.SetKind("Cluster")
+	fleetCluster.SetNamespace("fleet-local")
+	fleetCluster.SetName("local")
+	fleetCluster.SetLabels(map[string]string{fleetClusterNameLabel: "local"})
+	bundleDeployment := &unstructured.Unstructured{Object: map[string]any{}}
+	bundleDeployment.SetAPIVersion("fleet.cattle.io/v1alpha1")
+	bundleDeployment.SetKind("BundleDeployment")
+	bundleDeployment.SetNamespace("cluster-fleet-local-local-1a3d67d0a899")
+	bundleDeployment.SetName("shop-frontend")
+	bundleDeployment.SetLabels(map[string]string{bundleNameLabel: "shop-frontend", bundleNamespaceLabel: "fleet-local"})
+	bundle := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{"resources": []any{
+			map[string]any{
+				"name":    "ingress.yaml",
+				"content": "apiVersion: extensions/v1beta1\nkind: Ingress\nmetadata:\n  name: frontend\n  namespace: shop\n",
+			},
+			map[string]any{
+				"name":     "chart/templates/hpa.yaml",
+				"encoding": "base64+gz",
+				"content":  base64.StdEncoding.EncodeToString(gzipped(t, "apiVersion: autoscaling/v2beta2\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: {{ .Release.Name }}\n")),
+			},
+			map[string]any{
+				"name":     "broken.yaml",
+				"encoding": "base64",
+				"content":  "not base64!",
+			},
+		}},
+	}}
+	bundle.SetAPIVersion("fleet.cattle.io/v1alpha1")
+	bundle.SetKind("Bundle")
+	bundle.SetNamespace("fleet-local")
+	bundle.SetName("shop-frontend")
+
+	objs := []runtime.Object{
+		newLiveObject("policy/v1beta1", "PodDisruptionBudget", "shop", "web", map[string]string{"helm": "policy/v1beta1"}, nil),
+		newLiveObject("policy/v1beta1", "PodDisruptionBudget", "shop", "db", map[string]string{"kube-controller-manager": "policy/v1"}, nil),
+		newLiveObject("batch/v1beta1", "CronJob", "shop", "report", nil, map[string]string{lastAppliedAnnotation: `{"apiVersion":"batch/v1beta1","kind":"CronJob"}`}),
+		newHelmReleaseSecret(t, "shop", "web", 1, "superseded", "apiVersion: extensions/v1beta1\nkind: Deployment\nmetadata:\n  name: web\n"),
+		newHelmReleaseSecret(t, "shop", "web", 2, "deployed", "---\n# Source: web/templates/pdb.yaml\napiVersion: policy/v1beta1\nkind: PodDisruptionBudget\nmetadata:\n  name: web\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n"),
+		newHelmReleaseSecret(t, "shop", "web", 3, "failed", "apiVersion: extensions/v1beta1\nkind: DaemonSet\nmetadata:\n  name: web\n"),
+		fleetCluster,
+		bundleDeployment,
+		bundle,
+	}
+
+	tests := map[string]struct {
+		params         scanDeprecatedAPIsParams
+		listErrors     map[string]error
+		expectedResult deprecatedAPIScan
+		expectedError  string
+	}{
+		"APIs deprecated and removed by the target version": {
+			params: scanDeprecatedAPIsParams{Cluster: "local", TargetVersion: "v1.25.3"},
+			expectedResult: deprecatedAPIScan{
+				Cluster:       "local",
+				TargetVersion: "v1.25",
+				Usages: []deprecatedAPIUsage{
+					{Source: sourceFleet, Kind: "HorizontalPodAutoscaler", APIVersion: "autoscaling/v2beta2", Replacement: "autoscaling/v2", RemovedIn: "v1.26", Removed: false, Origin: "Fleet bundle fleet-local/shop-frontend, resource chart/templates/hpa.yaml"},
+					{Source: sourceFleet, Kind: "Ingress", Namespace: "shop", Name: "frontend", APIVersion: "extensions/v1beta1", Replacement: "networking.k8s.io/v1", RemovedIn: "v1.22", Removed: true, Origin: "Fleet bundle fleet-local/shop-frontend, resource ingress.yaml", Note: "spec.backend is renamed spec.defaultBackend, and the backends use service.name and service.port"},
+					{Source: sourceHelm, Kind: "PodDisruptionBudget", Name: "web", APIVersion: "policy/v1beta1", Replacement: "policy/v1", RemovedIn: "v1.25", Removed: true, Origin: "Helm release shop/web revision 2", Note: "an empty spec.selector selects every pod of the namespace instead of none"},
+					{Source: sourceLive, Kind: "CronJob", Namespace: "shop", Name: "report", APIVersion: "batch/v1beta1", Replacement: "batch/v1", RemovedIn: "v1.25", Removed: true, Origin: "written by kubectl apply"},
+					{Source: sourceLive, Kind: "PodDisruptionBudget", Namespace: "shop", Name: "web", APIVersion: "policy/v1beta1", Replacement: "policy/v1", RemovedIn: "v1.25", Removed: true, Origin: "written by helm", Note: "an empty spec.selector selects every pod of the namespace instead of none"},
+				},
+				Notes: []string{"resource broken.yaml of Fleet bundle fleet-local/shop-frontend was not scanned: illegal base64 data at input byte 3"},
+			},
+		},
+		"Fleet bundles that can't be listed": {
+			params:     scanDeprecatedAPIsParams{Cluster: "local", TargetVersion: "v1.25"},
+			listErrors: map[string]error{"bundledeployments": apierrors.NewForbidden(converter.K8sKindsToGVRs["bundledeployment"].GroupResource(), "", errors.New("denied"))},
+			expectedResult: deprecatedAPIScan{
+				Cluster:       "local",
+				TargetVersion: "v1.25",
+				Usages: []deprecatedAPIUsage{
+					{Source: sourceHelm, Kind: "PodDisruptionBudget", Name: "web", APIVersion: "policy/v1beta1", Replacement: "policy/v1", RemovedIn: "v1.25", Removed: true, Origin: "Helm release shop/web revision 2", Note: "an empty spec.selector selects every pod of the namespace instead of none"},
+					{Source: sourceLive, Kind: "CronJob", Namespace: "shop", Name: "report", APIVersion: "batch/v1beta1", Replacement: "batch/v1", RemovedIn: "v1.25", Removed: true, Origin: "written by kubectl apply"},
+					{Source: sourceLive, Kind: "PodDisruptionBudget", Namespace: "shop", Name: "web", APIVersion: "policy/v1beta1", Replacement: "policy/v1", RemovedIn: "v1.25", Removed: true, Origin: "written by helm", Note: "an empty spec.selector selects every pod of the namespace instead of none"},
+				},
+				Notes: []string{`the Fleet bundles of Fleet cluster fleet-local/local were not scanned: bundledeployments.fleet.cattle.io is forbidden: denied`},
+			},
+		},
+		"no API deprecated by the target version": {
+			params: scanDeprecatedAPIsParams{Cluster: "local", TargetVersion: "1.8"},
+			expectedResult: deprecatedAPIScan{
+				Cluster:       "local",
+				TargetVersion: "v1.8",
+				Usages:        []deprecatedAPIUsage{},
+				Notes:         []string{"no API is deprecated in Kubernetes v1.8"},
+			},
+		},
+		"invalid target version": {
+			params:        scanDeprecatedAPIsParams{Cluster: "local", TargetVersion: "latest"},
+			expectedError: "invalid targetVersion 'latest', expected a Kubernetes version like v1.32",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...)
+			for resource, err := range tt.listErrors {
+				fakeDynClient.PrependReactor("list", resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, err
+				})
+			}
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.scanDeprecatedAPIs(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM deprecatedAPIScan `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedResult, resp.LLM)
+		})
+	}
+}
+
+func TestParseManifests(t *testing.T) {
+	tests := map[string]struct {
+		manifest     string
+		expectedObjs []manifestObject
+	}{
+		"rendered manifests": {
+			manifest: "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: web\n  namespace: shop\n---\n\n---\napiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\n",
+			expectedObjs: []manifestObject{
+				{APIVersion: "v1", Kind: "Service", Namespace: "shop", Name: "web", Origin: "origin"},
+				{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Origin: "origin"},
+			},
+		},
+		"template": {
+			manifest: "{{- if .Values.ingress.enabled }}\napiVersion: \"networking.k8s.io/v1beta1\"\nkind: Ingress\nmetadata:\n  name: {{ .Release.Name }}\n{{- end }}\n",
+			expectedObjs: []manifestObject{
+				{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", Origin: "origin"},
+			},
+		},
+		"no kind": {
+			manifest: "apiVersion: v2\nname: web\nversion: 1.0.0\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedObjs, parseManifests([]byte(tt.manifest), "origin"))
+		})
+	}
+}
+
+func TestLoadDeprecatedAPIs(t *testing.T) {
+	tests := map[string]struct {
+		table         string
+		expectedError string
+	}{
+		"embedded table": {
+			table: string(deprecatedAPIsTable),
+		},
+		"missing resource": {
+			table:         `[{"apiVersion": "batch/v1beta1", "kind": "CronJob", "deprecatedIn": "1.21", "removedIn": "1.25"}]`,
+			expectedError: "entry 0: apiVersion, kind and resource must be set",
+		},
+		"invalid version": {
+			table:         `[{"apiVersion": "batch/v1beta1", "kind": "CronJob", "resource": "cronjobs", "deprecatedIn": "1.21", "removedIn": "soon"}]`,
+			expectedError: "entry 0: invalid removedIn",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			apis, err := loadDeprecatedAPIs([]byte(tt.table))
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, apis)
+			for _, api := range apis {
+				assert.True(t, reachedBy(api.deprecatedIn, api.removedIn), "%s %s is removed before being deprecated", api.APIVersion, api.Kind)
+			}
+		})
+	}
+}
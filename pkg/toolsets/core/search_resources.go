@@ -0,0 +1,241 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	defaultSearchLimitPerCluster = 20
+	defaultSearchLimit           = 100
+	// searchPageSize is the number of objects requested per page when a name pattern has to be applied client-side.
+	searchPageSize = 500
+)
+
+var zapSearchResources = zap.String("tool", "searchResources")
+
+// searchResourcesParams specifies the parameters needed to search resources across clusters.
+type searchResourcesParams struct {
+	Kind            string   `json:"kind" jsonschema:"the type of Kubernetes resource (e.g., Pod, Deployment, Service)"`
+	NamePattern     string   `json:"namePattern,omitempty" jsonschema:"optional pattern the resource name must match. Supports * and ? wildcards (e.g. payments*). Without wildcards any name containing the pattern matches. Matching is case-insensitive"`
+	LabelSelector   string   `json:"labelSelector,omitempty" jsonschema:"optional label selector to filter resources (e.g. team=checkout)"`
+	Namespace       string   `json:"namespace,omitempty" jsonschema:"optional namespace to search in. Empty to search all namespaces"`
+	Clusters        []string `json:"clusters,omitempty" jsonschema:"optional list of clusters to search. Empty to search all clusters"`
+	LimitPerCluster int      `json:"limitPerCluster,omitempty" jsonschema:"maximum number of matches returned per cluster, defaults to 20"`
+	Limit           int      `json:"limit,omitempty" jsonschema:"maximum number of matches returned across all clusters, defaults to 100"`
+}
+
+// searchMatch is a summary of a resource that matched the search.
+type searchMatch struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+}
+
+// clusterSearchResult holds the matches found in a single cluster.
+type clusterSearchResult struct {
+	Matches   []searchMatch `json:"matches"`
+	Truncated bool          `json:"truncated,omitempty"`
+
+	objs []*unstructured.Unstructured
+}
+
+// searchResult is the response of the searchResources tool.
+type searchResult struct {
+	Kind     string                          `json:"kind"`
+	Clusters map[string]*clusterSearchResult `json:"clusters"`
+	Errors   map[string]client.ClusterError  `json:"errors,omitempty"`
+	Note     string                          `json:"note,omitempty"`
+}
+
+// searchResources searches for resources of a kind across all clusters the caller can see, filtering by name pattern
+// and label selector. Clusters are queried concurrently and matches are grouped by cluster.
+func (t *Tools) searchResources(ctx context.Context, toolReq *mcp.CallToolRequest, params searchResourcesParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("searchResources called")
+
+	if _, ok := converter.K8sKindsToGVRs[strings.ToLower(params.Kind)]; !ok {
+		return nil, nil, fmt.Errorf("unknown kind: %s", params.Kind)
+	}
+
+	if _, err := matchesNamePattern("", params.NamePattern); err != nil {
+		return nil, nil, err
+	}
+
+	limitPerCluster := params.LimitPerCluster
+	if limitPerCluster <= 0 {
+		limitPerCluster = defaultSearchLimitPerCluster
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	clusters, err := client.ClustersOrAll(ctx, t.client, middleware.Token(ctx), params.Clusters)
+	if err != nil {
+		zap.L().Error("failed to get clusters", zapSearchResources, zap.Error(err))
+		return nil, nil, err
+	}
+
+	results, errs := client.FanOut(ctx, clusters, client.FanOutOptions{}, func(ctx context.Context, cluster string) (*clusterSearchResult, error) {
+		return t.searchCluster(ctx, cluster, params, limitPerCluster)
+	})
+	for cluster, err := range errs {
+		zap.L().Warn("failed to search cluster", zapSearchResources, zap.String("cluster", cluster), zap.Error(err))
+	}
+
+	// apply the total limit in a stable order so repeated calls return the same matches.
+	clusterNames := make([]string, 0, len(results))
+	for cluster := range results {
+		clusterNames = append(clusterNames, cluster)
+	}
+	slices.Sort(clusterNames)
+
+	result := searchResult{
+		Kind:     params.Kind,
+		Clusters: map[string]*clusterSearchResult{},
+		Errors:   client.NewClusterErrors(errs),
+	}
+	var uiContext []response.UIContext
+	remaining := limit
+	totalLimited := false
+	for _, cluster := range clusterNames {
+		clusterResult := results[cluster]
+		if len(clusterResult.Matches) == 0 {
+			continue
+		}
+		if remaining <= 0 {
+			totalLimited = true
+			break
+		}
+		if len(clusterResult.Matches) > remaining {
+			clusterResult.Matches = clusterResult.Matches[:remaining]
+			clusterResult.objs = clusterResult.objs[:remaining]
+			clusterResult.Truncated = true
+			totalLimited = true
+		}
+		remaining -= len(clusterResult.Matches)
+
+		result.Clusters[cluster] = clusterResult
+		for _, obj := range clusterResult.objs {
+			uiContext = append(uiContext, response.NewUIContext(obj, cluster))
+		}
+	}
+	switch {
+	case totalLimited:
+		result.Note = fmt.Sprintf("Results were limited to %d matches in total. Narrow the search or increase the limit to see more.", limit)
+	case hasTruncatedCluster(result.Clusters):
+		result.Note = fmt.Sprintf("Some clusters had more matches than returned (limit %d per cluster). Narrow the search or increase the limits to see more.", limitPerCluster)
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, uiContext...)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapSearchResources, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// searchCluster lists the resources of a single cluster and keeps the ones matching the name pattern.
+// Resources are listed in pages and listing stops as soon as the limit is reached, so that a search
+// across large clusters doesn't load every object of the kind into memory.
+func (t *Tools) searchCluster(ctx context.Context, cluster string, params searchResourcesParams, limit int) (*clusterSearchResult, error) {
+	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), params.Namespace, cluster, converter.K8sKindsToGVRs[strings.ToLower(params.Kind)])
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.ListOptions{
+		LabelSelector: params.LabelSelector,
+		Limit:         searchPageSize,
+	}
+	if params.NamePattern == "" {
+		// every object matches, one more than the limit is enough to know whether there are more.
+		opts.Limit = int64(limit) + 1
+	}
+
+	result := &clusterSearchResult{Matches: []searchMatch{}}
+	for {
+		list, err := resourceInterface.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range list.Items {
+			resource := &list.Items[i]
+			matched, err := matchesNamePattern(resource.GetName(), params.NamePattern)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+			if len(result.Matches) == limit {
+				result.Truncated = true
+				return result, nil
+			}
+
+			match := searchMatch{
+				Name:      resource.GetName(),
+				Namespace: resource.GetNamespace(),
+				Labels:    resource.GetLabels(),
+			}
+			if ts := resource.GetCreationTimestamp(); !ts.IsZero() {
+				match.CreationTimestamp = ts.UTC().Format(time.RFC3339)
+			}
+			result.Matches = append(result.Matches, match)
+			result.objs = append(result.objs, resource)
+		}
+
+		if list.GetContinue() == "" {
+			return result, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+// matchesNamePattern reports whether name matches pattern. Patterns with wildcards are matched as globs,
+// other patterns as substrings. Both are case-insensitive and an empty pattern matches everything.
+func matchesNamePattern(name, pattern string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+	name = strings.ToLower(name)
+	pattern = strings.ToLower(pattern)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.Contains(name, pattern), nil
+	}
+
+	matched, err := path.Match(pattern, name)
+	if err != nil {
+		return false, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+	}
+
+	return matched, nil
+}
+
+func hasTruncatedCluster(clusters map[string]*clusterSearchResult) bool {
+	for _, clusterResult := range clusters {
+		if clusterResult.Truncated {
+			return true
+		}
+	}
+
+	return false
+}
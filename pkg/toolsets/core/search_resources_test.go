@@ -0,0 +1,331 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func searchScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return scheme
+}
+
+func searchListKinds() map[schema.GroupVersionResource]string {
+	return map[schema.GroupVersionResource]string{
+		{Group: "", Version: "v1", Resource: "pods"}:                         "PodList",
+		{Group: "apps", Version: "v1", Resource: "deployments"}:              "DeploymentList",
+		{Group: "management.cattle.io", Version: "v3", Resource: "clusters"}: "ClusterList",
+	}
+}
+
+func newSearchDeployment(name, namespace string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+}
+
+func TestSearchResources(t *testing.T) {
+	fakeToken := "fakeToken"
+
+	tests := map[string]struct {
+		params              searchResourcesParams
+		localObjs           []runtime.Object
+		downstreamObjs      []runtime.Object
+		unreachableClusters []string
+		expectedResult      string
+		expectedError       string
+	}{
+		"find deployment by name in every cluster": {
+			params: searchResourcesParams{Kind: "Deployment", NamePattern: "payments"},
+			localObjs: []runtime.Object{
+				newFakeManagementCluster("local"),
+				newFakeManagementCluster("c-1"),
+				newSearchDeployment("payments", "shop", nil),
+				newSearchDeployment("orders", "shop", nil),
+			},
+			downstreamObjs: []runtime.Object{
+				newSearchDeployment("payments-api", "payments", nil),
+			},
+			expectedResult: `{
+				"llm": {
+					"kind": "Deployment",
+					"clusters": {
+						"c-1": {"matches": [{"name": "payments-api", "namespace": "payments"}]},
+						"local": {"matches": [{"name": "payments", "namespace": "shop"}]}
+					}
+				},
+				"uiContext": [
+					{"cluster": "c-1", "kind": "Deployment", "name": "payments-api", "namespace": "payments", "type": "apps.deployment"},
+					{"cluster": "local", "kind": "Deployment", "name": "payments", "namespace": "shop", "type": "apps.deployment"}
+				]
+			}`,
+		},
+		"glob pattern and label selector": {
+			params: searchResourcesParams{Kind: "deployment", NamePattern: "*-API", LabelSelector: "team=checkout", Clusters: []string{"local", "c-1"}},
+			localObjs: []runtime.Object{
+				newFakeManagementCluster("c-1"),
+				newSearchDeployment("checkout-api", "shop", map[string]string{"team": "checkout"}),
+				newSearchDeployment("checkout-worker", "shop", map[string]string{"team": "checkout"}),
+			},
+			downstreamObjs: []runtime.Object{
+				newSearchDeployment("search-api", "shop", map[string]string{"team": "search"}),
+			},
+			expectedResult: `{
+				"llm": {
+					"kind": "deployment",
+					"clusters": {
+						"local": {"matches": [{"name": "checkout-api", "namespace": "shop", "labels": {"team": "checkout"}}]}
+					}
+				},
+				"uiContext": [
+					{"cluster": "local", "kind": "Deployment", "name": "checkout-api", "namespace": "shop", "type": "apps.deployment"}
+				]
+			}`,
+		},
+		"per cluster and total limits": {
+			params: searchResourcesParams{Kind: "Deployment", Clusters: []string{"local", "c-1"}, LimitPerCluster: 2, Limit: 3},
+			localObjs: []runtime.Object{
+				newFakeManagementCluster("c-1"),
+				newSearchDeployment("a", "default", nil),
+				newSearchDeployment("b", "default", nil),
+				newSearchDeployment("c", "default", nil),
+			},
+			downstreamObjs: []runtime.Object{
+				newSearchDeployment("d", "default", nil),
+				newSearchDeployment("e", "default", nil),
+			},
+			expectedResult: `{
+				"llm": {
+					"kind": "Deployment",
+					"clusters": {
+						"c-1": {"matches": [{"name": "d", "namespace": "default"}, {"name": "e", "namespace": "default"}]},
+						"local": {"matches": [{"name": "a", "namespace": "default"}], "truncated": true}
+					},
+					"note": "Results were limited to 3 matches in total. Narrow the search or increase the limit to see more."
+				},
+				"uiContext": [
+					{"cluster": "c-1", "kind": "Deployment", "name": "d", "namespace": "default", "type": "apps.deployment"},
+					{"cluster": "c-1", "kind": "Deployment", "name": "e", "namespace": "default", "type": "apps.deployment"},
+					{"cluster": "local", "kind": "Deployment", "name": "a", "namespace": "default", "type": "apps.deployment"}
+				]
+			}`,
+		},
+		"per cluster limit only": {
+			params: searchResourcesParams{Kind: "Deployment", Clusters: []string{"local", "c-1"}, LimitPerCluster: 1, Limit: 10},
+			localObjs: []runtime.Object{
+				newFakeManagementCluster("c-1"),
+				newSearchDeployment("a", "default", nil),
+				newSearchDeployment("b", "default", nil),
+			},
+			downstreamObjs: []runtime.Object{
+				newSearchDeployment("d", "default", nil),
+			},
+			expectedResult: `{
+				"llm": {
+					"kind": "Deployment",
+					"clusters": {
+						"c-1": {"matches": [{"name": "d", "namespace": "default"}]},
+						"local": {"matches": [{"name": "a", "namespace": "default"}], "truncated": true}
+					},
+					"note": "Some clusters had more matches than returned (limit 1 per cluster). Narrow the search or increase the limits to see more."
+				},
+				"uiContext": [
+					{"cluster": "c-1", "kind": "Deployment", "name": "d", "namespace": "default", "type": "apps.deployment"},
+					{"cluster": "local", "kind": "Deployment", "name": "a", "namespace": "default", "type": "apps.deployment"}
+				]
+			}`,
+		},
+		"unreachable cluster is reported": {
+			params: searchResourcesParams{Kind: "Pod", Clusters: []string{"local", "c-1"}},
+			localObjs: []runtime.Object{
+				newFakeManagementCluster("c-1"),
+				&corev1.Pod{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+					ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default"},
+				},
+			},
+			unreachableClusters: []string{"c-1"},
+			expectedResult: `{
+				"llm": {
+					"kind": "Pod",
+					"clusters": {
+						"local": {"matches": [{"name": "pod-1", "namespace": "default"}]}
+					},
+					"errors": {
+						"c-1": {"reason": "Unreachable", "message": "cluster agent disconnected"}
+					}
+				},
+				"uiContext": [
+					{"cluster": "local", "kind": "Pod", "name": "pod-1", "namespace": "default", "type": "pod"}
+				]
+			}`,
+		},
+		"unknown kind": {
+			params:        searchResourcesParams{Kind: "Widget"},
+			expectedError: "unknown kind: Widget",
+		},
+		"invalid name pattern": {
+			params:        searchResourcesParams{Kind: "Pod", NamePattern: "[a-"},
+			expectedError: "invalid name pattern",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			localDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(searchScheme(), searchListKinds(), tt.localObjs...)
+			downstreamDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(searchScheme(), searchListKinds(), tt.downstreamObjs...)
+			unreachableDynClient := dynamicfake.NewSimpleDynamicClient(searchScheme())
+			unreachableDynClient.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, apierrors.NewServiceUnavailable("cluster agent disconnected")
+			})
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					for _, cluster := range tt.unreachableClusters {
+						if strings.HasSuffix(inConfig.Host, "/"+cluster) {
+							return unreachableDynClient, nil
+						}
+					}
+					if strings.HasSuffix(inConfig.Host, "/local") {
+						return localDynClient, nil
+					}
+					return downstreamDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.searchResources(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+			}
+		})
+	}
+}
+
+func TestSearchClusterPaging(t *testing.T) {
+	fakeToken := "fakeToken"
+	pages := []*unstructured.UnstructuredList{
+		{
+			Object: map[string]any{"apiVersion": "v1", "kind": "PodList", "metadata": map[string]any{"continue": "page-2"}},
+			Items:  []unstructured.Unstructured{newSearchPod("web-1"), newSearchPod("db-1")},
+		},
+		{
+			Object: map[string]any{"apiVersion": "v1", "kind": "PodList", "metadata": map[string]any{"continue": "page-3"}},
+			Items:  []unstructured.Unstructured{newSearchPod("web-2"), newSearchPod("web-3")},
+		},
+		{
+			Object: map[string]any{"apiVersion": "v1", "kind": "PodList", "metadata": map[string]any{}},
+			Items:  []unstructured.Unstructured{newSearchPod("web-4")},
+		},
+	}
+
+	tests := map[string]struct {
+		limit             int
+		expectedMatches   []string
+		expectedTruncated bool
+		expectedCalls     int
+	}{
+		"follows continue tokens until the last page": {
+			limit:           10,
+			expectedMatches: []string{"web-1", "web-2", "web-3", "web-4"},
+			expectedCalls:   3,
+		},
+		"stops listing once the limit is reached": {
+			limit:             2,
+			expectedMatches:   []string{"web-1", "web-2"},
+			expectedTruncated: true,
+			expectedCalls:     2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			fakeDynClient := dynamicfake.NewSimpleDynamicClient(searchScheme())
+			fakeDynClient.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				page := pages[calls]
+				calls++
+				return true, page, nil
+			})
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, err := tools.searchCluster(middleware.WithToken(t.Context(), fakeToken), "local", searchResourcesParams{Kind: "Pod", NamePattern: "web-*"}, tt.limit)
+
+			require.NoError(t, err)
+			var names []string
+			for _, match := range result.Matches {
+				names = append(names, match.Name)
+			}
+			assert.Equal(t, tt.expectedMatches, names)
+			assert.Equal(t, tt.expectedTruncated, result.Truncated)
+			assert.Equal(t, tt.expectedCalls, calls)
+		})
+	}
+}
+
+func newSearchPod(name string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]any{"name": name, "namespace": "default"},
+	}}
+}
+
+func TestMatchesNamePattern(t *testing.T) {
+	tests := map[string]struct {
+		name     string
+		pattern  string
+		expected bool
+	}{
+		"empty pattern":        {name: "payments", pattern: "", expected: true},
+		"substring":            {name: "payments-api", pattern: "ments", expected: true},
+		"substring no match":   {name: "orders", pattern: "payments", expected: false},
+		"case insensitive":     {name: "Payments", pattern: "PAYMENTS", expected: true},
+		"glob prefix":          {name: "payments-api", pattern: "payments*", expected: true},
+		"glob is anchored":     {name: "my-payments", pattern: "payments*", expected: false},
+		"single char":          {name: "web-1", pattern: "web-?", expected: true},
+		"single char no match": {name: "web-10", pattern: "web-?", expected: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			matched, err := matchesNamePattern(tt.name, tt.pattern)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, matched)
+		})
+	}
+}
@@ -60,24 +60,76 @@ func (t *Tools) AddTools(mcpServer *mcp.Server) {
 		t.listKubernetesResources,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "searchResources",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Searches resources of a kind across all clusters, or the given clusters, and returns the matches grouped by cluster. Filters by name pattern, label selector and namespace.
+Use it instead of calling listKubernetesResources once per cluster, e.g. to find where a deployment is running or which clusters have pods with a given label.
+The name pattern is applied after listing, so a name-only search of a common kind like Pod reads every object of that kind in every cluster. Prefer adding a label selector, a namespace or a list of clusters to keep searches cheap.`},
+		t.searchResources,
+	)
+
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name: "inspectPod",
 		Meta: map[string]any{
 			toolsSetAnn: toolsSet,
 		},
-		Description: `Returns all information related to a Pod. It includes its parent Deployment or StatefulSet, the CPU and memory consumption and the logs. It must be used for troubleshooting problems with pods.`},
+		Description: `Returns all information related to a Pod. It includes the workload managing it (Deployment, StatefulSet, DaemonSet, ReplicaSet or Job), the CPU and memory consumption, the logs and the warning events of the last hour. When the pod is failing, a diagnosis classifies the failure (image pull errors, OOMKilled, crash loops, failed probes, unschedulable pods, missing ConfigMaps or Secrets, container config errors, node not ready, eviction) with its evidence and suggested remediations. It must be used for troubleshooting problems with pods.`},
 		t.inspectPod,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "getPodLogs",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the logs of a pod, or the logs of all the pods matching a label selector or owned by a workload merged into one time-ordered stream where each line is prefixed with [pod/container].
+Supports init containers, the logs of the previous container instance (use previous=true for pods in CrashLoopBackOff), time windows with sinceSeconds or sinceTime, tail lines, regular expression filtering with grep and timestamps.`},
+		t.getPodLogs,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "getEvents",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the Kubernetes events of a cluster, optionally filtered by namespace, involved object (kind, name, uid), type (Warning or Normal), reason and age. Repeated events are deduplicated by reason and message and returned with their total count, most recent first.
+Use it to find out why a resource is failing, e.g. scheduling failures, image pull errors, failed probes or OOM kills.`},
+		t.getEvents,
+	)
+
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name: "getDeployment",
 		Meta: map[string]any{
 			toolsSetAnn: toolsSet,
 		},
-		Description: `Returns a Deployment and its Pods. It must be used for troubleshooting problems with deployments.`},
+		Description: `Returns a Deployment and its Pods, with the warning events of the last hour involving them. It must be used for troubleshooting problems with deployments.`},
 		t.getDeploymentDetails,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "inspectWorkload",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns a workload of any kind (Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob) with its Pods and the warning events of the last hour involving them.
+Deployments, StatefulSets and DaemonSets include their revision history and rollout status. Jobs and CronJobs include their most recent runs with their outcome. It must be used for troubleshooting problems with workloads.`},
+		t.inspectWorkload,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "recommendWorkloadResources",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Recommends CPU and memory requests and limits for the containers of a Deployment, StatefulSet or DaemonSet, based on the peak usage of its pods reported by the metrics server.
+Requests are the peak usage plus 20%, the memory limit is the peak usage plus 50% and the CPU limit, only set when the container already has one, is twice the request. Containers that were OOMKilled use their memory limit as peak usage.
+It reports over- and under-provisioned containers and returns the parameters to pass to patchKubernetesResourcePlan to review the recommended resources. It never applies them.`},
+		t.recommendWorkloadResources,
+	)
+
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name: "getNodeMetrics",
 		Meta: map[string]any{
@@ -87,15 +139,75 @@ func (t *Tools) AddTools(mcpServer *mcp.Server) {
 		t.getNodes,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "explainScheduling",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Evaluates a pod against every node of its cluster and returns, for each node, why the pod doesn't fit: cordons and untolerated taints, nodeSelector and required node affinity, allocatable minus requested resources, topology spread constraints and the node affinity of its PersistentVolumes.
+It must be used to explain why a pod is Pending or which nodes it could run on.`},
+		t.explainScheduling,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "analyzeServiceConnectivity",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Analyzes why a Service doesn't receive traffic and returns a pass/fail checklist with evidence: the pods matched by its selector and their readiness, its target ports against the container ports, its EndpointSlices, the Ingresses routing to it with their IngressClass, and the NetworkPolicies blocking the traffic from a source namespace or pod, both the egress policies of the source and the ingress policies of the pods.
+It must be used for troubleshooting Services without endpoints or unreachable applications.`},
+		t.analyzeServiceConnectivity,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "evaluateNetworkPolicy",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Evaluates whether traffic from a source (a namespace and pod labels, or an IP block outside of the cluster) to a port of a destination pod is allowed by the NetworkPolicies of the cluster, following the standard Kubernetes semantics.
+Returns the egress policies selecting the source, the ingress policies selecting the destination and the rules that allow the traffic. It must be used to understand which NetworkPolicies block or allow some traffic.`},
+		t.evaluateNetworkPolicy,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "checkAccess",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Checks whether the current user is allowed to perform an action (a verb on a resource, optionally in a namespace and on a named resource) in a cluster, with a SelfSubjectAccessReview. Also returns the rules of the user covering the resource, from a SelfSubjectRulesReview of the namespace, or of the default namespace for cluster-wide actions.
+It must be used to explain why a request was forbidden (HTTP 403) or before asking the user to perform an action.`},
+		t.checkAccess,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "whoCan",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Lists the users, groups and service accounts allowed to perform an action (a verb on a resource, optionally in a namespace and on a named resource) in a cluster, with the binding and the role granting it.
+Walks the Roles, ClusterRoles and their bindings of the cluster, and the Rancher RoleTemplates bound to the cluster (ClusterRoleTemplateBindings) or to the project of the namespace (ProjectRoleTemplateBindings). Use it to find who can grant a missing permission or who has access to a resource.`},
+		t.whoCan,
+	)
+
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name: "getClusterImages",
 		Meta: map[string]any{
 			toolsSetAnn: toolsSet,
 		},
-		Description: `Returns all container images running across the specified clusters, along with the pods (name and namespace) using each image. Use in priority this tool to audit clusters for container registry or image usage, or to find which pods are running a specific container image. If clusters is empty, returns data for all clusters.`},
+		Description: `Returns all container images running across the specified clusters, along with the pods (name and namespace) using each image. Use in priority this tool to audit clusters for container registry or image usage, or to find which pods are running a specific container image. If clusters is empty, returns data for all clusters. Clusters that could not be queried are listed under errors with the reason (e.g. Unreachable, Forbidden).`},
 		t.getClusterImages,
 	)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "scanDeprecatedAPIs",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Scans a cluster for the objects using a Kubernetes API that a target Kubernetes version deprecates or removes, before upgrading the cluster. It checks the live objects written with the API, the manifests of the latest revision of every Helm release and the manifests of the Fleet bundles deployed to the cluster.
+Returns every object found with its source (live, helm or fleet), its current apiVersion, the apiVersion replacing it, the Kubernetes version removing it, and what uses the API: the field managers of a live object, or the Helm release or Fleet bundle of a manifest.`},
+		t.scanDeprecatedAPIs,
+	)
+
 	mcp.AddTool(mcpServer, &mcp.Tool{
 		Name: "listClusters",
 		Meta: map[string]any{
@@ -153,5 +265,59 @@ Example of the patch parameter:
 Example of the patch parameter:
 [{"op": "replace", "path": "/spec/replicas", "value": 3}]`},
 			t.updateKubernetesResourcePlan)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "cordonNode",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Cordons a node: marks it as unschedulable so that no new pod is scheduled on it. The pods running on the node are not affected. Returns the modified node.`},
+			t.cordonNode,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "cordonNodePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to cordon a node. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.cordonNodePlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "uncordonNode",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Uncordons a node: marks it as schedulable again, e.g. after a drain once the maintenance is done. Returns the modified node.`},
+			t.uncordonNode,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "uncordonNodePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to uncordon a node. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.uncordonNodePlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "drainNode",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Drains a node for maintenance, like kubectl drain: cordons it and evicts its pods through the Eviction API, so that PodDisruptionBudgets are respected. DaemonSet, mirror and completed pods are skipped. Pods not managed by a controller and pods using emptyDir volumes are not evicted unless force and deleteEmptyDirData are set, since their eviction loses them or their emptyDir data. Evictions refused by a PodDisruptionBudget are retried until timeoutSeconds (defaults to 300). Returns the evicted, skipped and failed pods, and the pods still blocked with the PodDisruptionBudgets blocking them.`},
+			t.drainNode,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "drainNodePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to drain a node. It returns the pods that would be evicted, the PodDisruptionBudgets that currently block their eviction, the pods that would be skipped and the pods not evicted unless force or deleteEmptyDirData is set, without cordoning the node or evicting anything. Only used for displaying the change when using human validation.`},
+			t.drainNodePlan,
+		)
 	}
 }
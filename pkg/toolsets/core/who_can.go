@@ -0,0 +1,131 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+var zapWhoCan = zap.String("tool", "whoCan")
+
+// projectIDLabel is the label Rancher sets on the namespaces of a project, its value is the ID of the project.
+const projectIDLabel = "field.cattle.io/projectId"
+
+// whoCanResult is the response of the whoCan tool.
+type whoCanResult struct {
+	Action string       `json:"action"`
+	Grants []rbac.Grant `json:"grants"`
+	Notes  []string     `json:"notes"`
+}
+
+// whoCan lists the subjects allowed to perform an action, from the Roles, ClusterRoles and their bindings in the
+// cluster and from the Rancher RoleTemplates bound to the cluster or to the project of the namespace.
+func (t *Tools) whoCan(ctx context.Context, toolReq *mcp.CallToolRequest, params accessParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("whoCan called")
+
+	if err := params.validate(); err != nil {
+		return nil, nil, err
+	}
+	clusterID, err := t.client.GetClusterID(ctx, middleware.Token(ctx), params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to get cluster ID", zapWhoCan, zap.Error(err))
+		return nil, nil, err
+	}
+
+	var objs rbac.Objects
+	if objs.ClusterRoles, err = listTyped[rbacv1.ClusterRole](ctx, t, clusterID, "clusterrole", ""); err != nil {
+		zap.L().Error("failed to get ClusterRoles", zapWhoCan, zap.Error(err))
+		return nil, nil, err
+	}
+	if objs.ClusterRoleBindings, err = listTyped[rbacv1.ClusterRoleBinding](ctx, t, clusterID, "clusterrolebinding", ""); err != nil {
+		zap.L().Error("failed to get ClusterRoleBindings", zapWhoCan, zap.Error(err))
+		return nil, nil, err
+	}
+	if params.Namespace != "" {
+		if objs.Roles, err = listTyped[rbacv1.Role](ctx, t, clusterID, "role", params.Namespace); err != nil {
+			zap.L().Error("failed to get Roles", zapWhoCan, zap.Error(err))
+			return nil, nil, err
+		}
+		if objs.RoleBindings, err = listTyped[rbacv1.RoleBinding](ctx, t, clusterID, "rolebinding", params.Namespace); err != nil {
+			zap.L().Error("failed to get RoleBindings", zapWhoCan, zap.Error(err))
+			return nil, nil, err
+		}
+	}
+
+	result := whoCanResult{
+		Action: params.attributes().String() + actionLocation(params.Cluster, params.Namespace),
+		Notes: []string{
+			"Members of the listed groups are allowed too, group membership comes from the authentication provider and is not listed.",
+			"Rancher creates RoleBindings and ClusterRoleBindings for its role template bindings, so a subject can be listed both through the Rancher binding and the generated binding.",
+		},
+	}
+	// the Rancher objects are only readable by some users, the Kubernetes bindings are still evaluated without them.
+	if err := t.rancherBindings(ctx, clusterID, params.Namespace, &objs); err != nil {
+		zap.L().Warn("failed to get Rancher role templates and bindings", zapWhoCan, zap.Error(err))
+		result.Notes = append(result.Notes, fmt.Sprintf("The Rancher role templates and their bindings were not evaluated: %v.", err))
+	}
+	result.Grants = rbac.WhoCan(objs, params.attributes(), params.Namespace)
+
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zapWhoCan, zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// rancherBindings adds to objs the RoleTemplates, the ClusterRoleTemplateBindings of the cluster and, when the
+// namespace belongs to a project, the ProjectRoleTemplateBindings of the project. They are stored in the local cluster.
+func (t *Tools) rancherBindings(ctx context.Context, clusterID, namespace string, objs *rbac.Objects) error {
+	var err error
+	if objs.RoleTemplates, err = listTyped[rbac.RoleTemplate](ctx, t, LocalCluster, "roletemplate", ""); err != nil {
+		return err
+	}
+	if objs.ClusterRoleTemplateBindings, err = listTyped[rbac.RoleTemplateBinding](ctx, t, LocalCluster, "clusterroletemplatebinding", clusterID); err != nil {
+		return err
+	}
+	if namespace == "" {
+		return nil
+	}
+
+	namespaceLabels, err := t.namespaceLabels(ctx, clusterID, namespace)
+	if err != nil {
+		return err
+	}
+	projectID := namespaceLabels[projectIDLabel]
+	if projectID == "" {
+		return nil
+	}
+	objs.ProjectRoleTemplateBindings, err = listTyped[rbac.RoleTemplateBinding](ctx, t, LocalCluster, "projectroletemplatebinding", projectID)
+
+	return err
+}
+
+// listTyped lists the resources of a kind and converts them to T.
+func listTyped[T any](ctx context.Context, t *Tools, cluster, kind, namespace string) ([]T, error) {
+	resources, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster:   cluster,
+		Kind:      kind,
+		Namespace: namespace,
+		Token:     middleware.Token(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	typed, err := fromUnstructuredList[T](resources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert unstructured object to %s: %w", kind, err)
+	}
+
+	return typed, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/rancher/rancher-ai-mcp/pkg/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newManagementObject(kind, namespace, name string, fields map[string]any) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion("management.cattle.io/v3")
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+
+	return obj
+}
+
+func TestWhoCan(t *testing.T) {
+	fakeToken := "fakeToken"
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
+	listKinds := map[schema.GroupVersionResource]string{
+		{Group: "management.cattle.io", Version: "v3", Resource: "roletemplates"}:               "RoleTemplateList",
+		{Group: "management.cattle.io", Version: "v3", Resource: "clusterroletemplatebindings"}: "ClusterRoleTemplateBindingList",
+		{Group: "management.cattle.io", Version: "v3", Resource: "projectroletemplatebindings"}: "ProjectRoleTemplateBindingList",
+	}
+	objs := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{projectIDLabel: "p-xyz89"}}},
+		&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+		},
+		&rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:masters"}},
+		},
+		&rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-reader", Namespace: "shop"},
+			Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+		},
+		&rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "shop"},
+			RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "secret-reader"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Namespace: "shop", Name: "app"}},
+		},
+		newManagementObject("RoleTemplate", "", "project-owner", map[string]any{
+			"context": "project",
+			"rules":   []any{map[string]any{"verbs": []any{"*"}, "apiGroups": []any{""}, "resources": []any{"secrets"}}},
+		}),
+		newManagementObject("ClusterRoleTemplateBinding", "local", "crtb-member", map[string]any{
+			"userName": "u-member", "clusterName": "local", "roleTemplateName": "cluster-member",
+		}),
+		newManagementObject("ProjectRoleTemplateBinding", "p-xyz89", "prtb-owner", map[string]any{
+			"userName": "u-owner", "projectName": "local:p-xyz89", "roleTemplateName": "project-owner",
+		}),
+	}
+
+	tests := map[string]struct {
+		params         accessParams
+		expectedGrants []rbac.Grant
+		expectedError  string
+	}{
+		"namespaced action": {
+			params: accessParams{Cluster: "local", Verb: "get", Resource: "secrets", Namespace: "shop", Name: "db"},
+			expectedGrants: []rbac.Grant{
+				{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:masters"}, Binding: "ClusterRoleBinding cluster-admin", Role: "ClusterRole cluster-admin", Scope: "cluster"},
+				{Subject: rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: "shop", Name: "app"}, Binding: "RoleBinding shop/app", Role: "Role shop/secret-reader", Scope: "namespace shop"},
+				{Subject: rbacv1.Subject{Kind: rbacv1.UserKind, Name: "u-owner"}, Binding: "ProjectRoleTemplateBinding p-xyz89/prtb-owner", Role: "RoleTemplate project-owner", Scope: "project local:p-xyz89"},
+			},
+		},
+		"cluster-wide action": {
+			params: accessParams{Cluster: "local", Verb: "delete", Resource: "nodes"},
+			expectedGrants: []rbac.Grant{
+				{Subject: rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:masters"}, Binding: "ClusterRoleBinding cluster-admin", Role: "ClusterRole cluster-admin", Scope: "cluster"},
+			},
+		},
+		"missing resource": {
+			params:        accessParams{Cluster: "local", Verb: "get"},
+			expectedError: "verb and resource must be set",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeDynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, objs...)
+			c := &client.Client{
+				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+					return fakeDynClient, nil
+				},
+			}
+			tools := NewTools(test.WrapClient(c, fakeToken), false)
+
+			result, _, err := tools.whoCan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			var resp struct {
+				LLM whoCanResult `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, tt.expectedGrants, resp.LLM.Grants)
+			assert.Len(t, resp.LLM.Notes, 2, "the Rancher bindings must be evaluated")
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// maxAlerts is the maximum number of alerts returned by listAlerts. The most severe and oldest alerts are kept.
+const maxAlerts = 50
+
+// severities are the severities of the rancher-monitoring alerting rules, from the most severe.
+var severities = []string{"critical", "warning", "info", "none"}
+
+// alertResourceLabels are the labels of the alerting rules of rancher-monitoring that name the resource an alert is
+// about, in the order they are reported. Labels naming the exporter of a metric, like service, are not included.
+var alertResourceLabels = []struct {
+	label      string
+	kind       string
+	namespaced bool
+}{
+	{label: "pod", kind: "Pod", namespaced: true},
+	{label: "deployment", kind: "Deployment", namespaced: true},
+	{label: "statefulset", kind: "StatefulSet", namespaced: true},
+	{label: "daemonset", kind: "DaemonSet", namespaced: true},
+	{label: "replicaset", kind: "ReplicaSet", namespaced: true},
+	{label: "job_name", kind: "Job", namespaced: true},
+	{label: "cronjob", kind: "CronJob", namespaced: true},
+	{label: "horizontalpodautoscaler", kind: "HorizontalPodAutoscaler", namespaced: true},
+	{label: "persistentvolumeclaim", kind: "PersistentVolumeClaim", namespaced: true},
+	{label: "resourcequota", kind: "ResourceQuota", namespaced: true},
+	{label: "node", kind: "Node", namespaced: false},
+}
+
+// gettableAlert is an alert of the Alertmanager API v2.
+type gettableAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    string            `json:"startsAt"`
+	Status      struct {
+		State       string   `json:"state"`
+		SilencedBy  []string `json:"silencedBy"`
+		InhibitedBy []string `json:"inhibitedBy"`
+	} `json:"status"`
+}
+
+// alert is an alert returned by listAlerts.
+type alert struct {
+	Name        string            `json:"name"`
+	Severity    string            `json:"severity,omitempty"`
+	State       string            `json:"state"`
+	StartsAt    string            `json:"startsAt"`
+	Summary     string            `json:"summary,omitempty"`
+	Description string            `json:"description,omitempty"`
+	RunbookURL  string            `json:"runbookUrl,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	SilencedBy  []string          `json:"silencedBy,omitempty"`
+	InhibitedBy []string          `json:"inhibitedBy,omitempty"`
+	Resources   []alertResource   `json:"resources,omitempty"`
+}
+
+// alertResource is a Kubernetes resource referenced by the labels of an alert.
+type alertResource struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// listAlertsParams specifies the parameters needed to list the alerts of a cluster.
+type listAlertsParams struct {
+	Cluster         string            `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Severity        string            `json:"severity,omitempty" jsonschema:"only the alerts of this severity: critical, warning, info or none"`
+	Namespace       string            `json:"namespace,omitempty" jsonschema:"only the alerts about resources of this namespace"`
+	Labels          map[string]string `json:"labels,omitempty" jsonschema:"only the alerts with these label values, like alertname"`
+	IncludeSilenced bool              `json:"includeSilenced,omitempty" jsonschema:"whether silenced and inhibited alerts are included"`
+}
+
+// alertsResult is the result of listAlerts.
+type alertsResult struct {
+	Total  int      `json:"total"`
+	Alerts []alert  `json:"alerts"`
+	Notes  []string `json:"notes,omitempty"`
+}
+
+// listAlerts returns the active alerts of the rancher-monitoring Alertmanager of a cluster.
+func (t *Tools) listAlerts(ctx context.Context, toolReq *mcp.CallToolRequest, params listAlertsParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listAlerts called")
+
+	if params.Severity != "" && !slices.Contains(severities, params.Severity) {
+		return nil, nil, fmt.Errorf("invalid severity '%s', it must be one of %s", params.Severity, strings.Join(severities, ", "))
+	}
+	filters := []string{}
+	for name, value := range params.Labels {
+		filters = append(filters, labelFilter(name, "=", value))
+	}
+	if params.Severity != "" {
+		filters = append(filters, labelFilter("severity", "=", params.Severity))
+	}
+	if params.Namespace != "" {
+		filters = append(filters, labelFilter("namespace", "=", params.Namespace))
+	}
+
+	gettableAlerts, err := t.activeAlerts(ctx, params.Cluster, filters, params.IncludeSilenced)
+	if err != nil {
+		zap.L().Error("failed to list alerts", zap.String("tool", "listAlerts"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	result := alertsResult{Total: len(gettableAlerts), Alerts: []alert{}}
+	var uiContext []response.UIContext
+	for _, a := range gettableAlerts {
+		result.Alerts = append(result.Alerts, newAlert(a))
+	}
+	if len(result.Alerts) > maxAlerts {
+		result.Notes = append(result.Notes, fmt.Sprintf("Only the %d most severe and oldest of the %d alerts are included. Filter them by severity, namespace or labels to see the others.", maxAlerts, len(result.Alerts)))
+		result.Alerts = result.Alerts[:maxAlerts]
+	}
+	for _, a := range result.Alerts {
+		for _, r := range a.Resources {
+			obj := &unstructured.Unstructured{}
+			obj.SetKind(r.Kind)
+			obj.SetNamespace(r.Namespace)
+			obj.SetName(r.Name)
+			if resourceContext := response.NewUIContext(obj, params.Cluster); !slices.Contains(uiContext, resourceContext) {
+				uiContext = append(uiContext, resourceContext)
+			}
+		}
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(result, uiContext...)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "listAlerts"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// activeAlerts returns the active alerts of the Alertmanager of a cluster matching the filters, sorted from the most
+// severe and oldest. The filters are Alertmanager label matchers, like severity="critical".
+func (t *Tools) activeAlerts(ctx context.Context, cluster string, filters []string, includeSilenced bool) ([]gettableAlert, error) {
+	query := url.Values{
+		"active":    {"true"},
+		"silenced":  {strconv.FormatBool(includeSilenced)},
+		"inhibited": {strconv.FormatBool(includeSilenced)},
+	}
+	slices.Sort(filters)
+	for _, filter := range filters {
+		query.Add("filter", filter)
+	}
+
+	body, statusCode, err := t.serviceRequest(ctx, cluster, alertmanagerService, http.MethodGet, "/api/v2/alerts", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list the alerts of Alertmanager in cluster '%s': %d %s", cluster, statusCode, client.TruncateBody(body))
+	}
+
+	var alerts []gettableAlert
+	if err := json.Unmarshal(body, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to decode the alerts of Alertmanager: %w", err)
+	}
+	slices.SortStableFunc(alerts, func(a, b gettableAlert) int {
+		if c := severityRank(a.Labels["severity"]) - severityRank(b.Labels["severity"]); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.StartsAt, b.StartsAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Labels["alertname"], b.Labels["alertname"])
+	})
+
+	return alerts, nil
+}
+
+// newAlert returns the alert with its summary and the resources its labels reference.
+func newAlert(a gettableAlert) alert {
+	result := alert{
+		Name:        a.Labels["alertname"],
+		Severity:    a.Labels["severity"],
+		State:       a.Status.State,
+		StartsAt:    a.StartsAt,
+		Summary:     a.Annotations["summary"],
+		Description: a.Annotations["description"],
+		RunbookURL:  a.Annotations["runbook_url"],
+		SilencedBy:  a.Status.SilencedBy,
+		InhibitedBy: a.Status.InhibitedBy,
+		Labels:      map[string]string{},
+	}
+	if result.Description == "" {
+		result.Description = a.Annotations["message"]
+	}
+	for name, value := range a.Labels {
+		switch name {
+		case "alertname", "severity", "prometheus":
+		default:
+			result.Labels[name] = value
+		}
+	}
+
+	namespace := a.Labels["namespace"]
+	for _, l := range alertResourceLabels {
+		name := a.Labels[l.label]
+		if name == "" || (l.namespaced && namespace == "") {
+			continue
+		}
+		r := alertResource{Kind: l.kind, Name: name}
+		if l.namespaced {
+			r.Namespace = namespace
+		}
+		result.Resources = append(result.Resources, r)
+	}
+	if len(result.Resources) == 0 && namespace != "" {
+		result.Resources = append(result.Resources, alertResource{Kind: "Namespace", Name: namespace})
+	}
+
+	return result
+}
+
+// labelFilter returns an Alertmanager label matcher. The value is quoted as a Go string, which Alertmanager accepts.
+func labelFilter(name, operator, value string) string {
+	return name + operator + strconv.Quote(value)
+}
+
+// severityRank returns the rank of a severity, from 0 for the most severe. Unknown severities come last.
+func severityRank(severity string) int {
+	if i := slices.Index(severities, severity); i >= 0 {
+		return i
+	}
+
+	return len(severities)
+}
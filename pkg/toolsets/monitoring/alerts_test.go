@@ -0,0 +1,183 @@
+package monitoring
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alertmanagerRequest is a request received by the stand-in Alertmanager.
+type alertmanagerRequest struct {
+	method string
+	path   string
+	query  url.Values
+	body   string
+}
+
+// alertmanagerResponse is the response of the stand-in Alertmanager to the requests of a method and path.
+type alertmanagerResponse struct {
+	status int
+	body   string
+}
+
+// newAlertmanagerTools returns tools reaching a stand-in Alertmanager that answers the requests with the responses
+// keyed by "METHOD path", and records the requests it received.
+func newAlertmanagerTools(t *testing.T, responses map[string]alertmanagerResponse) (*Tools, *[]alertmanagerRequest) {
+	t.Helper()
+
+	var requests []alertmanagerRequest
+	tools := newStandInTools(t, alertmanagerService, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, alertmanagerRequest{method: r.Method, path: r.URL.Path, query: r.URL.Query(), body: string(body)})
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(resp.status)
+		fmt.Fprint(w, resp.body)
+	})
+
+	return tools, &requests
+}
+
+const fakeAlerts = `[
+	{
+		"labels": {"alertname": "KubeDeploymentReplicasMismatch", "severity": "warning", "namespace": "default", "deployment": "web", "prometheus": "cattle-monitoring-system/rancher-monitoring-prometheus"},
+		"annotations": {"summary": "Deployment has not matched the expected number of replicas.", "description": "Deployment default/web has not matched the expected number of replicas for longer than 15 minutes.", "runbook_url": "https://runbooks.prometheus-operator.dev/runbooks/kubernetes/kubedeploymentreplicasmismatch"},
+		"startsAt": "2023-11-14T21:00:00Z",
+		"status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+	},
+	{
+		"labels": {"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "default", "pod": "web-1", "container": "app", "service": "rancher-monitoring-kube-state-metrics"},
+		"annotations": {"description": "Pod default/web-1 (app) is in waiting state (reason: CrashLoopBackOff)."},
+		"startsAt": "2023-11-14T20:00:00Z",
+		"status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+	},
+	{
+		"labels": {"alertname": "KubeNodeNotReady", "severity": "critical", "node": "node-1"},
+		"annotations": {"message": "node-1 has been unready for more than 15 minutes."},
+		"startsAt": "2023-11-14T22:00:00Z",
+		"status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+	},
+	{
+		"labels": {"alertname": "KubeQuotaAlmostFull", "severity": "info", "namespace": "team-a"},
+		"annotations": {},
+		"startsAt": "2023-11-14T19:00:00Z",
+		"status": {"state": "suppressed", "silencedBy": ["3f1c"], "inhibitedBy": []}
+	}
+]`
+
+func TestListAlerts(t *testing.T) {
+	tests := map[string]struct {
+		params        listAlertsParams
+		status        int
+		expectedQuery url.Values
+		expected      string
+		expectedError string
+	}{
+		"all alerts": {
+			params:        listAlertsParams{Cluster: "local", IncludeSilenced: true},
+			status:        http.StatusOK,
+			expectedQuery: url.Values{"active": {"true"}, "silenced": {"true"}, "inhibited": {"true"}},
+			expected: `{
+				"llm": {
+					"total": 4,
+					"alerts": [
+						{
+							"name": "KubeNodeNotReady",
+							"severity": "critical",
+							"state": "active",
+							"startsAt": "2023-11-14T22:00:00Z",
+							"description": "node-1 has been unready for more than 15 minutes.",
+							"labels": {"node": "node-1"},
+							"resources": [{"kind": "Node", "name": "node-1"}]
+						},
+						{
+							"name": "KubePodCrashLooping",
+							"severity": "warning",
+							"state": "active",
+							"startsAt": "2023-11-14T20:00:00Z",
+							"description": "Pod default/web-1 (app) is in waiting state (reason: CrashLoopBackOff).",
+							"labels": {"namespace": "default", "pod": "web-1", "container": "app", "service": "rancher-monitoring-kube-state-metrics"},
+							"resources": [{"kind": "Pod", "namespace": "default", "name": "web-1"}]
+						},
+						{
+							"name": "KubeDeploymentReplicasMismatch",
+							"severity": "warning",
+							"state": "active",
+							"startsAt": "2023-11-14T21:00:00Z",
+							"summary": "Deployment has not matched the expected number of replicas.",
+							"description": "Deployment default/web has not matched the expected number of replicas for longer than 15 minutes.",
+							"runbookUrl": "https://runbooks.prometheus-operator.dev/runbooks/kubernetes/kubedeploymentreplicasmismatch",
+							"labels": {"namespace": "default", "deployment": "web"},
+							"resources": [{"kind": "Deployment", "namespace": "default", "name": "web"}]
+						},
+						{
+							"name": "KubeQuotaAlmostFull",
+							"severity": "info",
+							"state": "suppressed",
+							"startsAt": "2023-11-14T19:00:00Z",
+							"labels": {"namespace": "team-a"},
+							"silencedBy": ["3f1c"],
+							"resources": [{"kind": "Namespace", "name": "team-a"}]
+						}
+					]
+				},
+				"uiContext": [
+					{"namespace": "", "kind": "Node", "cluster": "local", "name": "node-1", "type": "node"},
+					{"namespace": "default", "kind": "Pod", "cluster": "local", "name": "web-1", "type": "pod"},
+					{"namespace": "default", "kind": "Deployment", "cluster": "local", "name": "web", "type": "apps.deployment"},
+					{"namespace": "", "kind": "Namespace", "cluster": "local", "name": "team-a", "type": "namespace"}
+				]
+			}`,
+		},
+		"filtered alerts": {
+			params:        listAlertsParams{Cluster: "local", Severity: "critical", Namespace: "default", Labels: map[string]string{"alertname": "KubePodCrashLooping"}},
+			status:        http.StatusOK,
+			expectedQuery: url.Values{"active": {"true"}, "silenced": {"false"}, "inhibited": {"false"}, "filter": {`alertname="KubePodCrashLooping"`, `namespace="default"`, `severity="critical"`}},
+		},
+		"invalid severity": {
+			params:        listAlertsParams{Cluster: "local", Severity: "high"},
+			expectedError: "invalid severity 'high', it must be one of critical, warning, info, none",
+		},
+		"invalid filter": {
+			params:        listAlertsParams{Cluster: "local", Labels: map[string]string{"a-b": "c"}},
+			status:        http.StatusBadRequest,
+			expectedError: "failed to list the alerts of Alertmanager in cluster 'local': 400",
+		},
+		"rancher-monitoring not installed": {
+			params:        listAlertsParams{Cluster: "local"},
+			status:        http.StatusServiceUnavailable,
+			expectedError: "cannot reach Alertmanager in cluster 'local', rancher-monitoring may not be installed or not ready: 503",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newAlertmanagerTools(t, map[string]alertmanagerResponse{
+				"GET /api/v2/alerts": {status: tt.status, body: fakeAlerts},
+			})
+
+			result, _, err := tools.listAlerts(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 1)
+			assert.Equal(t, tt.expectedQuery, (*requests)[0].query)
+			if tt.expected != "" {
+				assert.JSONEq(t, tt.expected, result.Content[0].(*mcp.TextContent).Text)
+			}
+		})
+	}
+}
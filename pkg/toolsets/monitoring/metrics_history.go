@@ -0,0 +1,105 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+)
+
+// curatedMetric is a PromQL query template of getMetricsHistory. The %s verb is replaced by the label matchers of
+// the filters supported by the metric.
+type curatedMetric struct {
+	query   string
+	filters []string
+}
+
+// curatedMetrics are the metrics of getMetricsHistory by name. The queries use the metrics of the kubelet,
+// kube-state-metrics and the API server scraped by rancher-monitoring.
+var curatedMetrics = map[string]curatedMetric{
+	"podCpu": {
+		query:   `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!=""%s}[5m]))`,
+		filters: []string{"namespace", "pod", "node"},
+	},
+	"podMemory": {
+		query:   `sum by (namespace, pod) (container_memory_working_set_bytes{container!=""%s})`,
+		filters: []string{"namespace", "pod", "node"},
+	},
+	"podRestarts": {
+		query:   `sum by (namespace, pod) (increase(kube_pod_container_status_restarts_total{job="kube-state-metrics"%s}[$step]))`,
+		filters: []string{"namespace", "pod"},
+	},
+	"nodePressure": {
+		query:   `max by (node, condition) (kube_node_status_condition{condition=~"MemoryPressure|DiskPressure|PIDPressure",status="true"%s})`,
+		filters: []string{"node"},
+	},
+	"apiServerLatency": {
+		query:   `histogram_quantile(0.99, sum by (le, verb) (rate(apiserver_request_duration_seconds_bucket{verb!~"WATCH|CONNECT"%s}[5m])))`,
+		filters: []string{},
+	},
+}
+
+// getMetricsHistoryParams specifies the parameters needed to get the history of a curated metric.
+type getMetricsHistoryParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Metric    string `json:"metric" jsonschema:"the metric: podCpu, podMemory, podRestarts, nodePressure or apiServerLatency"`
+	Namespace string `json:"namespace,omitempty" jsonschema:"only the pods of this namespace, for podCpu, podMemory and podRestarts"`
+	Pod       string `json:"pod,omitempty" jsonschema:"only this pod, for podCpu, podMemory and podRestarts"`
+	Node      string `json:"node,omitempty" jsonschema:"only the pods of this node for podCpu and podMemory, or only this node for nodePressure"`
+	Duration  string `json:"duration,omitempty" jsonschema:"the duration of the history, like 30m, 6h or 7d, defaults to 1h"`
+	End       string `json:"end,omitempty" jsonschema:"the RFC 3339 end time of the history, defaults to now"`
+}
+
+// getMetricsHistory returns the history of a curated metric over a time range.
+func (t *Tools) getMetricsHistory(ctx context.Context, toolReq *mcp.CallToolRequest, params getMetricsHistoryParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("getMetricsHistory called")
+
+	r, err := parseRange(params.Duration, params.End, "", t.now())
+	if err != nil {
+		return nil, nil, err
+	}
+	query, err := curatedQuery(params, r.step)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := t.rangeQuery(ctx, params.Cluster, query, r)
+	if err != nil {
+		zap.L().Error("failed to query Prometheus", zap.String("tool", "getMetricsHistory"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return queryResponse("getMetricsHistory", result)
+}
+
+// curatedQuery returns the PromQL query of a curated metric with the label matchers of its filters.
+func curatedQuery(params getMetricsHistoryParams, step time.Duration) (string, error) {
+	metric, ok := curatedMetrics[params.Metric]
+	if !ok {
+		return "", fmt.Errorf("unknown metric '%s', it must be one of %s", params.Metric, strings.Join(slices.Sorted(maps.Keys(curatedMetrics)), ", "))
+	}
+
+	filters := map[string]string{"namespace": params.Namespace, "pod": params.Pod, "node": params.Node}
+	for _, label := range []string{"namespace", "pod", "node"} {
+		if filters[label] != "" && !slices.Contains(metric.filters, label) {
+			return "", fmt.Errorf("the metric '%s' cannot be filtered by %s", params.Metric, label)
+		}
+	}
+
+	var matchers strings.Builder
+	for _, label := range metric.filters {
+		if value := filters[label]; value != "" {
+			// label values are quoted as Go strings, which PromQL string literals accept.
+			fmt.Fprintf(&matchers, ",%s=%s", label, strconv.Quote(value))
+		}
+	}
+
+	query := fmt.Sprintf(metric.query, matchers.String())
+	return strings.ReplaceAll(query, "$step", fmt.Sprintf("%ds", int64(step.Seconds()))), nil
+}
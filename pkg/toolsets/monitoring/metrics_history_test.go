@@ -0,0 +1,91 @@
+package monitoring
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCuratedQuery(t *testing.T) {
+	tests := map[string]struct {
+		params        getMetricsHistoryParams
+		expected      string
+		expectedError string
+	}{
+		"pod CPU of a namespace": {
+			params:   getMetricsHistoryParams{Metric: "podCpu", Namespace: "default"},
+			expected: `sum by (namespace, pod) (rate(container_cpu_usage_seconds_total{container!="",namespace="default"}[5m]))`,
+		},
+		"pod memory of a pod on a node": {
+			params:   getMetricsHistoryParams{Metric: "podMemory", Namespace: "default", Pod: "web-1", Node: "node-1"},
+			expected: `sum by (namespace, pod) (container_memory_working_set_bytes{container!="",namespace="default",pod="web-1",node="node-1"})`,
+		},
+		"pod restarts per step": {
+			params:   getMetricsHistoryParams{Metric: "podRestarts"},
+			expected: `sum by (namespace, pod) (increase(kube_pod_container_status_restarts_total{job="kube-state-metrics"}[300s]))`,
+		},
+		"node pressure with an escaped node name": {
+			params:   getMetricsHistoryParams{Metric: "nodePressure", Node: `node"1`},
+			expected: `max by (node, condition) (kube_node_status_condition{condition=~"MemoryPressure|DiskPressure|PIDPressure",status="true",node="node\"1"})`,
+		},
+		"API server latency": {
+			params:   getMetricsHistoryParams{Metric: "apiServerLatency"},
+			expected: `histogram_quantile(0.99, sum by (le, verb) (rate(apiserver_request_duration_seconds_bucket{verb!~"WATCH|CONNECT"}[5m])))`,
+		},
+		"unsupported filter": {
+			params:        getMetricsHistoryParams{Metric: "nodePressure", Namespace: "default"},
+			expectedError: "the metric 'nodePressure' cannot be filtered by namespace",
+		},
+		"unknown metric": {
+			params:        getMetricsHistoryParams{Metric: "diskUsage"},
+			expectedError: "unknown metric 'diskUsage', it must be one of apiServerLatency, nodePressure, podCpu, podMemory, podRestarts",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			query, err := curatedQuery(tt.params, 5*time.Minute)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestGetMetricsHistory(t *testing.T) {
+	tools, requests := newPrometheusTools(t, http.StatusOK, matrix(2, 100))
+
+	result, _, err := tools.getMetricsHistory(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, getMetricsHistoryParams{
+		Cluster:  "local",
+		Metric:   "podRestarts",
+		Pod:      "web-1",
+		Duration: "2h",
+		End:      "2023-11-14T22:13:20Z",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "/api/v1/query_range", (*requests)[0].path)
+	assert.Equal(t, `sum by (namespace, pod) (increase(kube_pod_container_status_restarts_total{job="kube-state-metrics",pod="web-1"}[120s]))`, (*requests)[0].query.Get("query"))
+	assert.Equal(t, "120", (*requests)[0].query.Get("step"))
+	assert.JSONEq(t, `{"llm": {
+		"query": "sum by (namespace, pod) (increase(kube_pod_container_status_restarts_total{job=\"kube-state-metrics\",pod=\"web-1\"}[120s]))",
+		"resultType": "matrix",
+		"start": "2023-11-14T20:13:20Z",
+		"end": "2023-11-14T22:13:20Z",
+		"step": "2m0s",
+		"series": [{"metric": {"pod": "pod-0"}, "values": [[1700000000, "100"], [1700000015, "101"]], "min": 100, "max": 101, "avg": 100.5, "last": 101}]
+	}}`, result.Content[0].(*mcp.TextContent).Text)
+
+	_, _, err = tools.getMetricsHistory(middleware.WithToken(t.Context(), "otherToken"), &mcp.CallToolRequest{}, getMetricsHistoryParams{Cluster: "local", Metric: "podCpu"})
+	assert.ErrorContains(t, err, "not allowed to reach Prometheus in cluster 'local'")
+}
@@ -0,0 +1,366 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+)
+
+const (
+	// maxSeries is the maximum number of series returned by a query. The series with the highest values are kept.
+	maxSeries = 20
+	// maxPoints is the maximum number of points returned for every series of a range query.
+	maxPoints = 60
+	// minStep is the smallest step used when the step of a range query is computed from its duration.
+	minStep = 15 * time.Second
+	// defaultDuration is the duration of a range query when none is given.
+	defaultDuration = time.Hour
+)
+
+// prometheusResponse is the envelope of the responses of the Prometheus HTTP API.
+type prometheusResponse struct {
+	Status    string         `json:"status"`
+	Data      prometheusData `json:"data"`
+	ErrorType string         `json:"errorType"`
+	Error     string         `json:"error"`
+	Warnings  []string       `json:"warnings"`
+}
+
+// prometheusData is the result of a query. Its shape depends on the result type, see prometheusSeries.
+type prometheusData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// prometheusSeries is a series of a vector or a matrix. Vectors have a value per series, matrices have values.
+type prometheusSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []any             `json:"value"`
+	Values [][]any           `json:"values"`
+}
+
+// series is a series of a query result, with the summary of its values.
+type series struct {
+	Metric map[string]string `json:"metric"`
+	// Values are the [unix timestamp, value] pairs of the series, downsampled to maxPoints.
+	Values [][]any `json:"values,omitempty"`
+	// Value is the [unix timestamp, value] pair of instant queries.
+	Value []any   `json:"value,omitempty"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+	Last  float64 `json:"last"`
+
+	points []point
+}
+
+// point is a sample of a series.
+type point struct {
+	timestamp float64
+	value     float64
+}
+
+// queryResult is the result of a Prometheus query returned by the monitoring tools.
+type queryResult struct {
+	Query      string   `json:"query"`
+	ResultType string   `json:"resultType"`
+	Start      string   `json:"start,omitempty"`
+	End        string   `json:"end,omitempty"`
+	Time       string   `json:"time,omitempty"`
+	Step       string   `json:"step,omitempty"`
+	Series     []series `json:"series"`
+	Notes      []string `json:"notes,omitempty"`
+}
+
+// rangeParams are the resolved time range of a range query.
+type rangeParams struct {
+	start time.Time
+	end   time.Time
+	step  time.Duration
+}
+
+// prometheusQuery runs a query against the Prometheus API of rancher-monitoring in a cluster. path is the API path,
+// like /api/v1/query.
+func (t *Tools) prometheusQuery(ctx context.Context, cluster, path string, values url.Values) (*prometheusResponse, error) {
+	body, statusCode, err := t.serviceRequest(ctx, cluster, prometheusService, http.MethodGet, path, values, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var promResp prometheusResponse
+	if err := json.Unmarshal(body, &promResp); err != nil || promResp.Status == "" {
+		return nil, fmt.Errorf("unexpected response from Prometheus in cluster '%s': %d %s", cluster, statusCode, client.TruncateBody(body))
+	}
+	if promResp.Status != "success" {
+		return nil, fmt.Errorf("the Prometheus query failed: %s: %s", promResp.ErrorType, promResp.Error)
+	}
+
+	return &promResp, nil
+}
+
+// instantQuery evaluates a PromQL query at a single time. A zero time evaluates it at the current time.
+func (t *Tools) instantQuery(ctx context.Context, cluster, query string, at time.Time) (queryResult, error) {
+	values := url.Values{"query": {query}}
+	result := queryResult{Query: query}
+	if !at.IsZero() {
+		values.Set("time", strconv.FormatInt(at.Unix(), 10))
+		result.Time = at.UTC().Format(time.RFC3339)
+	}
+
+	resp, err := t.prometheusQuery(ctx, cluster, "/api/v1/query", values)
+	if err != nil {
+		return queryResult{}, err
+	}
+
+	return withSeries(result, resp)
+}
+
+// rangeQuery evaluates a PromQL query over a time range.
+func (t *Tools) rangeQuery(ctx context.Context, cluster, query string, r rangeParams) (queryResult, error) {
+	values := url.Values{
+		"query": {query},
+		"start": {strconv.FormatInt(r.start.Unix(), 10)},
+		"end":   {strconv.FormatInt(r.end.Unix(), 10)},
+		"step":  {strconv.FormatFloat(r.step.Seconds(), 'f', -1, 64)},
+	}
+
+	resp, err := t.prometheusQuery(ctx, cluster, "/api/v1/query_range", values)
+	if err != nil {
+		return queryResult{}, err
+	}
+
+	return withSeries(queryResult{
+		Query: query,
+		Start: r.start.UTC().Format(time.RFC3339),
+		End:   r.end.UTC().Format(time.RFC3339),
+		Step:  r.step.String(),
+	}, resp)
+}
+
+// withSeries adds the series of a Prometheus response to the result, keeping the maxSeries series with the highest
+// values and downsampling them to maxPoints.
+func withSeries(result queryResult, resp *prometheusResponse) (queryResult, error) {
+	result.ResultType = resp.Data.ResultType
+	result.Notes = append(result.Notes, resp.Warnings...)
+
+	var results []prometheusSeries
+	switch resp.Data.ResultType {
+	case "vector", "matrix":
+		if err := json.Unmarshal(resp.Data.Result, &results); err != nil {
+			return queryResult{}, fmt.Errorf("failed to decode the Prometheus %s: %w", resp.Data.ResultType, err)
+		}
+	case "scalar":
+		var value []any
+		if err := json.Unmarshal(resp.Data.Result, &value); err != nil {
+			return queryResult{}, fmt.Errorf("failed to decode the Prometheus scalar: %w", err)
+		}
+		results = []prometheusSeries{{Metric: map[string]string{}, Value: value}}
+	default:
+		return queryResult{}, fmt.Errorf("unsupported Prometheus result type '%s', the query must return a vector, a matrix or a scalar", resp.Data.ResultType)
+	}
+
+	result.Series = make([]series, 0, len(results))
+	for _, r := range results {
+		samples := r.Values
+		if r.Value != nil {
+			samples = [][]any{r.Value}
+		}
+		s := series{Metric: r.Metric}
+		for _, sample := range samples {
+			p, err := parsePoint(sample)
+			if err != nil {
+				return queryResult{}, err
+			}
+			s.points = append(s.points, p)
+		}
+		s.summarize()
+		result.Series = append(result.Series, s)
+	}
+
+	if len(result.Series) > maxSeries {
+		slices.SortStableFunc(result.Series, func(a, b series) int {
+			return compareDesc(a.Max, b.Max)
+		})
+		result.Notes = append(result.Notes, fmt.Sprintf("The query returned %d series, only the %d with the highest values are included. Aggregate or filter the query to see the others.", len(result.Series), maxSeries))
+		result.Series = result.Series[:maxSeries]
+	}
+
+	downsampled := false
+	for i := range result.Series {
+		s := &result.Series[i]
+		if resp.Data.ResultType != "matrix" {
+			if len(s.points) > 0 {
+				s.Value = s.points[0].pair()
+			}
+			continue
+		}
+		points := s.points
+		if len(points) > maxPoints {
+			points = downsample(points, maxPoints)
+			downsampled = true
+		}
+		for _, p := range points {
+			s.Values = append(s.Values, p.pair())
+		}
+	}
+	if downsampled {
+		result.Notes = append(result.Notes, fmt.Sprintf("Series with more than %d points are downsampled to %d points, each the highest value of its interval. min, max, avg and last are computed from all the points.", maxPoints, maxPoints))
+	}
+
+	return result, nil
+}
+
+// summarize computes the min, max, average and last value of the series, ignoring NaN values.
+func (s *series) summarize() {
+	var sum float64
+	var count int
+	for _, p := range s.points {
+		if math.IsNaN(p.value) {
+			continue
+		}
+		if count == 0 || p.value < s.Min {
+			s.Min = p.value
+		}
+		if count == 0 || p.value > s.Max {
+			s.Max = p.value
+		}
+		sum += p.value
+		count++
+		s.Last = p.value
+	}
+	if count > 0 {
+		s.Avg = sum / float64(count)
+	}
+	// JSON has no infinity, the summary is clamped to the largest values.
+	s.Min, s.Max, s.Avg, s.Last = finite(s.Min), finite(s.Max), finite(s.Avg), finite(s.Last)
+}
+
+// downsample splits the points into n intervals and keeps the point with the highest value of each, so that peaks
+// are not averaged away.
+func downsample(points []point, n int) []point {
+	downsampled := make([]point, 0, n)
+	for i := range n {
+		interval := points[i*len(points)/n : (i+1)*len(points)/n]
+		highest := interval[0]
+		for _, p := range interval[1:] {
+			if p.value > highest.value || math.IsNaN(highest.value) {
+				highest = p
+			}
+		}
+		downsampled = append(downsampled, highest)
+	}
+
+	return downsampled
+}
+
+// parsePoint parses a [unix timestamp, "value"] pair of the Prometheus API.
+func parsePoint(sample []any) (point, error) {
+	if len(sample) != 2 {
+		return point{}, fmt.Errorf("invalid Prometheus sample %v", sample)
+	}
+	timestamp, ok := sample[0].(float64)
+	if !ok {
+		return point{}, fmt.Errorf("invalid Prometheus sample timestamp %v", sample[0])
+	}
+	rawValue, ok := sample[1].(string)
+	if !ok {
+		return point{}, fmt.Errorf("invalid Prometheus sample value %v", sample[1])
+	}
+	value, err := strconv.ParseFloat(rawValue, 64)
+	if err != nil {
+		return point{}, fmt.Errorf("invalid Prometheus sample value %s: %w", rawValue, err)
+	}
+
+	return point{timestamp: timestamp, value: value}, nil
+}
+
+// pair returns the point as a [unix timestamp, "value"] pair, like the Prometheus API.
+func (p point) pair() []any {
+	return []any{p.timestamp, strconv.FormatFloat(p.value, 'g', 6, 64)}
+}
+
+// parseRange resolves the time range of a range query. The range ends at end, or now when it is empty, and lasts
+// duration, or defaultDuration when it is empty. The step is computed from the duration to return maxPoints points
+// when it is empty.
+func parseRange(duration, end, step string, now time.Time) (rangeParams, error) {
+	r := rangeParams{end: now}
+
+	d := defaultDuration
+	if duration != "" {
+		var err error
+		if d, err = parseDuration(duration); err != nil {
+			return rangeParams{}, fmt.Errorf("invalid duration '%s': %w", duration, err)
+		}
+	}
+	if end != "" {
+		var err error
+		if r.end, err = time.Parse(time.RFC3339, end); err != nil {
+			return rangeParams{}, fmt.Errorf("invalid end '%s', it must be an RFC 3339 time: %w", end, err)
+		}
+	}
+	r.start = r.end.Add(-d)
+
+	if step != "" {
+		var err error
+		if r.step, err = parseDuration(step); err != nil {
+			return rangeParams{}, fmt.Errorf("invalid step '%s': %w", step, err)
+		}
+	} else {
+		r.step = max((d / maxPoints).Round(time.Second), minStep)
+	}
+
+	return r, nil
+}
+
+// parseDuration parses a positive duration like time.ParseDuration, also accepting days like Prometheus, e.g. 7d.
+func parseDuration(s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, err
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("it must be positive")
+	}
+
+	return d, nil
+}
+
+func compareDesc(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	}
+
+	return 0
+}
+
+func finite(v float64) float64 {
+	switch {
+	case math.IsInf(v, 1):
+		return math.MaxFloat64
+	case math.IsInf(v, -1):
+		return -math.MaxFloat64
+	}
+
+	return v
+}
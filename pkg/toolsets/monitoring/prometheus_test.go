@@ -0,0 +1,54 @@
+package monitoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsample(t *testing.T) {
+	tests := map[string]struct {
+		values   []float64
+		n        int
+		expected []point
+	}{
+		"keeps the peak of every interval": {
+			values:   []float64{1, 9, 2, 3, 4, 8},
+			n:        2,
+			expected: []point{{timestamp: 1, value: 9}, {timestamp: 5, value: 8}},
+		},
+		"uneven intervals": {
+			values:   []float64{5, 1, 2, 7, 3},
+			n:        2,
+			expected: []point{{timestamp: 0, value: 5}, {timestamp: 3, value: 7}},
+		},
+		"NaN is replaced by a value": {
+			values:   []float64{math.NaN(), 4, 1, 2},
+			n:        2,
+			expected: []point{{timestamp: 1, value: 4}, {timestamp: 3, value: 2}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var points []point
+			for i, v := range tt.values {
+				points = append(points, point{timestamp: float64(i), value: v})
+			}
+
+			assert.Equal(t, tt.expected, downsample(points, tt.n))
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := series{points: []point{{value: 2}, {value: math.NaN()}, {value: 6}, {value: math.Inf(1)}, {value: 4}}}
+
+	s.summarize()
+
+	assert.Equal(t, 2.0, s.Min)
+	assert.Equal(t, math.MaxFloat64, s.Max)
+	assert.Equal(t, math.MaxFloat64, s.Avg)
+	assert.Equal(t, 4.0, s.Last)
+}
@@ -0,0 +1,76 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// monitoringService is a service of rancher-monitoring reached through the Kubernetes service proxy of a cluster.
+type monitoringService struct {
+	// name is the name of the service in errors.
+	name string
+	// proxyPath is the path of the service proxy, relative to the cluster URL of the Rancher proxy.
+	proxyPath string
+}
+
+var (
+	prometheusService = monitoringService{
+		name:      "Prometheus",
+		proxyPath: "/api/v1/namespaces/cattle-monitoring-system/services/http:rancher-monitoring-prometheus:9090/proxy",
+	}
+	alertmanagerService = monitoringService{
+		name:      "Alertmanager",
+		proxyPath: "/api/v1/namespaces/cattle-monitoring-system/services/http:rancher-monitoring-alertmanager:9093/proxy",
+	}
+)
+
+// serviceRequest sends a request to a rancher-monitoring service of a cluster, through the Rancher proxy and with the
+// token of the caller. path is the API path of the service, like /api/v1/query. It returns the body and status code of
+// the response of the service, and an error when the proxy could not reach it.
+func (t *Tools) serviceRequest(ctx context.Context, cluster string, svc monitoringService, method, path string, query url.Values, body any) ([]byte, int, error) {
+	respBody, statusCode, err := client.DoProxyRequest(ctx, t.client, middleware.Token(ctx), client.ProxyRequest{
+		Cluster: cluster,
+		Method:  method,
+		Path:    svc.proxyPath + path,
+		Query:   query,
+		Body:    body,
+	})
+	if err != nil {
+		return nil, statusCode, fmt.Errorf("failed to reach %s: %w", svc.name, err)
+	}
+	if err := proxyError(svc, cluster, statusCode, respBody); err != nil {
+		return nil, statusCode, err
+	}
+
+	return respBody, statusCode, nil
+}
+
+// proxyError returns an error when the response was sent by Rancher or the Kubernetes proxy instead of the service:
+// a Kubernetes Status, or a status code the services never return since they have no authentication. It returns nil
+// for the responses of the service.
+func proxyError(svc monitoringService, cluster string, statusCode int, body []byte) error {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusBadGateway, http.StatusServiceUnavailable:
+	default:
+		var status metav1.Status
+		if statusCode < http.StatusMultipleChoices || json.Unmarshal(body, &status) != nil || status.Kind != "Status" {
+			return nil
+		}
+	}
+
+	switch statusCode {
+	case http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return fmt.Errorf("cannot reach %s in cluster '%s', rancher-monitoring may not be installed or not ready: %d %s", svc.name, cluster, statusCode, client.TruncateBody(body))
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("not allowed to reach %s in cluster '%s', the user needs access to the service proxy of rancher-monitoring: %d %s", svc.name, cluster, statusCode, client.TruncateBody(body))
+	}
+
+	return fmt.Errorf("unexpected response from the proxy of %s in cluster '%s': %d %s", svc.name, cluster, statusCode, client.TruncateBody(body))
+}
@@ -0,0 +1,85 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+)
+
+// queryPrometheusParams specifies the parameters needed to evaluate an instant query.
+type queryPrometheusParams struct {
+	Cluster string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Query   string `json:"query" jsonschema:"the PromQL query"`
+	Time    string `json:"time,omitempty" jsonschema:"the RFC 3339 time at which the query is evaluated, defaults to now"`
+}
+
+// queryPrometheusRangeParams specifies the parameters needed to evaluate a range query.
+type queryPrometheusRangeParams struct {
+	Cluster  string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Query    string `json:"query" jsonschema:"the PromQL query"`
+	Duration string `json:"duration,omitempty" jsonschema:"the duration of the range, like 30m, 6h or 7d, defaults to 1h"`
+	End      string `json:"end,omitempty" jsonschema:"the RFC 3339 end time of the range, defaults to now"`
+	Step     string `json:"step,omitempty" jsonschema:"the resolution of the range, like 30s or 5m, defaults to the duration divided by 60"`
+}
+
+// queryPrometheus evaluates a PromQL query at a single time.
+func (t *Tools) queryPrometheus(ctx context.Context, toolReq *mcp.CallToolRequest, params queryPrometheusParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("queryPrometheus called")
+
+	if params.Query == "" {
+		return nil, nil, fmt.Errorf("the query is required")
+	}
+	var at time.Time
+	if params.Time != "" {
+		var err error
+		if at, err = time.Parse(time.RFC3339, params.Time); err != nil {
+			return nil, nil, fmt.Errorf("invalid time '%s', it must be an RFC 3339 time: %w", params.Time, err)
+		}
+	}
+
+	result, err := t.instantQuery(ctx, params.Cluster, params.Query, at)
+	if err != nil {
+		zap.L().Error("failed to query Prometheus", zap.String("tool", "queryPrometheus"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return queryResponse("queryPrometheus", result)
+}
+
+// queryPrometheusRange evaluates a PromQL query over a time range.
+func (t *Tools) queryPrometheusRange(ctx context.Context, toolReq *mcp.CallToolRequest, params queryPrometheusRangeParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("queryPrometheusRange called")
+
+	if params.Query == "" {
+		return nil, nil, fmt.Errorf("the query is required")
+	}
+	r, err := parseRange(params.Duration, params.End, params.Step, t.now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := t.rangeQuery(ctx, params.Cluster, params.Query, r)
+	if err != nil {
+		zap.L().Error("failed to query Prometheus", zap.String("tool", "queryPrometheusRange"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return queryResponse("queryPrometheusRange", result)
+}
+
+// queryResponse returns the result of a query as the response of a tool.
+func queryResponse(tool string, result queryResult) (*mcp.CallToolResult, any, error) {
+	mcpResponse, err := response.CreateMcpResponseAny(result)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
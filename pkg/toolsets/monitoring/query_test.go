@@ -0,0 +1,211 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// prometheusRequest is a request received by the stand-in Prometheus.
+type prometheusRequest struct {
+	path  string
+	query url.Values
+}
+
+// newPrometheusTools returns tools querying a stand-in Prometheus that answers every request with the status and
+// body, and records the requests it received.
+func newPrometheusTools(t *testing.T, status int, body string) (*Tools, *[]prometheusRequest) {
+	t.Helper()
+
+	var requests []prometheusRequest
+	tools := newStandInTools(t, prometheusService, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, prometheusRequest{path: r.URL.Path, query: r.URL.Query()})
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	})
+
+	return tools, &requests
+}
+
+// matrix returns a Prometheus range query response with a series per value, each with the given number of points
+// counting up from value.
+func matrix(points int, values ...int) string {
+	var result []map[string]any
+	for i, value := range values {
+		var samples [][]any
+		for p := range points {
+			samples = append(samples, []any{1700000000 + p*15, fmt.Sprint(value + p)})
+		}
+		result = append(result, map[string]any{"metric": map[string]string{"pod": fmt.Sprintf("pod-%d", i)}, "values": samples})
+	}
+	body, _ := json.Marshal(map[string]any{"status": "success", "data": map[string]any{"resultType": "matrix", "result": result}})
+
+	return string(body)
+}
+
+func TestQueryPrometheus(t *testing.T) {
+	tests := map[string]struct {
+		params        queryPrometheusParams
+		status        int
+		body          string
+		expectedQuery url.Values
+		expected      string
+		expectedError string
+	}{
+		"vector": {
+			params: queryPrometheusParams{Cluster: "local", Query: "up", Time: "2023-11-14T22:13:20Z"},
+			status: http.StatusOK,
+			body: `{"status": "success", "data": {"resultType": "vector", "result": [
+				{"metric": {"job": "apiserver"}, "value": [1700000000, "1"]},
+				{"metric": {"job": "node-exporter"}, "value": [1700000000, "0"]}
+			]}}`,
+			expectedQuery: url.Values{"query": {"up"}, "time": {"1700000000"}},
+			expected: `{"llm": {
+				"query": "up",
+				"resultType": "vector",
+				"time": "2023-11-14T22:13:20Z",
+				"series": [
+					{"metric": {"job": "apiserver"}, "value": [1700000000, "1"], "min": 1, "max": 1, "avg": 1, "last": 1},
+					{"metric": {"job": "node-exporter"}, "value": [1700000000, "0"], "min": 0, "max": 0, "avg": 0, "last": 0}
+				]
+			}}`,
+		},
+		"scalar with warnings": {
+			params:        queryPrometheusParams{Cluster: "local", Query: "scalar(up)"},
+			status:        http.StatusOK,
+			body:          `{"status": "success", "data": {"resultType": "scalar", "result": [1700000000, "0.5"]}, "warnings": ["scalar of many series"]}`,
+			expectedQuery: url.Values{"query": {"scalar(up)"}},
+			expected: `{"llm": {
+				"query": "scalar(up)",
+				"resultType": "scalar",
+				"series": [{"metric": {}, "value": [1700000000, "0.5"], "min": 0.5, "max": 0.5, "avg": 0.5, "last": 0.5}],
+				"notes": ["scalar of many series"]
+			}}`,
+		},
+		"invalid query": {
+			params:        queryPrometheusParams{Cluster: "local", Query: "up{"},
+			status:        http.StatusBadRequest,
+			body:          `{"status": "error", "errorType": "bad_data", "error": "unexpected end of input"}`,
+			expectedError: "the Prometheus query failed: bad_data: unexpected end of input",
+		},
+		"rancher-monitoring not installed": {
+			params:        queryPrometheusParams{Cluster: "local", Query: "up"},
+			status:        http.StatusServiceUnavailable,
+			body:          `no endpoints available for service "rancher-monitoring-prometheus"`,
+			expectedError: "cannot reach Prometheus in cluster 'local', rancher-monitoring may not be installed or not ready: 503",
+		},
+		"forbidden": {
+			params:        queryPrometheusParams{Cluster: "local", Query: "up"},
+			status:        http.StatusForbidden,
+			body:          `{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Forbidden", "code": 403}`,
+			expectedError: "not allowed to reach Prometheus in cluster 'local'",
+		},
+		"invalid time": {
+			params:        queryPrometheusParams{Cluster: "local", Query: "up", Time: "yesterday"},
+			expectedError: "invalid time 'yesterday', it must be an RFC 3339 time",
+		},
+		"missing query": {
+			params:        queryPrometheusParams{Cluster: "local"},
+			expectedError: "the query is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newPrometheusTools(t, tt.status, tt.body)
+
+			result, _, err := tools.queryPrometheus(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, result.Content[0].(*mcp.TextContent).Text)
+			require.Len(t, *requests, 1)
+			assert.Equal(t, "/api/v1/query", (*requests)[0].path)
+			assert.Equal(t, tt.expectedQuery, (*requests)[0].query)
+		})
+	}
+}
+
+func TestQueryPrometheusRange(t *testing.T) {
+	tests := map[string]struct {
+		params         queryPrometheusRangeParams
+		body           string
+		expectedQuery  url.Values
+		expectedSeries []string
+		expectedPoints int
+		expectedNotes  int
+		expectedError  string
+	}{
+		"default step": {
+			params:         queryPrometheusRangeParams{Cluster: "local", Query: "up", Duration: "6h", End: "2023-11-14T22:13:20Z"},
+			body:           matrix(3, 1, 5),
+			expectedQuery:  url.Values{"query": {"up"}, "start": {"1699978400"}, "end": {"1700000000"}, "step": {"360"}},
+			expectedSeries: []string{"pod-0", "pod-1"},
+			expectedPoints: 3,
+		},
+		"downsampled": {
+			params:         queryPrometheusRangeParams{Cluster: "local", Query: "up", Duration: "1h", End: "2023-11-14T22:13:20Z", Step: "15s"},
+			body:           matrix(241, 0),
+			expectedQuery:  url.Values{"query": {"up"}, "start": {"1699996400"}, "end": {"1700000000"}, "step": {"15"}},
+			expectedSeries: []string{"pod-0"},
+			expectedPoints: maxPoints,
+			expectedNotes:  1,
+		},
+		"too many series": {
+			params:         queryPrometheusRangeParams{Cluster: "local", Query: "up", Duration: "1d", End: "2023-11-14T22:13:20Z"},
+			body:           matrix(2, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22),
+			expectedQuery:  url.Values{"query": {"up"}, "start": {"1699913600"}, "end": {"1700000000"}, "step": {"1440"}},
+			expectedSeries: []string{"pod-21", "pod-20", "pod-19", "pod-18", "pod-17", "pod-16", "pod-15", "pod-14", "pod-13", "pod-12", "pod-11", "pod-10", "pod-9", "pod-8", "pod-7", "pod-6", "pod-5", "pod-4", "pod-3", "pod-2"},
+			expectedPoints: 2,
+			expectedNotes:  1,
+		},
+		"invalid duration": {
+			params:        queryPrometheusRangeParams{Cluster: "local", Query: "up", Duration: "-1h"},
+			expectedError: "invalid duration '-1h': it must be positive",
+		},
+		"invalid end": {
+			params:        queryPrometheusRangeParams{Cluster: "local", Query: "up", End: "now"},
+			expectedError: "invalid end 'now', it must be an RFC 3339 time",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newPrometheusTools(t, http.StatusOK, tt.body)
+
+			result, _, err := tools.queryPrometheusRange(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 1)
+			assert.Equal(t, "/api/v1/query_range", (*requests)[0].path)
+			assert.Equal(t, tt.expectedQuery, (*requests)[0].query)
+
+			var resp struct {
+				LLM queryResult `json:"llm"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+			assert.Equal(t, "matrix", resp.LLM.ResultType)
+			assert.Len(t, resp.LLM.Notes, tt.expectedNotes)
+			var pods []string
+			for _, s := range resp.LLM.Series {
+				pods = append(pods, s.Metric["pod"])
+				assert.Len(t, s.Values, tt.expectedPoints)
+			}
+			assert.Equal(t, tt.expectedSeries, pods)
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// silenceKind and silenceNamespace identify silences in plans, where the namespace is the one of Alertmanager.
+	silenceKind      = "Silence"
+	silenceNamespace = "cattle-monitoring-system"
+	// defaultCreatedBy is the author of the silences created without one.
+	defaultCreatedBy = "Rancher AI agent"
+)
+
+// silenceMatcher is a label matcher of a silence in the Alertmanager API v2.
+type silenceMatcher struct {
+	Name    string `json:"name" jsonschema:"the label name, like alertname or namespace"`
+	Value   string `json:"value" jsonschema:"the label value, or a regular expression when isRegex is true"`
+	IsRegex bool   `json:"isRegex" jsonschema:"whether the value is a regular expression"`
+	IsEqual *bool  `json:"isEqual,omitempty" jsonschema:"whether the label must match the value (true) or not match it (false), defaults to true"`
+}
+
+// silence is a silence of the Alertmanager API v2. ID and Status are only set for existing silences.
+type silence struct {
+	ID        string           `json:"id,omitempty"`
+	Status    *silenceStatus   `json:"status,omitempty"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// silenceStatus is the state of a silence: pending, active or expired.
+type silenceStatus struct {
+	State string `json:"state"`
+}
+
+// listSilencesParams specifies the parameters needed to list the silences of a cluster.
+type listSilencesParams struct {
+	Cluster        string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	IncludeExpired bool   `json:"includeExpired,omitempty" jsonschema:"whether expired silences are included"`
+}
+
+// createSilenceParams specifies the parameters needed to create a silence.
+type createSilenceParams struct {
+	Cluster   string           `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Matchers  []silenceMatcher `json:"matchers" jsonschema:"the label matchers of the alerts to silence, all of them must match"`
+	Duration  string           `json:"duration" jsonschema:"how long the silence lasts from now, like 2h or 1d"`
+	Comment   string           `json:"comment" jsonschema:"why the alerts are silenced"`
+	CreatedBy string           `json:"createdBy,omitempty" jsonschema:"the author of the silence, defaults to Rancher AI agent"`
+}
+
+// expireSilenceParams specifies the parameters needed to expire a silence.
+type expireSilenceParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	SilenceID string `json:"silenceId" jsonschema:"the ID of the silence, as returned by listSilences or in the silencedBy field of the alerts"`
+}
+
+// listSilences returns the silences of the rancher-monitoring Alertmanager of a cluster.
+func (t *Tools) listSilences(ctx context.Context, toolReq *mcp.CallToolRequest, params listSilencesParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("listSilences called")
+
+	body, statusCode, err := t.serviceRequest(ctx, params.Cluster, alertmanagerService, http.MethodGet, "/api/v2/silences", nil, nil)
+	if err != nil {
+		zap.L().Error("failed to list silences", zap.String("tool", "listSilences"), zap.Error(err))
+		return nil, nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to list the silences of Alertmanager in cluster '%s': %d %s", params.Cluster, statusCode, client.TruncateBody(body))
+	}
+
+	var silences []silence
+	if err := json.Unmarshal(body, &silences); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode the silences of Alertmanager: %w", err)
+	}
+	silences = slices.DeleteFunc(silences, func(s silence) bool {
+		return !params.IncludeExpired && s.Status != nil && s.Status.State == "expired"
+	})
+	slices.SortStableFunc(silences, func(a, b silence) int {
+		return a.EndsAt.Compare(b.EndsAt)
+	})
+	if silences == nil {
+		silences = []silence{}
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(silences)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "listSilences"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// createSilence creates a silence muting the alerts matching its matchers, and returns it with its ID and the active
+// alerts it mutes.
+func (t *Tools) createSilence(ctx context.Context, toolReq *mcp.CallToolRequest, params createSilenceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("createSilence called", zap.String("cluster", params.Cluster))
+
+	s, err := t.newSilence(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	silenced, err := t.silencedAlerts(ctx, params.Cluster, s)
+	if err != nil {
+		zap.L().Error("failed to list the silenced alerts", zap.String("tool", "createSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	body, statusCode, err := t.serviceRequest(ctx, params.Cluster, alertmanagerService, http.MethodPost, "/api/v2/silences", nil, s)
+	if err != nil {
+		zap.L().Error("failed to create silence", zap.String("tool", "createSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to create the silence in cluster '%s': %d %s", params.Cluster, statusCode, client.TruncateBody(body))
+	}
+	var created struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode the created silence: %w", err)
+	}
+	s.ID = created.SilenceID
+
+	mcpResponse, err := response.CreateMcpResponseAny(map[string]any{
+		"silence":        s,
+		"silencedAlerts": silenced,
+	})
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "createSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// createSilencePlan returns the silence createSilence would create without creating it. The active alerts it would
+// mute are returned as a second content.
+func (t *Tools) createSilencePlan(ctx context.Context, toolReq *mcp.CallToolRequest, params createSilenceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("createSilencePlan called", zap.String("cluster", params.Cluster))
+
+	s, err := t.newSilence(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	silenced, err := t.silencedAlerts(ctx, params.Cluster, s)
+	if err != nil {
+		zap.L().Error("failed to list the silenced alerts", zap.String("tool", "createSilencePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	note := "The silence does not match any active alert."
+	if len(silenced) > 0 {
+		note = fmt.Sprintf("The silence mutes %d active alerts: %s", len(silenced), strings.Join(silenced, ", "))
+	}
+
+	return silencePlan(response.PlanResource{
+		Type:     response.OperationCreate,
+		Payload:  s,
+		Resource: silenceResource(params.Cluster, matchersString(s.Matchers)),
+	}, note, "createSilencePlan")
+}
+
+// expireSilence expires a pending or active silence, and returns it.
+func (t *Tools) expireSilence(ctx context.Context, toolReq *mcp.CallToolRequest, params expireSilenceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("expireSilence called", zap.String("cluster", params.Cluster), zap.String("silenceId", params.SilenceID))
+
+	s, err := t.expirableSilence(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to get silence", zap.String("tool", "expireSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	body, statusCode, err := t.serviceRequest(ctx, params.Cluster, alertmanagerService, http.MethodDelete, "/api/v2/silence/"+url.PathEscape(params.SilenceID), nil, nil)
+	if err != nil {
+		zap.L().Error("failed to expire silence", zap.String("tool", "expireSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to expire the silence '%s' in cluster '%s': %d %s", params.SilenceID, params.Cluster, statusCode, client.TruncateBody(body))
+	}
+	s.Status = &silenceStatus{State: "expired"}
+
+	mcpResponse, err := response.CreateMcpResponseAny(s)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "expireSilence"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// expireSilencePlan returns the silence expireSilence would expire without expiring it.
+func (t *Tools) expireSilencePlan(ctx context.Context, toolReq *mcp.CallToolRequest, params expireSilenceParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("expireSilencePlan called", zap.String("cluster", params.Cluster), zap.String("silenceId", params.SilenceID))
+
+	s, err := t.expirableSilence(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to get silence", zap.String("tool", "expireSilencePlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return silencePlan(response.PlanResource{
+		Type:     response.OperationDelete,
+		Payload:  s,
+		Resource: silenceResource(params.Cluster, s.ID),
+	}, fmt.Sprintf("The alerts matching %s will be notified again.", matchersString(s.Matchers)), "expireSilencePlan")
+}
+
+// newSilence validates the parameters and returns the silence to create, starting now.
+func (t *Tools) newSilence(params createSilenceParams) (silence, error) {
+	if len(params.Matchers) == 0 {
+		return silence{}, fmt.Errorf("at least one matcher is required")
+	}
+	matchers := make([]silenceMatcher, 0, len(params.Matchers))
+	for _, m := range params.Matchers {
+		if m.Name == "" {
+			return silence{}, fmt.Errorf("the name of every matcher is required")
+		}
+		if m.IsEqual == nil {
+			m.IsEqual = ptr.To(true)
+		}
+		matchers = append(matchers, m)
+	}
+	if !slices.ContainsFunc(matchers, func(m silenceMatcher) bool { return *m.IsEqual && m.Value != "" }) {
+		return silence{}, fmt.Errorf("at least one matcher must match a non-empty value, or the silence would mute every alert")
+	}
+	if params.Duration == "" {
+		return silence{}, fmt.Errorf("the duration is required")
+	}
+	duration, err := parseDuration(params.Duration)
+	if err != nil {
+		return silence{}, fmt.Errorf("invalid duration '%s': %w", params.Duration, err)
+	}
+	if params.Comment == "" {
+		return silence{}, fmt.Errorf("the comment is required, it must explain why the alerts are silenced")
+	}
+
+	createdBy := params.CreatedBy
+	if createdBy == "" {
+		createdBy = defaultCreatedBy
+	}
+	now := t.now().UTC()
+
+	return silence{
+		Matchers:  matchers,
+		StartsAt:  now,
+		EndsAt:    now.Add(duration),
+		CreatedBy: createdBy,
+		Comment:   params.Comment,
+	}, nil
+}
+
+// silencedAlerts returns the names of the active alerts that are not silenced yet and match the silence.
+func (t *Tools) silencedAlerts(ctx context.Context, cluster string, s silence) ([]string, error) {
+	var filters []string
+	for _, m := range s.Matchers {
+		filters = append(filters, labelFilter(m.Name, matcherOperator(m), m.Value))
+	}
+
+	alerts, err := t.activeAlerts(ctx, cluster, filters, false)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, a := range alerts {
+		name := a.Labels["alertname"]
+		for _, l := range alertResourceLabels {
+			if value := a.Labels[l.label]; value != "" {
+				name += " " + l.label + "=" + value
+				break
+			}
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// expirableSilence returns the silence to expire, and an error when it does not exist or is already expired.
+func (t *Tools) expirableSilence(ctx context.Context, params expireSilenceParams) (silence, error) {
+	if params.SilenceID == "" {
+		return silence{}, fmt.Errorf("the silence ID is required")
+	}
+
+	body, statusCode, err := t.serviceRequest(ctx, params.Cluster, alertmanagerService, http.MethodGet, "/api/v2/silence/"+url.PathEscape(params.SilenceID), nil, nil)
+	if err != nil {
+		return silence{}, err
+	}
+	switch statusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return silence{}, fmt.Errorf("silence '%s' not found in cluster '%s'", params.SilenceID, params.Cluster)
+	default:
+		return silence{}, fmt.Errorf("failed to get the silence '%s' in cluster '%s': %d %s", params.SilenceID, params.Cluster, statusCode, client.TruncateBody(body))
+	}
+
+	var s silence
+	if err := json.Unmarshal(body, &s); err != nil {
+		return silence{}, fmt.Errorf("failed to decode the silence: %w", err)
+	}
+	if s.Status != nil && s.Status.State == "expired" {
+		return silence{}, fmt.Errorf("silence '%s' is already expired", params.SilenceID)
+	}
+
+	return s, nil
+}
+
+// silencePlan returns the plan of a silence operation, with the note as a second content.
+func silencePlan(planResource response.PlanResource, note, tool string) (*mcp.CallToolResult, any, error) {
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{planResource})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}, &mcp.TextContent{Text: note}},
+	}, nil, nil
+}
+
+// silenceResource identifies a silence in a plan.
+func silenceResource(cluster, name string) response.Resource {
+	return response.Resource{
+		Name:      name,
+		Kind:      silenceKind,
+		Cluster:   cluster,
+		Namespace: silenceNamespace,
+	}
+}
+
+// matchersString returns the matchers of a silence like Alertmanager shows them, e.g. {alertname="Foo",severity=~"w.*"}.
+func matchersString(matchers []silenceMatcher) string {
+	var parts []string
+	for _, m := range matchers {
+		parts = append(parts, labelFilter(m.Name, matcherOperator(m), m.Value))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// matcherOperator returns the operator of a matcher: =, !=, =~ or !~.
+func matcherOperator(m silenceMatcher) string {
+	isEqual := m.IsEqual == nil || *m.IsEqual
+	switch {
+	case isEqual && !m.IsRegex:
+		return "="
+	case isEqual:
+		return "=~"
+	case !m.IsRegex:
+		return "!="
+	}
+
+	return "!~"
+}
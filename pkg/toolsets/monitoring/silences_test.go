@@ -0,0 +1,305 @@
+package monitoring
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+const fakeSilences = `[
+	{
+		"id": "3f1c",
+		"status": {"state": "active"},
+		"matchers": [{"name": "alertname", "value": "KubeQuotaAlmostFull", "isRegex": false, "isEqual": true}],
+		"startsAt": "2023-11-14T18:00:00Z",
+		"endsAt": "2023-11-15T18:00:00Z",
+		"createdBy": "admin",
+		"comment": "quota increase requested"
+	},
+	{
+		"id": "a7b2",
+		"status": {"state": "expired"},
+		"matchers": [{"name": "namespace", "value": "team-.*", "isRegex": true, "isEqual": true}],
+		"startsAt": "2023-11-13T18:00:00Z",
+		"endsAt": "2023-11-13T20:00:00Z",
+		"createdBy": "admin",
+		"comment": "maintenance"
+	},
+	{
+		"id": "c9d4",
+		"status": {"state": "pending"},
+		"matchers": [{"name": "node", "value": "node-2", "isRegex": false, "isEqual": true}],
+		"startsAt": "2023-11-15T00:00:00Z",
+		"endsAt": "2023-11-15T02:00:00Z",
+		"createdBy": "admin",
+		"comment": "node upgrade"
+	}
+]`
+
+func TestListSilences(t *testing.T) {
+	tools, _ := newAlertmanagerTools(t, map[string]alertmanagerResponse{
+		"GET /api/v2/silences": {status: http.StatusOK, body: fakeSilences},
+	})
+
+	result, _, err := tools.listSilences(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, listSilencesParams{Cluster: "local"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"llm": [
+		{
+			"id": "c9d4",
+			"status": {"state": "pending"},
+			"matchers": [{"name": "node", "value": "node-2", "isRegex": false, "isEqual": true}],
+			"startsAt": "2023-11-15T00:00:00Z",
+			"endsAt": "2023-11-15T02:00:00Z",
+			"createdBy": "admin",
+			"comment": "node upgrade"
+		},
+		{
+			"id": "3f1c",
+			"status": {"state": "active"},
+			"matchers": [{"name": "alertname", "value": "KubeQuotaAlmostFull", "isRegex": false, "isEqual": true}],
+			"startsAt": "2023-11-14T18:00:00Z",
+			"endsAt": "2023-11-15T18:00:00Z",
+			"createdBy": "admin",
+			"comment": "quota increase requested"
+		}
+	]}`, result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestCreateSilence(t *testing.T) {
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	crashLooping := `[{
+		"labels": {"alertname": "KubePodCrashLooping", "severity": "warning", "namespace": "default", "pod": "web-1"},
+		"annotations": {},
+		"startsAt": "2023-11-14T20:00:00Z",
+		"status": {"state": "active", "silencedBy": [], "inhibitedBy": []}
+	}]`
+	expectedSilence := `{
+		"matchers": [
+			{"name": "alertname", "value": "KubePodCrashLooping", "isRegex": false, "isEqual": true},
+			{"name": "pod", "value": "web-.*", "isRegex": true, "isEqual": false}
+		],
+		"startsAt": "2023-11-14T22:13:20Z",
+		"endsAt": "2023-11-15T00:13:20Z",
+		"createdBy": "Rancher AI agent",
+		"comment": "known issue, fix in progress"
+	}`
+	params := createSilenceParams{
+		Cluster: "local",
+		Matchers: []silenceMatcher{
+			{Name: "alertname", Value: "KubePodCrashLooping"},
+			{Name: "pod", Value: "web-.*", IsRegex: true, IsEqual: ptr.To(false)},
+		},
+		Duration: "2h",
+		Comment:  "known issue, fix in progress",
+	}
+
+	tests := map[string]struct {
+		params        createSilenceParams
+		responses     map[string]alertmanagerResponse
+		expectedError string
+	}{
+		"create": {
+			params: params,
+			responses: map[string]alertmanagerResponse{
+				"GET /api/v2/alerts":    {status: http.StatusOK, body: crashLooping},
+				"POST /api/v2/silences": {status: http.StatusOK, body: `{"silenceID": "e5f6"}`},
+			},
+		},
+		"rejected by Alertmanager": {
+			params: params,
+			responses: map[string]alertmanagerResponse{
+				"GET /api/v2/alerts":    {status: http.StatusOK, body: crashLooping},
+				"POST /api/v2/silences": {status: http.StatusBadRequest, body: `"invalid regular expression"`},
+			},
+			expectedError: `failed to create the silence in cluster 'local': 400 "invalid regular expression"`,
+		},
+		"no matcher": {
+			params:        createSilenceParams{Cluster: "local", Duration: "2h", Comment: "all"},
+			expectedError: "at least one matcher is required",
+		},
+		"only negative matchers": {
+			params:        createSilenceParams{Cluster: "local", Matchers: []silenceMatcher{{Name: "severity", Value: "critical", IsEqual: ptr.To(false)}}, Duration: "2h", Comment: "all"},
+			expectedError: "at least one matcher must match a non-empty value",
+		},
+		"missing comment": {
+			params:        createSilenceParams{Cluster: "local", Matchers: []silenceMatcher{{Name: "alertname", Value: "Watchdog"}}, Duration: "2h"},
+			expectedError: "the comment is required",
+		},
+		"invalid duration": {
+			params:        createSilenceParams{Cluster: "local", Matchers: []silenceMatcher{{Name: "alertname", Value: "Watchdog"}}, Duration: "forever", Comment: "noise"},
+			expectedError: "invalid duration 'forever'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newAlertmanagerTools(t, tt.responses)
+			tools.now = func() time.Time { return now }
+
+			result, _, err := tools.createSilence(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, 2)
+			assert.Equal(t, []string{`alertname="KubePodCrashLooping"`, `pod!~"web-.*"`}, (*requests)[0].query["filter"])
+			assert.JSONEq(t, expectedSilence, (*requests)[1].body)
+			assert.JSONEq(t, `{"llm": {
+				"silence": {
+					"id": "e5f6",
+					"matchers": [
+						{"name": "alertname", "value": "KubePodCrashLooping", "isRegex": false, "isEqual": true},
+						{"name": "pod", "value": "web-.*", "isRegex": true, "isEqual": false}
+					],
+					"startsAt": "2023-11-14T22:13:20Z",
+					"endsAt": "2023-11-15T00:13:20Z",
+					"createdBy": "Rancher AI agent",
+					"comment": "known issue, fix in progress"
+				},
+				"silencedAlerts": ["KubePodCrashLooping pod=web-1"]
+			}}`, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestCreateSilencePlan(t *testing.T) {
+	tools, requests := newAlertmanagerTools(t, map[string]alertmanagerResponse{
+		"GET /api/v2/alerts": {status: http.StatusOK, body: `[]`},
+	})
+	tools.now = func() time.Time { return time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC) }
+
+	result, _, err := tools.createSilencePlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, createSilenceParams{
+		Cluster:   "local",
+		Matchers:  []silenceMatcher{{Name: "alertname", Value: "Watchdog"}},
+		Duration:  "1d",
+		Comment:   "noise",
+		CreatedBy: "admin",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, *requests, 1, "the plan must not create the silence")
+	require.Len(t, result.Content, 2)
+	assert.JSONEq(t, `[{
+		"type": "create",
+		"payload": {
+			"matchers": [{"name": "alertname", "value": "Watchdog", "isRegex": false, "isEqual": true}],
+			"startsAt": "2023-11-14T22:13:20Z",
+			"endsAt": "2023-11-15T22:13:20Z",
+			"createdBy": "admin",
+			"comment": "noise"
+		},
+		"resource": {"name": "{alertname=\"Watchdog\"}", "kind": "Silence", "cluster": "local", "namespace": "cattle-monitoring-system"}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, "The silence does not match any active alert.", result.Content[1].(*mcp.TextContent).Text)
+}
+
+func TestExpireSilence(t *testing.T) {
+	activeSilence := `{
+		"id": "3f1c",
+		"status": {"state": "active"},
+		"matchers": [{"name": "alertname", "value": "KubeQuotaAlmostFull", "isRegex": false, "isEqual": true}],
+		"startsAt": "2023-11-14T18:00:00Z",
+		"endsAt": "2023-11-15T18:00:00Z",
+		"createdBy": "admin",
+		"comment": "quota increase requested"
+	}`
+
+	tests := map[string]struct {
+		params           expireSilenceParams
+		responses        map[string]alertmanagerResponse
+		expectedRequests int
+		expectedError    string
+	}{
+		"expire": {
+			params: expireSilenceParams{Cluster: "local", SilenceID: "3f1c"},
+			responses: map[string]alertmanagerResponse{
+				"GET /api/v2/silence/3f1c":    {status: http.StatusOK, body: activeSilence},
+				"DELETE /api/v2/silence/3f1c": {status: http.StatusOK},
+			},
+			expectedRequests: 2,
+		},
+		"already expired": {
+			params: expireSilenceParams{Cluster: "local", SilenceID: "a7b2"},
+			responses: map[string]alertmanagerResponse{
+				"GET /api/v2/silence/a7b2": {status: http.StatusOK, body: `{"id": "a7b2", "status": {"state": "expired"}, "matchers": []}`},
+			},
+			expectedError: "silence 'a7b2' is already expired",
+		},
+		"not found": {
+			params:        expireSilenceParams{Cluster: "local", SilenceID: "0000"},
+			expectedError: "silence '0000' not found in cluster 'local'",
+		},
+		"missing ID": {
+			params:        expireSilenceParams{Cluster: "local"},
+			expectedError: "the silence ID is required",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, requests := newAlertmanagerTools(t, tt.responses)
+
+			result, _, err := tools.expireSilence(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, tt.params)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, *requests, tt.expectedRequests)
+			assert.Equal(t, http.MethodDelete, (*requests)[1].method)
+			assert.JSONEq(t, `{"llm": {
+				"id": "3f1c",
+				"status": {"state": "expired"},
+				"matchers": [{"name": "alertname", "value": "KubeQuotaAlmostFull", "isRegex": false, "isEqual": true}],
+				"startsAt": "2023-11-14T18:00:00Z",
+				"endsAt": "2023-11-15T18:00:00Z",
+				"createdBy": "admin",
+				"comment": "quota increase requested"
+			}}`, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+func TestExpireSilencePlan(t *testing.T) {
+	tools, requests := newAlertmanagerTools(t, map[string]alertmanagerResponse{
+		"GET /api/v2/silence/3f1c": {status: http.StatusOK, body: `{
+			"id": "3f1c",
+			"status": {"state": "active"},
+			"matchers": [{"name": "namespace", "value": "team-a", "isRegex": false, "isEqual": true}],
+			"startsAt": "2023-11-14T18:00:00Z",
+			"endsAt": "2023-11-15T18:00:00Z",
+			"createdBy": "admin",
+			"comment": "quota increase requested"
+		}`},
+	})
+
+	result, _, err := tools.expireSilencePlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, expireSilenceParams{Cluster: "local", SilenceID: "3f1c"})
+
+	require.NoError(t, err)
+	require.Len(t, *requests, 1, "the plan must not expire the silence")
+	require.Len(t, result.Content, 2)
+	assert.JSONEq(t, `[{
+		"type": "delete",
+		"payload": {
+			"id": "3f1c",
+			"status": {"state": "active"},
+			"matchers": [{"name": "namespace", "value": "team-a", "isRegex": false, "isEqual": true}],
+			"startsAt": "2023-11-14T18:00:00Z",
+			"endsAt": "2023-11-15T18:00:00Z",
+			"createdBy": "admin",
+			"comment": "quota increase requested"
+		},
+		"resource": {"name": "3f1c", "kind": "Silence", "cluster": "local", "namespace": "cattle-monitoring-system"}
+	}]`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, `The alerts matching {namespace="team-a"} will be notified again.`, result.Content[1].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,129 @@
+package monitoring
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	toolsSet    = "monitoring"
+	toolsSetAnn = "toolset"
+)
+
+type toolsClient interface {
+	GetClusterID(ctx context.Context, token string, clusterNameOrID string) (string, error)
+	CreateRestConfig(token string, clusterID string) (*rest.Config, error)
+}
+
+// Tools contains the tools that query the rancher-monitoring Prometheus and Alertmanager of a cluster.
+type Tools struct {
+	client   toolsClient
+	ReadOnly bool
+	now      func() time.Time
+}
+
+// NewTools creates and returns a new Tools instance.
+func NewTools(client toolsClient, readOnly bool) *Tools {
+	return &Tools{
+		client:   client,
+		ReadOnly: readOnly,
+		now:      time.Now,
+	}
+}
+
+// AddTools registers all monitoring tools with the provided MCP server.
+// Each tool is configured with metadata identifying it as part of the monitoring toolset.
+func (t *Tools) AddTools(mcpServer *mcp.Server) {
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "getMetricsHistory",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the history of a curated metric from the rancher-monitoring Prometheus of a cluster: podCpu (cores), podMemory (working set bytes), podRestarts (container restarts per interval), nodePressure (1 while a node has the MemoryPressure, DiskPressure or PIDPressure condition) or apiServerLatency (99th percentile of the API server request duration in seconds by verb).
+Unlike getNodeMetrics and getResourceUsage, which return the current sample of the metrics server, it can show how usage evolved and when problems started. Every series includes its min, max, average and last value.
+It requires rancher-monitoring to be installed in the cluster.`},
+		t.getMetricsHistory,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "queryPrometheus",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Evaluates a PromQL instant query against the rancher-monitoring Prometheus of a cluster, at the current time or at a given time. Every series includes its labels and value.
+Queries returning many series must be aggregated, only the 20 series with the highest values are returned. It requires rancher-monitoring to be installed in the cluster.`},
+		t.queryPrometheus,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "queryPrometheusRange",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Evaluates a PromQL range query against the rancher-monitoring Prometheus of a cluster, over a duration ending now or at a given time. Every series includes its labels, its values and its min, max, average and last value.
+Only the 20 series with the highest values are returned, and series are downsampled to 60 points keeping the highest value of each interval. It requires rancher-monitoring to be installed in the cluster.`},
+		t.queryPrometheusRange,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listAlerts",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the active alerts of the rancher-monitoring Alertmanager of a cluster, from the most severe and oldest. They can be filtered by severity, namespace and label values.
+Every alert includes its summary, description, runbook URL and the Kubernetes resources its labels reference, like the Pod, Deployment or Node it is about. Silenced and inhibited alerts are only included when includeSilenced is true.
+It requires rancher-monitoring to be installed in the cluster.`},
+		t.listAlerts,
+	)
+
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "listSilences",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the pending and active silences of the rancher-monitoring Alertmanager of a cluster, with their ID, matchers, end time, author and comment. Expired silences are only included when includeExpired is true.`},
+		t.listSilences,
+	)
+
+	if !t.ReadOnly {
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "createSilence",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Creates a silence in the rancher-monitoring Alertmanager of a cluster, muting the notifications of the alerts matching all its label matchers from now for the given duration. The comment must explain why.
+Returns the silence with its ID and the active alerts it mutes.`},
+			t.createSilence,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "createSilencePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to create a silence in the rancher-monitoring Alertmanager of a cluster. It returns the silence to be created without creating it, followed by the active alerts it would mute. Only used for displaying the silence when using human validation.`},
+			t.createSilencePlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "expireSilence",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Expires a pending or active silence of the rancher-monitoring Alertmanager of a cluster, so that the alerts it muted are notified again. Returns the expired silence.`},
+			t.expireSilence,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "expireSilencePlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to expire a silence of the rancher-monitoring Alertmanager of a cluster. It returns the silence to be expired without expiring it. Only used for displaying the silence when using human validation.`},
+			t.expireSilencePlan,
+		)
+	}
+}
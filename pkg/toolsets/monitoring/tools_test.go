@@ -0,0 +1,77 @@
+package monitoring
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeToken = "fakeToken"
+
+// newStandInTools returns tools reaching a stand-in of a rancher-monitoring service, served behind its service proxy
+// path in the local cluster. Requests without the fake token are rejected like Rancher does. The handler receives the
+// requests with their path relative to the service.
+func newStandInTools(t *testing.T, svc monitoringService, handler http.HandlerFunc) *Tools {
+	t.Helper()
+
+	proxyPrefix := "/k8s/clusters/local" + svc.proxyPath
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+fakeToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path, ok := strings.CutPrefix(r.URL.Path, proxyPrefix)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "NotFound", "code": 404}`))
+			return
+		}
+		r.URL.Path = path
+		handler(w, r)
+	}))
+	t.Cleanup(svr.Close)
+
+	c, err := client.NewClient(false, svr.URL)
+	require.NoError(t, err)
+
+	return NewTools(c, false)
+}
+
+func TestAddTools(t *testing.T) {
+	tests := map[string]struct {
+		readOnly      bool
+		expectedTools int
+	}{
+		"all tools":       {readOnly: false, expectedTools: 9},
+		"read only tools": {readOnly: true, expectedTools: 5},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _ := client.NewClient(true, "")
+			mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "v1.0.0"}, nil)
+			NewTools(c, tt.readOnly).AddTools(mcpServer)
+
+			serverTransport, clientTransport := mcp.NewInMemoryTransports()
+			_, err := mcpServer.Connect(t.Context(), serverTransport, nil)
+			require.NoError(t, err)
+			cs, err := mcp.NewClient(&mcp.Implementation{Name: "mcp-client", Version: "v1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+			require.NoError(t, err)
+			defer cs.Close()
+
+			toolsResult, err := cs.ListTools(t.Context(), &mcp.ListToolsParams{})
+
+			require.NoError(t, err)
+			assert.Len(t, toolsResult.Tools, tt.expectedTools, "incorrect number of tools registered")
+			for _, tool := range toolsResult.Tools {
+				assert.Equal(t, toolsSet, tool.Meta[toolsSetAnn])
+			}
+		})
+	}
+}
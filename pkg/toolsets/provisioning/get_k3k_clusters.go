@@ -9,6 +9,7 @@ import (
 	"github.com/rancher/rancher-ai-mcp/internal/middleware"
 	"github.com/rancher/rancher-ai-mcp/pkg/client"
 	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -22,55 +23,33 @@ type K3kClusterDetails struct {
 	Status map[string]interface{} `json:"status,omitempty"`
 }
 
+// k3kClustersResult holds the K3k clusters found in each downstream cluster, and the downstream clusters that couldn't be queried.
+type k3kClustersResult struct {
+	Clusters map[string][]K3kClusterDetails `json:"clusters"`
+	Errors   map[string]client.ClusterError `json:"errors,omitempty"`
+}
+
 // getK3kClusters retrieves a list of K3k clusters deployed across specified downstream clusters.
 // If no clusters are provided, it fetches K3k clusters from all available downstream clusters.
+// Downstream clusters that can't be queried are reported under "errors" instead of being dropped.
 // Returns a JSON map of downstream cluster names to lists of K3k cluster names.
 func (t *Tools) getK3kClusters(ctx context.Context, toolReq *mcp.CallToolRequest, params getK3kClustersParams) (*mcp.CallToolResult, any, error) {
 	zap.L().Debug("getK3kClusters called for clusters ", zap.Any("clusters", params.Clusters))
 
-	var clusters []string
-	if len(params.Clusters) == 0 {
-		clusterList, err := t.client.GetResources(ctx, client.ListParams{
-			Cluster: "local",
-			Kind:    "managementcluster",
-			Token:   middleware.Token(ctx),
-		})
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get clusters: %w", err)
-		}
-		for _, cluster := range clusterList {
-			clusters = append(clusters, cluster.GetName())
-		}
-	} else {
-		clusters = params.Clusters
+	clusters, err := client.ClustersOrAll(ctx, t.client, middleware.Token(ctx), params.Clusters)
+	if err != nil {
+		zap.L().Error("failed to get clusters", zap.String("tool", "getK3kClusters"), zap.Error(err))
+		return nil, nil, err
 	}
-	k3kClustersMap := map[string][]K3kClusterDetails{}
-
-	for _, cluster := range clusters {
-		k3kClusters, err := t.client.GetResources(ctx, client.ListParams{
-			Cluster: cluster,
-			Kind:    "k3kcluster",
-			Token:   middleware.Token(ctx),
-		})
-
-		if err != nil {
-			zap.L().Warn("failed to get k3k clusters", zap.String("tool", "getK3kClusters"), zap.String("Downstream cluster", cluster))
-		} else {
-			var clusterDetails []K3kClusterDetails
-			for _, k3kCluster := range k3kClusters {
-				spec, _, _ := unstructured.NestedMap(k3kCluster.Object, "spec")
-				status, _, _ := unstructured.NestedMap(k3kCluster.Object, "status")
-				clusterDetails = append(clusterDetails, K3kClusterDetails{
-					Name:   k3kCluster.GetName(),
-					Spec:   spec,
-					Status: status,
-				})
-			}
-			k3kClustersMap[cluster] = clusterDetails
-		}
+	k3kClustersMap, errs := client.FanOut(ctx, clusters, client.FanOutOptions{}, t.getK3kClustersInCluster)
+	for cluster, err := range errs {
+		zap.L().Warn("failed to get k3k clusters", zap.String("tool", "getK3kClusters"), zap.String("Downstream cluster", cluster), zap.Error(err))
 	}
 
-	response, err := json.Marshal(k3kClustersMap)
+	response, err := json.Marshal(k3kClustersResult{
+		Clusters: k3kClustersMap,
+		Errors:   client.NewClusterErrors(errs),
+	})
 	if err != nil {
 		zap.L().Error("failed to create response", zap.String("tool", "getK3kClusters"), zap.Error(err))
 		return nil, nil, fmt.Errorf("failed to marshal JSON: %w", err)
@@ -80,3 +59,32 @@ func (t *Tools) getK3kClusters(ctx context.Context, toolReq *mcp.CallToolRequest
 		Content: []mcp.Content{&mcp.TextContent{Text: string(response)}},
 	}, nil, nil
 }
+
+// getK3kClustersInCluster returns the K3k clusters deployed in a single downstream cluster.
+func (t *Tools) getK3kClustersInCluster(ctx context.Context, cluster string) ([]K3kClusterDetails, error) {
+	k3kClusters, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: cluster,
+		Kind:    "k3kcluster",
+		Token:   middleware.Token(ctx),
+	})
+	if err != nil {
+		// clusters without the k3k CRD have no virtual clusters, this is not an error.
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var clusterDetails []K3kClusterDetails
+	for _, k3kCluster := range k3kClusters {
+		spec, _, _ := unstructured.NestedMap(k3kCluster.Object, "spec")
+		status, _, _ := unstructured.NestedMap(k3kCluster.Object, "status")
+		clusterDetails = append(clusterDetails, K3kClusterDetails{
+			Name:   k3kCluster.GetName(),
+			Spec:   spec,
+			Status: status,
+		})
+	}
+
+	return clusterDetails, nil
+}
@@ -1,6 +1,7 @@
 package provisioning
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -8,10 +9,13 @@ import (
 	"github.com/rancher/rancher-ai-mcp/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestGetK3kClusters(t *testing.T) {
@@ -21,6 +25,7 @@ func TestGetK3kClusters(t *testing.T) {
 	tests := map[string]struct {
 		params         getK3kClustersParams
 		fakeDynClient  *dynamicfake.FakeDynamicClient
+		listErr        error
 		expectedResult string
 		expectedError  string
 	}{
@@ -28,55 +33,90 @@ func TestGetK3kClusters(t *testing.T) {
 			params:        getK3kClustersParams{Clusters: []string{"local"}},
 			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, k3kCustomListKinds(), newK3kCluster("test-k3k-cluster", "shared", "v1.33.1-k3s1", 3, 0)),
 			expectedResult: `{
-				"local": [
-					{
-						"name": "test-k3k-cluster",
-						"spec": {
-							"mode": "shared",
-							"servers": 3,
-							"agents": 0,
-							"version": "v1.33.1-k3s1"
-						},
-						"status": {
-							"phase": "Running",
-							"ready": true
+				"clusters": {
+					"local": [
+						{
+							"name": "test-k3k-cluster",
+							"spec": {
+								"mode": "shared",
+								"servers": 3,
+								"agents": 0,
+								"version": "v1.33.1-k3s1"
+							},
+							"status": {
+								"phase": "Running",
+								"ready": true
+							}
 						}
-					}
-				]
+					]
+				}
 			}`,
 		},
 		"get K3k clusters from empty cluster list (auto-discovery)": {
 			params:        getK3kClustersParams{Clusters: []string{}},
 			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, k3kCustomListKinds(), newManagementCluster("downstream-1", true), newK3kCluster("test-k3k-cluster", "shared", "v1.33.1-k3s1", 3, 0)),
 			expectedResult: `{
-				"downstream-1": [
-					{
-						"name": "test-k3k-cluster",
-						"spec": {
-							"mode": "shared",
-							"servers": 3,
-							"agents": 0,
-							"version": "v1.33.1-k3s1"
-						},
-						"status": {
-							"phase": "Running",
-							"ready": true
+				"clusters": {
+					"downstream-1": [
+						{
+							"name": "test-k3k-cluster",
+							"spec": {
+								"mode": "shared",
+								"servers": 3,
+								"agents": 0,
+								"version": "v1.33.1-k3s1"
+							},
+							"status": {
+								"phase": "Running",
+								"ready": true
+							}
 						}
-					}
-				]
+					]
+				}
 			}`,
 		},
 		"get K3k clusters from cluster with no K3k deployments": {
 			params:        getK3kClustersParams{Clusters: []string{"local"}},
 			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, k3kCustomListKinds()),
 			expectedResult: `{
-				"local": null
+				"clusters": {
+					"local": null
+				}
+			}`,
+		},
+		"cluster without the k3k CRD has no K3k clusters": {
+			params:        getK3kClustersParams{Clusters: []string{"local"}},
+			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, k3kCustomListKinds()),
+			listErr:       apierrors.NewNotFound(schema.GroupResource{Group: "k3k.io", Resource: "clusters"}, ""),
+			expectedResult: `{
+				"clusters": {
+					"local": null
+				}
+			}`,
+		},
+		"forbidden cluster is reported instead of being dropped": {
+			params:        getK3kClustersParams{Clusters: []string{"local"}},
+			fakeDynClient: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, k3kCustomListKinds()),
+			listErr:       apierrors.NewForbidden(schema.GroupResource{Group: "k3k.io", Resource: "clusters"}, "", errors.New("access denied")),
+			expectedResult: `{
+				"clusters": {},
+				"errors": {
+					"local": {
+						"reason": "Forbidden",
+						"message": "clusters.k3k.io is forbidden: access denied"
+					}
+				}
 			}`,
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
+			if test.listErr != nil {
+				test.fakeDynClient.PrependReactor("list", "clusters", func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, test.listErr
+				})
+			}
 			c := &client.Client{
 				DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
 					return test.fakeDynClient, nil
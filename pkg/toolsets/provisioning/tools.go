@@ -8,6 +8,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 )
 
 const (
@@ -22,6 +23,8 @@ type toolsClient interface {
 	GetResourceByGVR(ctx context.Context, params client.GetParams, gvr schema.GroupVersionResource) (*unstructured.Unstructured, error)
 	GetResources(ctx context.Context, params client.ListParams) ([]*unstructured.Unstructured, error)
 	GetResourceInterface(ctx context.Context, token string, namespace string, cluster string, gvr schema.GroupVersionResource) (dynamic.ResourceInterface, error)
+	GetClusterID(ctx context.Context, token string, clusterNameOrID string) (string, error)
+	CreateRestConfig(token string, clusterID string) (*rest.Config, error)
 	RancherURL() string
 }
 
@@ -72,7 +75,7 @@ This should be used when detailed information about a specific machine is requir
 		Meta: map[string]any{
 			toolsSetAnn: toolsSet,
 		},
-		Description: `List K3k virtual clusters deployed across downstream clusters.`},
+		Description: `List K3k virtual clusters deployed across downstream clusters. Downstream clusters that could not be queried are listed under errors with the reason (e.g. Unreachable, Forbidden).`},
 		t.getK3kClusters)
 
 	mcp.AddTool(mcpServer, &mcp.Tool{
@@ -84,6 +87,15 @@ This should be used when detailed information about a specific machine is requir
 This should only be used when information about the supported rke2 and k3s is needed. This is often required to support provisioning custom and imported clusters.`},
 		t.listSupportedKubernetesVersions)
 
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "versionDriftReport",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Reports the upgrade candidates of every cluster, or of the given clusters: the most recent KDM release of the Kubernetes minor version of the cluster and of its distribution (rke2 or k3s), and the Apps whose chart has a newer version in the ClusterRepo it was installed from.
+Clusters whose Kubernetes minor version is no longer released by KDM are flagged as out of support. Clusters whose Apps could not be queried are listed under errors with the reason (e.g. Unreachable, Forbidden).`},
+		t.versionDriftReport)
+
 	if !t.ReadOnly {
 		mcp.AddTool(mcpServer, &mcp.Tool{
 			Name: "scaleClusterNodePool",
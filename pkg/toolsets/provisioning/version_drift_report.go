@@ -0,0 +1,348 @@
+package provisioning
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// kdmDistros are the distributions whose Kubernetes versions are released through KDM.
+var kdmDistros = []string{"rke2", "k3s"}
+
+type versionDriftReportParams struct {
+	Clusters []string `json:"clusters,omitempty" jsonschema:"the names or IDs of the clusters to report on. Empty to report on all clusters"`
+}
+
+// versionDriftReport holds the upgrade candidates of every cluster, and the clusters whose Apps couldn't be queried.
+type versionDriftReport struct {
+	// LatestReleases is the most recent KDM release of every distribution.
+	LatestReleases map[string]string              `json:"latestReleases,omitempty"`
+	Clusters       []clusterVersionDrift          `json:"clusters"`
+	Notes          []string                       `json:"notes,omitempty"`
+	Errors         map[string]client.ClusterError `json:"errors,omitempty"`
+}
+
+// clusterVersionDrift is the row of a cluster in the version drift report.
+type clusterVersionDrift struct {
+	Cluster           string `json:"cluster"`
+	ID                string `json:"id"`
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	Distribution      string `json:"distribution,omitempty"`
+	// LatestPatch is the most recent KDM release of the minor version of the cluster, when it is newer.
+	LatestPatch string `json:"latestPatch,omitempty"`
+	// LatestVersion is the most recent KDM release of the distribution of the cluster, when it is newer.
+	LatestVersion string `json:"latestVersion,omitempty"`
+	// OutOfSupport is set when KDM has no release of the minor version of the cluster anymore.
+	OutOfSupport  bool           `json:"outOfSupport"`
+	ChartUpgrades []chartUpgrade `json:"chartUpgrades"`
+	UpToDateApps  int            `json:"upToDateApps"`
+	Notes         []string       `json:"notes,omitempty"`
+}
+
+// chartUpgrade is an App whose ClusterRepo has a newer version of its chart.
+type chartUpgrade struct {
+	Namespace     string `json:"namespace"`
+	App           string `json:"app"`
+	Chart         string `json:"chart"`
+	Repo          string `json:"repo"`
+	Version       string `json:"version"`
+	LatestVersion string `json:"latestVersion"`
+}
+
+// clusterApps is the result of comparing the Apps of a cluster with the charts of their ClusterRepos.
+type clusterApps struct {
+	upgrades []chartUpgrade
+	upToDate int
+	notes    []string
+}
+
+// kubernetesRelease is a Kubernetes version released by KDM, like v1.30.4+rke2r1 or v1.30.4+k3s1.
+type kubernetesRelease struct {
+	name    string
+	version *version.Version
+	distro  string
+	// release is the release number of the distribution for the same Kubernetes version, like 1 in v1.30.4+rke2r1.
+	release int
+}
+
+// versionDriftReport reports, for every cluster, the newer Kubernetes versions released by KDM and the Apps whose
+// chart has a newer version in its ClusterRepo. Clusters whose minor version is no longer released by KDM are flagged
+// as out of support.
+func (t *Tools) versionDriftReport(ctx context.Context, toolReq *mcp.CallToolRequest, params versionDriftReportParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("versionDriftReport called")
+
+	token := middleware.Token(ctx)
+	clusters, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: "local",
+		Kind:    converter.ManagementClusterResourceKind,
+		Token:   token,
+	})
+	if err != nil {
+		zap.L().Error("failed to get clusters", zap.String("tool", "versionDriftReport"), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to get clusters: %w", err)
+	}
+
+	report := versionDriftReport{Clusters: []clusterVersionDrift{}}
+	clusters, missing := selectClusters(clusters, params.Clusters)
+	for _, name := range missing {
+		report.Notes = append(report.Notes, fmt.Sprintf("cluster '%s' not found", name))
+	}
+
+	releases := map[string][]kubernetesRelease{}
+	for _, distro := range kdmDistros {
+		names, err := getKDMReleases(t.client.RancherURL(), distro)
+		if err != nil {
+			zap.L().Warn("failed to get KDM releases", zap.String("tool", "versionDriftReport"), zap.String("distro", distro), zap.Error(err))
+			report.Notes = append(report.Notes, fmt.Sprintf("the Kubernetes versions of %s clusters are not compared: %v", distro, err))
+			continue
+		}
+		releases[distro] = parseKubernetesReleases(names)
+		if len(releases[distro]) > 0 {
+			if report.LatestReleases == nil {
+				report.LatestReleases = map[string]string{}
+			}
+			report.LatestReleases[distro] = releases[distro][0].name
+		}
+	}
+
+	ids := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		ids = append(ids, cluster.GetName())
+	}
+	appsInClusters, errs := client.FanOut(ctx, ids, client.FanOutOptions{}, t.compareClusterApps)
+	for cluster, err := range errs {
+		zap.L().Warn("failed to compare apps of cluster", zap.String("tool", "versionDriftReport"), zap.String("cluster", cluster), zap.Error(err))
+	}
+	report.Errors = client.NewClusterErrors(errs)
+
+	for _, cluster := range clusters {
+		drift := newClusterVersionDrift(cluster, releases)
+		if apps, ok := appsInClusters[cluster.GetName()]; ok {
+			drift.ChartUpgrades = apps.upgrades
+			drift.UpToDateApps = apps.upToDate
+			drift.Notes = append(drift.Notes, apps.notes...)
+		}
+		report.Clusters = append(report.Clusters, drift)
+	}
+	slices.SortFunc(report.Clusters, func(a, b clusterVersionDrift) int {
+		return cmp.Compare(a.Cluster, b.Cluster)
+	})
+
+	mcpResponse, err := response.CreateMcpResponseAny(report)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "versionDriftReport"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// compareClusterApps compares the chart version of every App of a cluster with the most recent version of the chart in
+// the ClusterRepo the App was installed from.
+func (t *Tools) compareClusterApps(ctx context.Context, clusterID string) (clusterApps, error) {
+	token := middleware.Token(ctx)
+	apps, err := t.client.GetResources(ctx, client.ListParams{
+		Cluster: clusterID,
+		Kind:    "app",
+		Token:   token,
+	})
+	if err != nil {
+		return clusterApps{}, fmt.Errorf("failed to get apps: %w", err)
+	}
+
+	slices.SortFunc(apps, func(a, b *unstructured.Unstructured) int {
+		return cmp.Or(cmp.Compare(a.GetNamespace(), b.GetNamespace()), cmp.Compare(a.GetName(), b.GetName()))
+	})
+
+	result := clusterApps{upgrades: []chartUpgrade{}}
+	indexes := map[string]*clusterrepo.Index{}
+	for _, app := range apps {
+		chart, _, _ := unstructured.NestedString(app.Object, "spec", "chart", "metadata", "name")
+		installed, _, _ := unstructured.NestedString(app.Object, "spec", "chart", "metadata", "version")
+		repo, _, _ := unstructured.NestedString(app.Object, "spec", "chart", "metadata", "annotations", clusterrepo.SourceRepoAnnotation)
+		if repo == "" {
+			result.notes = append(result.notes, fmt.Sprintf("App %s/%s is not compared, the ClusterRepo it was installed from is unknown", app.GetNamespace(), app.GetName()))
+			continue
+		}
+
+		index, ok := indexes[repo]
+		if !ok {
+			index, err = clusterrepo.GetIndex(ctx, t.client, token, clusterID, repo)
+			if err != nil {
+				result.notes = append(result.notes, err.Error())
+			}
+			indexes[repo] = index
+		}
+		if index == nil {
+			continue
+		}
+		versions := index.Entries[chart]
+		if len(versions) == 0 {
+			result.notes = append(result.notes, fmt.Sprintf("App %s/%s is not compared, chart '%s' is no longer in ClusterRepo '%s'", app.GetNamespace(), app.GetName(), chart, repo))
+			continue
+		}
+
+		// Helm indexes list the versions of a chart from the most recent one
+		if latest := versions[0].Version; newerChartVersion(latest, installed) {
+			result.upgrades = append(result.upgrades, chartUpgrade{
+				Namespace:     app.GetNamespace(),
+				App:           app.GetName(),
+				Chart:         chart,
+				Repo:          repo,
+				Version:       installed,
+				LatestVersion: latest,
+			})
+		} else {
+			result.upToDate++
+		}
+	}
+
+	return result, nil
+}
+
+// newClusterVersionDrift returns the Kubernetes part of the row of a cluster, comparing the version of the cluster
+// with the KDM releases of its distribution.
+func newClusterVersionDrift(cluster *unstructured.Unstructured, releases map[string][]kubernetesRelease) clusterVersionDrift {
+	drift := clusterVersionDrift{ID: cluster.GetName(), ChartUpgrades: []chartUpgrade{}}
+	drift.Cluster, _, _ = unstructured.NestedString(cluster.Object, "spec", "displayName")
+	if drift.Cluster == "" {
+		drift.Cluster = cluster.GetName()
+	}
+	drift.KubernetesVersion, _, _ = unstructured.NestedString(cluster.Object, "status", "version", "gitVersion")
+	if drift.KubernetesVersion == "" {
+		drift.Notes = append(drift.Notes, "the Kubernetes version of the cluster is unknown, it may not be ready")
+		return drift
+	}
+
+	current, ok := parseKubernetesRelease(drift.KubernetesVersion)
+	if !ok {
+		// hosted clusters like EKS or AKS are upgraded by their provider, not through KDM
+		drift.Distribution, _, _ = unstructured.NestedString(cluster.Object, "status", "provider")
+		drift.Notes = append(drift.Notes, "the Kubernetes version of the cluster is not released through KDM")
+		return drift
+	}
+	drift.Distribution = current.distro
+
+	distroReleases, ok := releases[current.distro]
+	if !ok {
+		return drift
+	}
+	drift.OutOfSupport = true
+	for _, release := range distroReleases {
+		if release.version.Major() == current.version.Major() && release.version.Minor() == current.version.Minor() {
+			drift.OutOfSupport = false
+			if compareKubernetesReleases(release, current) > 0 && drift.LatestPatch == "" {
+				drift.LatestPatch = release.name
+			}
+		}
+	}
+	if len(distroReleases) > 0 && compareKubernetesReleases(distroReleases[0], current) > 0 {
+		drift.LatestVersion = distroReleases[0].name
+	}
+	if drift.OutOfSupport {
+		drift.Notes = append(drift.Notes, fmt.Sprintf("KDM has no %s release of Kubernetes v%d.%d anymore", current.distro, current.version.Major(), current.version.Minor()))
+	}
+
+	return drift
+}
+
+// selectClusters returns the clusters whose ID or display name is in names, and the names that match no cluster. All
+// the clusters are returned when names is empty.
+func selectClusters(clusters []*unstructured.Unstructured, names []string) ([]*unstructured.Unstructured, []string) {
+	if len(names) == 0 {
+		return clusters, nil
+	}
+
+	var selected []*unstructured.Unstructured
+	var missing []string
+	for _, name := range names {
+		i := slices.IndexFunc(clusters, func(cluster *unstructured.Unstructured) bool {
+			displayName, _, _ := unstructured.NestedString(cluster.Object, "spec", "displayName")
+			return cluster.GetName() == name || strings.EqualFold(displayName, name)
+		})
+		if i < 0 {
+			missing = append(missing, name)
+			continue
+		}
+		if !slices.Contains(selected, clusters[i]) {
+			selected = append(selected, clusters[i])
+		}
+	}
+
+	return selected, missing
+}
+
+// parseKubernetesReleases returns the KDM releases that can be parsed, from the most recent one.
+func parseKubernetesReleases(names []string) []kubernetesRelease {
+	var releases []kubernetesRelease
+	for _, name := range names {
+		if release, ok := parseKubernetesRelease(name); ok {
+			releases = append(releases, release)
+		}
+	}
+	slices.SortFunc(releases, func(a, b kubernetesRelease) int {
+		return compareKubernetesReleases(b, a)
+	})
+
+	return releases
+}
+
+// parseKubernetesRelease parses an RKE2 or K3s version, like v1.30.4+rke2r1 or v1.30.4+k3s1.
+func parseKubernetesRelease(name string) (kubernetesRelease, bool) {
+	v, err := version.ParseSemantic(name)
+	if err != nil {
+		return kubernetesRelease{}, false
+	}
+
+	for _, prefix := range []string{"rke2r", "k3s"} {
+		if number, ok := strings.CutPrefix(v.BuildMetadata(), prefix); ok {
+			release, err := strconv.Atoi(number)
+			if err != nil {
+				return kubernetesRelease{}, false
+			}
+			return kubernetesRelease{name: name, version: v, distro: strings.TrimSuffix(prefix, "r"), release: release}, true
+		}
+	}
+
+	return kubernetesRelease{}, false
+}
+
+// compareKubernetesReleases compares the Kubernetes versions of two releases, then their release numbers since
+// semantic versions ignore the build metadata holding them.
+func compareKubernetesReleases(a, b kubernetesRelease) int {
+	if c, _ := a.version.Compare(b.version.String()); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a.release, b.release)
+}
+
+// newerChartVersion returns whether latest is a newer chart version than installed. Versions that are not semantic
+// are only compared for equality.
+func newerChartVersion(latest, installed string) bool {
+	latestVersion, err := version.ParseSemantic(latest)
+	if err != nil {
+		return latest != installed
+	}
+	installedVersion, err := version.ParseSemantic(installed)
+	if err != nil {
+		return latest != installed
+	}
+
+	return latestVersion.GreaterThan(installedVersion)
+}
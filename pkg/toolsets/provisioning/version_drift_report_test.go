@@ -0,0 +1,226 @@
+package provisioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/clusterrepo"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+)
+
+func newVersionedCluster(id, displayName, gitVersion, provider string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "management.cattle.io/v3",
+		"kind":       "Cluster",
+		"metadata":   map[string]any{"name": id},
+		"spec":       map[string]any{"displayName": displayName},
+		"status": map[string]any{
+			"provider": provider,
+			"version":  map[string]any{"gitVersion": gitVersion},
+		},
+	}}
+}
+
+func newChartApp(namespace, name, chart, version, repo string) *unstructured.Unstructured {
+	metadata := map[string]any{"name": chart, "version": version}
+	if repo != "" {
+		metadata["annotations"] = map[string]any{clusterrepo.SourceRepoAnnotation: repo}
+	}
+
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "catalog.cattle.io/v1",
+		"kind":       "App",
+		"metadata":   map[string]any{"name": name, "namespace": namespace},
+		"spec":       map[string]any{"chart": map[string]any{"metadata": metadata}},
+	}}
+}
+
+func TestVersionDriftReport(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{
+		converter.K8sKindsToGVRs[converter.ManagementClusterResourceKind]: "ClusterList",
+		converter.K8sKindsToGVRs["app"]:                                   "AppList",
+	}
+	clusterObjects := map[string][]runtime.Object{
+		"local": {
+			newVersionedCluster("local", "local", "v1.30.4+rke2r1", "rke2"),
+			newVersionedCluster("c-m-legacy", "legacy", "v1.26.15+k3s1", "k3s"),
+			newVersionedCluster("c-m-edge", "edge", "v1.30.5+k3s1", "k3s"),
+			newVersionedCluster("c-m-eks", "eks-prod", "v1.29.8-eks-a737599", "eks"),
+			newChartApp("cattle-resources-system", "rancher-backup", "rancher-backup", "105.0.0", "rancher-charts"),
+			newChartApp("cattle-monitoring-system", "rancher-monitoring", "rancher-monitoring", "105.1.0", "rancher-charts"),
+		},
+		"c-m-legacy": {
+			newChartApp("default", "nginx", "nginx", "15.0.0", ""),
+			newChartApp("cattle-system", "rancher-webhook", "rancher-webhook", "104.0.0", "rancher-charts"),
+		},
+		"c-m-eks": {},
+	}
+	index := `{"entries": {
+		"rancher-backup": [{"version": "106.0.1"}, {"version": "105.0.0"}],
+		"rancher-monitoring": [{"version": "105.1.0"}]
+	}}`
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1-rke2-release/releases":
+			w.Write([]byte(createDummyKDMData("v1.29.9+rke2r1", "v1.30.4+rke2r1", "v1.30.5+rke2r1", "v1.30.5+rke2r2", "v1.31.1+rke2r1")))
+		case "/v1-k3s-release/releases":
+			w.Write([]byte(createDummyKDMData("v1.30.5+k3s1", "v1.29.9+k3s1")))
+		case "/k8s/clusters/local/v1/catalog.cattle.io.clusterrepos/rancher-charts", "/k8s/clusters/c-m-legacy/v1/catalog.cattle.io.clusterrepos/rancher-charts":
+			assert.Equal(t, "index", r.URL.Query().Get("link"))
+			assert.Equal(t, "Bearer "+testToken, r.Header.Get("Authorization"))
+			w.Write([]byte(index))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer svr.Close()
+
+	tests := map[string]struct {
+		params         versionDriftReportParams
+		expectedResult string
+	}{
+		"all clusters": {
+			params: versionDriftReportParams{},
+			expectedResult: `{"llm": {
+				"latestReleases": {"rke2": "v1.31.1+rke2r1", "k3s": "v1.30.5+k3s1"},
+				"clusters": [
+					{
+						"cluster": "edge",
+						"id": "c-m-edge",
+						"kubernetesVersion": "v1.30.5+k3s1",
+						"distribution": "k3s",
+						"outOfSupport": false,
+						"chartUpgrades": [],
+						"upToDateApps": 0
+					},
+					{
+						"cluster": "eks-prod",
+						"id": "c-m-eks",
+						"kubernetesVersion": "v1.29.8-eks-a737599",
+						"distribution": "eks",
+						"outOfSupport": false,
+						"chartUpgrades": [],
+						"upToDateApps": 0,
+						"notes": ["the Kubernetes version of the cluster is not released through KDM"]
+					},
+					{
+						"cluster": "legacy",
+						"id": "c-m-legacy",
+						"kubernetesVersion": "v1.26.15+k3s1",
+						"distribution": "k3s",
+						"latestVersion": "v1.30.5+k3s1",
+						"outOfSupport": true,
+						"chartUpgrades": [],
+						"upToDateApps": 0,
+						"notes": [
+							"KDM has no k3s release of Kubernetes v1.26 anymore",
+							"App cattle-system/rancher-webhook is not compared, chart 'rancher-webhook' is no longer in ClusterRepo 'rancher-charts'",
+							"App default/nginx is not compared, the ClusterRepo it was installed from is unknown"
+						]
+					},
+					{
+						"cluster": "local",
+						"id": "local",
+						"kubernetesVersion": "v1.30.4+rke2r1",
+						"distribution": "rke2",
+						"latestPatch": "v1.30.5+rke2r2",
+						"latestVersion": "v1.31.1+rke2r1",
+						"outOfSupport": false,
+						"chartUpgrades": [
+							{"namespace": "cattle-resources-system", "app": "rancher-backup", "chart": "rancher-backup", "repo": "rancher-charts", "version": "105.0.0", "latestVersion": "106.0.1"}
+						],
+						"upToDateApps": 1
+					}
+				],
+				"errors": {"c-m-edge": {"reason": "Unreachable", "message": "failed to get apps: cluster c-m-edge is disconnected"}}
+			}}`,
+		},
+		"selected clusters": {
+			params: versionDriftReportParams{Clusters: []string{"Legacy", "unknown"}},
+			expectedResult: `{"llm": {
+				"latestReleases": {"rke2": "v1.31.1+rke2r1", "k3s": "v1.30.5+k3s1"},
+				"clusters": [
+					{
+						"cluster": "legacy",
+						"id": "c-m-legacy",
+						"kubernetesVersion": "v1.26.15+k3s1",
+						"distribution": "k3s",
+						"latestVersion": "v1.30.5+k3s1",
+						"outOfSupport": true,
+						"chartUpgrades": [],
+						"upToDateApps": 0,
+						"notes": [
+							"KDM has no k3s release of Kubernetes v1.26 anymore",
+							"App cattle-system/rancher-webhook is not compared, chart 'rancher-webhook' is no longer in ClusterRepo 'rancher-charts'",
+							"App default/nginx is not compared, the ClusterRepo it was installed from is unknown"
+						]
+					}
+				],
+				"notes": ["cluster 'unknown' not found"]
+			}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.NewClient(false, svr.URL)
+			require.NoError(t, err)
+			c.DynClientCreator = func(config *rest.Config) (dynamic.Interface, error) {
+				clusterID := config.Host[strings.LastIndex(config.Host, "/")+1:]
+				objs, ok := clusterObjects[clusterID]
+				if !ok {
+					return nil, &unreachableError{cluster: clusterID}
+				}
+				return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objs...), nil
+			}
+			tools := Tools{client: c}
+
+			result, _, err := tools.versionDriftReport(middleware.WithToken(t.Context(), testToken), &mcp.CallToolRequest{}, tt.params)
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expectedResult, result.Content[0].(*mcp.TextContent).Text)
+		})
+	}
+}
+
+// unreachableError is the error of a cluster whose agent is disconnected.
+type unreachableError struct {
+	cluster string
+}
+
+func (e *unreachableError) Error() string   { return "cluster " + e.cluster + " is disconnected" }
+func (e *unreachableError) Timeout() bool   { return false }
+func (e *unreachableError) Temporary() bool { return false }
+
+func TestParseKubernetesReleases(t *testing.T) {
+	releases := parseKubernetesReleases([]string{"v1.30.5+rke2r1", "v1.31.0+rke2r1", "not-a-version", "v1.30.5+rke2r2", "v1.30.10+k3s1"})
+
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.name)
+	}
+	assert.Equal(t, []string{"v1.31.0+rke2r1", "v1.30.10+k3s1", "v1.30.5+rke2r2", "v1.30.5+rke2r1"}, names)
+	assert.Equal(t, "k3s", releases[1].distro)
+	assert.Equal(t, "rke2", releases[2].distro)
+}
+
+func TestNewerChartVersion(t *testing.T) {
+	assert.True(t, newerChartVersion("106.0.1+up6.0.1", "105.0.0+up5.0.0"))
+	assert.False(t, newerChartVersion("105.0.0", "105.0.0"))
+	assert.False(t, newerChartVersion("104.0.0", "105.0.0"), "an App installed from a newer chart is not behind")
+	assert.True(t, newerChartVersion("latest", "stable"))
+}
@@ -0,0 +1,82 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pauseRollout pauses the rollout of a Deployment.
+func (t *Tools) pauseRollout(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("pauseRollout called")
+
+	_, patch, err := t.pausedPatch(ctx, params, true)
+	if err != nil {
+		zap.L().Error("failed to create pause patch", zap.String("tool", "pauseRollout"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyPatch(ctx, params, patch, "pauseRollout")
+}
+
+// pauseRolloutPlan returns the patch pauseRollout would apply.
+func (t *Tools) pauseRolloutPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("pauseRolloutPlan called")
+
+	obj, patch, err := t.pausedPatch(ctx, params, true)
+	if err != nil {
+		zap.L().Error("failed to create pause patch", zap.String("tool", "pauseRolloutPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planPatch(obj, patch, params.Cluster, "pauseRolloutPlan")
+}
+
+// resumeRollout resumes the paused rollout of a Deployment.
+func (t *Tools) resumeRollout(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("resumeRollout called")
+
+	_, patch, err := t.pausedPatch(ctx, params, false)
+	if err != nil {
+		zap.L().Error("failed to create resume patch", zap.String("tool", "resumeRollout"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyPatch(ctx, params, patch, "resumeRollout")
+}
+
+// resumeRolloutPlan returns the patch resumeRollout would apply.
+func (t *Tools) resumeRolloutPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("resumeRolloutPlan called")
+
+	obj, patch, err := t.pausedPatch(ctx, params, false)
+	if err != nil {
+		zap.L().Error("failed to create resume patch", zap.String("tool", "resumeRolloutPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planPatch(obj, patch, params.Cluster, "resumeRolloutPlan")
+}
+
+// pausedPatch returns the patch setting spec.paused of a Deployment. StatefulSets and DaemonSets have no such field.
+func (t *Tools) pausedPatch(ctx context.Context, params workloadParams, paused bool) (*unstructured.Unstructured, workloadPatch, error) {
+	obj, err := t.getWorkload(ctx, params)
+	if err != nil {
+		return nil, workloadPatch{}, err
+	}
+	if obj.GetKind() != "Deployment" {
+		return nil, workloadPatch{}, fmt.Errorf("the rollout of a %s can't be paused or resumed, only Deployments support it", obj.GetKind())
+	}
+	if current, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); current == paused {
+		state := "not paused"
+		if paused {
+			state = "already paused"
+		}
+		return nil, workloadPatch{}, fmt.Errorf("deployment '%s' is %s", obj.GetName(), state)
+	}
+
+	return obj, workloadPatch(fmt.Appendf(nil, `[{"op":"add","path":"/spec/paused","value":%t}]`, paused)), nil
+}
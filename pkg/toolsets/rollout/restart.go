@@ -0,0 +1,70 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// restartedAtAnnotation is the pod template annotation set by kubectl rollout restart.
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// restartRollout restarts the pods of a workload by changing an annotation of its pod template.
+func (t *Tools) restartRollout(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("restartRollout called")
+
+	_, patch, err := t.restartPatch(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create restart patch", zap.String("tool", "restartRollout"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyPatch(ctx, params, patch, "restartRollout")
+}
+
+// restartRolloutPlan returns the patch restartRollout would apply.
+func (t *Tools) restartRolloutPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("restartRolloutPlan called")
+
+	obj, patch, err := t.restartPatch(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create restart patch", zap.String("tool", "restartRolloutPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planPatch(obj, patch, params.Cluster, "restartRolloutPlan")
+}
+
+func (t *Tools) restartPatch(ctx context.Context, params workloadParams) (*unstructured.Unstructured, workloadPatch, error) {
+	obj, err := t.getWorkload(ctx, params)
+	if err != nil {
+		return nil, workloadPatch{}, err
+	}
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused {
+		return nil, workloadPatch{}, fmt.Errorf("%s '%s' is paused, resume its rollout before restarting it", obj.GetKind(), obj.GetName())
+	}
+
+	// a JSON Patch can't add a field to a missing map, the annotations are added with the map when it doesn't exist
+	restartedAt := time.Now().Format(time.RFC3339)
+	op := map[string]any{"op": "add", "path": "/spec/template/metadata/annotations/" + escapeJSONPointer(restartedAtAnnotation), "value": restartedAt}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "template", "metadata", "annotations"); !found {
+		op = map[string]any{"op": "add", "path": "/spec/template/metadata/annotations", "value": map[string]any{restartedAtAnnotation: restartedAt}}
+	}
+	data, err := json.Marshal([]map[string]any{op})
+	if err != nil {
+		return nil, workloadPatch{}, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	return obj, workloadPatch(data), nil
+}
+
+// escapeJSONPointer escapes a key to be used as a reference token of a JSON Pointer.
+func escapeJSONPointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
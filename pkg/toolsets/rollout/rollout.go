@@ -0,0 +1,94 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/converter"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// workloadParams identifies the workload whose rollout is managed.
+type workloadParams struct {
+	Cluster   string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace string `json:"namespace" jsonschema:"the namespace of the workload"`
+	Kind      string `json:"kind" jsonschema:"the kind of the workload: Deployment, StatefulSet or DaemonSet"`
+	Name      string `json:"name" jsonschema:"the name of the workload"`
+}
+
+// workloadPatch is the change of a workload made by a rollout operation, as a JSON Patch. Plans return it as payload
+// like the other UPDATE plans.
+type workloadPatch []byte
+
+// getWorkload returns the Deployment, StatefulSet or DaemonSet identified by params.
+func (t *Tools) getWorkload(ctx context.Context, params workloadParams) (*unstructured.Unstructured, error) {
+	switch kind := strings.ToLower(params.Kind); kind {
+	case "deployment", "statefulset", "daemonset":
+		return t.client.GetResource(ctx, client.GetParams{
+			Cluster:   params.Cluster,
+			Kind:      kind,
+			Namespace: params.Namespace,
+			Name:      params.Name,
+			Token:     middleware.Token(ctx),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported workload kind '%s', it must be one of Deployment, StatefulSet or DaemonSet", params.Kind)
+	}
+}
+
+// applyPatch applies the patch to the workload and returns the modified workload.
+func (t *Tools) applyPatch(ctx context.Context, params workloadParams, patch workloadPatch, tool string) (*mcp.CallToolResult, any, error) {
+	resourceInterface, err := t.client.GetResourceInterface(ctx, middleware.Token(ctx), params.Namespace, params.Cluster, converter.K8sKindsToGVRs[strings.ToLower(params.Kind)])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := resourceInterface.Patch(ctx, params.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		zap.L().Error("failed to apply patch", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to patch %s %s: %w", params.Kind, params.Name, err)
+	}
+
+	mcpResponse, err := response.CreateMcpResponse([]*unstructured.Unstructured{obj}, params.Cluster)
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
+
+// planPatch returns the plan to apply the patch to the workload.
+func planPatch(obj *unstructured.Unstructured, patch workloadPatch, cluster string, tool string) (*mcp.CallToolResult, any, error) {
+	planResource := response.PlanResource{
+		Type:    response.OperationUpdate,
+		Payload: json.RawMessage(patch),
+		Resource: response.Resource{
+			Name:      obj.GetName(),
+			Kind:      obj.GetKind(),
+			Cluster:   cluster,
+			Namespace: obj.GetNamespace(),
+		},
+	}
+
+	mcpResponse, err := response.CreatePlanResponse([]response.PlanResource{planResource})
+	if err != nil {
+		zap.L().Error("failed to create plan response", zap.String("tool", tool), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
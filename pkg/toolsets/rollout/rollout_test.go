@@ -0,0 +1,389 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/client/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+const fakeToken = "fakeToken"
+
+var (
+	webLabels   = map[string]string{"app": "web"}
+	webSelector = &metav1.LabelSelector{MatchLabels: webLabels}
+	webParams   = workloadParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"}
+)
+
+func rolloutScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return scheme
+}
+
+func podTemplate(image string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: webLabels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}},
+	}
+}
+
+func newDeployment(paused bool) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web-uid"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(2)),
+			Selector: webSelector,
+			Template: podTemplate("web:2"),
+			Paused:   paused,
+		},
+	}
+}
+
+func newReplicaSet(revision, image string) *appsv1.ReplicaSet {
+	template := podTemplate(image)
+	template.Labels = map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: "hash-" + revision}
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web-" + revision,
+			Namespace:       "default",
+			Labels:          webLabels,
+			Annotations:     map[string]string{"deployment.kubernetes.io/revision": revision},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", UID: "web-uid", Controller: ptr.To(true)}},
+		},
+		Spec: appsv1.ReplicaSetSpec{Selector: webSelector, Template: template},
+	}
+}
+
+func newTools(objs ...runtime.Object) (*Tools, *dynamicfake.FakeDynamicClient) {
+	fakeDynClient := dynamicfake.NewSimpleDynamicClient(rolloutScheme(), objs...)
+	c := &client.Client{
+		DynClientCreator: func(inConfig *rest.Config) (dynamic.Interface, error) {
+			return fakeDynClient, nil
+		},
+	}
+
+	return NewTools(test.WrapClient(c, fakeToken), false), fakeDynClient
+}
+
+// llmObject returns the object returned to the LLM by an operation.
+func llmObject(t *testing.T, result *mcp.CallToolResult) *unstructured.Unstructured {
+	t.Helper()
+	var resp struct {
+		LLM []map[string]any `json:"llm"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &resp))
+	require.Len(t, resp.LLM, 1)
+
+	return &unstructured.Unstructured{Object: resp.LLM[0]}
+}
+
+func TestRolloutOperations(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", UID: "db-uid"},
+		Spec:       appsv1.StatefulSetSpec{Selector: webSelector, Template: podTemplate("db:2")},
+	}
+	controllerRevision := func(revision int64, image string) *appsv1.ControllerRevision {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "db-" + image,
+				Namespace:       "default",
+				Labels:          webLabels,
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", UID: "db-uid", Controller: ptr.To(true)}},
+			},
+			Revision: revision,
+			Data:     runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","metadata":{"labels":{"app":"web"}},"spec":{"containers":[{"name":"app","image":"` + image + `"}]}}}}`)},
+		}
+	}
+
+	tests := map[string]struct {
+		objs          []runtime.Object
+		call          func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error)
+		check         func(t *testing.T, obj *unstructured.Unstructured)
+		expectedError string
+	}{
+		"restart sets the restartedAt annotation": {
+			objs: []runtime.Object{newDeployment(false)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.restartRollout(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			check: func(t *testing.T, obj *unstructured.Unstructured) {
+				restartedAt, _, _ := unstructured.NestedString(obj.Object, "spec", "template", "metadata", "annotations", restartedAtAnnotation)
+				assert.NotEmpty(t, restartedAt)
+			},
+		},
+		"restart of a paused deployment": {
+			objs: []runtime.Object{newDeployment(true)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.restartRollout(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			expectedError: "Deployment 'web' is paused, resume its rollout before restarting it",
+		},
+		"restart of an unsupported kind": {
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.restartRollout(ctx, &mcp.CallToolRequest{}, workloadParams{Cluster: "local", Namespace: "default", Kind: "Job", Name: "web"})
+			},
+			expectedError: "unsupported workload kind 'Job'",
+		},
+		"pause": {
+			objs: []runtime.Object{newDeployment(false)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.pauseRollout(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			check: func(t *testing.T, obj *unstructured.Unstructured) {
+				paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+				assert.True(t, paused)
+			},
+		},
+		"pause of a paused deployment": {
+			objs: []runtime.Object{newDeployment(true)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.pauseRollout(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			expectedError: "deployment 'web' is already paused",
+		},
+		"pause of a statefulset": {
+			objs: []runtime.Object{statefulSet},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.pauseRollout(ctx, &mcp.CallToolRequest{}, workloadParams{Cluster: "local", Namespace: "default", Kind: "StatefulSet", Name: "db"})
+			},
+			expectedError: "the rollout of a StatefulSet can't be paused or resumed, only Deployments support it",
+		},
+		"resume": {
+			objs: []runtime.Object{newDeployment(true)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.resumeRollout(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			check: func(t *testing.T, obj *unstructured.Unstructured) {
+				paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused")
+				assert.False(t, paused)
+			},
+		},
+		"undo to the previous revision": {
+			objs: []runtime.Object{newDeployment(false), newReplicaSet("1", "web:1"), newReplicaSet("2", "web:2")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRollout(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"})
+			},
+			check: func(t *testing.T, obj *unstructured.Unstructured) {
+				containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
+				require.Len(t, containers, 1)
+				assert.Equal(t, "web:1", containers[0].(map[string]any)["image"])
+				labels, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "template", "metadata", "labels")
+				assert.Equal(t, webLabels, labels, "the pod template hash must not be copied to the deployment")
+			},
+		},
+		"undo to a missing revision": {
+			objs: []runtime.Object{newDeployment(false), newReplicaSet("1", "web:1"), newReplicaSet("2", "web:2")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRollout(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web", ToRevision: 5})
+			},
+			expectedError: "revision 5 of Deployment 'web' not found, the available revisions are [2 1]",
+		},
+		"undo to the current revision": {
+			objs: []runtime.Object{newDeployment(false), newReplicaSet("1", "web:1"), newReplicaSet("2", "web:2")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRollout(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web", ToRevision: 2})
+			},
+			expectedError: "Deployment 'web' is already at revision 2",
+		},
+		"undo without a previous revision": {
+			objs: []runtime.Object{newDeployment(false), newReplicaSet("1", "web:1")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRollout(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"})
+			},
+			expectedError: "Deployment 'web' has no previous revision to roll back to",
+		},
+		"undo a statefulset to a controller revision": {
+			objs: []runtime.Object{statefulSet, controllerRevision(1, "db:1"), controllerRevision(2, "db:2")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRollout(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "StatefulSet", Name: "db", ToRevision: 1})
+			},
+			check: func(t *testing.T, obj *unstructured.Unstructured) {
+				template, _, _ := unstructured.NestedMap(obj.Object, "spec", "template")
+				assert.NotContains(t, template, "$patch")
+				containers, _, _ := unstructured.NestedSlice(template, "spec", "containers")
+				require.Len(t, containers, 1)
+				assert.Equal(t, "db:1", containers[0].(map[string]any)["image"])
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, _ := newTools(tt.objs...)
+
+			result, _, err := tt.call(middleware.WithToken(t.Context(), fakeToken), tools)
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				tt.check(t, llmObject(t, result))
+			}
+		})
+	}
+}
+
+func TestRolloutPlans(t *testing.T) {
+	tests := map[string]struct {
+		objs            []runtime.Object
+		call            func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error)
+		expectedPayload string
+	}{
+		"pause plan": {
+			objs: []runtime.Object{newDeployment(false)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.pauseRolloutPlan(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			expectedPayload: `[{"op": "add", "path": "/spec/paused", "value": true}]`,
+		},
+		"resume plan": {
+			objs: []runtime.Object{newDeployment(true)},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.resumeRolloutPlan(ctx, &mcp.CallToolRequest{}, webParams)
+			},
+			expectedPayload: `[{"op": "add", "path": "/spec/paused", "value": false}]`,
+		},
+		"undo plan": {
+			objs: []runtime.Object{newDeployment(false), newReplicaSet("1", "web:1"), newReplicaSet("2", "web:2")},
+			call: func(ctx context.Context, tools *Tools) (*mcp.CallToolResult, any, error) {
+				return tools.undoRolloutPlan(ctx, &mcp.CallToolRequest{}, undoRolloutParams{Cluster: "local", Namespace: "default", Kind: "Deployment", Name: "web"})
+			},
+			expectedPayload: `[{
+				"op": "replace",
+				"path": "/spec/template",
+				"value": {
+					"metadata": {"labels": {"app": "web"}},
+					"spec": {"containers": [{"name": "app", "image": "web:1", "resources": {}}]}
+				}
+			}]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, fakeDynClient := newTools(tt.objs...)
+
+			result, _, err := tt.call(middleware.WithToken(t.Context(), fakeToken), tools)
+
+			require.NoError(t, err)
+			assert.JSONEq(t, `[{
+				"type": "update",
+				"payload": `+tt.expectedPayload+`,
+				"resource": {"name": "web", "kind": "Deployment", "cluster": "local", "namespace": "default"}
+			}]`, result.Content[0].(*mcp.TextContent).Text)
+			for _, action := range fakeDynClient.Actions() {
+				assert.NotEqual(t, "patch", action.GetVerb(), "plans must not change the workload")
+			}
+		})
+	}
+}
+
+func TestRestartRolloutPlan(t *testing.T) {
+	annotated := newDeployment(false)
+	annotated.Spec.Template.Annotations = map[string]string{"team": "shop"}
+
+	tests := map[string]struct {
+		deployment   *appsv1.Deployment
+		expectedPath string
+		restartedAt  func(value any) string
+	}{
+		"pod template without annotations": {
+			deployment:   newDeployment(false),
+			expectedPath: "/spec/template/metadata/annotations",
+			restartedAt: func(value any) string {
+				annotations, _ := value.(map[string]any)
+				restartedAt, _ := annotations[restartedAtAnnotation].(string)
+				return restartedAt
+			},
+		},
+		"pod template with annotations": {
+			deployment:   annotated,
+			expectedPath: "/spec/template/metadata/annotations/kubectl.kubernetes.io~1restartedAt",
+			restartedAt: func(value any) string {
+				restartedAt, _ := value.(string)
+				return restartedAt
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, fakeDynClient := newTools(tt.deployment)
+
+			result, _, err := tools.restartRolloutPlan(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, webParams)
+
+			require.NoError(t, err)
+			var plan []struct {
+				Payload []struct {
+					Op    string `json:"op"`
+					Path  string `json:"path"`
+					Value any    `json:"value"`
+				} `json:"payload"`
+			}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &plan))
+			require.Len(t, plan, 1)
+			require.Len(t, plan[0].Payload, 1)
+			assert.Equal(t, "add", plan[0].Payload[0].Op)
+			assert.Equal(t, tt.expectedPath, plan[0].Payload[0].Path)
+			assert.NotEmpty(t, tt.restartedAt(plan[0].Payload[0].Value))
+			for _, action := range fakeDynClient.Actions() {
+				assert.NotEqual(t, "patch", action.GetVerb(), "plans must not change the workload")
+			}
+		})
+	}
+}
+
+func TestGetRolloutStatus(t *testing.T) {
+	deployment := newDeployment(false)
+	deployment.Generation = 2
+	deployment.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: 2,
+		Replicas:           2,
+		UpdatedReplicas:    2,
+		AvailableReplicas:  2,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+		},
+	}
+	tools, _ := newTools(deployment, newReplicaSet("1", "web:1"), newReplicaSet("2", "web:2"))
+
+	result, _, err := tools.getRolloutStatus(middleware.WithToken(t.Context(), fakeToken), &mcp.CallToolRequest{}, webParams)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"llm": {
+			"status": {
+				"state": "Complete",
+				"message": "deployment \"web\" successfully rolled out",
+				"conditions": [
+					{"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable"},
+					{"type": "Progressing", "status": "True", "reason": "NewReplicaSetAvailable"}
+				]
+			},
+			"revisions": [
+				{"revision": 2, "name": "web-2", "images": ["web:2"]},
+				{"revision": 1, "name": "web-1", "images": ["web:1"]}
+			]
+		},
+		"uiContext": [{"cluster": "local", "kind": "Deployment", "name": "web", "namespace": "default", "type": "apps.deployment"}]
+	}`, result.Content[0].(*mcp.TextContent).Text)
+}
@@ -0,0 +1,49 @@
+package rollout
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/response"
+	"github.com/rancher/rancher-ai-mcp/pkg/workload"
+	"go.uber.org/zap"
+)
+
+// rolloutStatusResult is the response of the getRolloutStatus tool.
+type rolloutStatusResult struct {
+	Status    workload.Status     `json:"status"`
+	Revisions []workload.Revision `json:"revisions"`
+}
+
+// getRolloutStatus returns the rollout status and revision history of a workload.
+func (t *Tools) getRolloutStatus(ctx context.Context, toolReq *mcp.CallToolRequest, params workloadParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("getRolloutStatus called")
+
+	obj, err := t.getWorkload(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to get workload", zap.String("tool", "getRolloutStatus"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	status, err := workload.RolloutStatus(obj)
+	if err != nil {
+		zap.L().Error("failed to get rollout status", zap.String("tool", "getRolloutStatus"), zap.Error(err))
+		return nil, nil, err
+	}
+	revisions, err := workload.ListRevisions(ctx, t.client, params.Cluster, middleware.Token(ctx), obj)
+	if err != nil {
+		zap.L().Error("failed to get revision history", zap.String("tool", "getRolloutStatus"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	mcpResponse, err := response.CreateMcpResponseAny(rolloutStatusResult{Status: status, Revisions: revisions}, response.NewUIContext(obj, params.Cluster))
+	if err != nil {
+		zap.L().Error("failed to create mcp response", zap.String("tool", "getRolloutStatus"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: mcpResponse}},
+	}, nil, nil
+}
@@ -0,0 +1,123 @@
+package rollout
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	toolsSet    = "rollout"
+	toolsSetAnn = "toolset"
+)
+
+type toolsClient interface {
+	GetResource(ctx context.Context, params client.GetParams) (*unstructured.Unstructured, error)
+	GetResources(ctx context.Context, params client.ListParams) ([]*unstructured.Unstructured, error)
+	GetResourceInterface(ctx context.Context, token string, namespace string, cluster string, gvr schema.GroupVersionResource) (dynamic.ResourceInterface, error)
+}
+
+// Tools contains the tools that manage the rollouts of Deployments, StatefulSets and DaemonSets.
+type Tools struct {
+	client   toolsClient
+	ReadOnly bool
+}
+
+// NewTools creates and returns a new Tools instance.
+func NewTools(client toolsClient, readOnly bool) *Tools {
+	return &Tools{
+		client:   client,
+		ReadOnly: readOnly,
+	}
+}
+
+// AddTools registers all rollout tools with the provided MCP server.
+// Each tool is configured with metadata identifying it as part of the rollout toolset.
+func (t *Tools) AddTools(mcpServer *mcp.Server) {
+	mcp.AddTool(mcpServer, &mcp.Tool{
+		Name: "getRolloutStatus",
+		Meta: map[string]any{
+			toolsSetAnn: toolsSet,
+		},
+		Description: `Returns the rollout status of a Deployment, StatefulSet or DaemonSet: whether the rollout is complete, progressing, paused or failed, with its Progressing and Available conditions and its revision history.`},
+		t.getRolloutStatus,
+	)
+
+	if !t.ReadOnly {
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "restartRollout",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Restarts all the pods of a Deployment, StatefulSet or DaemonSet with a rolling update, like kubectl rollout restart. Returns the modified workload.`},
+			t.restartRollout,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "restartRolloutPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to restart all the pods of a Deployment, StatefulSet or DaemonSet. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.restartRolloutPlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "pauseRollout",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Pauses the rollout of a Deployment, so that changes to its pod template don't trigger a new rollout until it is resumed. Only Deployments can be paused. Returns the modified Deployment.`},
+			t.pauseRollout,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "pauseRolloutPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to pause the rollout of a Deployment. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.pauseRolloutPlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "resumeRollout",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Resumes the paused rollout of a Deployment. Returns the modified Deployment.`},
+			t.resumeRollout,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "resumeRolloutPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to resume the paused rollout of a Deployment. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.resumeRolloutPlan,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "undoRollout",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Rolls a Deployment, StatefulSet or DaemonSet back to a previous revision of its pod template, like kubectl rollout undo. toRevision selects the revision, use getRolloutStatus to list them. It defaults to the revision before the current one. Returns the modified workload.`},
+			t.undoRollout,
+		)
+
+		mcp.AddTool(mcpServer, &mcp.Tool{
+			Name: "undoRolloutPlan",
+			Meta: map[string]any{
+				toolsSetAnn: toolsSet,
+			},
+			Description: `Plans to roll a Deployment, StatefulSet or DaemonSet back to a previous revision. It returns the patch that would be applied without applying it. Only used for displaying the change when using human validation.`},
+			t.undoRolloutPlan,
+		)
+	}
+}
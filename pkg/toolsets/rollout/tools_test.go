@@ -0,0 +1,43 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddTools(t *testing.T) {
+	tests := map[string]struct {
+		readOnly      bool
+		expectedTools int
+	}{
+		"all tools":       {readOnly: false, expectedTools: 9},
+		"read only tools": {readOnly: true, expectedTools: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _ := client.NewClient(true, "")
+			mcpServer := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "v1.0.0"}, nil)
+			NewTools(c, tt.readOnly).AddTools(mcpServer)
+
+			serverTransport, clientTransport := mcp.NewInMemoryTransports()
+			_, err := mcpServer.Connect(t.Context(), serverTransport, nil)
+			require.NoError(t, err)
+			cs, err := mcp.NewClient(&mcp.Implementation{Name: "mcp-client", Version: "v1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+			require.NoError(t, err)
+			defer cs.Close()
+
+			toolsResult, err := cs.ListTools(t.Context(), &mcp.ListToolsParams{})
+
+			require.NoError(t, err)
+			assert.Len(t, toolsResult.Tools, tt.expectedTools, "incorrect number of tools registered")
+			for _, tool := range toolsResult.Tools {
+				assert.Equal(t, toolsSet, tool.Meta[toolsSetAnn])
+			}
+		})
+	}
+}
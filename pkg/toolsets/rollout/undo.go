@@ -0,0 +1,117 @@
+package rollout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rancher/rancher-ai-mcp/internal/middleware"
+	"github.com/rancher/rancher-ai-mcp/pkg/workload"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// undoRolloutParams identifies the workload to roll back and the revision to roll back to.
+type undoRolloutParams struct {
+	Cluster    string `json:"cluster" jsonschema:"the name of the Kubernetes cluster"`
+	Namespace  string `json:"namespace" jsonschema:"the namespace of the workload"`
+	Kind       string `json:"kind" jsonschema:"the kind of the workload: Deployment, StatefulSet or DaemonSet"`
+	Name       string `json:"name" jsonschema:"the name of the workload"`
+	ToRevision int64  `json:"toRevision,omitempty" jsonschema:"the revision to roll back to. Defaults to the revision before the current one"`
+}
+
+func (p undoRolloutParams) workloadParams() workloadParams {
+	return workloadParams{Cluster: p.Cluster, Namespace: p.Namespace, Kind: p.Kind, Name: p.Name}
+}
+
+// undoRollout rolls a workload back to a previous revision of its pod template.
+func (t *Tools) undoRollout(ctx context.Context, toolReq *mcp.CallToolRequest, params undoRolloutParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("undoRollout called")
+
+	_, patch, err := t.undoPatch(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create undo patch", zap.String("tool", "undoRollout"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return t.applyPatch(ctx, params.workloadParams(), patch, "undoRollout")
+}
+
+// undoRolloutPlan returns the patch undoRollout would apply.
+func (t *Tools) undoRolloutPlan(ctx context.Context, toolReq *mcp.CallToolRequest, params undoRolloutParams) (*mcp.CallToolResult, any, error) {
+	zap.L().Debug("undoRolloutPlan called")
+
+	obj, patch, err := t.undoPatch(ctx, params)
+	if err != nil {
+		zap.L().Error("failed to create undo patch", zap.String("tool", "undoRolloutPlan"), zap.Error(err))
+		return nil, nil, err
+	}
+
+	return planPatch(obj, patch, params.Cluster, "undoRolloutPlan")
+}
+
+// undoPatch returns the patch replacing the pod template of a workload with the template of the target revision.
+// Like kubectl rollout undo, rolling back creates a new revision with the old template.
+func (t *Tools) undoPatch(ctx context.Context, params undoRolloutParams) (*unstructured.Unstructured, workloadPatch, error) {
+	obj, err := t.getWorkload(ctx, params.workloadParams())
+	if err != nil {
+		return nil, workloadPatch{}, err
+	}
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused {
+		return nil, workloadPatch{}, fmt.Errorf("%s '%s' is paused, resume its rollout before rolling it back", obj.GetKind(), obj.GetName())
+	}
+
+	revisions, err := workload.ListRevisions(ctx, t.client, params.Cluster, middleware.Token(ctx), obj)
+	if err != nil {
+		return nil, workloadPatch{}, err
+	}
+	if len(revisions) == 0 {
+		return nil, workloadPatch{}, fmt.Errorf("%s '%s' has no revision history", obj.GetKind(), obj.GetName())
+	}
+
+	// revisions are sorted most recent first, and the most recent one is the current template
+	current := revisions[0]
+	var target *workload.Revision
+	switch {
+	case params.ToRevision == 0 && len(revisions) < 2:
+		return nil, workloadPatch{}, fmt.Errorf("%s '%s' has no previous revision to roll back to", obj.GetKind(), obj.GetName())
+	case params.ToRevision == 0:
+		target = &revisions[1]
+	default:
+		for i := range revisions {
+			if revisions[i].Revision == params.ToRevision {
+				target = &revisions[i]
+				break
+			}
+		}
+	}
+	if target == nil {
+		available := make([]int64, 0, len(revisions))
+		for _, revision := range revisions {
+			available = append(available, revision.Revision)
+		}
+		return nil, workloadPatch{}, fmt.Errorf("revision %d of %s '%s' not found, the available revisions are %v", params.ToRevision, obj.GetKind(), obj.GetName(), available)
+	}
+	if target.Revision == current.Revision {
+		return nil, workloadPatch{}, fmt.Errorf("%s '%s' is already at revision %d", obj.GetKind(), obj.GetName(), target.Revision)
+	}
+	if target.Template == nil {
+		return nil, workloadPatch{}, fmt.Errorf("revision %d of %s '%s' has no pod template", target.Revision, obj.GetKind(), obj.GetName())
+	}
+
+	template := runtime.DeepCopyJSON(target.Template)
+	// ControllerRevisions store the template as a patch replacing the whole template
+	delete(template, "$patch")
+	// the pod template hash is added by the Deployment controller to the ReplicaSet template, not part of the Deployment
+	unstructured.RemoveNestedField(template, "metadata", "labels", appsv1.DefaultDeploymentUniqueLabelKey)
+
+	data, err := json.Marshal([]map[string]any{{"op": "replace", "path": "/spec/template", "value": template}})
+	if err != nil {
+		return nil, workloadPatch{}, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+
+	return obj, workloadPatch(data), nil
+}
@@ -3,9 +3,12 @@ package toolsets
 import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/catalog"
 	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/core"
 	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/fleet"
+	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/monitoring"
 	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/provisioning"
+	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/rollout"
 )
 
 // toolsAdder is an interface for types that can add tools to an MCP server.
@@ -13,17 +16,25 @@ type toolsAdder interface {
 	AddTools(mcpServer *mcp.Server)
 }
 
-// AddAllTools adds all available tools to the MCP server.
-func AddAllTools(client *client.Client, mcpServer *mcp.Server, readOnly bool) {
-	for _, ta := range allToolSets(client, readOnly) {
+// AddAllTools adds all available tools to the MCP server. The monitoring tools are only added when monitoring is
+// enabled, as they need rancher-monitoring to be installed in the clusters.
+func AddAllTools(client *client.Client, mcpServer *mcp.Server, readOnly, monitoring bool) {
+	for _, ta := range allToolSets(client, readOnly, monitoring) {
 		ta.AddTools(mcpServer)
 	}
 }
 
-func allToolSets(client *client.Client, readOnly bool) []toolsAdder {
-	return []toolsAdder{
+func allToolSets(client *client.Client, readOnly, monitoringEnabled bool) []toolsAdder {
+	toolSets := []toolsAdder{
+		catalog.NewTools(client, readOnly),
 		core.NewTools(client, readOnly),
 		fleet.NewTools(client),
 		provisioning.NewTools(client, readOnly),
+		rollout.NewTools(client, readOnly),
 	}
+	if monitoringEnabled {
+		toolSets = append(toolSets, monitoring.NewTools(client, readOnly))
+	}
+
+	return toolSets
 }
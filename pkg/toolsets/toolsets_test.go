@@ -4,15 +4,42 @@ import (
 	"testing"
 
 	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/rancher/rancher-ai-mcp/pkg/toolsets/monitoring"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestAllToolSets(t *testing.T) {
-	c, err := client.NewClient(true, "")
-	require.NoError(t, err)
-	toolsets := allToolSets(c, false)
+	tests := map[string]struct {
+		monitoring       bool
+		expectedLen      int
+		expectMonitoring bool
+	}{
+		"monitoring disabled": {
+			monitoring:  false,
+			expectedLen: 5,
+		},
+		"monitoring enabled": {
+			monitoring:       true,
+			expectedLen:      6,
+			expectMonitoring: true,
+		},
+	}
 
-	assert.NotNil(t, toolsets)
-	assert.Len(t, toolsets, 3, "should have exactly 3 toolsets (core, fleet, and provisioning)")
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.NewClient(true, "")
+			require.NoError(t, err)
+			toolsets := allToolSets(c, false, tt.monitoring)
+
+			assert.Len(t, toolsets, tt.expectedLen)
+			hasMonitoring := false
+			for _, ta := range toolsets {
+				if _, ok := ta.(*monitoring.Tools); ok {
+					hasMonitoring = true
+				}
+			}
+			assert.Equal(t, tt.expectMonitoring, hasMonitoring)
+		})
+	}
 }
@@ -0,0 +1,152 @@
+package workload
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// The recommendation formula. The peak is the highest usage sample of the container across its pods, raised to the
+// memory limit for containers that were OOMKilled since their usage reached the limit before being killed.
+//
+//	CPU request    = peak CPU × 1.2, rounded up to 10m
+//	CPU limit      = CPU request × 2, only for containers that already have a CPU limit
+//	memory request = peak memory × 1.2, rounded up to 1Mi
+//	memory limit   = peak memory × 1.5, rounded up to 1Mi
+const (
+	requestHeadroomPercent     = 20
+	memoryLimitHeadroomPercent = 50
+	cpuLimitFactor             = 2
+
+	cpuStepMilli = 10
+	memoryStep   = 1024 * 1024
+
+	// overProvisionedFactor is how many times the peak usage a request must be to be over-provisioned.
+	overProvisionedFactor = 2
+	// limitPressurePercent is the percentage of a limit above which the peak usage is close to it.
+	limitPressurePercent = 90
+)
+
+// ContainerUsage is the resources of a container of a workload with the usage samples of its pods.
+type ContainerUsage struct {
+	Name          string
+	Resources     corev1.ResourceRequirements
+	CPUSamples    []resource.Quantity
+	MemorySamples []resource.Quantity
+	OOMKilled     bool
+}
+
+// ResourceRecommendation is the recommended requests and limits of a container. Recommended is empty when the
+// container has no usage sample.
+type ResourceRecommendation struct {
+	Container   string                      `json:"container"`
+	Samples     int                         `json:"samples"`
+	PeakCPU     string                      `json:"peakCpu,omitempty"`
+	PeakMemory  string                      `json:"peakMemory,omitempty"`
+	OOMKilled   bool                        `json:"oomKilled,omitempty"`
+	Current     corev1.ResourceRequirements `json:"current"`
+	Recommended corev1.ResourceRequirements `json:"recommended"`
+	Findings    []string                    `json:"findings,omitempty"`
+}
+
+// RecommendResources compares the requests and limits of a container to its peak usage and recommends CPU and
+// memory requests and limits. Other resources, like ephemeral storage, are kept as they are.
+func RecommendResources(c ContainerUsage) ResourceRecommendation {
+	recommendation := ResourceRecommendation{
+		Container: c.Name,
+		Samples:   max(len(c.CPUSamples), len(c.MemorySamples)),
+		OOMKilled: c.OOMKilled,
+		Current:   c.Resources,
+	}
+
+	memoryLimit, hasMemoryLimit := c.Resources.Limits[corev1.ResourceMemory]
+	if c.OOMKilled {
+		if hasMemoryLimit {
+			recommendation.Findings = append(recommendation.Findings, fmt.Sprintf("the container was OOMKilled, its peak memory usage reached its limit of %s", memoryLimit.String()))
+		} else {
+			recommendation.Findings = append(recommendation.Findings, "the container was OOMKilled without memory limit, the node ran out of memory")
+		}
+	}
+	if recommendation.Samples == 0 {
+		recommendation.Findings = append(recommendation.Findings, "no usage sample, the metrics server may not be installed or the pods are not running")
+		return recommendation
+	}
+
+	peakCPU := peak(c.CPUSamples)
+	peakMemory := peak(c.MemorySamples)
+	if c.OOMKilled && hasMemoryLimit && memoryLimit.Cmp(peakMemory) > 0 {
+		peakMemory = memoryLimit.DeepCopy()
+	}
+	recommendation.PeakCPU = peakCPU.String()
+	recommendation.PeakMemory = peakMemory.String()
+
+	recommendation.Findings = append(recommendation.Findings, provisioningFindings("CPU", corev1.ResourceCPU, c.Resources, peakCPU)...)
+	recommendation.Findings = append(recommendation.Findings, provisioningFindings("memory", corev1.ResourceMemory, c.Resources, peakMemory)...)
+
+	cpuRequest := roundUp(percentOf(peakCPU.MilliValue(), 100+requestHeadroomPercent), cpuStepMilli)
+	memoryRequest := roundUp(percentOf(peakMemory.Value(), 100+requestHeadroomPercent), memoryStep)
+	memoryLimitValue := roundUp(percentOf(peakMemory.Value(), 100+memoryLimitHeadroomPercent), memoryStep)
+
+	recommendation.Recommended = corev1.ResourceRequirements{
+		Requests: c.Resources.Requests.DeepCopy(),
+		Limits:   c.Resources.Limits.DeepCopy(),
+	}
+	if recommendation.Recommended.Requests == nil {
+		recommendation.Recommended.Requests = corev1.ResourceList{}
+	}
+	if recommendation.Recommended.Limits == nil {
+		recommendation.Recommended.Limits = corev1.ResourceList{}
+	}
+	recommendation.Recommended.Requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpuRequest, resource.DecimalSI)
+	recommendation.Recommended.Requests[corev1.ResourceMemory] = *resource.NewQuantity(memoryRequest, resource.BinarySI)
+	recommendation.Recommended.Limits[corev1.ResourceMemory] = *resource.NewQuantity(memoryLimitValue, resource.BinarySI)
+	if _, hasCPULimit := c.Resources.Limits[corev1.ResourceCPU]; hasCPULimit {
+		recommendation.Recommended.Limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpuRequest*cpuLimitFactor, resource.DecimalSI)
+	}
+
+	return recommendation
+}
+
+// provisioningFindings compares the request and limit of a resource to its peak usage.
+func provisioningFindings(description string, name corev1.ResourceName, resources corev1.ResourceRequirements, peak resource.Quantity) []string {
+	var findings []string
+
+	request, hasRequest := resources.Requests[name]
+	switch {
+	case !hasRequest || request.IsZero():
+		findings = append(findings, fmt.Sprintf("no %s request, the pods can be scheduled on nodes without enough %s for their peak usage of %s", description, description, peak.String()))
+	case request.MilliValue() > peak.MilliValue()*overProvisionedFactor:
+		findings = append(findings, fmt.Sprintf("%s request of %s is more than %d times the peak usage of %s", description, request.String(), overProvisionedFactor, peak.String()))
+	case peak.Cmp(request) > 0:
+		findings = append(findings, fmt.Sprintf("peak %s usage of %s exceeds the request of %s", description, peak.String(), request.String()))
+	}
+
+	if limit, hasLimit := resources.Limits[name]; hasLimit && !limit.IsZero() && peak.MilliValue()*100 > limit.MilliValue()*limitPressurePercent {
+		findings = append(findings, fmt.Sprintf("peak %s usage of %s is over %d%% of the limit of %s", description, peak.String(), limitPressurePercent, limit.String()))
+	}
+
+	return findings
+}
+
+// peak returns the highest of the samples, zero when there is none.
+func peak(samples []resource.Quantity) resource.Quantity {
+	var highest resource.Quantity
+	for _, sample := range samples {
+		if sample.Cmp(highest) > 0 {
+			highest = sample.DeepCopy()
+		}
+	}
+
+	return highest
+}
+
+// percentOf returns the percentage of the value, rounded up.
+func percentOf(value int64, percent int64) int64 {
+	return (value*percent + 99) / 100
+}
+
+// roundUp rounds the value up to a multiple of step, and to at least one step.
+func roundUp(value, step int64) int64 {
+	return max((value+step-1)/step*step, step)
+}
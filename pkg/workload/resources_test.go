@@ -0,0 +1,137 @@
+package workload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func quantities(values ...string) []resource.Quantity {
+	var q []resource.Quantity
+	for _, value := range values {
+		q = append(q, resource.MustParse(value))
+	}
+
+	return q
+}
+
+func resourceList(cpu, memory string) corev1.ResourceList {
+	list := corev1.ResourceList{}
+	if cpu != "" {
+		list[corev1.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		list[corev1.ResourceMemory] = resource.MustParse(memory)
+	}
+
+	return list
+}
+
+func TestRecommendResources(t *testing.T) {
+	tests := map[string]struct {
+		usage               ContainerUsage
+		expectedRequests    map[string]string
+		expectedLimits      map[string]string
+		expectedPeakMemory  string
+		expectedFindings    []string
+		expectedNoRecommend bool
+	}{
+		"over-provisioned container with a CPU limit": {
+			usage: ContainerUsage{
+				Name:          "app",
+				Resources:     corev1.ResourceRequirements{Requests: resourceList("1", "1Gi"), Limits: resourceList("2", "2Gi")},
+				CPUSamples:    quantities("120m", "95m", "101m"),
+				MemorySamples: quantities("200Mi", "250Mi", "180Mi"),
+			},
+			expectedRequests:   map[string]string{"cpu": "150m", "memory": "300Mi"},
+			expectedLimits:     map[string]string{"cpu": "300m", "memory": "375Mi"},
+			expectedPeakMemory: "250Mi",
+			expectedFindings: []string{
+				"CPU request of 1 is more than 2 times the peak usage of 120m",
+				"memory request of 1Gi is more than 2 times the peak usage of 250Mi",
+			},
+		},
+		"under-provisioned container without limits": {
+			usage: ContainerUsage{
+				Name:          "app",
+				Resources:     corev1.ResourceRequirements{Requests: resourceList("100m", "")},
+				CPUSamples:    quantities("333m"),
+				MemorySamples: quantities("100M"),
+			},
+			expectedRequests:   map[string]string{"cpu": "400m", "memory": "115Mi"},
+			expectedLimits:     map[string]string{"memory": "144Mi"},
+			expectedPeakMemory: "100M",
+			expectedFindings: []string{
+				"peak CPU usage of 333m exceeds the request of 100m",
+				"no memory request, the pods can be scheduled on nodes without enough memory for their peak usage of 100M",
+			},
+		},
+		"OOMKilled container": {
+			usage: ContainerUsage{
+				Name:          "app",
+				Resources:     corev1.ResourceRequirements{Requests: resourceList("50m", "128Mi"), Limits: resourceList("", "256Mi")},
+				CPUSamples:    quantities("40m"),
+				MemorySamples: quantities("90Mi"),
+				OOMKilled:     true,
+			},
+			expectedRequests:   map[string]string{"cpu": "50m", "memory": "308Mi"},
+			expectedLimits:     map[string]string{"memory": "384Mi"},
+			expectedPeakMemory: "256Mi",
+			expectedFindings: []string{
+				"the container was OOMKilled, its peak memory usage reached its limit of 256Mi",
+				"peak memory usage of 256Mi exceeds the request of 128Mi",
+				"peak memory usage of 256Mi is over 90% of the limit of 256Mi",
+			},
+		},
+		"idle container": {
+			usage: ContainerUsage{
+				Name:          "app",
+				Resources:     corev1.ResourceRequirements{Requests: resourceList("10m", "16Mi")},
+				CPUSamples:    quantities("0"),
+				MemorySamples: quantities("0"),
+			},
+			expectedRequests:   map[string]string{"cpu": "10m", "memory": "1Mi"},
+			expectedLimits:     map[string]string{"memory": "1Mi"},
+			expectedPeakMemory: "0",
+			expectedFindings: []string{
+				"CPU request of 10m is more than 2 times the peak usage of 0",
+				"memory request of 16Mi is more than 2 times the peak usage of 0",
+			},
+		},
+		"no sample": {
+			usage: ContainerUsage{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Requests: resourceList("100m", "")},
+			},
+			expectedNoRecommend: true,
+			expectedFindings:    []string{"no usage sample, the metrics server may not be installed or the pods are not running"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			recommendation := RecommendResources(tt.usage)
+
+			assert.Equal(t, tt.expectedFindings, recommendation.Findings)
+			assert.Equal(t, tt.usage.Resources, recommendation.Current)
+			if tt.expectedNoRecommend {
+				assert.Empty(t, recommendation.Recommended)
+				return
+			}
+			assert.Equal(t, tt.expectedPeakMemory, recommendation.PeakMemory)
+			assert.Equal(t, tt.expectedRequests, toStrings(recommendation.Recommended.Requests))
+			assert.Equal(t, tt.expectedLimits, toStrings(recommendation.Recommended.Limits))
+		})
+	}
+}
+
+func toStrings(list corev1.ResourceList) map[string]string {
+	strs := map[string]string{}
+	for name, q := range list {
+		strs[string(name)] = q.String()
+	}
+
+	return strs
+}
@@ -0,0 +1,173 @@
+package workload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// RevisionAnnotation is the annotation holding the revision of the ReplicaSets of a Deployment.
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+	// ChangeCauseAnnotation is the annotation recording why a revision was created.
+	ChangeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// Revision is an entry of the revision history of a workload. Deployments keep their history in ReplicaSets, and
+// StatefulSets and DaemonSets in ControllerRevisions.
+type Revision struct {
+	Revision    int64    `json:"revision"`
+	Name        string   `json:"name"`
+	Images      []string `json:"images,omitempty"`
+	Replicas    *int32   `json:"replicas,omitempty"`
+	ChangeCause string   `json:"changeCause,omitempty"`
+	Created     string   `json:"created,omitempty"`
+
+	// Template is the pod template of the revision. ControllerRevisions only store the fields that changed.
+	Template map[string]any `json:"-"`
+}
+
+// ListRevisions returns the revision history of a Deployment, StatefulSet or DaemonSet, most recent first.
+func ListRevisions(ctx context.Context, lister client.ResourceLister, cluster, token string, obj *unstructured.Unstructured) ([]Revision, error) {
+	var kind string
+	switch obj.GetKind() {
+	case "Deployment":
+		kind = "replicaset"
+	case "StatefulSet", "DaemonSet":
+		kind = "controllerrevision"
+	default:
+		return nil, fmt.Errorf("revision history is not supported for kind '%s', it must be one of Deployment, StatefulSet or DaemonSet", obj.GetKind())
+	}
+
+	selector, err := PodSelector(obj)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := lister.GetResources(ctx, client.ListParams{
+		Cluster:       cluster,
+		Kind:          kind,
+		Namespace:     obj.GetNamespace(),
+		LabelSelector: selector,
+		Token:         token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the revisions of %s '%s': %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	var revisions []Revision
+	for _, resource := range resources {
+		if !IsControlledBy(resource, obj) {
+			continue
+		}
+		revision, err := newRevision(resource)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	slices.SortFunc(revisions, func(a, b Revision) int { return int(b.Revision - a.Revision) })
+
+	return revisions, nil
+}
+
+// IsControlledBy reports whether owner is the controller of obj. Objects are matched by uid when owner has one.
+func IsControlledBy(obj, owner *unstructured.Unstructured) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if owner.GetUID() != "" {
+			return ref.UID == owner.GetUID()
+		}
+		return ref.Kind == owner.GetKind() && ref.Name == owner.GetName()
+	}
+
+	return false
+}
+
+// PodSelector returns the pod selector of a workload as a label selector string.
+func PodSelector(obj *unstructured.Unstructured) (string, error) {
+	selectorMap, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return "", fmt.Errorf("%s '%s' has no pod selector", obj.GetKind(), obj.GetName())
+	}
+	var labelSelector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, &labelSelector); err != nil {
+		return "", fmt.Errorf("invalid selector of %s '%s': %w", obj.GetKind(), obj.GetName(), err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector of %s '%s': %w", obj.GetKind(), obj.GetName(), err)
+	}
+
+	return selector.String(), nil
+}
+
+func newRevision(resource *unstructured.Unstructured) (Revision, error) {
+	revision := Revision{
+		Name:        resource.GetName(),
+		ChangeCause: resource.GetAnnotations()[ChangeCauseAnnotation],
+	}
+	if created := resource.GetCreationTimestamp(); !created.IsZero() {
+		revision.Created = created.UTC().Format(time.RFC3339)
+	}
+
+	switch resource.GetKind() {
+	case "ReplicaSet":
+		var replicaSet appsv1.ReplicaSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &replicaSet); err != nil {
+			return Revision{}, fmt.Errorf("failed to convert unstructured object to ReplicaSet: %w", err)
+		}
+		revision.Revision, _ = strconv.ParseInt(replicaSet.Annotations[RevisionAnnotation], 10, 64)
+		revision.Replicas = replicaSet.Spec.Replicas
+		revision.Images = containerImages(replicaSet.Spec.Template.Spec)
+		revision.Template, _, _ = unstructured.NestedMap(resource.Object, "spec", "template")
+	case "ControllerRevision":
+		var controllerRevision appsv1.ControllerRevision
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &controllerRevision); err != nil {
+			return Revision{}, fmt.Errorf("failed to convert unstructured object to ControllerRevision: %w", err)
+		}
+		revision.Revision = controllerRevision.Revision
+		// the data of a ControllerRevision is a patch of the workload spec holding its pod template
+		var data struct {
+			Spec struct {
+				Template map[string]any `json:"template"`
+			} `json:"spec"`
+		}
+		if len(controllerRevision.Data.Raw) == 0 {
+			break
+		}
+		if err := json.Unmarshal(controllerRevision.Data.Raw, &data); err != nil {
+			return Revision{}, fmt.Errorf("failed to read the data of ControllerRevision '%s': %w", controllerRevision.Name, err)
+		}
+		revision.Template = data.Spec.Template
+		var template corev1.PodTemplateSpec
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(data.Spec.Template, &template); err == nil {
+			revision.Images = containerImages(template.Spec)
+		}
+	}
+
+	return revision, nil
+}
+
+func containerImages(spec corev1.PodSpec) []string {
+	var images []string
+	for _, container := range spec.InitContainers {
+		images = append(images, container.Image)
+	}
+	for _, container := range spec.Containers {
+		images = append(images, container.Image)
+	}
+
+	return images
+}
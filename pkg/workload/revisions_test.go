@@ -0,0 +1,101 @@
+package workload
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rancher/rancher-ai-mcp/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+type fakeLister struct {
+	resources []*unstructured.Unstructured
+	params    client.ListParams
+}
+
+func (f *fakeLister) GetResources(_ context.Context, params client.ListParams) ([]*unstructured.Unstructured, error) {
+	f.params = params
+	return f.resources, nil
+}
+
+// ownedBy returns the owner references of an object controlled by the workload with the given name.
+func ownedBy(name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: "Deployment", Name: name, UID: types.UID("uid-" + name), Controller: ptr.To(true)}}
+}
+
+func TestListRevisions(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	replicaSet := func(name, revision, image, owner string) *unstructured.Unstructured {
+		return toUnstructured(t, "ReplicaSet", &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Annotations:     map[string]string{RevisionAnnotation: revision},
+				OwnerReferences: ownedBy(owner),
+			},
+			Spec: appsv1.ReplicaSetSpec{
+				Replicas: ptr.To(int32(1)),
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: image}}}},
+			},
+		})
+	}
+
+	t.Run("deployment replicasets", func(t *testing.T) {
+		deployment := toUnstructured(t, "Deployment", &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-web"},
+			Spec:       appsv1.DeploymentSpec{Selector: selector},
+		})
+		lister := &fakeLister{resources: []*unstructured.Unstructured{
+			replicaSet("web-1", "1", "web:1", "web"),
+			replicaSet("web-3", "3", "web:3", "web"),
+			replicaSet("web-2", "2", "web:2", "web"),
+			replicaSet("other", "7", "other:1", "other"),
+		}}
+
+		revisions, err := ListRevisions(t.Context(), lister, "local", "token", deployment)
+
+		require.NoError(t, err)
+		assert.Equal(t, "replicaset", lister.params.Kind)
+		assert.Equal(t, "app=web", lister.params.LabelSelector)
+		require.Len(t, revisions, 3)
+		assert.Equal(t, []int64{3, 2, 1}, []int64{revisions[0].Revision, revisions[1].Revision, revisions[2].Revision})
+		assert.Equal(t, []string{"web:3"}, revisions[0].Images)
+		assert.NotNil(t, revisions[0].Template)
+	})
+
+	t.Run("statefulset controllerrevisions", func(t *testing.T) {
+		statefulSet := toUnstructured(t, "StatefulSet", &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "uid-web"},
+			Spec:       appsv1.StatefulSetSpec{Selector: selector},
+		})
+		lister := &fakeLister{resources: []*unstructured.Unstructured{
+			toUnstructured(t, "ControllerRevision", &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: "default", OwnerReferences: ownedBy("web")},
+				Revision:   4,
+				Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"app","image":"web:4"}]}}}}`)},
+			}),
+		}}
+
+		revisions, err := ListRevisions(t.Context(), lister, "local", "token", statefulSet)
+
+		require.NoError(t, err)
+		assert.Equal(t, "controllerrevision", lister.params.Kind)
+		require.Len(t, revisions, 1)
+		assert.Equal(t, int64(4), revisions[0].Revision)
+		assert.Equal(t, []string{"web:4"}, revisions[0].Images)
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		_, err := ListRevisions(t.Context(), &fakeLister{}, "local", "token", toUnstructured(t, "Job", &appsv1.ReplicaSet{}))
+
+		assert.ErrorContains(t, err, "revision history is not supported for kind 'Job'")
+	})
+}
@@ -0,0 +1,191 @@
+// Package workload contains helpers shared by the tools that inspect and operate on Kubernetes workloads.
+package workload
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Rollout states reported by RolloutStatus.
+const (
+	StateComplete    = "Complete"
+	StateProgressing = "Progressing"
+	StatePaused      = "Paused"
+	StateFailed      = "Failed"
+	StateUnknown     = "Unknown"
+)
+
+// Condition is a condition of a workload.
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Status is the rollout status of a Deployment, StatefulSet or DaemonSet.
+type Status struct {
+	State      string      `json:"state"`
+	Message    string      `json:"message"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// RolloutStatus returns the rollout status of a Deployment, StatefulSet or DaemonSet. It follows the same rules as
+// kubectl rollout status, and also reports paused Deployments and the conditions of Deployments.
+func RolloutStatus(obj *unstructured.Unstructured) (Status, error) {
+	switch obj.GetKind() {
+	case "Deployment":
+		var deployment appsv1.Deployment
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &deployment); err != nil {
+			return Status{}, fmt.Errorf("failed to convert unstructured object to Deployment: %w", err)
+		}
+		return deploymentStatus(deployment), nil
+	case "StatefulSet":
+		var statefulSet appsv1.StatefulSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &statefulSet); err != nil {
+			return Status{}, fmt.Errorf("failed to convert unstructured object to StatefulSet: %w", err)
+		}
+		return statefulSetStatus(statefulSet), nil
+	case "DaemonSet":
+		var daemonSet appsv1.DaemonSet
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &daemonSet); err != nil {
+			return Status{}, fmt.Errorf("failed to convert unstructured object to DaemonSet: %w", err)
+		}
+		return daemonSetStatus(daemonSet), nil
+	default:
+		return Status{}, fmt.Errorf("rollout status is not supported for kind '%s', it must be one of Deployment, StatefulSet or DaemonSet", obj.GetKind())
+	}
+}
+
+func deploymentStatus(deployment appsv1.Deployment) Status {
+	status := Status{State: StateProgressing}
+	for _, condition := range deployment.Status.Conditions {
+		status.Conditions = append(status.Conditions, Condition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	progressing := deploymentCondition(deployment, appsv1.DeploymentProgressing)
+
+	switch {
+	case deployment.Generation > deployment.Status.ObservedGeneration:
+		status.Message = "waiting for the deployment spec update to be observed"
+	case progressing != nil && progressing.Reason == "ProgressDeadlineExceeded":
+		status.State = StateFailed
+		status.Message = fmt.Sprintf("deployment %q exceeded its progress deadline", deployment.Name)
+	case deployment.Spec.Paused:
+		status.State = StatePaused
+		status.Message = fmt.Sprintf("deployment %q rollout is paused, %d out of %d new replicas have been updated", deployment.Name, deployment.Status.UpdatedReplicas, replicas)
+	case deployment.Status.UpdatedReplicas < replicas:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d out of %d new replicas have been updated", deployment.Name, deployment.Status.UpdatedReplicas, replicas)
+	case deployment.Status.Replicas > deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d old replicas are pending termination", deployment.Name, deployment.Status.Replicas-deployment.Status.UpdatedReplicas)
+	case deployment.Status.AvailableReplicas < deployment.Status.UpdatedReplicas:
+		status.Message = fmt.Sprintf("waiting for deployment %q rollout to finish: %d of %d updated replicas are available", deployment.Name, deployment.Status.AvailableReplicas, deployment.Status.UpdatedReplicas)
+	default:
+		status.State = StateComplete
+		status.Message = fmt.Sprintf("deployment %q successfully rolled out", deployment.Name)
+	}
+
+	return status
+}
+
+func deploymentCondition(deployment appsv1.Deployment, conditionType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[i].Type == conditionType {
+			return &deployment.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
+
+func statefulSetStatus(statefulSet appsv1.StatefulSet) Status {
+	status := Status{State: StateProgressing}
+	for _, condition := range statefulSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, Condition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+	var partition int32
+	if rollingUpdate := statefulSet.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.Partition != nil {
+		partition = *rollingUpdate.Partition
+	}
+
+	switch {
+	case isOnDelete(string(statefulSet.Spec.UpdateStrategy.Type)):
+		status.State = StateUnknown
+		status.Message = fmt.Sprintf("statefulset %q uses the OnDelete update strategy, pods are only updated when they are deleted", statefulSet.Name)
+	case statefulSet.Status.ObservedGeneration == 0 || statefulSet.Generation > statefulSet.Status.ObservedGeneration:
+		status.Message = "waiting for the statefulset spec update to be observed"
+	case statefulSet.Status.ReadyReplicas < replicas:
+		status.Message = fmt.Sprintf("waiting for statefulset %q rollout to finish: %d out of %d pods are ready", statefulSet.Name, statefulSet.Status.ReadyReplicas, replicas)
+	case partition > 0 && statefulSet.Status.UpdatedReplicas < replicas-partition:
+		status.Message = fmt.Sprintf("waiting for statefulset %q partitioned rollout to finish: %d out of %d new pods have been updated", statefulSet.Name, statefulSet.Status.UpdatedReplicas, replicas-partition)
+	case partition > 0:
+		status.State = StateComplete
+		status.Message = fmt.Sprintf("statefulset %q partitioned rollout complete: %d new pods have been updated", statefulSet.Name, statefulSet.Status.UpdatedReplicas)
+	case statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision:
+		status.Message = fmt.Sprintf("waiting for statefulset %q rolling update to complete: %d pods at revision %s", statefulSet.Name, statefulSet.Status.UpdatedReplicas, statefulSet.Status.UpdateRevision)
+	default:
+		status.State = StateComplete
+		status.Message = fmt.Sprintf("statefulset %q rolling update complete: %d pods at revision %s", statefulSet.Name, statefulSet.Status.CurrentReplicas, statefulSet.Status.CurrentRevision)
+	}
+
+	return status
+}
+
+func daemonSetStatus(daemonSet appsv1.DaemonSet) Status {
+	status := Status{State: StateProgressing}
+	for _, condition := range daemonSet.Status.Conditions {
+		status.Conditions = append(status.Conditions, Condition{
+			Type:    string(condition.Type),
+			Status:  string(condition.Status),
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	desired := daemonSet.Status.DesiredNumberScheduled
+	switch {
+	case isOnDelete(string(daemonSet.Spec.UpdateStrategy.Type)):
+		status.State = StateUnknown
+		status.Message = fmt.Sprintf("daemonset %q uses the OnDelete update strategy, pods are only updated when they are deleted", daemonSet.Name)
+	case daemonSet.Generation > daemonSet.Status.ObservedGeneration:
+		status.Message = "waiting for the daemonset spec update to be observed"
+	case daemonSet.Status.UpdatedNumberScheduled < desired:
+		status.Message = fmt.Sprintf("waiting for daemonset %q rollout to finish: %d out of %d new pods have been updated", daemonSet.Name, daemonSet.Status.UpdatedNumberScheduled, desired)
+	case daemonSet.Status.NumberAvailable < desired:
+		status.Message = fmt.Sprintf("waiting for daemonset %q rollout to finish: %d of %d updated pods are available", daemonSet.Name, daemonSet.Status.NumberAvailable, desired)
+	default:
+		status.State = StateComplete
+		status.Message = fmt.Sprintf("daemonset %q successfully rolled out", daemonSet.Name)
+	}
+
+	return status
+}
+
+// isOnDelete reports whether an update strategy only replaces pods when they are deleted. An empty strategy is
+// defaulted to RollingUpdate by the API server.
+func isOnDelete(strategy string) bool {
+	return strings.EqualFold(strategy, "OnDelete")
+}
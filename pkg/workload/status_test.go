@@ -0,0 +1,166 @@
+package workload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+func toUnstructured(t *testing.T, kind string, obj any) *unstructured.Unstructured {
+	t.Helper()
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	require.NoError(t, err)
+	u := &unstructured.Unstructured{Object: content}
+	u.SetKind(kind)
+
+	return u
+}
+
+func TestRolloutStatus(t *testing.T) {
+	meta := metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2}
+
+	tests := map[string]struct {
+		kind            string
+		obj             any
+		expectedState   string
+		expectedMessage string
+		expectedError   string
+	}{
+		"deployment rolled out": {
+			kind: "Deployment",
+			obj: &appsv1.Deployment{
+				ObjectMeta: meta,
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			expectedState:   StateComplete,
+			expectedMessage: `deployment "web" successfully rolled out`,
+		},
+		"deployment spec not observed": {
+			kind: "Deployment",
+			obj: &appsv1.Deployment{
+				ObjectMeta: meta,
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+			},
+			expectedState:   StateProgressing,
+			expectedMessage: "waiting for the deployment spec update to be observed",
+		},
+		"deployment with old replicas": {
+			kind: "Deployment",
+			obj: &appsv1.Deployment{
+				ObjectMeta: meta,
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			expectedState:   StateProgressing,
+			expectedMessage: `waiting for deployment "web" rollout to finish: 1 old replicas are pending termination`,
+		},
+		"deployment exceeded its progress deadline": {
+			kind: "Deployment",
+			obj: &appsv1.Deployment{
+				ObjectMeta: meta,
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+				Status: appsv1.DeploymentStatus{
+					ObservedGeneration: 2,
+					UpdatedReplicas:    1,
+					Conditions: []appsv1.DeploymentCondition{{
+						Type:   appsv1.DeploymentProgressing,
+						Status: corev1.ConditionFalse,
+						Reason: "ProgressDeadlineExceeded",
+					}},
+				},
+			},
+			expectedState:   StateFailed,
+			expectedMessage: `deployment "web" exceeded its progress deadline`,
+		},
+		"paused deployment": {
+			kind: "Deployment",
+			obj: &appsv1.Deployment{
+				ObjectMeta: meta,
+				Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2)), Paused: true},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, UpdatedReplicas: 1},
+			},
+			expectedState:   StatePaused,
+			expectedMessage: `deployment "web" rollout is paused, 1 out of 2 new replicas have been updated`,
+		},
+		"statefulset rolling update in progress": {
+			kind: "StatefulSet",
+			obj: &appsv1.StatefulSet{
+				ObjectMeta: meta,
+				Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To(int32(3))},
+				Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "web-1", UpdateRevision: "web-2"},
+			},
+			expectedState:   StateProgressing,
+			expectedMessage: `waiting for statefulset "web" rolling update to complete: 1 pods at revision web-2`,
+		},
+		"statefulset partitioned rollout complete": {
+			kind: "StatefulSet",
+			obj: &appsv1.StatefulSet{
+				ObjectMeta: meta,
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To(int32(3)),
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+						Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+						RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To(int32(2))},
+					},
+				},
+				Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, ReadyReplicas: 3, UpdatedReplicas: 1, CurrentRevision: "web-1", UpdateRevision: "web-2"},
+			},
+			expectedState:   StateComplete,
+			expectedMessage: `statefulset "web" partitioned rollout complete: 1 new pods have been updated`,
+		},
+		"statefulset with OnDelete strategy": {
+			kind: "StatefulSet",
+			obj: &appsv1.StatefulSet{
+				ObjectMeta: meta,
+				Spec:       appsv1.StatefulSetSpec{UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType}},
+			},
+			expectedState:   StateUnknown,
+			expectedMessage: `statefulset "web" uses the OnDelete update strategy, pods are only updated when they are deleted`,
+		},
+		"daemonset waiting for available pods": {
+			kind: "DaemonSet",
+			obj: &appsv1.DaemonSet{
+				ObjectMeta: meta,
+				Status:     appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+			},
+			expectedState:   StateProgressing,
+			expectedMessage: `waiting for daemonset "web" rollout to finish: 2 of 3 updated pods are available`,
+		},
+		"daemonset rolled out": {
+			kind: "DaemonSet",
+			obj: &appsv1.DaemonSet{
+				ObjectMeta: meta,
+				Status:     appsv1.DaemonSetStatus{ObservedGeneration: 2, DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+			},
+			expectedState:   StateComplete,
+			expectedMessage: `daemonset "web" successfully rolled out`,
+		},
+		"unsupported kind": {
+			kind:          "ReplicaSet",
+			obj:           &appsv1.ReplicaSet{ObjectMeta: meta},
+			expectedError: "rollout status is not supported for kind 'ReplicaSet'",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			status, err := RolloutStatus(toUnstructured(t, tt.kind, tt.obj))
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedState, status.State)
+				assert.Equal(t, tt.expectedMessage, status.Message)
+			}
+		})
+	}
+}